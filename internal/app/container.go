@@ -1,17 +1,30 @@
 package app
 
 import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/mkeOrt/tasks-go/internal/config"
 	"github.com/mkeOrt/tasks-go/internal/database"
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/jwt"
+	"github.com/mkeOrt/tasks-go/internal/notifier"
 	"github.com/mkeOrt/tasks-go/internal/repository"
+	"github.com/mkeOrt/tasks-go/internal/scheduler"
 	"github.com/mkeOrt/tasks-go/internal/service"
+	"github.com/mkeOrt/tasks-go/internal/transport/cursor"
 	"github.com/mkeOrt/tasks-go/internal/transport/httphandler"
 	"github.com/mkeOrt/tasks-go/internal/transport/middleware"
 )
 
+// schedulerStopTimeout es cuánto espera el cleanup a que terminen los jobs en curso.
+const schedulerStopTimeout = 5 * time.Second
+
 // Container centraliza las dependencias de la aplicación.
 type Container struct {
 	Handler http.Handler
@@ -25,17 +38,158 @@ func NewContainer(cfg *config.Config, logger *slog.Logger) (*Container, error) {
 		return nil, err
 	}
 
+	// Los triggers de tasks_fts fallan en cada escritura si SQLite no tiene FTS5.
+	hasFTS5, err := database.HasFTS5(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if !hasFTS5 {
+		db.Close()
+		return nil, errors.New("sqlite3 was built without FTS5; build with -tags sqlite_fts5")
+	}
+
+	deleteMode := domain.ProjectDeleteMode(cfg.Projects.DeleteMode)
+	if !deleteMode.Valid() {
+		db.Close()
+		return nil, fmt.Errorf("invalid PROJECT_DELETE_MODE %q: must be restrict, cascade or inbox", cfg.Projects.DeleteMode)
+	}
+
+	if cfg.Auth.SessionTTL <= 0 {
+		db.Close()
+		return nil, fmt.Errorf("invalid AUTH_SESSION_TTL %s: must be positive", cfg.Auth.SessionTTL)
+	}
+	if cfg.Tokens.AccessTTL <= 0 || cfg.Tokens.RefreshTTL <= 0 {
+		db.Close()
+		return nil, errors.New("invalid JWT_ACCESS_TTL or JWT_REFRESH_TTL: must be positive")
+	}
+	keys, err := tokenKeyset(cfg, logger)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	repo := repository.NewTaskRepository(db)
-	taskService := service.NewTaskService(repo)
-	taskHandler := httphandler.NewTaskHandler(logger.With(slog.String("package", "task")), taskService)
+	taskService := service.NewTaskService(repo, repository.NewTransactor(db), cfg.Tasks.CompleteChildren)
+	taskHandler := httphandler.NewTaskHandler(
+		logger.With(slog.String("package", "task")),
+		taskService,
+		cursor.NewCodec(cursorSecret(cfg, logger)),
+		cfg.Tasks.RequireIfMatch,
+	)
+
+	tagService := service.NewTagService(repository.NewTagRepository(db))
+	tagHandler := httphandler.NewTagHandler(logger.With(slog.String("package", "tag")), tagService)
 
+	projectService := service.NewProjectService(repository.NewProjectRepository(db), deleteMode)
+	projectHandler := httphandler.NewProjectHandler(logger.With(slog.String("package", "project")), projectService, taskHandler)
+
+	todoTxtHandler := httphandler.NewTodoTxtHandler(logger.With(slog.String("package", "todotxt")), taskHandler, projectService, tagService)
+
+	trashLogger := logger.With(slog.String("package", "trash"))
+	trashService := service.NewTrashService(repository.NewTrashRepository(db), cfg.Trash.Retention)
+	trashHandler := httphandler.NewTrashHandler(trashLogger, trashService)
+
+	calendarService := service.NewCalendarService(repository.NewCalendarFeedRepository(db), taskService)
+	calendarHandler := httphandler.NewCalendarHandler(logger.With(slog.String("package", "calendar")), calendarService, cfg.Calendar.UIDDomain)
+
+	authLogger := logger.With(slog.String("package", "auth"))
+	authService := service.NewAuthService(repository.NewUserRepository(db), repository.NewSessionRepository(db), cfg.Auth.SessionTTL)
+	authHandler := httphandler.NewAuthHandler(authLogger, authService, cfg.Auth.SecureCookies)
+	tokenService := service.NewTokenService(
+		authService,
+		repository.NewRefreshTokenRepository(db),
+		repository.NewTransactor(db),
+		keys,
+		cfg.Tokens.Issuer,
+		cfg.Tokens.AccessTTL,
+		cfg.Tokens.RefreshTTL,
+	)
+	tokenHandler := httphandler.NewTokenHandler(authLogger, tokenService)
+	apiKeyService := service.NewAPIKeyService(repository.NewAPIKeyRepository(db))
+	apiKeyHandler := httphandler.NewAPIKeyHandler(authLogger, apiKeyService)
+
+	// Las API keys sólo llegan a las rutas de tareas, que piden un scope por ruta; el resto las rechaza.
+	keyless := middleware.RejectAPIKeys()
 	mux := http.NewServeMux()
-	mux.Handle("/api/tasks", taskHandler.RegisterRoutes())
+	taskRoutes := taskHandler.RegisterRoutes()
+	mux.Handle("/api/tasks", taskRoutes)
+	mux.Handle("/api/tasks/", taskRoutes)
+	tagRoutes := keyless(tagHandler.RegisterRoutes())
+	mux.Handle("/api/tags", tagRoutes)
+	mux.Handle("/api/tags/", tagRoutes)
+	mux.Handle("/api/tasks/{id}/tags/{tag_id}", tagRoutes)
+	todoTxtRoutes := todoTxtHandler.RegisterRoutes()
+	mux.Handle("/api/tasks/export.txt", todoTxtRoutes)
+	mux.Handle("/api/tasks/import/todotxt", todoTxtRoutes)
+	projectRoutes := keyless(projectHandler.RegisterRoutes())
+	mux.Handle("/api/projects", projectRoutes)
+	mux.Handle("/api/projects/", projectRoutes)
+	trashRoutes := keyless(trashHandler.RegisterRoutes())
+	mux.Handle("/api/trash", trashRoutes)
+	mux.Handle("/api/trash/", trashRoutes)
+	mux.Handle("/api/tasks/{id}/restore", trashRoutes)
+	mux.Handle("/api/calendar/", keyless(calendarHandler.RegisterRoutes()))
+	mux.Handle("/api/auth/", keyless(authHandler.RegisterRoutes()))
+	tokenRoutes := keyless(tokenHandler.RegisterRoutes())
+	mux.Handle("/api/auth/token", tokenRoutes)
+	mux.Handle("/api/auth/token/", tokenRoutes)
+	apiKeyRoutes := keyless(apiKeyHandler.RegisterRoutes())
+	mux.Handle("/api/api-keys", apiKeyRoutes)
+	mux.Handle("/api/api-keys/", apiKeyRoutes)
 
-	handler := middleware.Logger(logger)(mux)
+	// La autenticación va dentro del logger para que el actor ya esté en el contexto de cada handler.
+	// Bearer corre después de la sesión, así que un token explícito gana sobre la cookie; una API key gana sobre ambos.
+	handler := middleware.APIKey(authLogger, apiKeyService)(mux)
+	handler = middleware.Bearer(authLogger, tokenService)(handler)
+	handler = middleware.Session(authLogger, authService)(handler)
+	handler = middleware.Logger(logger)(handler)
+	handler = middleware.RequestID()(handler)
 	handler = middleware.Cors(&cfg.Cors)(handler)
 
+	// Los jobs en segundo plano corren hasta que el cleanup los detiene.
+	sched := scheduler.New(logger.With(slog.String("package", "scheduler")))
+	if cfg.Reminders.PollInterval > 0 {
+		reminderService := service.NewReminderService(
+			repository.NewReminderRepository(db),
+			notifier.NewLog(logger.With(slog.String("package", "notifier"))),
+			cfg.Reminders.Offset,
+		)
+		sched.Every("reminders", cfg.Reminders.PollInterval, reminderService.Dispatch)
+	}
+	if cfg.Trash.Retention > 0 && cfg.Trash.PurgeInterval > 0 {
+		sched.Every("trash-purge", cfg.Trash.PurgeInterval, func(ctx context.Context) error {
+			n, err := trashService.PurgeExpired(ctx)
+			if n > 0 {
+				trashLogger.Info("purged expired tasks from the trash", slog.Int64("count", n))
+			}
+			return err
+		})
+	}
+	if cfg.Auth.SessionPurgeInterval > 0 {
+		sched.Every("session-purge", cfg.Auth.SessionPurgeInterval, func(ctx context.Context) error {
+			n, err := authService.PurgeExpired(ctx)
+			if n > 0 {
+				authLogger.Info("purged expired sessions", slog.Int64("count", n))
+			}
+			if err != nil {
+				return err
+			}
+			n, err = tokenService.PurgeExpired(ctx)
+			if n > 0 {
+				authLogger.Info("purged expired refresh tokens", slog.Int64("count", n))
+			}
+			return err
+		})
+	}
+	sched.Start()
+
 	cleanup := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), schedulerStopTimeout)
+		defer cancel()
+		if err := sched.Stop(ctx); err != nil {
+			logger.Error("scheduler did not stop in time", "error", err)
+		}
 		db.Close()
 	}
 
@@ -44,3 +198,56 @@ func NewContainer(cfg *config.Config, logger *slog.Logger) (*Container, error) {
 		Cleanup: cleanup,
 	}, nil
 }
+
+// cursorSecret devuelve la clave de firma de cursores, generando una aleatoria si no está configurada.
+func cursorSecret(cfg *config.Config, logger *slog.Logger) []byte {
+	if cfg.Pagination.CursorSecret != "" {
+		return []byte(cfg.Pagination.CursorSecret)
+	}
+
+	logger.Warn("CURSOR_SECRET is not set, pagination cursors will not survive restarts")
+	key := make([]byte, 32)
+	rand.Read(key)
+	return key
+}
+
+// tokenKeyset arma las claves de los tokens de acceso, generando una aleatoria si no hay ninguna configurada.
+func tokenKeyset(cfg *config.Config, logger *slog.Logger) (*jwt.Keyset, error) {
+	alg := cfg.Tokens.Algorithm
+	var keys []jwt.Key
+	for _, spec := range cfg.Tokens.Keys {
+		key, err := jwt.ParseKey(alg, spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_KEYS: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		logger.Warn("JWT_KEYS is not set, access tokens will not survive restarts")
+		secret := make([]byte, jwt.MinHS256SecretLength)
+		rand.Read(secret)
+		var (
+			key jwt.Key
+			err error
+		)
+		switch alg {
+		case jwt.HS256:
+			key, err = jwt.NewHS256Key("ephemeral", secret)
+		case jwt.EdDSA:
+			key, err = jwt.NewEdDSAKey("ephemeral", secret)
+		default:
+			err = fmt.Errorf("unsupported algorithm %q", alg)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_ALGORITHM: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	keyset, err := jwt.NewKeyset(cfg.Tokens.Leeway, keys...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_KEYS: %w", err)
+	}
+	return keyset, nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log/slog"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,10 +24,101 @@ type CorsConfig struct {
 	AllowedOrigins []string
 }
 
+// PaginationConfig holds the settings for list pagination.
+type PaginationConfig struct {
+	// CursorSecret signs pagination cursors. When empty, a random key is
+	// generated at startup and cursors do not survive restarts.
+	CursorSecret string
+}
+
+// ProjectsConfig holds the settings for projects.
+type ProjectsConfig struct {
+	// DeleteMode decides what happens to the tasks of a deleted project:
+	// "restrict" refuses while it has tasks, "cascade" moves them to the
+	// trash and "inbox" moves them to the inbox project.
+	DeleteMode string
+}
+
+// TasksConfig holds the settings for tasks.
+type TasksConfig struct {
+	// CompleteChildren makes completing a task also complete all of its subtasks.
+	CompleteChildren bool
+	// RequireIfMatch makes writes to an existing task fail unless they send
+	// an If-Match header with the task's ETag.
+	RequireIfMatch bool
+}
+
+// RemindersConfig holds the settings for task reminders.
+type RemindersConfig struct {
+	// Offset is how long before a task's due time its reminder fires.
+	Offset time.Duration
+	// PollInterval is how often due reminders are looked for. Zero
+	// disables reminders.
+	PollInterval time.Duration
+}
+
+// TrashConfig holds the settings for deleted tasks.
+type TrashConfig struct {
+	// Retention is how long deleted tasks stay in the trash before they are
+	// purged. Zero keeps them until purged by hand.
+	Retention time.Duration
+	// PurgeInterval is how often expired tasks are purged.
+	PurgeInterval time.Duration
+}
+
+// CalendarConfig holds the settings for iCalendar feeds.
+type CalendarConfig struct {
+	// UIDDomain is the domain part of the UID of every task in a feed. It
+	// must not change once feeds are in use, or clients see new tasks.
+	UIDDomain string
+}
+
+// AuthConfig holds the settings for user accounts and sessions.
+type AuthConfig struct {
+	// SessionTTL is how long a session lasts after signing in.
+	SessionTTL time.Duration
+	// SecureCookies limits the session cookie to HTTPS. Only turn it off
+	// for local development over plain HTTP.
+	SecureCookies bool
+	// SessionPurgeInterval is how often expired sessions and refresh tokens
+	// are deleted.
+	SessionPurgeInterval time.Duration
+}
+
+// TokensConfig holds the settings for bearer tokens.
+type TokensConfig struct {
+	// Algorithm signs access tokens: "HS256" or "EdDSA".
+	Algorithm string
+	// Keys are the signing keys as "kid:secret" pairs. The first signs new
+	// tokens; after a rotation, keep the old keys listed until the tokens
+	// they signed expire. HS256 secrets are used as they are and need at
+	// least 32 bytes; EdDSA secrets are base64-encoded 32-byte seeds. When
+	// empty, a random key is generated at startup and tokens do not survive
+	// restarts.
+	Keys []string
+	// Issuer names this server in the tokens it signs.
+	Issuer string
+	// AccessTTL is how long an access token is valid.
+	AccessTTL time.Duration
+	// RefreshTTL is how long a refresh token is valid. Every refresh
+	// starts it again.
+	RefreshTTL time.Duration
+	// Leeway is how much clock skew is tolerated when checking expiry.
+	Leeway time.Duration
+}
+
 type Config struct {
-	Server ServerConfig
-	DB     DatabaseConfig
-	Cors   CorsConfig
+	Server     ServerConfig
+	DB         DatabaseConfig
+	Cors       CorsConfig
+	Pagination PaginationConfig
+	Projects   ProjectsConfig
+	Tasks      TasksConfig
+	Reminders  RemindersConfig
+	Trash      TrashConfig
+	Calendar   CalendarConfig
+	Auth       AuthConfig
+	Tokens     TokensConfig
 }
 
 func NewConfig(logger *slog.Logger) *Config {
@@ -47,6 +139,40 @@ func NewConfig(logger *slog.Logger) *Config {
 		Cors: CorsConfig{
 			AllowedOrigins: getSliceEnvOrDefault("ALLOWED_ORIGINS", []string{"*"}),
 		},
+		Pagination: PaginationConfig{
+			CursorSecret: os.Getenv("CURSOR_SECRET"),
+		},
+		Projects: ProjectsConfig{
+			DeleteMode: getEnvOrDefault("PROJECT_DELETE_MODE", "restrict"),
+		},
+		Tasks: TasksConfig{
+			CompleteChildren: getBoolEnvOrDefault("TASK_COMPLETE_CHILDREN", false),
+			RequireIfMatch:   getBoolEnvOrDefault("TASK_REQUIRE_IF_MATCH", false),
+		},
+		Reminders: RemindersConfig{
+			Offset:       getDurationEnvOrDefault("REMINDER_OFFSET", 15*time.Minute),
+			PollInterval: getDurationEnvOrDefault("REMINDER_POLL_INTERVAL", 30*time.Second),
+		},
+		Trash: TrashConfig{
+			Retention:     getDurationEnvOrDefault("TRASH_RETENTION", 30*24*time.Hour),
+			PurgeInterval: getDurationEnvOrDefault("TRASH_PURGE_INTERVAL", time.Hour),
+		},
+		Calendar: CalendarConfig{
+			UIDDomain: getEnvOrDefault("CALENDAR_UID_DOMAIN", "tasks-go.local"),
+		},
+		Auth: AuthConfig{
+			SessionTTL:           getDurationEnvOrDefault("AUTH_SESSION_TTL", 30*24*time.Hour),
+			SecureCookies:        getBoolEnvOrDefault("AUTH_SECURE_COOKIES", true),
+			SessionPurgeInterval: getDurationEnvOrDefault("AUTH_SESSION_PURGE_INTERVAL", time.Hour),
+		},
+		Tokens: TokensConfig{
+			Algorithm:  getEnvOrDefault("JWT_ALGORITHM", "HS256"),
+			Keys:       getSliceEnvOrDefault("JWT_KEYS", nil),
+			Issuer:     getEnvOrDefault("JWT_ISSUER", "tasks-go"),
+			AccessTTL:  getDurationEnvOrDefault("JWT_ACCESS_TTL", 15*time.Minute),
+			RefreshTTL: getDurationEnvOrDefault("JWT_REFRESH_TTL", 30*24*time.Hour),
+			Leeway:     getDurationEnvOrDefault("JWT_LEEWAY", 30*time.Second),
+		},
 	}
 }
 
@@ -68,6 +194,17 @@ func getDurationEnvOrDefault(key string, defaultValue time.Duration) time.Durati
 	return defaultValue
 }
 
+func getBoolEnvOrDefault(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return defaultValue
+		}
+		return b
+	}
+	return defaultValue
+}
+
 func getSliceEnvOrDefault(key string, defaultValue []string) []string {
 	if value := os.Getenv(key); value != "" {
 		return strings.Split(value, ",")
@@ -2,13 +2,21 @@ package database
 
 import (
 	"database/sql"
+	"strconv"
+	"strings"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
-// NewSqliteDB creates a new SQLite database connection.
+// busyTimeout is how long, in milliseconds, a connection waits for a lock
+// another one holds before failing with SQLITE_BUSY.
+const busyTimeout = 5000
+
+// NewSqliteDB creates a new SQLite database connection. Foreign key
+// enforcement, the busyTimeout and immediate transactions are set on every
+// pooled connection unless the data source name configures them explicitly.
 func NewSqliteDB(datasourceName string) (*sql.DB, error) {
-	db, err := sql.Open("sqlite3", datasourceName)
+	db, err := sql.Open("sqlite3", withImmediateTx(withBusyTimeout(withForeignKeys(datasourceName))))
 	if err != nil {
 		return nil, err
 	}
@@ -19,3 +27,50 @@ func NewSqliteDB(datasourceName string) (*sql.DB, error) {
 
 	return db, nil
 }
+
+// HasFTS5 reports whether the linked SQLite library was compiled with the
+// FTS5 full-text search extension. go-sqlite3 only enables it when built with
+// the sqlite_fts5 tag.
+func HasFTS5(db *sql.DB) (bool, error) {
+	var enabled bool
+	if err := db.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled); err != nil {
+		return false, err
+	}
+	return enabled, nil
+}
+
+// withForeignKeys adds go-sqlite3's _foreign_keys option to dsn. SQLite
+// leaves enforcement off by default, and a PRAGMA would only reach one of
+// the pooled connections.
+func withForeignKeys(dsn string) string {
+	return withParam(dsn, "_foreign_keys=on", "_foreign_keys=", "_fk=")
+}
+
+// withBusyTimeout adds go-sqlite3's _busy_timeout option to dsn, so that
+// writers, such as requests stamping the last use of an API key, wait for
+// each other instead of failing.
+func withBusyTimeout(dsn string) string {
+	return withParam(dsn, "_busy_timeout="+strconv.Itoa(busyTimeout), "_busy_timeout=", "_timeout=")
+}
+
+// withImmediateTx adds go-sqlite3's _txlock=immediate option to dsn, so
+// transactions take the write lock when they begin. A deferred transaction
+// that read before writing fails at once when another writer got there
+// first, where an immediate one waits for it and then reads what it wrote.
+func withImmediateTx(dsn string) string {
+	return withParam(dsn, "_txlock=immediate", "_txlock=")
+}
+
+// withParam appends param to the query of dsn unless one of the names it
+// goes by is there already.
+func withParam(dsn, param string, names ...string) string {
+	for _, name := range names {
+		if strings.Contains(dsn, name) {
+			return dsn
+		}
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&" + param
+	}
+	return dsn + "?" + param
+}
@@ -13,3 +13,88 @@ func TestNewSqliteDB(t *testing.T) {
 		t.Fatal("expected sqlite db")
 	}
 }
+
+func TestHasFTS5(t *testing.T) {
+	t.Parallel()
+	db, err := NewSqliteDB(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// The result depends on the build tags, so only the query itself is checked.
+	if _, err := HasFTS5(db); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewSqliteDB_ForeignKeys(t *testing.T) {
+	t.Parallel()
+	db, err := NewSqliteDB(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var enabled bool
+	if err := db.QueryRow("PRAGMA foreign_keys").Scan(&enabled); err != nil {
+		t.Fatal(err)
+	}
+	if !enabled {
+		t.Fatal("expected foreign keys to be enforced")
+	}
+}
+
+func TestWithForeignKeys(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		dsn      string
+		expected string
+	}{
+		{dsn: "database.db", expected: "database.db?_foreign_keys=on"},
+		{dsn: "file:test.db?cache=shared", expected: "file:test.db?cache=shared&_foreign_keys=on"},
+		{dsn: "database.db?_fk=off", expected: "database.db?_fk=off"},
+	}
+
+	for _, tt := range tests {
+		if got := withForeignKeys(tt.dsn); got != tt.expected {
+			t.Errorf("withForeignKeys(%q) = %q, want %q", tt.dsn, got, tt.expected)
+		}
+	}
+}
+
+func TestWithBusyTimeout(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		dsn      string
+		expected string
+	}{
+		{dsn: "database.db", expected: "database.db?_busy_timeout=5000"},
+		{dsn: "database.db?_foreign_keys=on", expected: "database.db?_foreign_keys=on&_busy_timeout=5000"},
+		{dsn: "database.db?_timeout=100", expected: "database.db?_timeout=100"},
+	}
+
+	for _, tt := range tests {
+		if got := withBusyTimeout(tt.dsn); got != tt.expected {
+			t.Errorf("withBusyTimeout(%q) = %q, want %q", tt.dsn, got, tt.expected)
+		}
+	}
+}
+
+func TestWithImmediateTx(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		dsn      string
+		expected string
+	}{
+		{dsn: "database.db", expected: "database.db?_txlock=immediate"},
+		{dsn: "database.db?_busy_timeout=5000", expected: "database.db?_busy_timeout=5000&_txlock=immediate"},
+		{dsn: "database.db?_txlock=deferred", expected: "database.db?_txlock=deferred"},
+	}
+
+	for _, tt := range tests {
+		if got := withImmediateTx(tt.dsn); got != tt.expected {
+			t.Errorf("withImmediateTx(%q) = %q, want %q", tt.dsn, got, tt.expected)
+		}
+	}
+}
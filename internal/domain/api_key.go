@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// APIKeyPrefix starts every API key, so that keys are easy to spot in
+// configs and logs and are told apart from access tokens.
+const APIKeyPrefix = "tsk_"
+
+// APIKeyScope names what an API key may do.
+type APIKeyScope string
+
+const (
+	// ScopeTasksRead allows reading tasks.
+	ScopeTasksRead APIKeyScope = "tasks:read"
+	// ScopeTasksWrite allows creating, changing and deleting tasks.
+	ScopeTasksWrite APIKeyScope = "tasks:write"
+)
+
+// Valid reports whether s is a known scope.
+func (s APIKeyScope) Valid() bool {
+	return s == ScopeTasksRead || s == ScopeTasksWrite
+}
+
+// APIKey is a long-lived credential a user makes for scripts and services.
+// Requests made with it act as its user, but only on the routes its scopes
+// allow. Only a hash of the key is stored; Prefix, the start of the key,
+// tells keys apart in lists.
+type APIKey struct {
+	ID         int64
+	User       User
+	Name       string
+	Prefix     string
+	Scopes     []APIKeyScope
+	ExpiresAt  *time.Time
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+// HasScope reports whether the key was granted scope.
+func (k APIKey) HasScope(scope APIKeyScope) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// Actor returns how the changes made with the key are attributed in task
+// histories.
+func (k APIKey) Actor() string {
+	return "api_key:" + strconv.FormatInt(k.ID, 10)
+}
+
+type APIKeyRepository interface {
+	// GetAll returns the keys of a user.
+	GetAll(ctx context.Context, userID int64) ([]APIKey, error)
+	// Create stores a key of key.User that hashes to keyHash.
+	Create(ctx context.Context, key APIKey, keyHash string) (APIKey, error)
+	// Delete removes a key of a user. Keys of other users are reported as
+	// ErrAPIKeyNotFound.
+	Delete(ctx context.Context, userID, id int64) error
+	// Get returns the key that hashes to keyHash with its user, whether or
+	// not it expired.
+	Get(ctx context.Context, keyHash string) (APIKey, error)
+	// Touch records that the key with the given ID was used at the given
+	// time.
+	Touch(ctx context.Context, id int64, at time.Time) error
+}
+
+// WithAPIKey returns a copy of ctx carrying the API key a request was
+// made with.
+func WithAPIKey(ctx context.Context, key APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyKey, key)
+}
+
+// APIKeyFrom returns the API key carried by ctx, if the request was made
+// with one.
+func APIKeyFrom(ctx context.Context) (APIKey, bool) {
+	key, ok := ctx.Value(apiKeyKey).(APIKey)
+	return key, ok
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// CalendarFeed is a subscription to the tasks as an iCalendar feed. Calendar
+// clients cannot send auth headers, so a feed is reached through a secret
+// token in its URL. Only a hash of the token is stored. A feed lists the
+// tasks of its owner; feeds without one list the tasks without an owner.
+type CalendarFeed struct {
+	ID         int64
+	Name       string
+	OwnerID    *int64
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+// CalendarFeedRepository stores calendar feeds. GetAll, Create and Delete
+// work on the feeds of the tenant of the context.
+type CalendarFeedRepository interface {
+	GetAll(ctx context.Context) ([]CalendarFeed, error)
+	// Create stores a feed reached with the token that hashes to tokenHash.
+	Create(ctx context.Context, feed CalendarFeed, tokenHash string) (CalendarFeed, error)
+	Delete(ctx context.Context, id int64) error
+	// Use returns the feed whose token hashes to tokenHash, whoever owns
+	// it, and records that it was read at the given time.
+	Use(ctx context.Context, tokenHash string, at time.Time) (CalendarFeed, error)
+}
@@ -9,4 +9,162 @@ var (
 	ErrTaskNotFound = errors.New("task not found")
 	// ErrTaskRetrievalFailed indicates a failure when fetching tasks.
 	ErrTaskRetrievalFailed = errors.New("failed to retrieve tasks")
+	// ErrTaskCreationFailed indicates a failure when creating a task.
+	ErrTaskCreationFailed = errors.New("failed to create task")
+	// ErrTaskUpdateFailed indicates a failure when updating a task.
+	ErrTaskUpdateFailed = errors.New("failed to update task")
+	// ErrTaskDeletionFailed indicates a failure when deleting a task.
+	ErrTaskDeletionFailed = errors.New("failed to delete task")
+	// ErrInvalidCursor indicates a pagination cursor that is malformed, has been
+	// tampered with, or was issued for a different sort order.
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrInvalidSearchQuery indicates a full-text query with invalid syntax.
+	ErrInvalidSearchQuery = errors.New("invalid search query")
+	// ErrParentTaskNotFound indicates a parent_id that refers to no task.
+	ErrParentTaskNotFound = errors.New("parent task not found")
+	// ErrTaskHierarchyCycle indicates an attempt to nest a task under itself
+	// or one of its own subtasks.
+	ErrTaskHierarchyCycle = errors.New("task cannot be nested under itself")
+	// ErrTaskTooDeep indicates a task tree that would exceed MaxTaskDepth levels.
+	ErrTaskTooDeep = errors.New("task tree too deep")
+	// ErrDependencyCycle indicates a dependency that would make a task wait,
+	// directly or transitively, on itself.
+	ErrDependencyCycle = errors.New("task dependency cycle")
+	// ErrTaskBlocked indicates an attempt to complete a task while a task it
+	// depends on is still open.
+	ErrTaskBlocked = errors.New("task is blocked by open tasks")
+	// ErrParentTaskDeleted indicates an attempt to restore a subtask whose
+	// parent task is still in the trash.
+	ErrParentTaskDeleted = errors.New("parent task is in the trash")
+	// ErrTaskVersionConflict indicates a write based on a version of the task
+	// that has since been changed by someone else.
+	ErrTaskVersionConflict = errors.New("task was modified concurrently")
+	// ErrTaskVersionRequired indicates a write that does not state which
+	// version of the task it is based on, when one is required.
+	ErrTaskVersionRequired = errors.New("task version required")
+	// ErrTaskOperationSkipped indicates a batch operation that was not run
+	// because an earlier one failed in an all-or-nothing batch.
+	ErrTaskOperationSkipped = errors.New("task operation skipped")
+	// ErrTaskBatchFailed indicates a failure when running a batch of task
+	// operations as a whole.
+	ErrTaskBatchFailed = errors.New("failed to run task batch")
+	// ErrTaskImportFailed indicates a failure when importing tasks as a whole.
+	ErrTaskImportFailed = errors.New("failed to import tasks")
+	// ErrTaskImportRejected indicates an import that was rolled back because
+	// some of its rows failed.
+	ErrTaskImportRejected = errors.New("task import has failing rows")
+)
+
+// Tag errors represent domain-level error conditions for tags.
+var (
+	// ErrTagNotFound indicates that the requested tag does not exist.
+	ErrTagNotFound = errors.New("tag not found")
+	// ErrTagAlreadyExists indicates that another tag already has the same name.
+	ErrTagAlreadyExists = errors.New("tag already exists")
+	// ErrTagRetrievalFailed indicates a failure when fetching tags.
+	ErrTagRetrievalFailed = errors.New("failed to retrieve tags")
+	// ErrTagCreationFailed indicates a failure when creating a tag.
+	ErrTagCreationFailed = errors.New("failed to create tag")
+	// ErrTagUpdateFailed indicates a failure when updating a tag or its
+	// assignment to a task.
+	ErrTagUpdateFailed = errors.New("failed to update tag")
+	// ErrTagDeletionFailed indicates a failure when deleting a tag.
+	ErrTagDeletionFailed = errors.New("failed to delete tag")
+)
+
+// Project errors represent domain-level error conditions for projects.
+var (
+	// ErrProjectNotFound indicates that the requested project does not exist.
+	ErrProjectNotFound = errors.New("project not found")
+	// ErrProjectNotEmpty indicates a delete refused because the project still has tasks.
+	ErrProjectNotEmpty = errors.New("project still has tasks")
+	// ErrInboxProjectDelete indicates an attempt to delete the inbox project.
+	ErrInboxProjectDelete = errors.New("inbox project cannot be deleted")
+	// ErrProjectRetrievalFailed indicates a failure when fetching projects.
+	ErrProjectRetrievalFailed = errors.New("failed to retrieve projects")
+	// ErrProjectCreationFailed indicates a failure when creating a project.
+	ErrProjectCreationFailed = errors.New("failed to create project")
+	// ErrProjectUpdateFailed indicates a failure when updating a project.
+	ErrProjectUpdateFailed = errors.New("failed to update project")
+	// ErrProjectDeletionFailed indicates a failure when deleting a project.
+	ErrProjectDeletionFailed = errors.New("failed to delete project")
+)
+
+// Calendar feed errors represent domain-level error conditions for calendar
+// feeds.
+var (
+	// ErrCalendarFeedNotFound indicates a calendar feed that does not exist,
+	// or a token that matches no feed.
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+	// ErrCalendarFeedRetrievalFailed indicates a failure when fetching
+	// calendar feeds or their tasks.
+	ErrCalendarFeedRetrievalFailed = errors.New("failed to retrieve calendar feeds")
+	// ErrCalendarFeedCreationFailed indicates a failure when creating a
+	// calendar feed.
+	ErrCalendarFeedCreationFailed = errors.New("failed to create calendar feed")
+	// ErrCalendarFeedDeletionFailed indicates a failure when deleting a
+	// calendar feed.
+	ErrCalendarFeedDeletionFailed = errors.New("failed to delete calendar feed")
+)
+
+// User and session errors represent domain-level error conditions for
+// accounts and signing in.
+var (
+	// ErrUserNotFound indicates that the requested user does not exist.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrUserAlreadyExists indicates an email address that is already
+	// registered.
+	ErrUserAlreadyExists = errors.New("user already exists")
+	// ErrInvalidCredentials indicates a sign-in with an unknown email address
+	// or a wrong password. The two are not told apart.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrUnauthenticated indicates a request that needs a signed-in user and
+	// has none, or whose session is unknown or expired.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrSessionNotFound indicates a session token that matches no live
+	// session.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrUserCreationFailed indicates a failure when registering a user.
+	ErrUserCreationFailed = errors.New("failed to create user")
+	// ErrSessionFailed indicates a failure when creating, reading or ending
+	// a session.
+	ErrSessionFailed = errors.New("failed to handle session")
+	// ErrInvalidToken indicates a bearer or refresh token that is malformed,
+	// forged, unknown or expired.
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrRefreshTokenNotFound indicates a refresh token that matches no
+	// stored token.
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	// ErrRefreshTokenReused indicates a refresh token presented after it was
+	// already traded for a new one, which revokes its whole family.
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+	// ErrTokenFailed indicates a failure when issuing, refreshing or
+	// revoking tokens.
+	ErrTokenFailed = errors.New("failed to handle tokens")
+	// ErrAPIKeyNotFound indicates that the requested API key does not exist.
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrInvalidAPIKey indicates a request made with an API key that is
+	// malformed, unknown or expired.
+	ErrInvalidAPIKey = errors.New("invalid api key")
+	// ErrInsufficientScope indicates a request made with an API key whose
+	// scopes do not allow it.
+	ErrInsufficientScope = errors.New("insufficient scope")
+	// ErrAPIKeyRetrievalFailed indicates a failure when listing API keys.
+	ErrAPIKeyRetrievalFailed = errors.New("failed to retrieve api keys")
+	// ErrAPIKeyCreationFailed indicates a failure when creating an API key.
+	ErrAPIKeyCreationFailed = errors.New("failed to create api key")
+	// ErrAPIKeyDeletionFailed indicates a failure when deleting an API key.
+	ErrAPIKeyDeletionFailed = errors.New("failed to delete api key")
+	// ErrAPIKeyAuthenticationFailed indicates a failure when checking the
+	// API key of a request.
+	ErrAPIKeyAuthenticationFailed = errors.New("failed to authenticate api key")
+)
+
+// Validation errors are returned when input is rejected before reaching storage.
+var (
+	// ErrInvalidRequest indicates a request that cannot be decoded,
+	// such as malformed JSON, unknown fields or values of the wrong type.
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrValidationFailed indicates well-formed input that breaks a business rule.
+	ErrValidationFailed = errors.New("validation failed")
 )
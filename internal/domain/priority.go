@@ -0,0 +1,43 @@
+package domain
+
+// Priority ranks how urgent a task is. The zero value is PriorityNone.
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+var priorityNames = [...]string{
+	PriorityNone:   "none",
+	PriorityLow:    "low",
+	PriorityMedium: "medium",
+	PriorityHigh:   "high",
+	PriorityUrgent: "urgent",
+}
+
+// String returns the lowercase name of p, such as "high".
+func (p Priority) String() string {
+	if !p.Valid() {
+		return "unknown"
+	}
+	return priorityNames[p]
+}
+
+// Valid reports whether p is one of the defined priorities.
+func (p Priority) Valid() bool {
+	return p >= PriorityNone && p <= PriorityUrgent
+}
+
+// ParsePriority returns the priority named s.
+func ParsePriority(s string) (Priority, bool) {
+	for p, name := range priorityNames {
+		if name == s {
+			return Priority(p), true
+		}
+	}
+	return PriorityNone, false
+}
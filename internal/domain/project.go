@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Project groups tasks. Projects belong to a tenant, and exactly one project
+// of each tenant is its inbox, which cannot be deleted and receives the tasks
+// of the tenant's deleted projects in ProjectDeleteInbox mode.
+type Project struct {
+	ID          int64
+	Name        string
+	Description string
+	Inbox       bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// ProjectDeleteMode decides what happens to the tasks of a deleted project.
+type ProjectDeleteMode string
+
+const (
+	// ProjectDeleteRestrict refuses to delete a project that still has tasks.
+	ProjectDeleteRestrict ProjectDeleteMode = "restrict"
+	// ProjectDeleteCascade deletes the project's tasks along with it, moving
+	// them to the trash out of the project.
+	ProjectDeleteCascade ProjectDeleteMode = "cascade"
+	// ProjectDeleteInbox moves the project's tasks to the inbox project.
+	ProjectDeleteInbox ProjectDeleteMode = "inbox"
+)
+
+// Valid reports whether m is a supported delete mode.
+func (m ProjectDeleteMode) Valid() bool {
+	switch m {
+	case ProjectDeleteRestrict, ProjectDeleteCascade, ProjectDeleteInbox:
+		return true
+	default:
+		return false
+	}
+}
+
+type ProjectRepository interface {
+	GetAll(ctx context.Context) ([]Project, error)
+	GetByID(ctx context.Context, id int64) (Project, error)
+	Create(ctx context.Context, project Project) (Project, error)
+	Update(ctx context.Context, project Project) (Project, error)
+	// Delete removes a project, handling its tasks according to mode.
+	Delete(ctx context.Context, id int64, mode ProjectDeleteMode) error
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Reminder is a notification that a task is coming due. It fires once, a
+// configured offset before the task's due time.
+type Reminder struct {
+	ID        int64
+	TaskID    int64
+	TaskTitle string
+	// DueAt is the task's due time the reminder was scheduled for.
+	DueAt    time.Time
+	RemindAt time.Time
+	FiredAt  *time.Time
+}
+
+type ReminderRepository interface {
+	// Schedule creates the missing reminders of open tasks due after now,
+	// set to fire offset before their due time.
+	Schedule(ctx context.Context, offset time.Duration, now time.Time) error
+	// Pending returns up to limit unfired reminders whose time has come by
+	// now, skipping those of tasks completed, rescheduled or deleted since.
+	Pending(ctx context.Context, now time.Time, limit int) ([]Reminder, error)
+	// MarkFired records that the reminder fired at the given time. It
+	// reports false when the reminder had fired already.
+	MarkFired(ctx context.Context, id int64, at time.Time) (bool, error)
+}
+
+// Notifier delivers reminders to the task's owner.
+type Notifier interface {
+	Notify(ctx context.Context, reminder Reminder) error
+}
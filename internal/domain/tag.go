@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Tag is a label that can be attached to any number of tasks.
+// Tags belong to a tenant, and names are unique per tenant, ignoring case.
+type Tag struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TagMatchMode controls how a task list filtered by several tags is matched.
+type TagMatchMode string
+
+const (
+	// TagMatchAll selects tasks carrying every requested tag.
+	TagMatchAll TagMatchMode = "all"
+	// TagMatchAny selects tasks carrying at least one requested tag.
+	TagMatchAny TagMatchMode = "any"
+)
+
+// Valid reports whether m is a supported match mode.
+func (m TagMatchMode) Valid() bool {
+	return m == TagMatchAll || m == TagMatchAny
+}
+
+type TagRepository interface {
+	GetAll(ctx context.Context) ([]Tag, error)
+	GetByID(ctx context.Context, id int64) (Tag, error)
+	Create(ctx context.Context, tag Tag) (Tag, error)
+	Update(ctx context.Context, tag Tag) (Tag, error)
+	Delete(ctx context.Context, id int64) error
+	// Attach adds the tag to the task; attaching it twice is a no-op.
+	Attach(ctx context.Context, taskID, tagID int64) error
+	// Detach removes the tag from the task; detaching a tag the task does
+	// not carry is a no-op.
+	Detach(ctx context.Context, taskID, tagID int64) error
+}
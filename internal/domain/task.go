@@ -2,17 +2,275 @@ package domain
 
 import (
 	"context"
+	"strings"
 	"time"
 )
 
 type Task struct {
-	ID        int64
-	Title     string
-	Done      bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID          int64
+	Title       string
+	Description string
+	Done        bool
+	Priority    Priority
+	StartAt     *time.Time
+	DueAt       *time.Time
+	// ProjectID is the project the task belongs to, or nil for none.
+	ProjectID *int64
+	// ParentID is the task this one is a subtask of, or nil for a top-level task.
+	ParentID *int64
+	// Recurrence is the RFC 5545 RRULE the task repeats by, in canonical
+	// form, or empty for a one-off task. Recurring tasks have a due date.
+	Recurrence string
+	// Blocked is maintained by storage: it reports whether any task this one
+	// depends on is still open.
+	Blocked bool
+	// CompletedAt is maintained by storage: it is set when Done becomes true
+	// and cleared when it becomes false.
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	// Version is maintained by storage: it starts at 1 and grows with every
+	// change to the task. When non-zero on Update, it is the version the
+	// caller expects to replace.
+	Version int64
+	// Tags are the tags attached to the task, ordered by name.
+	Tags []Tag
+}
+
+// NullableTime is a patch value for an optional timestamp. Set reports
+// whether the field changes at all; a nil Time clears it.
+type NullableTime struct {
+	Set  bool
+	Time *time.Time
+}
+
+// NullableID is a patch value for an optional reference. Set reports
+// whether the field changes at all; a nil ID clears it.
+type NullableID struct {
+	Set bool
+	ID  *int64
+}
+
+// TaskPatch holds the fields of a partial task update.
+// A nil (or unset) field is left unchanged.
+type TaskPatch struct {
+	Title       *string
+	Description *string
+	Done        *bool
+	Priority    *Priority
+	StartAt     NullableTime
+	DueAt       NullableTime
+	ProjectID   NullableID
+	ParentID    NullableID
+	// Recurrence replaces the task's RRULE; an empty string stops it repeating.
+	Recurrence *string
+	// Version, when non-zero, is the version of the task the patch expects
+	// to apply to.
+	Version int64
+}
+
+// MaxTaskDepth is the number of levels a task tree may have, counting the
+// top-level task as the first.
+const MaxTaskDepth = 5
+
+// TaskNode is a task together with its subtasks, recursively.
+type TaskNode struct {
+	Task     Task
+	Children []TaskNode
+}
+
+// BuildTaskTree nests tasks under the task with the given root ID. Tasks
+// must list every parent before its children; siblings keep their order.
+func BuildTaskTree(rootID int64, tasks []Task) TaskNode {
+	children := make(map[int64][]Task)
+	var root Task
+	for _, t := range tasks {
+		if t.ID == rootID {
+			root = t
+			continue
+		}
+		if t.ParentID != nil {
+			children[*t.ParentID] = append(children[*t.ParentID], t)
+		}
+	}
+
+	var build func(t Task) TaskNode
+	build = func(t Task) TaskNode {
+		node := TaskNode{Task: t, Children: []TaskNode{}}
+		for _, c := range children[t.ID] {
+			node.Children = append(node.Children, build(c))
+		}
+		return node
+	}
+	return build(root)
+}
+
+// TaskSortField is a column tasks can be ordered by.
+type TaskSortField string
+
+const (
+	TaskSortCreatedAt TaskSortField = "created_at"
+	TaskSortUpdatedAt TaskSortField = "updated_at"
+	TaskSortTitle     TaskSortField = "title"
+)
+
+// Valid reports whether f is a supported sort field.
+func (f TaskSortField) Valid() bool {
+	switch f {
+	case TaskSortCreatedAt, TaskSortUpdatedAt, TaskSortTitle:
+		return true
+	default:
+		return false
+	}
+}
+
+// TaskSort describes the order of a task list. Ties are broken by ID.
+type TaskSort struct {
+	Field TaskSortField
+	Desc  bool
+}
+
+// ParseTaskSort parses a sort expression such as "title" or "-updated_at".
+func ParseTaskSort(s string) (TaskSort, bool) {
+	field, desc := strings.CutPrefix(s, "-")
+	sort := TaskSort{Field: TaskSortField(field), Desc: desc}
+	return sort, sort.Field.Valid()
+}
+
+// String returns the sort expression accepted by ParseTaskSort.
+func (s TaskSort) String() string {
+	if s.Desc {
+		return "-" + string(s.Field)
+	}
+	return string(s.Field)
+}
+
+// TaskCursor marks a position in a sorted task list for keyset pagination.
+// A forward cursor selects the tasks after the position, a backward cursor
+// the tasks before it.
+type TaskCursor struct {
+	Sort TaskSort
+	// Value is the sort key of the task at the position: an RFC 3339
+	// timestamp for time fields, the title otherwise.
+	Value    string
+	ID       int64
+	Backward bool
+}
+
+// NewTaskCursor returns the cursor positioned at t for the given sort.
+func NewTaskCursor(t Task, sort TaskSort, backward bool) TaskCursor {
+	var value string
+	switch sort.Field {
+	case TaskSortUpdatedAt:
+		value = t.UpdatedAt.UTC().Format(time.RFC3339)
+	case TaskSortTitle:
+		value = t.Title
+	default:
+		value = t.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	return TaskCursor{Sort: sort, Value: value, ID: t.ID, Backward: backward}
+}
+
+const (
+	// DefaultTaskLimit is the page size used when none is requested.
+	DefaultTaskLimit = 50
+	// MaxTaskLimit is the largest page size a client may request.
+	MaxTaskLimit = 100
+	// DefaultOccurrenceLimit is the number of occurrences previewed when none
+	// is requested.
+	DefaultOccurrenceLimit = 10
+	// MaxOccurrenceLimit is the largest number of occurrences a client may
+	// preview.
+	MaxOccurrenceLimit = 100
+)
+
+// TaskFilter narrows, orders and paginates a task list.
+// Nil fields do not filter.
+type TaskFilter struct {
+	Done          *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	ProjectID     *int64
+	// Tags, when not empty, keeps the tasks carrying these distinct tag
+	// names, matched according to TagMode.
+	Tags    []string
+	TagMode TagMatchMode
+	Sort    TaskSort
+	Limit   int
+	Offset  int
+	// Cursor, when set, replaces Offset with a keyset seek. Its Sort must
+	// match the filter's.
+	Cursor *TaskCursor
+}
+
+// WithDefaults returns a copy of f with the sort, limit and tag mode filled in.
+func (f TaskFilter) WithDefaults() TaskFilter {
+	if f.TagMode == "" {
+		f.TagMode = TagMatchAll
+	}
+	if f.Sort.Field == "" {
+		f.Sort.Field = TaskSortCreatedAt
+	}
+	if f.Limit <= 0 {
+		f.Limit = DefaultTaskLimit
+	}
+	return f
+}
+
+// TaskPage is one page of a filtered task list.
+type TaskPage struct {
+	Tasks []Task
+	// Total is the number of tasks matching the filter, ignoring pagination.
+	Total  int
+	Limit  int
+	Offset int
+	// NextCursor and PrevCursor are nil when there is no page in that direction.
+	NextCursor *TaskCursor
+	PrevCursor *TaskCursor
+}
+
+// TaskSearchResult is a task matched by a full-text search.
+type TaskSearchResult struct {
+	Task Task
+	// Score is the relevance of the match; higher is better.
+	Score float64
+	// Snippet is an excerpt of the matching text with hits wrapped in <mark> tags.
+	Snippet string
 }
 
 type TaskRepository interface {
-	GetAll(ctx context.Context) ([]Task, error)
+	GetAll(ctx context.Context, filter TaskFilter) ([]Task, error)
+	Count(ctx context.Context, filter TaskFilter) (int, error)
+	Search(ctx context.Context, query string, filter TaskFilter) ([]TaskSearchResult, error)
+	GetByID(ctx context.Context, id int64) (Task, error)
+	Create(ctx context.Context, task Task) (Task, error)
+	// Update and Patch fail with ErrTaskVersionConflict when the task is no
+	// longer at the version they expect.
+	Update(ctx context.Context, task Task) (Task, error)
+	Patch(ctx context.Context, id int64, patch TaskPatch) (Task, error)
+	// Delete moves the task and its subtasks to the trash. A non-zero
+	// version must match the task's current version.
+	Delete(ctx context.Context, id, version int64) error
+	// Subtree returns the task with the given ID followed by all of its
+	// descendants, each level after the one above it.
+	Subtree(ctx context.Context, id int64) ([]Task, error)
+	// CompleteDescendants marks every open descendant of the task as done. It
+	// returns ErrTaskBlocked when one of them waits for a task outside them.
+	CompleteDescendants(ctx context.Context, id int64) error
+	// AddBlocker records that the task cannot be completed before blockerID is done.
+	AddBlocker(ctx context.Context, taskID, blockerID int64) error
+	// RemoveBlocker deletes the dependency of the task on blockerID.
+	RemoveBlocker(ctx context.Context, taskID, blockerID int64) error
+	// Blockers returns the tasks the task depends on, open or done.
+	Blockers(ctx context.Context, taskID int64) ([]Task, error)
+	// History returns a page of the task's change history, newest first.
+	// Tasks in the trash keep their history until they are purged.
+	History(ctx context.Context, taskID int64, limit, offset int) ([]TaskEvent, error)
+	// CountHistory returns the number of events in the task's history.
+	CountHistory(ctx context.Context, taskID int64) (int, error)
+	// SetTags replaces the tags of the task with the named ones, creating
+	// the tags that do not exist yet.
+	SetTags(ctx context.Context, id int64, names []string) error
 }
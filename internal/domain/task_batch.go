@@ -0,0 +1,63 @@
+package domain
+
+const (
+	// MaxBatchOperations is the maximum number of operations in one batch.
+	MaxBatchOperations = 100
+	// MaxImportRows is the maximum number of rows in one import.
+	MaxImportRows = 10000
+)
+
+// TaskOperationKind identifies what a batch operation does.
+type TaskOperationKind string
+
+const (
+	TaskOperationCreate TaskOperationKind = "create"
+	TaskOperationUpdate TaskOperationKind = "update"
+	TaskOperationPatch  TaskOperationKind = "patch"
+	TaskOperationDelete TaskOperationKind = "delete"
+)
+
+// Valid reports whether k is a known operation kind.
+func (k TaskOperationKind) Valid() bool {
+	switch k {
+	case TaskOperationCreate, TaskOperationUpdate, TaskOperationPatch, TaskOperationDelete:
+		return true
+	}
+	return false
+}
+
+// TaskOperation is one write of a batch. Task holds the task to create or
+// the replacement of an update, Patch the changes of a patch. ID and
+// Version name the task a patch or delete applies to; a zero Version skips
+// the version check. Tags, when not nil, replaces the tags of the written
+// task by name, creating the missing ones. ParentOp, when not nil, makes a
+// created task a subtask of the one written by the earlier operation at
+// that index, for parents that do not exist before the batch runs.
+// CreateMissing makes a patch of a task that does not exist create Task
+// instead, for imports that upsert.
+type TaskOperation struct {
+	Kind          TaskOperationKind
+	ID            int64
+	Version       int64
+	Task          Task
+	Patch         TaskPatch
+	Tags          []string
+	ParentOp      *int
+	CreateMissing bool
+}
+
+// TaskOperationResult is the outcome of one batch operation: the written
+// task, or the error that made it fail. Deletes leave Task empty. Created
+// reports that a patch with CreateMissing created its task.
+type TaskOperationResult struct {
+	Task    Task
+	Err     error
+	Created bool
+}
+
+// TaskBatchResult holds the results of a batch in operation order and
+// whether its changes were committed.
+type TaskBatchResult struct {
+	Results   []TaskOperationResult
+	Committed bool
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TaskEventAction is the kind of change a task event records.
+type TaskEventAction string
+
+const (
+	TaskEventCreated  TaskEventAction = "created"
+	TaskEventUpdated  TaskEventAction = "updated"
+	TaskEventDeleted  TaskEventAction = "deleted"
+	TaskEventRestored TaskEventAction = "restored"
+)
+
+// FieldChange is the value of a task field before and after a change. Values
+// are plain JSON values: strings, booleans, numbers, RFC 3339 timestamps or
+// nil.
+type FieldChange struct {
+	Old any
+	New any
+}
+
+// TaskEvent is an entry in the change history of a task.
+type TaskEvent struct {
+	ID     int64
+	TaskID int64
+	Action TaskEventAction
+	// Changes holds the fields that changed, keyed by their API name. A
+	// created event lists every field; deleted and restored events list none.
+	Changes map[string]FieldChange
+	// Actor identifies who made the change, or is empty when unknown.
+	Actor string
+	// RequestID is the ID of the HTTP request that made the change, or
+	// empty for changes made outside a request.
+	RequestID string
+	CreatedAt time.Time
+}
+
+// TaskEventPage is one page of a task's change history, newest first.
+type TaskEventPage struct {
+	Events []TaskEvent
+	// Total is the number of events in the task's history.
+	Total  int
+	Limit  int
+	Offset int
+}
+
+const (
+	// DefaultHistoryLimit is the number of events per page when none is requested.
+	DefaultHistoryLimit = 50
+	// MaxHistoryLimit is the largest page of events a client may request.
+	MaxHistoryLimit = 100
+)
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	requestIDKey
+	userKey
+	apiKeyKey
+	tenantKey
+)
+
+// WithActor returns a copy of ctx carrying the actor that task changes made
+// with it are attributed to.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFrom returns the actor carried by ctx, or an empty string.
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey).(string)
+	return actor
+}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request being served.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestIDFrom returns the request ID carried by ctx, or an empty string.
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+// Transactor runs units of work atomically. Repository calls made with the
+// context fn receives join the unit of work. A nested WithinTx runs as a
+// savepoint, so its failure only undoes its own changes.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
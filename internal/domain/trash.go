@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TrashedTask is a deleted task waiting in the trash to be restored or purged.
+type TrashedTask struct {
+	Task      Task
+	DeletedAt time.Time
+}
+
+type TrashRepository interface {
+	// List returns the tasks in the trash, most recently deleted first.
+	List(ctx context.Context) ([]TrashedTask, error)
+	// Restore takes the task, and the subtasks deleted along with it, out of
+	// the trash.
+	Restore(ctx context.Context, id int64) (Task, error)
+	// Purge permanently deletes a task in the trash and its subtasks.
+	Purge(ctx context.Context, id int64) error
+	// PurgeDeletedBefore permanently deletes the tasks that went into the
+	// trash before cutoff and reports how many there were.
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// User is an account that signs in with an email address and a password.
+// Only a hash of the password is stored.
+type User struct {
+	ID        int64
+	Email     string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Actor returns how the changes a user makes are attributed in task
+// histories.
+func (u User) Actor() string {
+	return "user:" + strconv.FormatInt(u.ID, 10)
+}
+
+// Session is a signed-in client of a user. The client holds a secret token;
+// only a hash of it is stored.
+type Session struct {
+	User      User
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// TokenPair is what a client signed in with bearer tokens holds: a
+// short-lived access token sent with every request, and a single-use
+// refresh token traded for the next pair.
+type TokenPair struct {
+	User             User
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// RefreshToken is a stored refresh token. Tokens issued by refreshing one
+// another share a family; only a hash of the token itself is stored.
+type RefreshToken struct {
+	ID        int64
+	User      User
+	Family    string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+type UserRepository interface {
+	// Create stores a user with the given password hash. An email already
+	// in use, ignoring case, fails with ErrUserAlreadyExists.
+	Create(ctx context.Context, user User, passwordHash string) (User, error)
+	// GetByEmail returns the user with the given email, ignoring case, and
+	// its password hash.
+	GetByEmail(ctx context.Context, email string) (User, string, error)
+}
+
+type SessionRepository interface {
+	// Create stores a session of session.User reached with the token that
+	// hashes to tokenHash.
+	Create(ctx context.Context, session Session, tokenHash string) error
+	// Get returns the session whose token hashes to tokenHash, unless it
+	// expired at or before now.
+	Get(ctx context.Context, tokenHash string, now time.Time) (Session, error)
+	Delete(ctx context.Context, tokenHash string) error
+	// DeleteExpired removes the sessions that expired at or before now and
+	// reports how many there were.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type RefreshTokenRepository interface {
+	// Create stores a refresh token of token.User that hashes to tokenHash.
+	Create(ctx context.Context, token RefreshToken, tokenHash string) error
+	// Get returns the refresh token that hashes to tokenHash with its user,
+	// whether or not it was used or expired.
+	Get(ctx context.Context, tokenHash string) (RefreshToken, error)
+	// MarkUsed records that the token with the given ID was refreshed at
+	// now. A token already used fails with ErrRefreshTokenReused.
+	MarkUsed(ctx context.Context, id int64, now time.Time) error
+	// DeleteFamily removes every token of a family.
+	DeleteFamily(ctx context.Context, family string) error
+	// DeleteExpired removes the tokens that expired at or before now and
+	// reports how many there were.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// WithUser returns a copy of ctx carrying the signed-in user of a request.
+func WithUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// UserFrom returns the signed-in user carried by ctx, if any.
+func UserFrom(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(userKey).(User)
+	return user, ok
+}
+
+// WithTenant returns a copy of ctx whose task storage is scoped to the tasks
+// of the user with the given ID. Other users' tasks are out of its reach, as
+// if they did not exist.
+func WithTenant(ctx context.Context, ownerID int64) context.Context {
+	return context.WithValue(ctx, tenantKey, ownerID)
+}
+
+// WithoutTenant returns a copy of ctx scoped to the tasks without an owner,
+// like an anonymous caller, whatever tenant ctx carried before.
+func WithoutTenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tenantKey, nil)
+}
+
+// TenantFrom returns the ID of the user whose tasks ctx is scoped to. It
+// returns false for anonymous callers, who share the tasks without an owner.
+func TenantFrom(ctx context.Context) (int64, bool) {
+	ownerID, ok := ctx.Value(tenantKey).(int64)
+	return ownerID, ok
+}
@@ -0,0 +1,182 @@
+// Package ical writes tasks as an RFC 5545 iCalendar stream of VTODO
+// components.
+//
+// Lines are folded at 75 octets without splitting UTF-8 sequences, text
+// values are escaped, and every timestamp is written in UTC. A task's UID is
+// derived from its ID alone, so it stays the same across edits and feeds.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+// ProdID identifies the product that created the calendar.
+const ProdID = "-//tasks-go//Tasks//EN"
+
+// maxLineOctets is the longest a content line may be, excluding its CRLF.
+const maxLineOctets = 75
+
+const timeLayout = "20060102T150405Z"
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// Writer writes a VCALENDAR holding one VTODO per task.
+type Writer struct {
+	w         *bufio.Writer
+	uidDomain string
+}
+
+// NewWriter creates a Writer on w whose UIDs end in @uidDomain.
+func NewWriter(w io.Writer, uidDomain string) *Writer {
+	return &Writer{w: bufio.NewWriter(w), uidDomain: uidDomain}
+}
+
+// UID returns the UID of the task with the given ID.
+func UID(id int64, uidDomain string) string {
+	return "task-" + strconv.FormatInt(id, 10) + "@" + uidDomain
+}
+
+// Begin opens the calendar, naming it name in clients that support it.
+func (w *Writer) Begin(name string) error {
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", ProdID)
+	w.line("CALSCALE", "GREGORIAN")
+	if name != "" {
+		w.line("X-WR-CALNAME", escapeText(name))
+	}
+	return w.err()
+}
+
+// Write writes the VTODO of a task. DTSTAMP is the time the task last
+// changed, as RFC 5545 asks of objects published without a METHOD, and
+// SEQUENCE counts its revisions.
+func (w *Writer) Write(t domain.Task) error {
+	w.line("BEGIN", "VTODO")
+	w.line("UID", UID(t.ID, w.uidDomain))
+	w.line("DTSTAMP", formatTime(t.UpdatedAt))
+	w.line("CREATED", formatTime(t.CreatedAt))
+	w.line("LAST-MODIFIED", formatTime(t.UpdatedAt))
+	if t.Version > 1 {
+		w.line("SEQUENCE", strconv.FormatInt(t.Version-1, 10))
+	}
+	w.line("SUMMARY", escapeText(t.Title))
+	if t.Description != "" {
+		w.line("DESCRIPTION", escapeText(t.Description))
+	}
+	if t.StartAt != nil {
+		w.line("DTSTART", formatTime(*t.StartAt))
+	}
+	if t.DueAt != nil {
+		w.line("DUE", formatTime(*t.DueAt))
+	}
+	if t.Done {
+		w.line("STATUS", "COMPLETED")
+		w.line("PERCENT-COMPLETE", "100")
+		if t.CompletedAt != nil {
+			w.line("COMPLETED", formatTime(*t.CompletedAt))
+		}
+	} else {
+		w.line("STATUS", "NEEDS-ACTION")
+	}
+	if p := priority(t.Priority); p > 0 {
+		w.line("PRIORITY", strconv.Itoa(p))
+	}
+	if len(t.Tags) > 0 {
+		names := make([]string, len(t.Tags))
+		for i, tag := range t.Tags {
+			names[i] = escapeText(tag.Name)
+		}
+		w.line("CATEGORIES", strings.Join(names, ","))
+	}
+	// Each occurrence is stored as a task of its own, spawned when the one
+	// before it is completed. Only the open head of a series carries the
+	// rule, so clients do not expand the done ones into duplicates, and only
+	// with the DTSTART the rule is anchored on.
+	if t.Recurrence != "" && !t.Done && t.StartAt != nil {
+		w.line("RRULE", t.Recurrence)
+	}
+	if t.ParentID != nil {
+		w.line("RELATED-TO", UID(*t.ParentID, w.uidDomain))
+	}
+	w.line("END", "VTODO")
+	return w.err()
+}
+
+// End closes the calendar and flushes everything written.
+func (w *Writer) End() error {
+	w.line("END", "VCALENDAR")
+	return w.w.Flush()
+}
+
+// line writes a content line, folding it so no physical line is longer than
+// maxLineOctets. Continuation lines start with a space, which counts toward
+// their length.
+func (w *Writer) line(name, value string) {
+	l := name + ":" + value
+	limit := maxLineOctets
+	for len(l) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(l[cut]) {
+			cut--
+		}
+		w.w.WriteString(l[:cut])
+		w.w.WriteString("\r\n ")
+		l = l[cut:]
+		limit = maxLineOctets - 1
+	}
+	w.w.WriteString(l)
+	w.w.WriteString("\r\n")
+}
+
+// err reports the first error met writing to the underlying writer.
+func (w *Writer) err() error {
+	// A bufio.Writer keeps its first error and returns it from every later
+	// call; an empty write surfaces it without side effects.
+	_, err := w.w.Write(nil)
+	return err
+}
+
+// escapeText escapes a TEXT value. Line breaks become \n and other control
+// characters, which TEXT may not carry, are dropped.
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s)
+	return textEscaper.Replace(s)
+}
+
+// priority maps a task priority to the iCalendar scale, where 1 is the
+// highest, 9 the lowest and 0 undefined.
+func priority(p domain.Priority) int {
+	switch p {
+	case domain.PriorityUrgent:
+		return 1
+	case domain.PriorityHigh:
+		return 3
+	case domain.PriorityMedium:
+		return 5
+	case domain.PriorityLow:
+		return 7
+	default:
+		return 0
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
@@ -0,0 +1,194 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+func TestWriter(t *testing.T) {
+	created := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	updated := time.Date(2026, 10, 19, 8, 15, 0, 0, time.UTC)
+	due := time.Date(2026, 10, 20, 17, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	parentID := int64(1)
+
+	var b strings.Builder
+	w := NewWriter(&b, "tasks.example.com")
+	if err := w.Begin("Work, mostly"); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	tasks := []domain.Task{
+		{
+			ID: 2, Title: "Plan; then ship", Description: "two\r\nlines \\ done", Priority: domain.PriorityHigh,
+			StartAt: &created, DueAt: &due, ParentID: &parentID, Recurrence: "FREQ=WEEKLY",
+			Tags:    []domain.Tag{{Name: "backend"}, {Name: "a,b"}},
+			Version: 3, CreatedAt: created, UpdatedAt: updated,
+		},
+		{ID: 3, Title: "Done", Done: true, CompletedAt: &updated, DueAt: &due, Recurrence: "FREQ=WEEKLY", Version: 1, CreatedAt: created, UpdatedAt: updated},
+	}
+	for _, task := range tasks {
+		if err := w.Write(task); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	}
+	if err := w.End(); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//tasks-go//Tasks//EN",
+		"CALSCALE:GREGORIAN",
+		`X-WR-CALNAME:Work\, mostly`,
+		"BEGIN:VTODO",
+		"UID:task-2@tasks.example.com",
+		"DTSTAMP:20261019T081500Z",
+		"CREATED:20261018T090000Z",
+		"LAST-MODIFIED:20261019T081500Z",
+		"SEQUENCE:2",
+		`SUMMARY:Plan\; then ship`,
+		`DESCRIPTION:two\nlines \\ done`,
+		"DTSTART:20261018T090000Z",
+		"DUE:20261020T153000Z",
+		"STATUS:NEEDS-ACTION",
+		"PRIORITY:3",
+		`CATEGORIES:backend,a\,b`,
+		"RRULE:FREQ=WEEKLY",
+		"RELATED-TO:task-1@tasks.example.com",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:task-3@tasks.example.com",
+		"DTSTAMP:20261019T081500Z",
+		"CREATED:20261018T090000Z",
+		"LAST-MODIFIED:20261019T081500Z",
+		"SUMMARY:Done",
+		"DUE:20261020T153000Z",
+		"STATUS:COMPLETED",
+		"PERCENT-COMPLETE:100",
+		"COMPLETED:20261019T081500Z",
+		"END:VTODO",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if b.String() != expected {
+		t.Fatalf("expected\n%q\nbut got\n%q", expected, b.String())
+	}
+}
+
+func TestWriter_Recurrence(t *testing.T) {
+	start := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		task       domain.Task
+		expectRule bool
+	}{
+		{name: "should write the rule of the open head of a series", task: domain.Task{StartAt: &start, Recurrence: "FREQ=DAILY"}, expectRule: true},
+		{name: "should leave out the rule of a done occurrence", task: domain.Task{StartAt: &start, Recurrence: "FREQ=DAILY", Done: true}},
+		{name: "should leave out a rule without DTSTART", task: domain.Task{Recurrence: "FREQ=DAILY"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b strings.Builder
+			w := NewWriter(&b, "example.com")
+			_ = w.Write(tc.task)
+			if err := w.End(); err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if got := strings.Contains(b.String(), "\r\nRRULE:FREQ=DAILY\r\n"); got != tc.expectRule {
+				t.Fatalf("expected RRULE %v, got %q", tc.expectRule, b.String())
+			}
+		})
+	}
+}
+
+func TestWriter_Folding(t *testing.T) {
+	testCases := []struct {
+		name     string
+		title    string
+		expected string
+	}{
+		{
+			name:     "should not fold a line of 75 octets",
+			title:    strings.Repeat("a", 67),
+			expected: "SUMMARY:" + strings.Repeat("a", 67) + "\r\n",
+		},
+		{
+			name:  "should fold long lines at 75 octets",
+			title: strings.Repeat("a", 150),
+			expected: "SUMMARY:" + strings.Repeat("a", 67) + "\r\n" +
+				" " + strings.Repeat("a", 74) + "\r\n" +
+				" " + strings.Repeat("a", 9) + "\r\n",
+		},
+		{
+			name:  "should not split multi-byte characters",
+			title: strings.Repeat("a", 66) + "é" + "b",
+			expected: "SUMMARY:" + strings.Repeat("a", 66) + "\r\n" +
+				" éb\r\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b strings.Builder
+			w := NewWriter(&b, "example.com")
+			_ = w.Write(domain.Task{ID: 1, Title: tc.title})
+			if err := w.End(); err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+
+			var summary string
+			for _, line := range strings.SplitAfter(b.String(), "\r\n") {
+				if strings.HasPrefix(line, "SUMMARY:") || (summary != "" && strings.HasPrefix(line, " ")) {
+					summary += line
+				}
+			}
+			if summary != tc.expected {
+				t.Fatalf("expected %q but got %q", tc.expected, summary)
+			}
+			for _, line := range strings.Split(b.String(), "\r\n") {
+				if len(line) > maxLineOctets {
+					t.Fatalf("line longer than %d octets: %q", maxLineOctets, line)
+				}
+			}
+		})
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "plain", expected: "plain"},
+		{input: `a\b;c,d`, expected: `a\\b\;c\,d`},
+		{input: "one\ntwo\r\nthree", expected: `one\ntwo\nthree`},
+		{input: "tab\tbell\a", expected: "tab\tbell"},
+	}
+
+	for _, tc := range testCases {
+		if got := escapeText(tc.input); got != tc.expected {
+			t.Errorf("escapeText(%q): expected %q but got %q", tc.input, tc.expected, got)
+		}
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWriter_Error(t *testing.T) {
+	w := NewWriter(failingWriter{}, "example.com")
+	var err error
+	for i := 0; err == nil && i < 1000; i++ {
+		err = w.Write(domain.Task{ID: int64(i + 1), Title: "task"})
+	}
+	if err == nil || err.Error() != "disk full" {
+		t.Fatalf("expected the write error but got %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+// Package jwt signs and verifies JSON Web Tokens (RFC 7519) in compact
+// form. Tokens are signed with HMAC-SHA256 (HS256) or Ed25519 (EdDSA) keys,
+// and carry the ID of their key so that keys can be rotated.
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Signing algorithms, as named in the "alg" header.
+const (
+	HS256 = "HS256"
+	EdDSA = "EdDSA"
+)
+
+// MinHS256SecretLength is the minimum number of bytes in an HS256 secret:
+// as many as the hash it keys.
+const MinHS256SecretLength = sha256.Size
+
+var (
+	// ErrInvalidKey indicates a key that cannot sign or verify tokens.
+	ErrInvalidKey = errors.New("jwt: invalid key")
+	// ErrMalformed indicates a token that is not a well-formed JWT.
+	ErrMalformed = errors.New("jwt: malformed token")
+	// ErrUnknownKey indicates a token signed with a key that is not in the
+	// keyset, or with another algorithm than its key's.
+	ErrUnknownKey = errors.New("jwt: unknown key")
+	// ErrSignature indicates a token whose signature does not match.
+	ErrSignature = errors.New("jwt: invalid signature")
+	// ErrExpired indicates a token past its expiry.
+	ErrExpired = errors.New("jwt: token expired")
+	// ErrNotYetValid indicates a token used before it becomes valid.
+	ErrNotYetValid = errors.New("jwt: token not valid yet")
+)
+
+// Claims are the registered claims a token is checked against. Embed it in
+// a struct to carry more claims.
+type Claims struct {
+	Issuer    string `json:"iss,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	ID        string `json:"jti,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	NotBefore int64  `json:"nbf,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// header is the JOSE header of a token.
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+	Kid string `json:"kid"`
+}
+
+// Key is a named signing key.
+type Key struct {
+	ID      string
+	alg     string
+	secret  []byte
+	private ed25519.PrivateKey
+	public  ed25519.PublicKey
+}
+
+// NewHS256Key creates an HS256 key from a secret of at least
+// MinHS256SecretLength bytes.
+func NewHS256Key(id string, secret []byte) (Key, error) {
+	if id == "" || len(secret) < MinHS256SecretLength {
+		return Key{}, fmt.Errorf("%w: HS256 keys need an ID and at least %d bytes of secret", ErrInvalidKey, MinHS256SecretLength)
+	}
+	return Key{ID: id, alg: HS256, secret: secret}, nil
+}
+
+// NewEdDSAKey creates an EdDSA key from a 32-byte Ed25519 seed.
+func NewEdDSAKey(id string, seed []byte) (Key, error) {
+	if id == "" || len(seed) != ed25519.SeedSize {
+		return Key{}, fmt.Errorf("%w: EdDSA keys need an ID and a %d-byte seed", ErrInvalidKey, ed25519.SeedSize)
+	}
+	private := ed25519.NewKeyFromSeed(seed)
+	return Key{ID: id, alg: EdDSA, private: private, public: private.Public().(ed25519.PublicKey)}, nil
+}
+
+// ParseKey creates a key of the algorithm alg from a "kid:secret" spec.
+// HS256 secrets are taken as they are; EdDSA secrets are base64-encoded
+// seeds.
+func ParseKey(alg, spec string) (Key, error) {
+	id, secret, ok := strings.Cut(spec, ":")
+	if !ok {
+		return Key{}, fmt.Errorf("%w: expected kid:secret", ErrInvalidKey)
+	}
+
+	switch alg {
+	case HS256:
+		return NewHS256Key(id, []byte(secret))
+	case EdDSA:
+		seed, err := base64.StdEncoding.DecodeString(secret)
+		if err != nil {
+			return Key{}, fmt.Errorf("%w: decoding seed of %q: %w", ErrInvalidKey, id, err)
+		}
+		return NewEdDSAKey(id, seed)
+	default:
+		return Key{}, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidKey, alg)
+	}
+}
+
+// Algorithm returns the algorithm the key signs with.
+func (k Key) Algorithm() string {
+	return k.alg
+}
+
+func (k Key) sign(input []byte) []byte {
+	if k.alg == EdDSA {
+		return ed25519.Sign(k.private, input)
+	}
+	mac := hmac.New(sha256.New, k.secret)
+	mac.Write(input)
+	return mac.Sum(nil)
+}
+
+func (k Key) verify(input, sig []byte) bool {
+	if k.alg == EdDSA {
+		return ed25519.Verify(k.public, input, sig)
+	}
+	return hmac.Equal(sig, k.sign(input))
+}
+
+// Keyset signs tokens with its current key and verifies them with any of
+// its keys, so that tokens signed before a rotation stay valid until they
+// expire.
+type Keyset struct {
+	current Key
+	keys    map[string]Key
+	leeway  time.Duration
+}
+
+// NewKeyset creates a Keyset that signs with the first of keys. Expiry and
+// not-before times are checked with leeway to allow for clock skew between
+// machines.
+func NewKeyset(leeway time.Duration, keys ...Key) (*Keyset, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: a keyset needs at least one key", ErrInvalidKey)
+	}
+
+	ks := &Keyset{current: keys[0], keys: make(map[string]Key, len(keys)), leeway: leeway}
+	for _, k := range keys {
+		if k.alg == "" {
+			return nil, fmt.Errorf("%w: key %q was not created with a constructor", ErrInvalidKey, k.ID)
+		}
+		if _, ok := ks.keys[k.ID]; ok {
+			return nil, fmt.Errorf("%w: duplicate key ID %q", ErrInvalidKey, k.ID)
+		}
+		ks.keys[k.ID] = k
+	}
+	return ks, nil
+}
+
+// Sign returns the signed token for claims, which is marshaled to JSON and
+// should embed Claims.
+func (ks *Keyset) Sign(claims any) (string, error) {
+	h, err := json.Marshal(header{Alg: ks.current.alg, Typ: "JWT", Kid: ks.current.ID})
+	if err != nil {
+		return "", fmt.Errorf("Keyset.Sign: marshaling header: %w", err)
+	}
+	body, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("Keyset.Sign: marshaling claims: %w", err)
+	}
+
+	enc := base64.RawURLEncoding
+	input := enc.EncodeToString(h) + "." + enc.EncodeToString(body)
+	return input + "." + enc.EncodeToString(ks.current.sign([]byte(input))), nil
+}
+
+// Parse verifies token at time now and unmarshals its claims into dst,
+// which should embed Claims. The token must name a key of the keyset, use
+// that key's algorithm, have an expiry and be valid at now, give or take
+// the leeway.
+func (ks *Keyset) Parse(token string, now time.Time, dst any) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ErrMalformed
+	}
+	enc := base64.RawURLEncoding
+
+	rawHeader, err := enc.DecodeString(parts[0])
+	if err != nil {
+		return ErrMalformed
+	}
+	var h header
+	if err := json.Unmarshal(rawHeader, &h); err != nil {
+		return ErrMalformed
+	}
+	// The algorithm is fixed by the key, never taken from the token, so
+	// that "none" or an HMAC keyed with a public key cannot get through.
+	key, ok := ks.keys[h.Kid]
+	if !ok || h.Alg != key.alg {
+		return ErrUnknownKey
+	}
+
+	sig, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return ErrMalformed
+	}
+	if !key.verify([]byte(parts[0]+"."+parts[1]), sig) {
+		return ErrSignature
+	}
+
+	body, err := enc.DecodeString(parts[1])
+	if err != nil {
+		return ErrMalformed
+	}
+	var claims Claims
+	if err := json.Unmarshal(body, &claims); err != nil || claims.ExpiresAt == 0 {
+		return ErrMalformed
+	}
+	if !now.Before(time.Unix(claims.ExpiresAt, 0).Add(ks.leeway)) {
+		return ErrExpired
+	}
+	if claims.NotBefore != 0 && now.Add(ks.leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return ErrNotYetValid
+	}
+
+	if err := json.Unmarshal(body, dst); err != nil {
+		return ErrMalformed
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testClaims struct {
+	Claims
+	Email string `json:"email"`
+}
+
+var testNow = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+func mustKey(t *testing.T, alg, spec string) Key {
+	t.Helper()
+	k, err := ParseKey(alg, spec)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	return k
+}
+
+func mustKeyset(t *testing.T, keys ...Key) *Keyset {
+	t.Helper()
+	ks, err := NewKeyset(time.Minute, keys...)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	return ks
+}
+
+func hsKey(t *testing.T, id string) Key {
+	return mustKey(t, HS256, id+":"+strings.Repeat(id, 32))
+}
+
+func edKey(t *testing.T, id string) Key {
+	return mustKey(t, EdDSA, id+":"+base64.StdEncoding.EncodeToString(bytes.Repeat([]byte(id[:1]), 32)))
+}
+
+func TestKeyset_RoundTrip(t *testing.T) {
+	for _, key := range []Key{hsKey(t, "a"), edKey(t, "b")} {
+		t.Run(key.Algorithm(), func(t *testing.T) {
+			ks := mustKeyset(t, key)
+			claims := testClaims{
+				Claims: Claims{Issuer: "tasks", Subject: "7", IssuedAt: testNow.Unix(), ExpiresAt: testNow.Add(time.Hour).Unix()},
+				Email:  "ada@example.com",
+			}
+
+			token, err := ks.Sign(claims)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			header, _ := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+			if !strings.Contains(string(header), `"kid":"`+key.ID+`"`) {
+				t.Errorf("expected the header to name the key, got %s", header)
+			}
+
+			var got testClaims
+			if err := ks.Parse(token, testNow, &got); err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if got != claims {
+				t.Errorf("expected claims %+v, got %+v", claims, got)
+			}
+		})
+	}
+}
+
+func TestKeyset_Parse_Invalid(t *testing.T) {
+	ks := mustKeyset(t, hsKey(t, "a"))
+	valid, _ := ks.Sign(Claims{ExpiresAt: testNow.Add(time.Hour).Unix(), NotBefore: testNow.Add(-time.Hour).Unix()})
+	parts := strings.Split(valid, ".")
+	enc := base64.RawURLEncoding
+	sign := func(ks *Keyset, claims any) string {
+		token, err := ks.Sign(claims)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		return token
+	}
+	// none is an unsigned token claiming to need no signature.
+	none := enc.EncodeToString([]byte(`{"alg":"none","kid":"a"}`)) + "." + parts[1] + "."
+
+	testCases := []struct {
+		name        string
+		token       string
+		now         time.Time
+		expectedErr error
+	}{
+		{name: "should reject garbage", token: "not a token", expectedErr: ErrMalformed},
+		{name: "should reject a tampered payload", token: parts[0] + "." + enc.EncodeToString([]byte(`{"exp":9999999999}`)) + "." + parts[2], expectedErr: ErrSignature},
+		{name: "should reject an unsigned token", token: none, expectedErr: ErrUnknownKey},
+		{name: "should reject an unknown key", token: sign(mustKeyset(t, hsKey(t, "z")), Claims{ExpiresAt: testNow.Add(time.Hour).Unix()}), expectedErr: ErrUnknownKey},
+		{name: "should reject the same kid with another secret", token: sign(mustKeyset(t, mustKey(t, HS256, "a:"+strings.Repeat("b", 32))), Claims{ExpiresAt: testNow.Add(time.Hour).Unix()}), expectedErr: ErrSignature},
+		{name: "should reject a token without expiry", token: sign(ks, Claims{Subject: "7"}), expectedErr: ErrMalformed},
+		{name: "should reject an expired token", token: valid, now: testNow.Add(time.Hour + time.Minute), expectedErr: ErrExpired},
+		{name: "should accept an expired token within the leeway", token: valid, now: testNow.Add(time.Hour + 59*time.Second)},
+		{name: "should reject a token used too early", token: valid, now: testNow.Add(-time.Hour - 2*time.Minute), expectedErr: ErrNotYetValid},
+		{name: "should accept an early token within the leeway", token: valid, now: testNow.Add(-time.Hour - 30*time.Second)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := tc.now
+			if now.IsZero() {
+				now = testNow
+			}
+			var claims Claims
+			if err := ks.Parse(tc.token, now, &claims); !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestKeyset_Rotation(t *testing.T) {
+	old := mustKeyset(t, hsKey(t, "a"))
+	token, _ := old.Sign(Claims{ExpiresAt: testNow.Add(time.Hour).Unix()})
+
+	rotated := mustKeyset(t, edKey(t, "b"), hsKey(t, "a"))
+	var claims Claims
+	if err := rotated.Parse(token, testNow, &claims); err != nil {
+		t.Fatalf("expected a token of a retired key to verify, got %v", err)
+	}
+
+	fresh, _ := rotated.Sign(Claims{ExpiresAt: testNow.Add(time.Hour).Unix()})
+	if err := old.Parse(fresh, testNow, &claims); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected error %v but got %v", ErrUnknownKey, err)
+	}
+	if err := rotated.Parse(fresh, testNow, &claims); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+}
+
+func TestParseKey_Invalid(t *testing.T) {
+	for _, tc := range []struct{ alg, spec string }{
+		{HS256, "no-separator"},
+		{HS256, "a:short"},
+		{HS256, ":" + strings.Repeat("x", 32)},
+		{EdDSA, "a:not base64"},
+		{EdDSA, "a:" + base64.StdEncoding.EncodeToString([]byte("short"))},
+		{"RS256", "a:" + strings.Repeat("x", 32)},
+	} {
+		if _, err := ParseKey(tc.alg, tc.spec); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected error %v for %s %q but got %v", ErrInvalidKey, tc.alg, tc.spec, err)
+		}
+	}
+
+	if _, err := NewKeyset(0, hsKey(t, "a"), hsKey(t, "a")); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected duplicate key IDs to fail with %v but got %v", ErrInvalidKey, err)
+	}
+}
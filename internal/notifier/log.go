@@ -0,0 +1,32 @@
+// Package notifier provides the domain.Notifier implementations that
+// deliver task reminders.
+package notifier
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+// Log writes each reminder to a logger. It is the default notifier when no
+// delivery channel is configured.
+type Log struct {
+	logger *slog.Logger
+}
+
+// NewLog creates a Log notifier writing to logger.
+func NewLog(logger *slog.Logger) *Log {
+	return &Log{logger: logger}
+}
+
+// Notify logs the reminder at info level.
+func (n *Log) Notify(ctx context.Context, reminder domain.Reminder) error {
+	n.logger.InfoContext(ctx, "task reminder",
+		slog.Int64("task_id", reminder.TaskID),
+		slog.String("title", reminder.TaskTitle),
+		slog.String("due_at", reminder.DueAt.UTC().Format(time.RFC3339)),
+	)
+	return nil
+}
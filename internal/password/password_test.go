@@ -0,0 +1,59 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testParams keep the tests fast; they are far too cheap for real use.
+var testParams = Params{Memory: 64, Time: 1, Threads: 1}
+
+func TestHash(t *testing.T) {
+	first, err := Hash("correct horse", testParams)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	second, _ := Hash("correct horse", testParams)
+
+	if !strings.HasPrefix(first, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("unexpected encoding %q", first)
+	}
+	if first == second {
+		t.Error("expected every hash to use a fresh salt")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	hash, err := Hash("correct horse", testParams)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	testCases := []struct {
+		name        string
+		password    string
+		encoded     string
+		expected    bool
+		expectedErr error
+	}{
+		{name: "should accept the right password", password: "correct horse", encoded: hash, expected: true},
+		{name: "should reject a wrong password", password: "correct horse!", encoded: hash},
+		{name: "should reject another algorithm", password: "x", encoded: "$2a$10$abcdefghijklmnopqrstuv", expectedErr: ErrMalformedHash},
+		{name: "should reject another version", password: "x", encoded: strings.Replace(hash, "v=19", "v=16", 1), expectedErr: ErrMalformedHash},
+		{name: "should reject bad parameters", password: "x", encoded: strings.Replace(hash, "t=1", "t=0", 1), expectedErr: ErrMalformedHash},
+		{name: "should reject a truncated hash", password: "x", encoded: hash[:strings.LastIndex(hash, "$")], expectedErr: ErrMalformedHash},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ok, err := Verify(tc.password, tc.encoded)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+			if ok != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,412 @@
+// Package recurrence parses the subset of RFC 5545 recurrence rules (RRULE)
+// supported for tasks and generates their occurrences.
+//
+// The supported parts are FREQ (DAILY, WEEKLY, MONTHLY or YEARLY), INTERVAL,
+// BYDAY, BYMONTHDAY, COUNT and UNTIL. Weeks start on Monday.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRule is returned for a rule that is malformed or uses an
+// unsupported part.
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// Frequency is the RRULE FREQ part.
+type Frequency int
+
+const (
+	Daily Frequency = iota + 1
+	Weekly
+	Monthly
+	Yearly
+)
+
+var frequencyNames = map[Frequency]string{
+	Daily:   "DAILY",
+	Weekly:  "WEEKLY",
+	Monthly: "MONTHLY",
+	Yearly:  "YEARLY",
+}
+
+func (f Frequency) String() string {
+	return frequencyNames[f]
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// WeekdayNum is a BYDAY entry. N selects the Nth such weekday of the month,
+// counting from the end when negative; zero selects every one of them.
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+func (w WeekdayNum) String() string {
+	if w.N == 0 {
+		return weekdayNames[w.Day]
+	}
+	return strconv.Itoa(w.N) + weekdayNames[w.Day]
+}
+
+// Rule is a parsed recurrence rule. A zero Count and a nil Until mean the
+// rule repeats forever.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	Count      int
+	Until      *time.Time
+}
+
+// untilLayout is the UTC DATE-TIME form used for UNTIL.
+const untilLayout = "20060102T150405Z"
+
+// maxEmptyPeriods bounds the search for the next occurrence, so a rule that
+// can never match again (such as BYMONTHDAY=31 every 12 months from a
+// February) ends instead of looping forever.
+const maxEmptyPeriods = 1000
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR".
+// A leading "RRULE:" is accepted.
+func Parse(s string) (Rule, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= len("RRULE:") && strings.EqualFold(s[:len("RRULE:")], "RRULE:") {
+		s = s[len("RRULE:"):]
+	}
+	if s == "" {
+		return Rule{}, fmt.Errorf("%w: rule is empty", ErrInvalidRule)
+	}
+
+	r := Rule{Interval: 1}
+	seen := make(map[string]bool)
+	for part := range strings.SplitSeq(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(name)
+		if !ok || value == "" {
+			return Rule{}, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		if seen[name] {
+			return Rule{}, fmt.Errorf("%w: %s appears more than once", ErrInvalidRule, name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			r.Freq, err = parseFreq(value)
+		case "INTERVAL":
+			r.Interval, err = parsePositive(name, value)
+		case "COUNT":
+			r.Count, err = parsePositive(name, value)
+		case "UNTIL":
+			r.Until, err = parseUntil(value)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseByMonthDay(value)
+		default:
+			err = fmt.Errorf("%w: unsupported part %s", ErrInvalidRule, name)
+		}
+		if err != nil {
+			return Rule{}, err
+		}
+	}
+
+	if err := r.check(); err != nil {
+		return Rule{}, err
+	}
+	return r, nil
+}
+
+// check reports combinations of parts that Parse accepts individually but
+// that are invalid together.
+func (r Rule) check() error {
+	switch {
+	case r.Freq == 0:
+		return fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	case r.Count > 0 && r.Until != nil:
+		return fmt.Errorf("%w: COUNT and UNTIL cannot be combined", ErrInvalidRule)
+	case r.Freq == Yearly && (len(r.ByDay) > 0 || len(r.ByMonthDay) > 0):
+		return fmt.Errorf("%w: BYDAY and BYMONTHDAY are not supported with FREQ=YEARLY", ErrInvalidRule)
+	case r.Freq == Weekly && len(r.ByMonthDay) > 0:
+		return fmt.Errorf("%w: BYMONTHDAY cannot be used with FREQ=WEEKLY", ErrInvalidRule)
+	}
+	if r.Freq != Monthly {
+		for _, d := range r.ByDay {
+			if d.N != 0 {
+				return fmt.Errorf("%w: numbered BYDAY values require FREQ=MONTHLY", ErrInvalidRule)
+			}
+		}
+	}
+	return nil
+}
+
+func parseFreq(value string) (Frequency, error) {
+	for f, name := range frequencyNames {
+		if strings.EqualFold(value, name) {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unsupported FREQ %q", ErrInvalidRule, value)
+}
+
+func parsePositive(name, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%w: %s must be a positive integer", ErrInvalidRule, name)
+	}
+	return n, nil
+}
+
+// parseUntil accepts a DATE, a UTC DATE-TIME or a floating DATE-TIME, which
+// is taken as UTC. A DATE includes the whole day.
+func parseUntil(value string) (*time.Time, error) {
+	for _, layout := range []string{untilLayout, "20060102T150405"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		end := t.Add(24*time.Hour - time.Second)
+		return &end, nil
+	}
+	return nil, fmt.Errorf("%w: UNTIL must be a date or a date-time such as 20261231T235959Z", ErrInvalidRule)
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for item := range strings.SplitSeq(value, ",") {
+		item = strings.ToUpper(item)
+		if len(item) < 2 {
+			return nil, fmt.Errorf("%w: invalid BYDAY value %q", ErrInvalidRule, item)
+		}
+		day := slices.Index(weekdayNames[:], item[len(item)-2:])
+		if day < 0 {
+			return nil, fmt.Errorf("%w: invalid BYDAY value %q", ErrInvalidRule, item)
+		}
+		w := WeekdayNum{Day: time.Weekday(day)}
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("%w: invalid BYDAY value %q", ErrInvalidRule, item)
+			}
+			w.N = n
+		}
+		if !slices.Contains(days, w) {
+			days = append(days, w)
+		}
+	}
+	return days, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	var days []int
+	for item := range strings.SplitSeq(value, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil || n == 0 || n < -31 || n > 31 {
+			return nil, fmt.Errorf("%w: BYMONTHDAY values must be between 1 and 31 or -31 and -1", ErrInvalidRule)
+		}
+		if !slices.Contains(days, n) {
+			days = append(days, n)
+		}
+	}
+	return days, nil
+}
+
+// String returns the rule in canonical RRULE form, without the "RRULE:"
+// prefix. Parsing the result yields an equal rule.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq.String()}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = d.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences returns up to n occurrences of the rule, in order, for a
+// series starting at start. As in RFC 5545, start is always the first
+// occurrence and counts towards COUNT; later occurrences keep its time of
+// day and location.
+func (r Rule) Occurrences(start time.Time, n int) []time.Time {
+	if n <= 0 || (r.Until != nil && start.After(*r.Until)) {
+		return nil
+	}
+	if r.Count > 0 && r.Count < n {
+		n = r.Count
+	}
+
+	out := []time.Time{start}
+	for period, empty := 0, 0; len(out) < n && empty < maxEmptyPeriods; period++ {
+		found := false
+		for _, t := range r.expand(start, period) {
+			if !t.After(start) {
+				continue
+			}
+			if r.Until != nil && t.After(*r.Until) {
+				return out
+			}
+			found = true
+			out = append(out, t)
+			if len(out) == n {
+				return out
+			}
+		}
+		if found {
+			empty = 0
+		} else {
+			empty++
+		}
+	}
+	return out
+}
+
+// expand returns the candidate occurrences of the given period after start,
+// in order. Period zero is the day, week, month or year containing start.
+func (r Rule) expand(start time.Time, period int) []time.Time {
+	step := period * r.Interval
+	y, m, d := start.Date()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+	}
+
+	switch r.Freq {
+	case Daily:
+		day := at(y, m, d+step)
+		if r.matchesDay(day) {
+			return []time.Time{day}
+		}
+		return nil
+
+	case Weekly:
+		monday := d - (int(start.Weekday())+6)%7 + 7*step
+		weekdays := []time.Weekday{start.Weekday()}
+		if len(r.ByDay) > 0 {
+			weekdays = weekdays[:0]
+			for _, wd := range r.ByDay {
+				weekdays = append(weekdays, wd.Day)
+			}
+		}
+		var days []time.Time
+		for _, wd := range weekdays {
+			days = append(days, at(y, m, monday+(int(wd)+6)%7))
+		}
+		slices.SortFunc(days, time.Time.Compare)
+		return days
+
+	case Monthly:
+		first := at(y, m+time.Month(step), 1)
+		var days []time.Time
+		for _, day := range r.monthDays(first, d) {
+			days = append(days, at(first.Year(), first.Month(), day))
+		}
+		return days
+
+	case Yearly:
+		day := at(y+step, m, d)
+		if day.Day() != d {
+			// February 29th only recurs in leap years.
+			return nil
+		}
+		return []time.Time{day}
+	}
+	return nil
+}
+
+// matchesDay applies BYDAY and BYMONTHDAY as filters, which is how they act
+// on a daily rule.
+func (r Rule) matchesDay(t time.Time) bool {
+	if len(r.ByDay) > 0 && !slices.ContainsFunc(r.ByDay, func(w WeekdayNum) bool { return w.Day == t.Weekday() }) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 {
+		last := daysIn(t.Year(), t.Month())
+		return slices.ContainsFunc(r.ByMonthDay, func(n int) bool { return resolveMonthDay(n, last) == t.Day() })
+	}
+	return true
+}
+
+// monthDays returns the sorted days of the month starting at first that the
+// rule selects. Without BYDAY or BYMONTHDAY the series repeats on
+// startDay, skipping months that are too short.
+func (r Rule) monthDays(first time.Time, startDay int) []int {
+	last := daysIn(first.Year(), first.Month())
+	if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+		if startDay > last {
+			return nil
+		}
+		return []int{startDay}
+	}
+
+	var days []int
+	for day := 1; day <= last; day++ {
+		if len(r.ByMonthDay) > 0 && !slices.ContainsFunc(r.ByMonthDay, func(n int) bool { return resolveMonthDay(n, last) == day }) {
+			continue
+		}
+		if len(r.ByDay) > 0 && !slices.ContainsFunc(r.ByDay, func(w WeekdayNum) bool { return matchesWeekdayNum(w, first, day, last) }) {
+			continue
+		}
+		days = append(days, day)
+	}
+	return days
+}
+
+// matchesWeekdayNum reports whether the given day of the month starting at
+// first is selected by w.
+func matchesWeekdayNum(w WeekdayNum, first time.Time, day, last int) bool {
+	weekday := time.Weekday((int(first.Weekday()) + day - 1) % 7)
+	switch {
+	case weekday != w.Day:
+		return false
+	case w.N > 0:
+		return (day-1)/7+1 == w.N
+	case w.N < 0:
+		return (last-day)/7+1 == -w.N
+	default:
+		return true
+	}
+}
+
+// resolveMonthDay turns a BYMONTHDAY value into a day of a month with last
+// days, counting negative values from the end. It returns 0 when the month
+// has no such day.
+func resolveMonthDay(n, last int) int {
+	if n < 0 {
+		n = last + 1 + n
+	}
+	if n < 1 || n > last {
+		return 0
+	}
+	return n
+}
+
+func daysIn(y int, m time.Month) int {
+	return time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,210 @@
+package recurrence
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	until := time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		input     string
+		expected  Rule
+		canonical string
+	}{
+		{
+			name:      "should parse a daily rule",
+			input:     "FREQ=DAILY",
+			expected:  Rule{Freq: Daily, Interval: 1},
+			canonical: "FREQ=DAILY",
+		},
+		{
+			name:      "should accept the RRULE prefix and lowercase values",
+			input:     "RRULE:freq=weekly;interval=2;byday=mo,fr",
+			expected:  Rule{Freq: Weekly, Interval: 2, ByDay: []WeekdayNum{{Day: time.Monday}, {Day: time.Friday}}},
+			canonical: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR",
+		},
+		{
+			name:      "should parse numbered weekdays",
+			input:     "FREQ=MONTHLY;BYDAY=-1FR,+2MO;COUNT=6",
+			expected:  Rule{Freq: Monthly, Interval: 1, ByDay: []WeekdayNum{{N: -1, Day: time.Friday}, {N: 2, Day: time.Monday}}, Count: 6},
+			canonical: "FREQ=MONTHLY;BYDAY=-1FR,2MO;COUNT=6",
+		},
+		{
+			name:      "should parse month days and a date-time UNTIL",
+			input:     "FREQ=MONTHLY;BYMONTHDAY=1,-1;UNTIL=20261231T235959Z",
+			expected:  Rule{Freq: Monthly, Interval: 1, ByMonthDay: []int{1, -1}, Until: &until},
+			canonical: "FREQ=MONTHLY;BYMONTHDAY=1,-1;UNTIL=20261231T235959Z",
+		},
+		{
+			name:      "should include the whole day of a date UNTIL",
+			input:     "FREQ=YEARLY;UNTIL=20261231",
+			expected:  Rule{Freq: Yearly, Interval: 1, Until: &until},
+			canonical: "FREQ=YEARLY;UNTIL=20261231T235959Z",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := Parse(tc.input)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if !reflect.DeepEqual(rule, tc.expected) {
+				t.Fatalf("expected rule %+v but got %+v", tc.expected, rule)
+			}
+			if got := rule.String(); got != tc.canonical {
+				t.Fatalf("expected canonical form %q but got %q", tc.canonical, got)
+			}
+			again, err := Parse(rule.String())
+			if err != nil || !reflect.DeepEqual(again, rule) {
+				t.Fatalf("canonical form did not round-trip: %+v, %v", again, err)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	inputs := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=x",
+		"FREQ=DAILY;COUNT=3;UNTIL=20261231",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=YEARLY;BYDAY=MO",
+		"FREQ=DAILY;BYSETPOS=-1",
+		"FREQ=DAILY;COUNT",
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			if _, err := Parse(input); !errors.Is(err, ErrInvalidRule) {
+				t.Fatalf("expected error %v but got %v", ErrInvalidRule, err)
+			}
+		})
+	}
+}
+
+func TestRule_Occurrences(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 9, 30, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		name     string
+		rule     string
+		start    time.Time
+		n        int
+		expected []time.Time
+	}{
+		{
+			name:     "should repeat every other day",
+			rule:     "FREQ=DAILY;INTERVAL=2",
+			start:    date(2026, 10, 30),
+			n:        3,
+			expected: []time.Time{date(2026, 10, 30), date(2026, 11, 1), date(2026, 11, 3)},
+		},
+		{
+			name:     "should keep only weekdays on a daily rule",
+			rule:     "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+			start:    date(2026, 10, 16),
+			n:        3,
+			expected: []time.Time{date(2026, 10, 16), date(2026, 10, 19), date(2026, 10, 20)},
+		},
+		{
+			name:     "should repeat on the start weekday",
+			rule:     "FREQ=WEEKLY",
+			start:    date(2026, 10, 19),
+			n:        3,
+			expected: []time.Time{date(2026, 10, 19), date(2026, 10, 26), date(2026, 11, 2)},
+		},
+		{
+			name:     "should expand weekdays of every other week",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR,MO",
+			start:    date(2026, 10, 21),
+			n:        4,
+			expected: []time.Time{date(2026, 10, 21), date(2026, 10, 23), date(2026, 11, 2), date(2026, 11, 6)},
+		},
+		{
+			name:     "should skip months without the start day",
+			rule:     "FREQ=MONTHLY",
+			start:    date(2026, 12, 31),
+			n:        3,
+			expected: []time.Time{date(2026, 12, 31), date(2027, 1, 31), date(2027, 3, 31)},
+		},
+		{
+			name:     "should count month days from the end",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start:    date(2027, 1, 31),
+			n:        3,
+			expected: []time.Time{date(2027, 1, 31), date(2027, 2, 28), date(2027, 3, 31)},
+		},
+		{
+			name:     "should pick the last friday of the month",
+			rule:     "FREQ=MONTHLY;BYDAY=-1FR",
+			start:    date(2026, 10, 30),
+			n:        3,
+			expected: []time.Time{date(2026, 10, 30), date(2026, 11, 27), date(2026, 12, 25)},
+		},
+		{
+			name:     "should intersect weekdays and month days",
+			rule:     "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			start:    date(2026, 2, 13),
+			n:        3,
+			expected: []time.Time{date(2026, 2, 13), date(2026, 3, 13), date(2026, 11, 13)},
+		},
+		{
+			name:     "should only repeat february 29th in leap years",
+			rule:     "FREQ=YEARLY",
+			start:    date(2028, 2, 29),
+			n:        2,
+			expected: []time.Time{date(2028, 2, 29), date(2032, 2, 29)},
+		},
+		{
+			name:     "should count the start towards COUNT",
+			rule:     "FREQ=DAILY;COUNT=2",
+			start:    date(2026, 10, 18),
+			n:        5,
+			expected: []time.Time{date(2026, 10, 18), date(2026, 10, 19)},
+		},
+		{
+			name:     "should stop at UNTIL",
+			rule:     "FREQ=WEEKLY;UNTIL=20261102T093000Z",
+			start:    date(2026, 10, 19),
+			n:        5,
+			expected: []time.Time{date(2026, 10, 19), date(2026, 10, 26), date(2026, 11, 2)},
+		},
+		{
+			name:     "should give up on a rule that never matches again",
+			rule:     "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30",
+			start:    date(2026, 2, 1),
+			n:        3,
+			expected: []time.Time{date(2026, 2, 1)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := Parse(tc.rule)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			got := rule.Occurrences(tc.start, tc.n)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected occurrences %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
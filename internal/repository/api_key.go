@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+const apiKeyColumns = "id, user_id, name, prefix, scopes, expires_at, created_at, last_used_at"
+
+// APIKeyRepository implements domain.APIKeyRepository.
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository.
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func scanAPIKey(s rowScanner, extra ...any) (domain.APIKey, error) {
+	var (
+		key        domain.APIKey
+		scopes     string
+		expiresAt  sql.NullTime
+		lastUsedAt sql.NullTime
+	)
+	dest := append([]any{&key.ID, &key.User.ID, &key.Name, &key.Prefix, &scopes, &expiresAt, &key.CreatedAt, &lastUsedAt}, extra...)
+	if err := s.Scan(dest...); err != nil {
+		return domain.APIKey{}, err
+	}
+	for _, scope := range strings.Fields(scopes) {
+		key.Scopes = append(key.Scopes, domain.APIKeyScope(scope))
+	}
+	key.ExpiresAt = timePtr(expiresAt)
+	key.LastUsedAt = timePtr(lastUsedAt)
+	return key, nil
+}
+
+// GetAll retrieves the keys of a user, oldest first.
+func (r *APIKeyRepository) GetAll(ctx context.Context, userID int64) ([]domain.APIKey, error) {
+	q := "SELECT " + apiKeyColumns + " FROM api_keys WHERE user_id = ? ORDER BY id"
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyRepository.GetAll: querying: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []domain.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("APIKeyRepository.GetAll: scanning row: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("APIKeyRepository.GetAll: iterating rows: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Create inserts a new key and returns it as stored.
+func (r *APIKeyRepository) Create(ctx context.Context, key domain.APIKey, keyHash string) (domain.APIKey, error) {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	q := "INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at) VALUES (?, ?, ?, ?, ?, ?) RETURNING " + apiKeyColumns
+	row := conn(ctx, r.db).QueryRowContext(ctx, q,
+		key.User.ID, key.Name, key.Prefix, keyHash, strings.Join(scopes, " "), nullableTimestamp(key.ExpiresAt))
+	created, err := scanAPIKey(row)
+	if err != nil {
+		return domain.APIKey{}, fmt.Errorf("APIKeyRepository.Create: inserting: %w", err)
+	}
+	created.User = key.User
+
+	return created, nil
+}
+
+// Delete removes a key of a user by its ID.
+func (r *APIKeyRepository) Delete(ctx context.Context, userID, id int64) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM api_keys WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return fmt.Errorf("APIKeyRepository.Delete: deleting: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("APIKeyRepository.Delete: reading affected rows: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("APIKeyRepository.Delete: %w", domain.ErrAPIKeyNotFound)
+	}
+
+	return nil
+}
+
+// Get retrieves a key and its user by the hash of the key.
+func (r *APIKeyRepository) Get(ctx context.Context, keyHash string) (domain.APIKey, error) {
+	q := `SELECT k.id, k.user_id, k.name, k.prefix, k.scopes, k.expires_at, k.created_at, k.last_used_at,
+			u.email, u.name, u.created_at, u.updated_at
+		FROM api_keys k JOIN users u ON u.id = k.user_id
+		WHERE k.key_hash = ?`
+	var u domain.User
+	key, err := scanAPIKey(conn(ctx, r.db).QueryRowContext(ctx, q, keyHash), &u.Email, &u.Name, &u.CreatedAt, &u.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.APIKey{}, fmt.Errorf("APIKeyRepository.Get: %w", domain.ErrAPIKeyNotFound)
+	}
+	if err != nil {
+		return domain.APIKey{}, fmt.Errorf("APIKeyRepository.Get: querying: %w", err)
+	}
+	u.ID = key.User.ID
+	key.User = u
+
+	return key, nil
+}
+
+// Touch stamps last_used_at on a key.
+func (r *APIKeyRepository) Touch(ctx context.Context, id int64, at time.Time) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, "UPDATE api_keys SET last_used_at = ? WHERE id = ?", formatTimestamp(at), id)
+	if err != nil {
+		return fmt.Errorf("APIKeyRepository.Touch: updating: %w", err)
+	}
+
+	return nil
+}
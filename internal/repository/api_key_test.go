@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+var apiKeyColumnNames = []string{"id", "user_id", "name", "prefix", "scopes", "expires_at", "created_at", "last_used_at"}
+
+func TestAPIKeyRepository_GetAll(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	query := regexp.QuoteMeta("SELECT id, user_id, name, prefix, scopes, expires_at, created_at, last_used_at FROM api_keys WHERE user_id = ? ORDER BY id")
+
+	t.Run("should return the keys of the user", func(t *testing.T) {
+		rows := sqlmock.NewRows(apiKeyColumnNames).
+			AddRow(1, 7, "CI", "tsk_abcdefgh", "tasks:read tasks:write", nil, now, now).
+			AddRow(2, 7, "Script", "tsk_ijklmnop", "tasks:read", now, now, nil)
+		mock.ExpectQuery(query).WithArgs(7).WillReturnRows(rows)
+
+		keys, err := NewAPIKeyRepository(db).GetAll(t.Context(), 7)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		expected := []domain.APIKey{
+			{ID: 1, User: domain.User{ID: 7}, Name: "CI", Prefix: "tsk_abcdefgh", Scopes: []domain.APIKeyScope{domain.ScopeTasksRead, domain.ScopeTasksWrite}, CreatedAt: now, LastUsedAt: &now},
+			{ID: 2, User: domain.User{ID: 7}, Name: "Script", Prefix: "tsk_ijklmnop", Scopes: []domain.APIKeyScope{domain.ScopeTasksRead}, ExpiresAt: &now, CreatedAt: now},
+		}
+		if !reflect.DeepEqual(keys, expected) {
+			t.Fatalf("expected keys %+v but got %+v", expected, keys)
+		}
+	})
+
+	t.Run("should return error when query fails", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
+
+		if _, err := NewAPIKeyRepository(db).GetAll(t.Context(), 7); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAPIKeyRepository_Create(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	expires := now.Add(24 * time.Hour)
+	query := regexp.QuoteMeta("INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at) VALUES (?, ?, ?, ?, ?, ?) RETURNING id, user_id, name, prefix, scopes, expires_at, created_at, last_used_at")
+	key := domain.APIKey{
+		User:      domain.User{ID: 7, Email: "ada@example.com"},
+		Name:      "CI",
+		Prefix:    "tsk_abcdefgh",
+		Scopes:    []domain.APIKeyScope{domain.ScopeTasksRead, domain.ScopeTasksWrite},
+		ExpiresAt: &expires,
+	}
+
+	t.Run("should store the key hash and scopes", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(7, "CI", "tsk_abcdefgh", "abc123", "tasks:read tasks:write", "2026-10-19 09:00:00").
+			WillReturnRows(sqlmock.NewRows(apiKeyColumnNames).AddRow(1, 7, "CI", "tsk_abcdefgh", "tasks:read tasks:write", expires, now, nil))
+
+		created, err := NewAPIKeyRepository(db).Create(t.Context(), key, "abc123")
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		expected := key
+		expected.ID = 1
+		expected.CreatedAt = now
+		if !reflect.DeepEqual(created, expected) {
+			t.Fatalf("expected key %+v but got %+v", expected, created)
+		}
+	})
+
+	t.Run("should return error when insert fails", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
+
+		if _, err := NewAPIKeyRepository(db).Create(t.Context(), key, "abc123"); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAPIKeyRepository_Delete(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	query := regexp.QuoteMeta("DELETE FROM api_keys WHERE id = ? AND user_id = ?")
+
+	t.Run("should delete a key of the user", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(1, 7).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		if err := NewAPIKeyRepository(db).Delete(t.Context(), 7, 1); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	})
+
+	t.Run("should return not found for keys of other users", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(1, 8).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		if err := NewAPIKeyRepository(db).Delete(t.Context(), 8, 1); !errors.Is(err, domain.ErrAPIKeyNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrAPIKeyNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAPIKeyRepository_Get(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	query := regexp.QuoteMeta("FROM api_keys k JOIN users u ON u.id = k.user_id") + `\s+` + regexp.QuoteMeta("WHERE k.key_hash = ?")
+	columns := append(append([]string{}, apiKeyColumnNames...), "email", "name", "created_at", "updated_at")
+
+	t.Run("should return the key and its user", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).AddRow(1, 7, "CI", "tsk_abcdefgh", "tasks:read", nil, now, nil, "ada@example.com", "Ada", now, now)
+		mock.ExpectQuery(query).WithArgs("abc123").WillReturnRows(rows)
+
+		key, err := NewAPIKeyRepository(db).Get(t.Context(), "abc123")
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		expected := domain.APIKey{
+			ID:        1,
+			User:      domain.User{ID: 7, Email: "ada@example.com", Name: "Ada", CreatedAt: now, UpdatedAt: now},
+			Name:      "CI",
+			Prefix:    "tsk_abcdefgh",
+			Scopes:    []domain.APIKeyScope{domain.ScopeTasksRead},
+			CreatedAt: now,
+		}
+		if !reflect.DeepEqual(key, expected) {
+			t.Fatalf("expected key %+v but got %+v", expected, key)
+		}
+	})
+
+	t.Run("should return not found", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(sql.ErrNoRows)
+
+		if _, err := NewAPIKeyRepository(db).Get(t.Context(), "abc123"); !errors.Is(err, domain.ErrAPIKeyNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrAPIKeyNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAPIKeyRepository_Touch(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE api_keys SET last_used_at = ? WHERE id = ?")).
+		WithArgs("2026-10-18 09:00:00", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := NewAPIKeyRepository(db).Touch(t.Context(), 1, now); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+const calendarFeedColumns = "id, name, owner_id, created_at, last_used_at"
+
+// CalendarFeedRepository implements domain.CalendarFeedRepository.
+type CalendarFeedRepository struct {
+	db *sql.DB
+}
+
+// NewCalendarFeedRepository creates a new CalendarFeedRepository.
+func NewCalendarFeedRepository(db *sql.DB) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+func scanCalendarFeed(s rowScanner) (domain.CalendarFeed, error) {
+	var (
+		feed       domain.CalendarFeed
+		ownerID    sql.NullInt64
+		lastUsedAt sql.NullTime
+	)
+	if err := s.Scan(&feed.ID, &feed.Name, &ownerID, &feed.CreatedAt, &lastUsedAt); err != nil {
+		return domain.CalendarFeed{}, err
+	}
+	feed.OwnerID = int64Ptr(ownerID)
+	feed.LastUsedAt = timePtr(lastUsedAt)
+	return feed, nil
+}
+
+// GetAll retrieves the calendar feeds of the tenant, oldest first.
+func (r *CalendarFeedRepository) GetAll(ctx context.Context) ([]domain.CalendarFeed, error) {
+	q := "SELECT " + calendarFeedColumns + " FROM calendar_feeds WHERE " + ownerCondition + " ORDER BY id"
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, tenantOwner(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("CalendarFeedRepository.GetAll: querying: %w", err)
+	}
+	defer rows.Close()
+
+	feeds := []domain.CalendarFeed{}
+	for rows.Next() {
+		feed, err := scanCalendarFeed(rows)
+		if err != nil {
+			return nil, fmt.Errorf("CalendarFeedRepository.GetAll: scanning row: %w", err)
+		}
+		feeds = append(feeds, feed)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("CalendarFeedRepository.GetAll: iterating rows: %w", err)
+	}
+
+	return feeds, nil
+}
+
+// Create inserts a new calendar feed owned by the tenant and returns it as
+// stored.
+func (r *CalendarFeedRepository) Create(ctx context.Context, feed domain.CalendarFeed, tokenHash string) (domain.CalendarFeed, error) {
+	q := "INSERT INTO calendar_feeds (name, token_hash, owner_id) VALUES (?, ?, ?) RETURNING " + calendarFeedColumns
+	created, err := scanCalendarFeed(conn(ctx, r.db).QueryRowContext(ctx, q, feed.Name, tokenHash, tenantOwner(ctx)))
+	if err != nil {
+		return domain.CalendarFeed{}, fmt.Errorf("CalendarFeedRepository.Create: inserting: %w", err)
+	}
+
+	return created, nil
+}
+
+// Delete removes a calendar feed of the tenant by its ID, revoking its
+// token.
+func (r *CalendarFeedRepository) Delete(ctx context.Context, id int64) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM calendar_feeds WHERE id = ? AND "+ownerCondition, id, tenantOwner(ctx))
+	if err != nil {
+		return fmt.Errorf("CalendarFeedRepository.Delete: deleting: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("CalendarFeedRepository.Delete: reading affected rows: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("CalendarFeedRepository.Delete: %w", domain.ErrCalendarFeedNotFound)
+	}
+
+	return nil
+}
+
+// Use looks a feed up by the hash of its token, stamping last_used_at.
+func (r *CalendarFeedRepository) Use(ctx context.Context, tokenHash string, at time.Time) (domain.CalendarFeed, error) {
+	q := "UPDATE calendar_feeds SET last_used_at = ? WHERE token_hash = ? RETURNING " + calendarFeedColumns
+	feed, err := scanCalendarFeed(conn(ctx, r.db).QueryRowContext(ctx, q, formatTimestamp(at), tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.CalendarFeed{}, fmt.Errorf("CalendarFeedRepository.Use: %w", domain.ErrCalendarFeedNotFound)
+	}
+	if err != nil {
+		return domain.CalendarFeed{}, fmt.Errorf("CalendarFeedRepository.Use: updating: %w", err)
+	}
+
+	return feed, nil
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+var calendarFeedColumnNames = []string{"id", "name", "owner_id", "created_at", "last_used_at"}
+
+func TestCalendarFeedRepository_GetAll(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	query := regexp.QuoteMeta("SELECT id, name, owner_id, created_at, last_used_at FROM calendar_feeds WHERE owner_id IS ? ORDER BY id")
+
+	t.Run("should return feeds", func(t *testing.T) {
+		rows := sqlmock.NewRows(calendarFeedColumnNames).
+			AddRow(1, "Phone", nil, now, nil).
+			AddRow(2, "Laptop", nil, now, now)
+		mock.ExpectQuery(query).WithArgs(nil).WillReturnRows(rows)
+
+		feeds, err := NewCalendarFeedRepository(db).GetAll(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		expected := []domain.CalendarFeed{
+			{ID: 1, Name: "Phone", CreatedAt: now},
+			{ID: 2, Name: "Laptop", CreatedAt: now, LastUsedAt: &now},
+		}
+		if !reflect.DeepEqual(feeds, expected) {
+			t.Fatalf("expected feeds %v but got %v", expected, feeds)
+		}
+	})
+
+	t.Run("should return error when query fails", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
+
+		if _, err := NewCalendarFeedRepository(db).GetAll(t.Context()); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCalendarFeedRepository_Create(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	query := regexp.QuoteMeta("INSERT INTO calendar_feeds (name, token_hash, owner_id) VALUES (?, ?, ?) RETURNING id, name, owner_id, created_at, last_used_at")
+
+	t.Run("should store the token hash", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs("Phone", "abc123", nil).
+			WillReturnRows(sqlmock.NewRows(calendarFeedColumnNames).AddRow(1, "Phone", nil, now, nil))
+
+		feed, err := NewCalendarFeedRepository(db).Create(t.Context(), domain.CalendarFeed{Name: "Phone"}, "abc123")
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		expected := domain.CalendarFeed{ID: 1, Name: "Phone", CreatedAt: now}
+		if !reflect.DeepEqual(feed, expected) {
+			t.Fatalf("expected feed %v but got %v", expected, feed)
+		}
+	})
+
+	t.Run("should return error when insert fails", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
+
+		if _, err := NewCalendarFeedRepository(db).Create(t.Context(), domain.CalendarFeed{Name: "Phone"}, "abc123"); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCalendarFeedRepository_Delete(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	query := regexp.QuoteMeta("DELETE FROM calendar_feeds WHERE id = ? AND owner_id IS ?")
+
+	testCases := []struct {
+		name          string
+		result        sql.Result
+		expectedError error
+	}{
+		{name: "should delete the feed", result: sqlmock.NewResult(0, 1)},
+		{name: "should return not found", result: sqlmock.NewResult(0, 0), expectedError: domain.ErrCalendarFeedNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock.ExpectExec(query).WithArgs(1, nil).WillReturnResult(tc.result)
+
+			err := NewCalendarFeedRepository(db).Delete(t.Context(), 1)
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected error %v but got %v", tc.expectedError, err)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCalendarFeedRepository_Use(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta("UPDATE calendar_feeds SET last_used_at = ? WHERE token_hash = ? RETURNING id, name, owner_id, created_at, last_used_at")
+
+	t.Run("should stamp and return the feed", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs("2026-10-18 09:00:00", "abc123").
+			WillReturnRows(sqlmock.NewRows(calendarFeedColumnNames).AddRow(1, "Phone", 7, now, now))
+
+		feed, err := NewCalendarFeedRepository(db).Use(t.Context(), "abc123", now)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		owner := int64(7)
+		expected := domain.CalendarFeed{ID: 1, Name: "Phone", OwnerID: &owner, CreatedAt: now, LastUsedAt: &now}
+		if !reflect.DeepEqual(feed, expected) {
+			t.Fatalf("expected feed %v but got %v", expected, feed)
+		}
+	})
+
+	t.Run("should return not found for an unknown token", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(calendarFeedColumnNames))
+
+		if _, err := NewCalendarFeedRepository(db).Use(t.Context(), "nope", now); !errors.Is(err, domain.ErrCalendarFeedNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrCalendarFeedNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCalendarFeedRepository_Isolation(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	owner := int64(7)
+	ctx := domain.WithTenant(t.Context(), owner)
+	repo := NewCalendarFeedRepository(db)
+
+	t.Run("should list only the feeds of the tenant", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("FROM calendar_feeds WHERE owner_id IS ?")).WithArgs(owner).
+			WillReturnRows(sqlmock.NewRows(calendarFeedColumnNames).AddRow(1, "Phone", owner, now, nil))
+
+		feeds, err := repo.GetAll(ctx)
+		if err != nil || len(feeds) != 1 || *feeds[0].OwnerID != owner {
+			t.Fatalf("expected the feed of the tenant but got %v, %v", feeds, err)
+		}
+	})
+
+	t.Run("should create the feed for the tenant", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO calendar_feeds")).WithArgs("Phone", "abc123", owner).
+			WillReturnRows(sqlmock.NewRows(calendarFeedColumnNames).AddRow(2, "Phone", owner, now, nil))
+
+		feed, err := repo.Create(ctx, domain.CalendarFeed{Name: "Phone"}, "abc123")
+		if err != nil || feed.OwnerID == nil || *feed.OwnerID != owner {
+			t.Fatalf("expected a feed of the tenant but got %v, %v", feed, err)
+		}
+	})
+
+	t.Run("should not delete a feed of another tenant", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM calendar_feeds WHERE id = ? AND owner_id IS ?")).WithArgs(3, owner).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		if err := repo.Delete(ctx, 3); !errors.Is(err, domain.ErrCalendarFeedNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrCalendarFeedNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
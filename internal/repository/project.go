@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+const projectColumns = "id, name, description, inbox, created_at, updated_at"
+
+// ProjectRepository provides access to project storage. Projects belong to
+// the tenant of ctx like tasks do; those of other tenants behave as if they
+// did not exist.
+type ProjectRepository struct {
+	db *sql.DB
+}
+
+// NewProjectRepository creates a new ProjectRepository.
+func NewProjectRepository(db *sql.DB) *ProjectRepository {
+	return &ProjectRepository{db: db}
+}
+
+func scanProject(s rowScanner) (domain.Project, error) {
+	var p domain.Project
+	err := s.Scan(&p.ID, &p.Name, &p.Description, &p.Inbox, &p.CreatedAt, &p.UpdatedAt)
+	return p, err
+}
+
+// GetAll retrieves every project of the tenant, the inbox first and the rest
+// by name.
+func (r *ProjectRepository) GetAll(ctx context.Context) ([]domain.Project, error) {
+	q := "SELECT " + projectColumns + " FROM projects WHERE " + ownerCondition + " ORDER BY inbox DESC, name COLLATE NOCASE, id"
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, tenantOwner(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("ProjectRepository.GetAll: querying: %w", err)
+	}
+	defer rows.Close()
+
+	projects := []domain.Project{}
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ProjectRepository.GetAll: scanning row: %w", err)
+		}
+		projects = append(projects, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ProjectRepository.GetAll: iterating rows: %w", err)
+	}
+
+	return projects, nil
+}
+
+// GetByID retrieves a single project by its ID.
+func (r *ProjectRepository) GetByID(ctx context.Context, id int64) (domain.Project, error) {
+	q := "SELECT " + projectColumns + " FROM projects WHERE id = ? AND " + ownerCondition
+	p, err := scanProject(conn(ctx, r.db).QueryRowContext(ctx, q, id, tenantOwner(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Project{}, fmt.Errorf("ProjectRepository.GetByID: %w", domain.ErrProjectNotFound)
+	}
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("ProjectRepository.GetByID: scanning row: %w", err)
+	}
+
+	return p, nil
+}
+
+// Create inserts a new project owned by the tenant and returns it as stored.
+// The inbox flag is never copied from project.
+func (r *ProjectRepository) Create(ctx context.Context, project domain.Project) (domain.Project, error) {
+	q := "INSERT INTO projects (name, description, owner_id) VALUES (?, ?, ?) RETURNING " + projectColumns
+	created, err := scanProject(conn(ctx, r.db).QueryRowContext(ctx, q, project.Name, project.Description, tenantOwner(ctx)))
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("ProjectRepository.Create: inserting: %w", err)
+	}
+
+	return created, nil
+}
+
+// Update replaces the name and description of an existing project.
+func (r *ProjectRepository) Update(ctx context.Context, project domain.Project) (domain.Project, error) {
+	q := "UPDATE projects SET name = ?, description = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND " + ownerCondition + " RETURNING " + projectColumns
+	updated, err := scanProject(conn(ctx, r.db).QueryRowContext(ctx, q, project.Name, project.Description, project.ID, tenantOwner(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Project{}, fmt.Errorf("ProjectRepository.Update: %w", domain.ErrProjectNotFound)
+	}
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("ProjectRepository.Update: updating: %w", err)
+	}
+
+	return updated, nil
+}
+
+// Delete removes a project in a single transaction, first deleting its
+// tasks, moving them to the inbox or refusing, according to mode. Only tasks
+// outside the trash keep a project from being deleted; with modes restrict
+// and cascade, the project's tasks in the trash leave it and stay there,
+// restorable without a project. Every task deleted or moved gets an event in
+// its history.
+func (r *ProjectRepository) Delete(ctx context.Context, id int64, mode domain.ProjectDeleteMode) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("ProjectRepository.Delete: beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var inbox bool
+	err = tx.QueryRowContext(ctx, "SELECT inbox FROM projects WHERE id = ? AND "+ownerCondition, id, tenantOwner(ctx)).Scan(&inbox)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("ProjectRepository.Delete: %w", domain.ErrProjectNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("ProjectRepository.Delete: reading project: %w", err)
+	}
+	if inbox {
+		return fmt.Errorf("ProjectRepository.Delete: %w", domain.ErrInboxProjectDelete)
+	}
+
+	switch mode {
+	case domain.ProjectDeleteCascade:
+		// The tasks outside the trash are deleted like TaskRepository.Delete
+		// does, subtasks and all, and leave the project so that it can go.
+		// The tasks in the trash already leave it as in restrict mode.
+		roots, err := queryIDs(ctx, tx,
+			"SELECT id FROM tasks WHERE project_id = ? AND "+ownerCondition+" AND deleted_at IS NULL", id, tenantOwner(ctx))
+		if err != nil {
+			return fmt.Errorf("ProjectRepository.Delete: reading tasks: %w", err)
+		}
+		deletionID, err := nextDeletionID(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("ProjectRepository.Delete: %w", err)
+		}
+		q := "WITH RECURSIVE subtree(task_id) AS (" +
+			"SELECT id FROM tasks WHERE project_id = ? AND " + ownerCondition + " AND deleted_at IS NULL " +
+			"UNION SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.task_id WHERE tasks.deleted_at IS NULL" +
+			") UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP, deletion_id = ?, project_id = CASE WHEN project_id = ? THEN NULL ELSE project_id END, " +
+			taskTouch + " WHERE id IN (SELECT task_id FROM subtree) RETURNING id"
+		deleted, err := queryIDs(ctx, tx, q, id, tenantOwner(ctx), deletionID, id)
+		if err != nil {
+			return fmt.Errorf("ProjectRepository.Delete: deleting tasks: %w", err)
+		}
+		for _, taskID := range deleted {
+			var changes map[string]domain.FieldChange
+			if slices.Contains(roots, taskID) {
+				changes = map[string]domain.FieldChange{"project_id": {Old: id}}
+			}
+			if err := recordTaskEvent(ctx, tx, taskID, domain.TaskEventDeleted, changes); err != nil {
+				return fmt.Errorf("ProjectRepository.Delete: %w", err)
+			}
+		}
+		if err := touchDependents(ctx, tx, deleted); err != nil {
+			return fmt.Errorf("ProjectRepository.Delete: %w", err)
+		}
+		if err := detachTrashed(ctx, tx, id); err != nil {
+			return fmt.Errorf("ProjectRepository.Delete: %w", err)
+		}
+	case domain.ProjectDeleteInbox:
+		var inboxID int64
+		if err := tx.QueryRowContext(ctx, "SELECT id FROM projects WHERE inbox = 1 AND "+ownerCondition, tenantOwner(ctx)).Scan(&inboxID); err != nil {
+			return fmt.Errorf("ProjectRepository.Delete: reading inbox: %w", err)
+		}
+		q := "UPDATE tasks SET project_id = ?, " + taskTouch + " WHERE project_id = ? AND " + ownerCondition + " RETURNING id"
+		moved, err := queryIDs(ctx, tx, q, inboxID, id, tenantOwner(ctx))
+		if err != nil {
+			return fmt.Errorf("ProjectRepository.Delete: moving tasks: %w", err)
+		}
+		for _, taskID := range moved {
+			changes := map[string]domain.FieldChange{"project_id": {Old: id, New: inboxID}}
+			if err := recordTaskEvent(ctx, tx, taskID, domain.TaskEventUpdated, changes); err != nil {
+				return fmt.Errorf("ProjectRepository.Delete: %w", err)
+			}
+		}
+	default:
+		var hasTasks bool
+		err := tx.QueryRowContext(ctx,
+			"SELECT EXISTS (SELECT 1 FROM tasks WHERE project_id = ? AND "+ownerCondition+" AND deleted_at IS NULL)",
+			id, tenantOwner(ctx)).Scan(&hasTasks)
+		if err != nil {
+			return fmt.Errorf("ProjectRepository.Delete: counting tasks: %w", err)
+		}
+		if hasTasks {
+			return fmt.Errorf("ProjectRepository.Delete: %w", domain.ErrProjectNotEmpty)
+		}
+		if err := detachTrashed(ctx, tx, id); err != nil {
+			return fmt.Errorf("ProjectRepository.Delete: %w", err)
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM projects WHERE id = ? AND "+ownerCondition, id, tenantOwner(ctx))
+	if err != nil {
+		return fmt.Errorf("ProjectRepository.Delete: deleting: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ProjectRepository.Delete: reading affected rows: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("ProjectRepository.Delete: %w", domain.ErrProjectNotFound)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ProjectRepository.Delete: committing: %w", err)
+	}
+
+	return nil
+}
+
+// detachTrashed takes the tasks in the trash out of the project, so that it
+// can go while they stay restorable, and records the change in their history.
+func detachTrashed(ctx context.Context, tx DBTX, projectID int64) error {
+	q := "UPDATE tasks SET project_id = NULL, " + taskTouch + " WHERE project_id = ? AND " + ownerCondition + " AND deleted_at IS NOT NULL RETURNING id"
+	detached, err := queryIDs(ctx, tx, q, projectID, tenantOwner(ctx))
+	if err != nil {
+		return fmt.Errorf("detaching deleted tasks: %w", err)
+	}
+	for _, taskID := range detached {
+		changes := map[string]domain.FieldChange{"project_id": {Old: projectID}}
+		if err := recordTaskEvent(ctx, tx, taskID, domain.TaskEventUpdated, changes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,371 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+var projectColumnNames = []string{"id", "name", "description", "inbox", "created_at", "updated_at"}
+
+func TestProjectRepository_GetAll(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	query := regexp.QuoteMeta("SELECT id, name, description, inbox, created_at, updated_at FROM projects WHERE owner_id IS ? ORDER BY inbox DESC, name COLLATE NOCASE, id")
+
+	t.Run("should return projects", func(t *testing.T) {
+		rows := sqlmock.NewRows(projectColumnNames).
+			AddRow(1, "Inbox", "", true, now, now).
+			AddRow(2, "Work", "Office", false, now, now)
+		mock.ExpectQuery(query).WithArgs(nil).WillReturnRows(rows)
+
+		projects, err := NewProjectRepository(db).GetAll(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		expected := []domain.Project{
+			{ID: 1, Name: "Inbox", Inbox: true, CreatedAt: now, UpdatedAt: now},
+			{ID: 2, Name: "Work", Description: "Office", CreatedAt: now, UpdatedAt: now},
+		}
+		if !reflect.DeepEqual(projects, expected) {
+			t.Fatalf("expected projects %v but got %v", expected, projects)
+		}
+	})
+
+	t.Run("should return error when query fails", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(nil).WillReturnError(sql.ErrConnDone)
+
+		if _, err := NewProjectRepository(db).GetAll(t.Context()); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProjectRepository_GetByID(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	query := regexp.QuoteMeta("SELECT id, name, description, inbox, created_at, updated_at FROM projects WHERE id = ? AND owner_id IS ?")
+
+	t.Run("should return project not found", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(9, nil).WillReturnError(sql.ErrNoRows)
+
+		if _, err := NewProjectRepository(db).GetByID(t.Context(), 9); !errors.Is(err, domain.ErrProjectNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrProjectNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProjectRepository_Create(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	query := regexp.QuoteMeta("INSERT INTO projects (name, description, owner_id) VALUES (?, ?, ?) RETURNING id, name, description, inbox, created_at, updated_at")
+	mock.ExpectQuery(query).WithArgs("Work", "Office", nil).
+		WillReturnRows(sqlmock.NewRows(projectColumnNames).AddRow(2, "Work", "Office", false, now, now))
+
+	project, err := NewProjectRepository(db).Create(t.Context(), domain.Project{Name: "Work", Description: "Office", Inbox: true})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if project.ID != 2 || project.Inbox {
+		t.Fatalf("unexpected project %v", project)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProjectRepository_Update(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	query := regexp.QuoteMeta("UPDATE projects SET name = ?, description = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND owner_id IS ? RETURNING id, name, description, inbox, created_at, updated_at")
+	mock.ExpectQuery(query).WithArgs("Work", "", 9, nil).WillReturnError(sql.ErrNoRows)
+
+	_, err = NewProjectRepository(db).Update(t.Context(), domain.Project{ID: 9, Name: "Work"})
+	if !errors.Is(err, domain.ErrProjectNotFound) {
+		t.Fatalf("expected error %v but got %v", domain.ErrProjectNotFound, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProjectRepository_Delete(t *testing.T) {
+	t.Parallel()
+
+	selectInbox := regexp.QuoteMeta("SELECT inbox FROM projects WHERE id = ? AND owner_id IS ?")
+	hasTasks := regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM tasks WHERE project_id = ? AND owner_id IS ? AND deleted_at IS NULL)")
+	detachTrashed := regexp.QuoteMeta("UPDATE tasks SET project_id = NULL, updated_at = CURRENT_TIMESTAMP, version = version + 1 " +
+		"WHERE project_id = ? AND owner_id IS ? AND deleted_at IS NOT NULL RETURNING id")
+	selectRoots := regexp.QuoteMeta("SELECT id FROM tasks WHERE project_id = ? AND owner_id IS ? AND deleted_at IS NULL")
+	trashTasks := regexp.QuoteMeta("WITH RECURSIVE subtree(task_id) AS (SELECT id FROM tasks WHERE project_id = ? AND owner_id IS ? AND deleted_at IS NULL " +
+		"UNION SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.task_id WHERE tasks.deleted_at IS NULL) " +
+		"UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP, deletion_id = ?, project_id = CASE WHEN project_id = ? THEN NULL ELSE project_id END, " +
+		"updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id IN (SELECT task_id FROM subtree) RETURNING id")
+	selectInboxID := regexp.QuoteMeta("SELECT id FROM projects WHERE inbox = 1 AND owner_id IS ?")
+	moveTasks := regexp.QuoteMeta("UPDATE tasks SET project_id = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 " +
+		"WHERE project_id = ? AND owner_id IS ? RETURNING id")
+	event := regexp.QuoteMeta("INSERT INTO task_events (task_id, action, changes, actor, request_id) VALUES (?, ?, ?, ?, ?)")
+	deleteProject := regexp.QuoteMeta("DELETE FROM projects WHERE id = ? AND owner_id IS ?")
+
+	testCases := []struct {
+		name        string
+		mode        domain.ProjectDeleteMode
+		setup       func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "should delete an empty project in restrict mode, keeping its deleted tasks in the trash",
+			mode: domain.ProjectDeleteRestrict,
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectInbox).WithArgs(2, nil).WillReturnRows(sqlmock.NewRows([]string{"inbox"}).AddRow(false))
+				mock.ExpectQuery(hasTasks).WithArgs(2, nil).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectQuery(detachTrashed).WithArgs(2, nil).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+				mock.ExpectExec(event).WithArgs(8, "updated", `{"project_id":{"old":2,"new":null}}`, nil, nil).WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec(deleteProject).WithArgs(2, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "should refuse a project with tasks in restrict mode",
+			mode: domain.ProjectDeleteRestrict,
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectInbox).WithArgs(2, nil).WillReturnRows(sqlmock.NewRows([]string{"inbox"}).AddRow(false))
+				mock.ExpectQuery(hasTasks).WithArgs(2, nil).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectRollback()
+			},
+			expectedErr: domain.ErrProjectNotEmpty,
+		},
+		{
+			name: "should move the tasks and their subtasks to the trash in cascade mode",
+			mode: domain.ProjectDeleteCascade,
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectInbox).WithArgs(2, nil).WillReturnRows(sqlmock.NewRows([]string{"inbox"}).AddRow(false))
+				mock.ExpectQuery(selectRoots).WithArgs(2, nil).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				expectNextDeletionID(mock, 6)
+				mock.ExpectQuery(trashTasks).WithArgs(2, nil, 6, 2).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(4))
+				mock.ExpectExec(event).WithArgs(3, "deleted", `{"project_id":{"old":2,"new":null}}`, nil, nil).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(event).WithArgs(4, "deleted", `{}`, nil, nil).WillReturnResult(sqlmock.NewResult(2, 1))
+				expectTouchDependents(mock, 3, 4).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(detachTrashed).WithArgs(2, nil).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+				mock.ExpectExec(event).WithArgs(8, "updated", `{"project_id":{"old":2,"new":null}}`, nil, nil).WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec(deleteProject).WithArgs(2, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "should move the tasks to the inbox in inbox mode",
+			mode: domain.ProjectDeleteInbox,
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectInbox).WithArgs(2, nil).WillReturnRows(sqlmock.NewRows([]string{"inbox"}).AddRow(false))
+				mock.ExpectQuery(selectInboxID).WithArgs(nil).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(moveTasks).WithArgs(1, 2, nil).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectExec(event).WithArgs(3, "updated", `{"project_id":{"old":2,"new":1}}`, nil, nil).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(deleteProject).WithArgs(2, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "should return project not found when the project is already gone",
+			mode: domain.ProjectDeleteRestrict,
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectInbox).WithArgs(2, nil).WillReturnRows(sqlmock.NewRows([]string{"inbox"}).AddRow(false))
+				mock.ExpectQuery(hasTasks).WithArgs(2, nil).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectQuery(detachTrashed).WithArgs(2, nil).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectExec(deleteProject).WithArgs(2, nil).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedErr: domain.ErrProjectNotFound,
+		},
+		{
+			name: "should refuse to delete the inbox",
+			mode: domain.ProjectDeleteCascade,
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectInbox).WithArgs(2, nil).WillReturnRows(sqlmock.NewRows([]string{"inbox"}).AddRow(true))
+				mock.ExpectRollback()
+			},
+			expectedErr: domain.ErrInboxProjectDelete,
+		},
+		{
+			name: "should return project not found",
+			mode: domain.ProjectDeleteRestrict,
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectInbox).WithArgs(2, nil).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			expectedErr: domain.ErrProjectNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal("failed to create mock")
+			}
+			defer db.Close()
+			tc.setup(mock)
+
+			err = NewProjectRepository(db).Delete(t.Context(), 2, tc.mode)
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// TestProjectRepository_Isolation runs every method as tenant 7 against
+// project 2, which belongs to another tenant, and checks that each statement
+// is bound to tenant 7 and that the project behaves as if it did not exist.
+func TestProjectRepository_Isolation(t *testing.T) {
+	t.Parallel()
+
+	const tenant = 7
+	noRows := func() *sqlmock.Rows { return sqlmock.NewRows(projectColumnNames) }
+
+	testCases := []struct {
+		name  string
+		setup func(mock sqlmock.Sqlmock)
+		call  func(ctx context.Context, r *ProjectRepository) error
+		// expectedErr is nil for the methods that list or create, which
+		// find nothing or write a project of the tenant instead of failing.
+		expectedErr error
+	}{
+		{
+			name: "GetAll",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM projects WHERE owner_id IS ?")).WithArgs(tenant).WillReturnRows(noRows())
+			},
+			call: func(ctx context.Context, r *ProjectRepository) error {
+				projects, err := r.GetAll(ctx)
+				if err == nil && len(projects) != 0 {
+					return fmt.Errorf("unexpected projects %v", projects)
+				}
+				return err
+			},
+		},
+		{
+			name: "GetByID",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM projects WHERE id = ? AND owner_id IS ?")).WithArgs(2, tenant).WillReturnRows(noRows())
+			},
+			call: func(ctx context.Context, r *ProjectRepository) error {
+				_, err := r.GetByID(ctx, 2)
+				return err
+			},
+			expectedErr: domain.ErrProjectNotFound,
+		},
+		{
+			name: "Create",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO projects (name, description, owner_id) VALUES (?, ?, ?)")).WithArgs("Work", "", tenant).
+					WillReturnRows(sqlmock.NewRows(projectColumnNames).AddRow(3, "Work", "", false, time.Now(), time.Now()))
+			},
+			call: func(ctx context.Context, r *ProjectRepository) error {
+				_, err := r.Create(ctx, domain.Project{Name: "Work"})
+				return err
+			},
+		},
+		{
+			name: "Update",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE projects SET name = ?, description = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND owner_id IS ?")).
+					WithArgs("Work", "", 2, tenant).WillReturnRows(noRows())
+			},
+			call: func(ctx context.Context, r *ProjectRepository) error {
+				_, err := r.Update(ctx, domain.Project{ID: 2, Name: "Work"})
+				return err
+			},
+			expectedErr: domain.ErrProjectNotFound,
+		},
+		{
+			name: "Delete",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT inbox FROM projects WHERE id = ? AND owner_id IS ?")).WithArgs(2, tenant).
+					WillReturnRows(sqlmock.NewRows([]string{"inbox"}))
+				mock.ExpectRollback()
+			},
+			call: func(ctx context.Context, r *ProjectRepository) error {
+				return r.Delete(ctx, 2, domain.ProjectDeleteCascade)
+			},
+			expectedErr: domain.ErrProjectNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal("failed to create mock")
+			}
+			defer db.Close()
+			tc.setup(mock)
+
+			err = tc.call(domain.WithTenant(t.Context(), tenant), NewProjectRepository(db))
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+// RefreshTokenRepository implements domain.RefreshTokenRepository.
+type RefreshTokenRepository struct {
+	db *sql.DB
+}
+
+// NewRefreshTokenRepository creates a new RefreshTokenRepository.
+func NewRefreshTokenRepository(db *sql.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+// Create inserts a new refresh token.
+func (r *RefreshTokenRepository) Create(ctx context.Context, token domain.RefreshToken, tokenHash string) error {
+	q := "INSERT INTO refresh_tokens (user_id, family, token_hash, created_at, expires_at) VALUES (?, ?, ?, ?, ?)"
+	_, err := conn(ctx, r.db).ExecContext(ctx, q,
+		token.User.ID, token.Family, tokenHash, formatTimestamp(token.CreatedAt), formatTimestamp(token.ExpiresAt))
+	if err != nil {
+		return fmt.Errorf("RefreshTokenRepository.Create: inserting: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a refresh token and its user by the hash of the token.
+func (r *RefreshTokenRepository) Get(ctx context.Context, tokenHash string) (domain.RefreshToken, error) {
+	q := `SELECT t.id, t.family, t.created_at, t.expires_at, t.used_at,
+			u.id, u.email, u.name, u.created_at, u.updated_at
+		FROM refresh_tokens t JOIN users u ON u.id = t.user_id
+		WHERE t.token_hash = ?`
+	var (
+		token  domain.RefreshToken
+		u      = &token.User
+		usedAt sql.NullTime
+	)
+	err := conn(ctx, r.db).QueryRowContext(ctx, q, tokenHash).
+		Scan(&token.ID, &token.Family, &token.CreatedAt, &token.ExpiresAt, &usedAt,
+			&u.ID, &u.Email, &u.Name, &u.CreatedAt, &u.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.RefreshToken{}, fmt.Errorf("RefreshTokenRepository.Get: %w", domain.ErrRefreshTokenNotFound)
+	}
+	if err != nil {
+		return domain.RefreshToken{}, fmt.Errorf("RefreshTokenRepository.Get: querying: %w", err)
+	}
+	token.UsedAt = timePtr(usedAt)
+
+	return token, nil
+}
+
+// MarkUsed stamps used_at on a token that was not used yet. The check and
+// the stamp are one statement, so of two concurrent refreshes with the same
+// token only one succeeds.
+func (r *RefreshTokenRepository) MarkUsed(ctx context.Context, id int64, now time.Time) error {
+	q := "UPDATE refresh_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL"
+	res, err := conn(ctx, r.db).ExecContext(ctx, q, formatTimestamp(now), id)
+	if err != nil {
+		return fmt.Errorf("RefreshTokenRepository.MarkUsed: updating: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("RefreshTokenRepository.MarkUsed: reading affected rows: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("RefreshTokenRepository.MarkUsed: %w", domain.ErrRefreshTokenReused)
+	}
+
+	return nil
+}
+
+// DeleteFamily removes every token of a family.
+func (r *RefreshTokenRepository) DeleteFamily(ctx context.Context, family string) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM refresh_tokens WHERE family = ?", family)
+	if err != nil {
+		return fmt.Errorf("RefreshTokenRepository.DeleteFamily: deleting: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes the tokens that expired at or before now.
+func (r *RefreshTokenRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM refresh_tokens WHERE expires_at <= ?", formatTimestamp(now))
+	if err != nil {
+		return 0, fmt.Errorf("RefreshTokenRepository.DeleteExpired: deleting: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("RefreshTokenRepository.DeleteExpired: reading affected rows: %w", err)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+func TestRefreshTokenRepository_Create(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta("INSERT INTO refresh_tokens (user_id, family, token_hash, created_at, expires_at) VALUES (?, ?, ?, ?, ?)")
+	token := domain.RefreshToken{User: domain.User{ID: 7}, Family: "fam", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	t.Run("should store the token hash", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(7, "fam", "abc123", "2026-10-18 09:00:00", "2026-10-18 10:00:00").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		if err := NewRefreshTokenRepository(db).Create(t.Context(), token, "abc123"); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	})
+
+	t.Run("should return error when insert fails", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(sql.ErrConnDone)
+
+		if err := NewRefreshTokenRepository(db).Create(t.Context(), token, "abc123"); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRefreshTokenRepository_Get(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta("FROM refresh_tokens t JOIN users u ON u.id = t.user_id") + `\s+` +
+		regexp.QuoteMeta("WHERE t.token_hash = ?")
+	columns := append([]string{"id", "family", "created_at", "expires_at", "used_at"}, userColumnNames...)
+
+	t.Run("should return the token and its user", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).AddRow(3, "fam", now, now.Add(time.Hour), now, 7, "ada@example.com", "Ada", now, now)
+		mock.ExpectQuery(query).WithArgs("abc123").WillReturnRows(rows)
+
+		token, err := NewRefreshTokenRepository(db).Get(t.Context(), "abc123")
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		expected := domain.RefreshToken{
+			ID:        3,
+			User:      domain.User{ID: 7, Email: "ada@example.com", Name: "Ada", CreatedAt: now, UpdatedAt: now},
+			Family:    "fam",
+			CreatedAt: now,
+			ExpiresAt: now.Add(time.Hour),
+			UsedAt:    &now,
+		}
+		if !reflect.DeepEqual(token, expected) {
+			t.Fatalf("expected token %+v but got %+v", expected, token)
+		}
+	})
+
+	t.Run("should return not found", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(sql.ErrNoRows)
+
+		if _, err := NewRefreshTokenRepository(db).Get(t.Context(), "abc123"); !errors.Is(err, domain.ErrRefreshTokenNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrRefreshTokenNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRefreshTokenRepository_MarkUsed(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta("UPDATE refresh_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL")
+
+	t.Run("should stamp an unused token", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs("2026-10-18 09:00:00", 3).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		if err := NewRefreshTokenRepository(db).MarkUsed(t.Context(), 3, now); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	})
+
+	t.Run("should report a token already used", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		if err := NewRefreshTokenRepository(db).MarkUsed(t.Context(), 3, now); !errors.Is(err, domain.ErrRefreshTokenReused) {
+			t.Fatalf("expected error %v but got %v", domain.ErrRefreshTokenReused, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRefreshTokenRepository_DeleteFamily(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	query := regexp.QuoteMeta("DELETE FROM refresh_tokens WHERE family = ?")
+
+	t.Run("should delete the family", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs("fam").WillReturnResult(sqlmock.NewResult(0, 2))
+
+		if err := NewRefreshTokenRepository(db).DeleteFamily(t.Context(), "fam"); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	})
+
+	t.Run("should return error when delete fails", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(sql.ErrConnDone)
+
+		if err := NewRefreshTokenRepository(db).DeleteFamily(t.Context(), "fam"); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRefreshTokenRepository_DeleteExpired(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM refresh_tokens WHERE expires_at <= ?")).
+		WithArgs("2026-10-18 09:00:00").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	n, err := NewRefreshTokenRepository(db).DeleteExpired(t.Context(), now)
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 purged tokens, got %d and %v", n, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
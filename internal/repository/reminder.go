@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+// ReminderRepository implements domain.ReminderRepository.
+type ReminderRepository struct {
+	db *sql.DB
+}
+
+// NewReminderRepository creates a new ReminderRepository.
+func NewReminderRepository(db *sql.DB) *ReminderRepository {
+	return &ReminderRepository{db: db}
+}
+
+// Schedule creates the missing reminders of open tasks due after now. The
+// unique (task_id, due_at) key makes it safe to run repeatedly.
+func (r *ReminderRepository) Schedule(ctx context.Context, offset time.Duration, now time.Time) error {
+	q := "INSERT INTO reminders (task_id, due_at, remind_at) " +
+		"SELECT id, due_at, datetime(due_at, ?) FROM tasks WHERE NOT done AND deleted_at IS NULL AND due_at > ? " +
+		"ON CONFLICT (task_id, due_at) DO NOTHING"
+	modifier := fmt.Sprintf("%+d seconds", -int64(offset/time.Second))
+	if _, err := conn(ctx, r.db).ExecContext(ctx, q, modifier, formatTimestamp(now)); err != nil {
+		return fmt.Errorf("ReminderRepository.Schedule: inserting: %w", err)
+	}
+	return nil
+}
+
+// Pending returns the reminders ready to fire, oldest first. Reminders of
+// tasks in the trash wait, and fire if the task is restored in time.
+func (r *ReminderRepository) Pending(ctx context.Context, now time.Time, limit int) ([]domain.Reminder, error) {
+	q := "SELECT reminders.id, reminders.task_id, tasks.title, reminders.due_at, reminders.remind_at " +
+		"FROM reminders JOIN tasks ON tasks.id = reminders.task_id " +
+		"WHERE reminders.fired_at IS NULL AND reminders.remind_at <= ? AND NOT tasks.done AND tasks.deleted_at IS NULL AND tasks.due_at = reminders.due_at " +
+		"ORDER BY reminders.remind_at, reminders.id LIMIT ?"
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, formatTimestamp(now), limit)
+	if err != nil {
+		return nil, fmt.Errorf("ReminderRepository.Pending: querying: %w", err)
+	}
+	defer rows.Close()
+
+	reminders := []domain.Reminder{}
+	for rows.Next() {
+		var rem domain.Reminder
+		if err := rows.Scan(&rem.ID, &rem.TaskID, &rem.TaskTitle, &rem.DueAt, &rem.RemindAt); err != nil {
+			return nil, fmt.Errorf("ReminderRepository.Pending: scanning row: %w", err)
+		}
+		reminders = append(reminders, rem)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ReminderRepository.Pending: iterating rows: %w", err)
+	}
+
+	return reminders, nil
+}
+
+// MarkFired stamps fired_at unless another run did so first.
+func (r *ReminderRepository) MarkFired(ctx context.Context, id int64, at time.Time) (bool, error) {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		"UPDATE reminders SET fired_at = ? WHERE id = ? AND fired_at IS NULL", formatTimestamp(at), id)
+	if err != nil {
+		return false, fmt.Errorf("ReminderRepository.MarkFired: updating: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("ReminderRepository.MarkFired: reading affected rows: %w", err)
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+func TestReminderRepository_Schedule(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta("INSERT INTO reminders (task_id, due_at, remind_at) " +
+		"SELECT id, due_at, datetime(due_at, ?) FROM tasks WHERE NOT done AND deleted_at IS NULL AND due_at > ? " +
+		"ON CONFLICT (task_id, due_at) DO NOTHING")
+
+	t.Run("should schedule reminders offset before the due time", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs("-900 seconds", "2026-10-18 09:00:00").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		if err := NewReminderRepository(db).Schedule(t.Context(), 15*time.Minute, now); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	})
+
+	t.Run("should return error when insert fails", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(sql.ErrConnDone)
+
+		if err := NewReminderRepository(db).Schedule(t.Context(), time.Hour, now); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReminderRepository_Pending(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	dueAt := now.Add(10 * time.Minute)
+	remindAt := now.Add(-5 * time.Minute)
+	query := regexp.QuoteMeta("SELECT reminders.id, reminders.task_id, tasks.title, reminders.due_at, reminders.remind_at " +
+		"FROM reminders JOIN tasks ON tasks.id = reminders.task_id " +
+		"WHERE reminders.fired_at IS NULL AND reminders.remind_at <= ? AND NOT tasks.done AND tasks.deleted_at IS NULL AND tasks.due_at = reminders.due_at " +
+		"ORDER BY reminders.remind_at, reminders.id LIMIT ?")
+	columns := []string{"id", "task_id", "title", "due_at", "remind_at"}
+
+	t.Run("should return reminders ready to fire", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).AddRow(3, 7, "Ship it", dueAt, remindAt)
+		mock.ExpectQuery(query).WithArgs("2026-10-18 09:00:00", 50).WillReturnRows(rows)
+
+		reminders, err := NewReminderRepository(db).Pending(t.Context(), now, 50)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		expected := []domain.Reminder{{ID: 3, TaskID: 7, TaskTitle: "Ship it", DueAt: dueAt, RemindAt: remindAt}}
+		if !reflect.DeepEqual(reminders, expected) {
+			t.Fatalf("expected reminders %v but got %v", expected, reminders)
+		}
+	})
+
+	t.Run("should return empty list", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(columns))
+
+		reminders, err := NewReminderRepository(db).Pending(t.Context(), now, 50)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if reminders == nil || len(reminders) != 0 {
+			t.Fatalf("expected empty list but got %v", reminders)
+		}
+	})
+
+	t.Run("should return error when query fails", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
+
+		if _, err := NewReminderRepository(db).Pending(t.Context(), now, 50); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReminderRepository_MarkFired(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta("UPDATE reminders SET fired_at = ? WHERE id = ? AND fired_at IS NULL")
+
+	t.Run("should claim an unfired reminder", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs("2026-10-18 09:00:00", int64(3)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		claimed, err := NewReminderRepository(db).MarkFired(t.Context(), 3, now)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if !claimed {
+			t.Fatal("expected the reminder to be claimed")
+		}
+	})
+
+	t.Run("should not claim a reminder that already fired", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs("2026-10-18 09:00:00", int64(3)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		claimed, err := NewReminderRepository(db).MarkFired(t.Context(), 3, now)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if claimed {
+			t.Fatal("expected the reminder not to be claimed")
+		}
+	})
+
+	t.Run("should return error when update fails", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(sql.ErrConnDone)
+
+		if _, err := NewReminderRepository(db).MarkFired(t.Context(), 3, now); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
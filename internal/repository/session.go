@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+// SessionRepository implements domain.SessionRepository.
+type SessionRepository struct {
+	db *sql.DB
+}
+
+// NewSessionRepository creates a new SessionRepository.
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Create inserts a new session.
+func (r *SessionRepository) Create(ctx context.Context, session domain.Session, tokenHash string) error {
+	q := "INSERT INTO sessions (user_id, token_hash, created_at, expires_at) VALUES (?, ?, ?, ?)"
+	_, err := conn(ctx, r.db).ExecContext(ctx, q,
+		session.User.ID, tokenHash, formatTimestamp(session.CreatedAt), formatTimestamp(session.ExpiresAt))
+	if err != nil {
+		return fmt.Errorf("SessionRepository.Create: inserting: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a live session and its user by the hash of its token.
+func (r *SessionRepository) Get(ctx context.Context, tokenHash string, now time.Time) (domain.Session, error) {
+	q := `SELECT u.id, u.email, u.name, u.created_at, u.updated_at, s.created_at, s.expires_at
+		FROM sessions s JOIN users u ON u.id = s.user_id
+		WHERE s.token_hash = ? AND s.expires_at > ?`
+	var (
+		session domain.Session
+		u       = &session.User
+	)
+	err := conn(ctx, r.db).QueryRowContext(ctx, q, tokenHash, formatTimestamp(now)).
+		Scan(&u.ID, &u.Email, &u.Name, &u.CreatedAt, &u.UpdatedAt, &session.CreatedAt, &session.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Session{}, fmt.Errorf("SessionRepository.Get: %w", domain.ErrSessionNotFound)
+	}
+	if err != nil {
+		return domain.Session{}, fmt.Errorf("SessionRepository.Get: querying: %w", err)
+	}
+
+	return session, nil
+}
+
+// Delete removes a session by the hash of its token. Unknown tokens are
+// reported as domain.ErrSessionNotFound.
+func (r *SessionRepository) Delete(ctx context.Context, tokenHash string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM sessions WHERE token_hash = ?", tokenHash)
+	if err != nil {
+		return fmt.Errorf("SessionRepository.Delete: deleting: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("SessionRepository.Delete: reading affected rows: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("SessionRepository.Delete: %w", domain.ErrSessionNotFound)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes the sessions that expired at or before now.
+func (r *SessionRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM sessions WHERE expires_at <= ?", formatTimestamp(now))
+	if err != nil {
+		return 0, fmt.Errorf("SessionRepository.DeleteExpired: deleting: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("SessionRepository.DeleteExpired: reading affected rows: %w", err)
+	}
+
+	return n, nil
+}
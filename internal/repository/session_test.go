@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+func TestSessionRepository_Create(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta("INSERT INTO sessions (user_id, token_hash, created_at, expires_at) VALUES (?, ?, ?, ?)")
+	session := domain.Session{User: domain.User{ID: 7}, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	t.Run("should store the token hash", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(7, "abc123", "2026-10-18 09:00:00", "2026-10-18 10:00:00").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		if err := NewSessionRepository(db).Create(t.Context(), session, "abc123"); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	})
+
+	t.Run("should return error when insert fails", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(sql.ErrConnDone)
+
+		if err := NewSessionRepository(db).Create(t.Context(), session, "abc123"); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSessionRepository_Get(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta("FROM sessions s JOIN users u ON u.id = s.user_id") + `\s+` +
+		regexp.QuoteMeta("WHERE s.token_hash = ? AND s.expires_at > ?")
+	columns := append(userColumnNames, "created_at", "expires_at")
+
+	t.Run("should return the live session and its user", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).AddRow(7, "ada@example.com", "Ada", now, now, now, now.Add(time.Hour))
+		mock.ExpectQuery(query).WithArgs("abc123", "2026-10-18 09:00:00").WillReturnRows(rows)
+
+		session, err := NewSessionRepository(db).Get(t.Context(), "abc123", now)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		expected := domain.Session{
+			User:      domain.User{ID: 7, Email: "ada@example.com", Name: "Ada", CreatedAt: now, UpdatedAt: now},
+			CreatedAt: now,
+			ExpiresAt: now.Add(time.Hour),
+		}
+		if !reflect.DeepEqual(session, expected) {
+			t.Fatalf("expected session %v but got %v", expected, session)
+		}
+	})
+
+	t.Run("should return not found for unknown or expired tokens", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(sql.ErrNoRows)
+
+		if _, err := NewSessionRepository(db).Get(t.Context(), "abc123", now); !errors.Is(err, domain.ErrSessionNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrSessionNotFound, err)
+		}
+	})
+
+	t.Run("should return error when query fails", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
+
+		if _, err := NewSessionRepository(db).Get(t.Context(), "abc123", now); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSessionRepository_Delete(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	query := regexp.QuoteMeta("DELETE FROM sessions WHERE token_hash = ?")
+
+	t.Run("should delete the session", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs("abc123").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		if err := NewSessionRepository(db).Delete(t.Context(), "abc123"); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	})
+
+	t.Run("should return not found", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		if err := NewSessionRepository(db).Delete(t.Context(), "abc123"); !errors.Is(err, domain.ErrSessionNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrSessionNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSessionRepository_DeleteExpired(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta("DELETE FROM sessions WHERE expires_at <= ?")
+
+	t.Run("should report how many sessions were purged", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs("2026-10-18 09:00:00").WillReturnResult(sqlmock.NewResult(0, 3))
+
+		n, err := NewSessionRepository(db).DeleteExpired(t.Context(), now)
+		if err != nil || n != 3 {
+			t.Fatalf("expected 3 purged sessions, got %d and %v", n, err)
+		}
+	})
+
+	t.Run("should return error when delete fails", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(sql.ErrConnDone)
+
+		if _, err := NewSessionRepository(db).DeleteExpired(t.Context(), now); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
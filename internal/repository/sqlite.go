@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// isUniqueViolation reports whether err was caused by a UNIQUE constraint.
+func isUniqueViolation(err error) bool {
+	return hasExtendedCode(err, sqlite3.ErrConstraintUnique)
+}
+
+// isForeignKeyViolation reports whether err was caused by a FOREIGN KEY
+// constraint, such as a reference to a row that does not exist.
+func isForeignKeyViolation(err error) bool {
+	return hasExtendedCode(err, sqlite3.ErrConstraintForeignKey)
+}
+
+func hasExtendedCode(err error, code sqlite3.ErrNoExtended) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == code
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+const tagColumns = "id, name, created_at, updated_at"
+
+// touchTaggedTasks touches every task carrying a tag, since the
+// tags embedded in those tasks change with it. It takes the tag ID and the
+// owner, so that only tasks of the tag's tenant are touched.
+const touchTaggedTasks = "UPDATE tasks SET " + taskTouch + " " +
+	"WHERE id IN (SELECT task_id FROM task_tags WHERE tag_id = ?) AND " + ownerCondition
+
+// TagRepository provides access to tag storage and task assignments. Tags
+// belong to the tenant of ctx like tasks do; those of other tenants behave as
+// if they did not exist.
+type TagRepository struct {
+	db *sql.DB
+}
+
+// NewTagRepository creates a new TagRepository.
+func NewTagRepository(db *sql.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+func scanTag(s rowScanner) (domain.Tag, error) {
+	var tag domain.Tag
+	err := s.Scan(&tag.ID, &tag.Name, &tag.CreatedAt, &tag.UpdatedAt)
+	return tag, err
+}
+
+// GetAll retrieves every tag of the tenant, ordered by name.
+func (r *TagRepository) GetAll(ctx context.Context) ([]domain.Tag, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		"SELECT "+tagColumns+" FROM tags WHERE "+ownerCondition+" ORDER BY name, id", tenantOwner(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("TagRepository.GetAll: querying: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []domain.Tag{}
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf("TagRepository.GetAll: scanning row: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("TagRepository.GetAll: iterating rows: %w", err)
+	}
+
+	return tags, nil
+}
+
+// GetByID retrieves a single tag by its ID.
+func (r *TagRepository) GetByID(ctx context.Context, id int64) (domain.Tag, error) {
+	tag, err := scanTag(conn(ctx, r.db).QueryRowContext(ctx, "SELECT "+tagColumns+" FROM tags WHERE id = ? AND "+ownerCondition, id, tenantOwner(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Tag{}, fmt.Errorf("TagRepository.GetByID: %w", domain.ErrTagNotFound)
+	}
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("TagRepository.GetByID: scanning row: %w", err)
+	}
+
+	return tag, nil
+}
+
+// Create inserts a new tag owned by the tenant and returns it as stored.
+// Names are unique per tenant.
+func (r *TagRepository) Create(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+	q := "INSERT INTO tags (owner_id, name) VALUES (?, ?) RETURNING " + tagColumns
+	created, err := scanTag(conn(ctx, r.db).QueryRowContext(ctx, q, tenantOwner(ctx), tag.Name))
+	if isUniqueViolation(err) {
+		return domain.Tag{}, fmt.Errorf("TagRepository.Create: %w", domain.ErrTagAlreadyExists)
+	}
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("TagRepository.Create: inserting: %w", err)
+	}
+
+	return created, nil
+}
+
+// Update renames an existing tag.
+func (r *TagRepository) Update(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("TagRepository.Update: beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := "UPDATE tags SET name = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND " + ownerCondition + " RETURNING " + tagColumns
+	updated, err := scanTag(tx.QueryRowContext(ctx, q, tag.Name, tag.ID, tenantOwner(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Tag{}, fmt.Errorf("TagRepository.Update: %w", domain.ErrTagNotFound)
+	}
+	if isUniqueViolation(err) {
+		return domain.Tag{}, fmt.Errorf("TagRepository.Update: %w", domain.ErrTagAlreadyExists)
+	}
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("TagRepository.Update: updating: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, touchTaggedTasks, tag.ID, tenantOwner(ctx)); err != nil {
+		return domain.Tag{}, fmt.Errorf("TagRepository.Update: touching tasks: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Tag{}, fmt.Errorf("TagRepository.Update: committing: %w", err)
+	}
+
+	return updated, nil
+}
+
+// Delete removes a tag by its ID, detaching it from every task.
+func (r *TagRepository) Delete(ctx context.Context, id int64) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("TagRepository.Delete: beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, touchTaggedTasks, id, tenantOwner(ctx)); err != nil {
+		return fmt.Errorf("TagRepository.Delete: touching tasks: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM tags WHERE id = ? AND "+ownerCondition, id, tenantOwner(ctx))
+	if err != nil {
+		return fmt.Errorf("TagRepository.Delete: deleting: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("TagRepository.Delete: reading affected rows: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("TagRepository.Delete: %w", domain.ErrTagNotFound)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("TagRepository.Delete: committing: %w", err)
+	}
+
+	return nil
+}
+
+// Attach adds the tag to the task. The task's updated_at only changes when
+// the tag was not attached yet.
+func (r *TagRepository) Attach(ctx context.Context, taskID, tagID int64) error {
+	return r.assign(ctx, "TagRepository.Attach",
+		"INSERT INTO task_tags (task_id, tag_id) VALUES (?, ?) ON CONFLICT DO NOTHING", taskID, tagID)
+}
+
+// Detach removes the tag from the task. The task's updated_at only changes
+// when the tag was attached.
+func (r *TagRepository) Detach(ctx context.Context, taskID, tagID int64) error {
+	return r.assign(ctx, "TagRepository.Detach",
+		"DELETE FROM task_tags WHERE task_id = ? AND tag_id = ?", taskID, tagID)
+}
+
+// assign runs stmt, which inserts or deletes the (taskID, tagID) pair, after
+// checking that both sides exist. Tasks and tags of other tenants than the
+// one of ctx do not.
+func (r *TagRepository) assign(ctx context.Context, op, stmt string, taskID, tagID int64) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("%s: beginning transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var taskExists, tagExists bool
+	err = tx.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND "+ownerCondition+" AND deleted_at IS NULL), EXISTS (SELECT 1 FROM tags WHERE id = ? AND "+ownerCondition+")",
+		taskID, tenantOwner(ctx), tagID, tenantOwner(ctx),
+	).Scan(&taskExists, &tagExists)
+	if err != nil {
+		return fmt.Errorf("%s: checking existence: %w", op, err)
+	}
+	if !taskExists {
+		return fmt.Errorf("%s: %w", op, domain.ErrTaskNotFound)
+	}
+	if !tagExists {
+		return fmt.Errorf("%s: %w", op, domain.ErrTagNotFound)
+	}
+
+	res, err := tx.ExecContext(ctx, stmt, taskID, tagID)
+	if err != nil {
+		return fmt.Errorf("%s: writing assignment: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: reading affected rows: %w", op, err)
+	}
+	if n > 0 {
+		if _, err := tx.ExecContext(ctx, "UPDATE tasks SET "+taskTouch+" WHERE id = ? AND "+ownerCondition, taskID, tenantOwner(ctx)); err != nil {
+			return fmt.Errorf("%s: touching task: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: committing: %w", op, err)
+	}
+
+	return nil
+}
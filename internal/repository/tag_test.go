@@ -0,0 +1,471 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mattn/go-sqlite3"
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+var tagColumnNames = []string{"id", "name", "created_at", "updated_at"}
+
+func TestTagRepository_GetAll(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	query := regexp.QuoteMeta("SELECT id, name, created_at, updated_at FROM tags WHERE owner_id IS ? ORDER BY name, id")
+
+	t.Run("should return tags", func(t *testing.T) {
+		rows := sqlmock.NewRows(tagColumnNames).
+			AddRow(1, "backend", now, now).
+			AddRow(2, "urgent", now, now)
+		mock.ExpectQuery(query).WithArgs(nil).WillReturnRows(rows)
+
+		tags, err := NewTagRepository(db).GetAll(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		expected := []domain.Tag{
+			{ID: 1, Name: "backend", CreatedAt: now, UpdatedAt: now},
+			{ID: 2, Name: "urgent", CreatedAt: now, UpdatedAt: now},
+		}
+		if !reflect.DeepEqual(tags, expected) {
+			t.Fatalf("expected tags %v but got %v", expected, tags)
+		}
+	})
+
+	t.Run("should return empty list", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(nil).WillReturnRows(sqlmock.NewRows(tagColumnNames))
+
+		tags, err := NewTagRepository(db).GetAll(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if tags == nil || len(tags) != 0 {
+			t.Fatalf("expected empty list but got %v", tags)
+		}
+	})
+
+	t.Run("should return error when query fails", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(nil).WillReturnError(sql.ErrConnDone)
+
+		if _, err := NewTagRepository(db).GetAll(t.Context()); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTagRepository_GetByID(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	query := regexp.QuoteMeta("SELECT id, name, created_at, updated_at FROM tags WHERE id = ? AND owner_id IS ?")
+
+	t.Run("should return tag when it exists", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(1, nil).WillReturnRows(sqlmock.NewRows(tagColumnNames).AddRow(1, "backend", now, now))
+
+		tag, err := NewTagRepository(db).GetByID(t.Context(), 1)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if tag.Name != "backend" {
+			t.Fatalf("unexpected tag %v", tag)
+		}
+	})
+
+	t.Run("should return not found when no row matches", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(1, nil).WillReturnError(sql.ErrNoRows)
+
+		if _, err := NewTagRepository(db).GetByID(t.Context(), 1); !errors.Is(err, domain.ErrTagNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTagNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTagRepository_Create(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	query := regexp.QuoteMeta("INSERT INTO tags (owner_id, name) VALUES (?, ?) RETURNING id, name, created_at, updated_at")
+
+	t.Run("should return created tag", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(nil, "backend").WillReturnRows(sqlmock.NewRows(tagColumnNames).AddRow(3, "backend", now, now))
+
+		tag, err := NewTagRepository(db).Create(t.Context(), domain.Tag{Name: "backend"})
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		expected := domain.Tag{ID: 3, Name: "backend", CreatedAt: now, UpdatedAt: now}
+		if !reflect.DeepEqual(tag, expected) {
+			t.Fatalf("expected tag %v but got %v", expected, tag)
+		}
+	})
+
+	t.Run("should report duplicate names", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(nil, "backend").
+			WillReturnError(sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique})
+
+		if _, err := NewTagRepository(db).Create(t.Context(), domain.Tag{Name: "backend"}); !errors.Is(err, domain.ErrTagAlreadyExists) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTagAlreadyExists, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTagRepository_Update(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	query := regexp.QuoteMeta("UPDATE tags SET name = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND owner_id IS ? RETURNING id, name, created_at, updated_at")
+	touch := regexp.QuoteMeta("UPDATE tasks SET updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id IN (SELECT task_id FROM task_tags WHERE tag_id = ?) AND owner_id IS ?")
+
+	t.Run("should rename the tag and touch its tasks", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(query).WithArgs("frontend", 3, nil).WillReturnRows(sqlmock.NewRows(tagColumnNames).AddRow(3, "frontend", now, now))
+		mock.ExpectExec(touch).WithArgs(3, nil).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		tag, err := NewTagRepository(db).Update(t.Context(), domain.Tag{ID: 3, Name: "frontend"})
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if tag.Name != "frontend" {
+			t.Fatalf("unexpected tag %v", tag)
+		}
+	})
+
+	t.Run("should return not found when no row matches", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(query).WithArgs("frontend", 3, nil).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		if _, err := NewTagRepository(db).Update(t.Context(), domain.Tag{ID: 3, Name: "frontend"}); !errors.Is(err, domain.ErrTagNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTagNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTagRepository_Delete(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	touch := regexp.QuoteMeta("UPDATE tasks SET updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id IN (SELECT task_id FROM task_tags WHERE tag_id = ?) AND owner_id IS ?")
+	query := regexp.QuoteMeta("DELETE FROM tags WHERE id = ? AND owner_id IS ?")
+
+	t.Run("should delete existing tag", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(touch).WithArgs(1, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(query).WithArgs(1, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		if err := NewTagRepository(db).Delete(t.Context(), 1); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	})
+
+	t.Run("should return not found and roll back when nothing is deleted", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(touch).WithArgs(1, nil).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(query).WithArgs(1, nil).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		if err := NewTagRepository(db).Delete(t.Context(), 1); !errors.Is(err, domain.ErrTagNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTagNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTagRepository_Attach(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	exists := regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL), EXISTS (SELECT 1 FROM tags WHERE id = ? AND owner_id IS ?)")
+	insert := regexp.QuoteMeta("INSERT INTO task_tags (task_id, tag_id) VALUES (?, ?) ON CONFLICT DO NOTHING")
+	touch := regexp.QuoteMeta("UPDATE tasks SET updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND owner_id IS ?")
+	existsColumns := []string{"task_exists", "tag_exists"}
+
+	testCases := []struct {
+		name        string
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name: "should attach and touch the task",
+			setup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(exists).WithArgs(1, nil, 2, nil).WillReturnRows(sqlmock.NewRows(existsColumns).AddRow(true, true))
+				mock.ExpectExec(insert).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(touch).WithArgs(1, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "should not touch the task when already attached",
+			setup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(exists).WithArgs(1, nil, 2, nil).WillReturnRows(sqlmock.NewRows(existsColumns).AddRow(true, true))
+				mock.ExpectExec(insert).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "should return task not found",
+			setup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(exists).WithArgs(1, nil, 2, nil).WillReturnRows(sqlmock.NewRows(existsColumns).AddRow(false, true))
+				mock.ExpectRollback()
+			},
+			expectedErr: domain.ErrTaskNotFound,
+		},
+		{
+			name: "should return tag not found",
+			setup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(exists).WithArgs(1, nil, 2, nil).WillReturnRows(sqlmock.NewRows(existsColumns).AddRow(true, false))
+				mock.ExpectRollback()
+			},
+			expectedErr: domain.ErrTagNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setup()
+			err := NewTagRepository(db).Attach(t.Context(), 1, 2)
+
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+		})
+	}
+
+	t.Run("should return task not found for a task of another tenant", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(exists).WithArgs(1, 7, 2, 7).WillReturnRows(sqlmock.NewRows(existsColumns).AddRow(false, true))
+		mock.ExpectRollback()
+
+		err := NewTagRepository(db).Attach(domain.WithTenant(t.Context(), 7), 1, 2)
+		if !errors.Is(err, domain.ErrTaskNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTagRepository_Detach(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT EXISTS").WithArgs(1, nil, 2, nil).WillReturnRows(sqlmock.NewRows([]string{"task_exists", "tag_exists"}).AddRow(true, true))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM task_tags WHERE task_id = ? AND tag_id = ?")).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tasks SET updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND owner_id IS ?")).WithArgs(1, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := NewTagRepository(db).Detach(t.Context(), 1, 2); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestTagRepository_Isolation runs every method as tenant 7 against tag 2,
+// which belongs to another tenant, and checks that each statement is bound to
+// tenant 7 and that the tag behaves as if it did not exist.
+func TestTagRepository_Isolation(t *testing.T) {
+	t.Parallel()
+
+	const tenant = 7
+	exists := regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL), EXISTS (SELECT 1 FROM tags WHERE id = ? AND owner_id IS ?)")
+	touch := regexp.QuoteMeta("UPDATE tasks SET updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id IN (SELECT task_id FROM task_tags WHERE tag_id = ?) AND owner_id IS ?")
+	existsColumns := []string{"task_exists", "tag_exists"}
+
+	testCases := []struct {
+		name  string
+		setup func(mock sqlmock.Sqlmock)
+		call  func(ctx context.Context, r *TagRepository) error
+		// expectedErr is nil for the methods that list or create, which
+		// find nothing or write a tag of the tenant instead of failing.
+		expectedErr error
+	}{
+		{
+			name: "GetAll",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM tags WHERE owner_id IS ?")).WithArgs(tenant).WillReturnRows(sqlmock.NewRows(tagColumnNames))
+			},
+			call: func(ctx context.Context, r *TagRepository) error {
+				tags, err := r.GetAll(ctx)
+				if err == nil && len(tags) != 0 {
+					return fmt.Errorf("unexpected tags %v", tags)
+				}
+				return err
+			},
+		},
+		{
+			name: "GetByID",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM tags WHERE id = ? AND owner_id IS ?")).WithArgs(2, tenant).WillReturnRows(sqlmock.NewRows(tagColumnNames))
+			},
+			call: func(ctx context.Context, r *TagRepository) error {
+				_, err := r.GetByID(ctx, 2)
+				return err
+			},
+			expectedErr: domain.ErrTagNotFound,
+		},
+		{
+			name: "Create",
+			setup: func(mock sqlmock.Sqlmock) {
+				// Another tenant's tag with the same name does not conflict.
+				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO tags (owner_id, name) VALUES (?, ?)")).WithArgs(tenant, "work").
+					WillReturnRows(sqlmock.NewRows(tagColumnNames).AddRow(3, "work", time.Now(), time.Now()))
+			},
+			call: func(ctx context.Context, r *TagRepository) error {
+				_, err := r.Create(ctx, domain.Tag{Name: "work"})
+				return err
+			},
+		},
+		{
+			name: "Update",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE tags SET name = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND owner_id IS ?")).
+					WithArgs("work", 2, tenant).WillReturnRows(sqlmock.NewRows(tagColumnNames))
+				mock.ExpectRollback()
+			},
+			call: func(ctx context.Context, r *TagRepository) error {
+				_, err := r.Update(ctx, domain.Tag{ID: 2, Name: "work"})
+				return err
+			},
+			expectedErr: domain.ErrTagNotFound,
+		},
+		{
+			name: "Delete",
+			setup: func(mock sqlmock.Sqlmock) {
+				// The owner's tasks carrying the tag are left untouched.
+				mock.ExpectBegin()
+				mock.ExpectExec(touch).WithArgs(2, tenant).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM tags WHERE id = ? AND owner_id IS ?")).WithArgs(2, tenant).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			call: func(ctx context.Context, r *TagRepository) error {
+				return r.Delete(ctx, 2)
+			},
+			expectedErr: domain.ErrTagNotFound,
+		},
+		{
+			name: "Attach",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(exists).WithArgs(1, tenant, 2, tenant).WillReturnRows(sqlmock.NewRows(existsColumns).AddRow(true, false))
+				mock.ExpectRollback()
+			},
+			call: func(ctx context.Context, r *TagRepository) error {
+				return r.Attach(ctx, 1, 2)
+			},
+			expectedErr: domain.ErrTagNotFound,
+		},
+		{
+			name: "Detach",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(exists).WithArgs(1, tenant, 2, tenant).WillReturnRows(sqlmock.NewRows(existsColumns).AddRow(true, false))
+				mock.ExpectRollback()
+			},
+			call: func(ctx context.Context, r *TagRepository) error {
+				return r.Detach(ctx, 1, 2)
+			},
+			expectedErr: domain.ErrTagNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal("failed to create mock")
+			}
+			defer db.Close()
+			tc.setup(mock)
+
+			err = tc.call(domain.WithTenant(t.Context(), tenant), NewTagRepository(db))
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
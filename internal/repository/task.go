@@ -3,12 +3,44 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/mkeOrt/tasks-go/internal/domain"
 )
 
-// TaskRepository provides access to task storage.
+const taskColumns = "id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " +
+	taskBlockedColumn
+
+// taskBlockedColumn computes whether a task still waits on an open blocker.
+// Blockers in the trash no longer count.
+const taskBlockedColumn = "EXISTS (SELECT 1 FROM task_dependencies JOIN tasks AS blockers ON blockers.id = task_dependencies.blocker_id " +
+	"WHERE task_dependencies.task_id = tasks.id AND NOT blockers.done AND blockers.deleted_at IS NULL) AS blocked"
+
+// completedAtAssignment keeps completed_at in step with done: it is stamped
+// when a task becomes done, kept while it stays done and cleared otherwise.
+// It takes the new done value as its only argument.
+const completedAtAssignment = "completed_at = CASE WHEN ? THEN COALESCE(completed_at, CURRENT_TIMESTAMP) END"
+
+// taskTouch marks a task as changed: it stamps updated_at and bumps the
+// version that optimistic locking compares against.
+const taskTouch = "updated_at = CURRENT_TIMESTAMP, version = version + 1"
+
+// ownerCondition scopes a statement to the rows of the tenant, bound with
+// tenantOwner as its only argument.
+const ownerCondition = "owner_id IS ?"
+
+// taskExistsQuery checks that a task of the tenant exists outside the trash.
+// It takes the task ID and tenantOwner.
+const taskExistsQuery = "SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND " + ownerCondition + " AND deleted_at IS NULL)"
+
+// TaskRepository provides access to task storage. Tasks in the trash are
+// invisible to it: they are neither returned nor modified, and referring to
+// one fails as if it did not exist. So are the tasks of other tenants than
+// the one of the context, which keeps their existence from leaking.
 type TaskRepository struct {
 	db *sql.DB
 }
@@ -18,10 +50,75 @@ func NewTaskRepository(db *sql.DB) *TaskRepository {
 	return &TaskRepository{db: db}
 }
 
-// GetAll retrieves all tasks from the database.
-func (r *TaskRepository) GetAll(ctx context.Context) ([]domain.Task, error) {
-	q := "SELECT id, title, done, created_at, updated_at FROM tasks"
-	rows, err := r.db.QueryContext(ctx, q)
+// tenantOwner returns the owner of the rows ctx reaches, for binding to
+// ownerCondition: its tenant, or NULL for anonymous callers, who share the
+// rows without an owner. IS matches NULL like any other value.
+func tenantOwner(ctx context.Context) any {
+	if ownerID, ok := domain.TenantFrom(ctx); ok {
+		return ownerID
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTask scans the taskColumns of a row, followed by any extra columns
+// into extra.
+func scanTask(s rowScanner, extra ...any) (domain.Task, error) {
+	var (
+		task                        domain.Task
+		startAt, dueAt, completedAt sql.NullTime
+		projectID, parentID         sql.NullInt64
+	)
+	dest := []any{
+		&task.ID, &task.Title, &task.Description, &task.Done, &task.Priority,
+		&startAt, &dueAt, &projectID, &parentID, &task.Recurrence, &completedAt, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.Blocked,
+	}
+	if err := s.Scan(append(dest, extra...)...); err != nil {
+		return domain.Task{}, err
+	}
+
+	task.StartAt = timePtr(startAt)
+	task.DueAt = timePtr(dueAt)
+	task.ProjectID = int64Ptr(projectID)
+	task.ParentID = int64Ptr(parentID)
+	task.CompletedAt = timePtr(completedAt)
+	return task, nil
+}
+
+// GetAll retrieves the tasks matching filter, in the requested order.
+// When filter.Cursor is set the page starts right after (or, for backward
+// cursors, ends right before) the cursor position and Offset is ignored.
+func (r *TaskRepository) GetAll(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+	where, args := buildTaskWhere(ctx, filter)
+	sort := filter.Sort
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+	if filter.Cursor != nil {
+		seek, seekArgs, err := buildTaskSeek(*filter.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("TaskRepository.GetAll: %w", err)
+		}
+		where = appendCondition(where, seek)
+		args = append(args, seekArgs...)
+		sort = filter.Cursor.Sort
+	}
+	if backward {
+		sort.Desc = !sort.Desc
+	}
+
+	q := "SELECT " + taskColumns + " FROM tasks" + where + buildTaskOrderBy(sort)
+	if filter.Limit > 0 {
+		q += " LIMIT ?"
+		args = append(args, filter.Limit)
+		if filter.Cursor == nil {
+			q += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, fmt.Errorf("TaskRepository.GetAll: querying: %w", err)
 	}
@@ -29,8 +126,8 @@ func (r *TaskRepository) GetAll(ctx context.Context) ([]domain.Task, error) {
 
 	var tasks []domain.Task
 	for rows.Next() {
-		var task domain.Task
-		if err := rows.Scan(&task.ID, &task.Title, &task.Done, &task.CreatedAt, &task.UpdatedAt); err != nil {
+		task, err := scanTask(rows)
+		if err != nil {
 			return nil, fmt.Errorf("TaskRepository.GetAll: scanning row: %w", err)
 		}
 		tasks = append(tasks, task)
@@ -44,5 +141,1006 @@ func (r *TaskRepository) GetAll(ctx context.Context) ([]domain.Task, error) {
 		return []domain.Task{}, nil
 	}
 
+	if backward {
+		slices.Reverse(tasks)
+	}
+
+	if err := r.loadTags(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("TaskRepository.GetAll: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// Count returns the number of tasks matching filter, ignoring pagination.
+func (r *TaskRepository) Count(ctx context.Context, filter domain.TaskFilter) (int, error) {
+	where, args := buildTaskWhere(ctx, filter)
+	var total int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks"+where, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("TaskRepository.Count: querying: %w", err)
+	}
+
+	return total, nil
+}
+
+// Search runs a full-text query against task titles and descriptions and returns the matches
+// ranked by relevance. Only the filter's conditions and pagination are used;
+// its sort and cursor are ignored.
+func (r *TaskRepository) Search(ctx context.Context, query string, filter domain.TaskFilter) ([]domain.TaskSearchResult, error) {
+	where, whereArgs := buildTaskWhere(ctx, filter)
+	q := "SELECT " + taskColumns + ", score, snippet FROM (" +
+		"SELECT rowid AS match_id, -bm25(tasks_fts) AS score, " +
+		"snippet(tasks_fts, -1, '<mark>', '</mark>', '…', 16) AS snippet " +
+		"FROM tasks_fts WHERE tasks_fts MATCH ?" +
+		") JOIN tasks ON tasks.id = match_id" + where +
+		" ORDER BY score DESC, id ASC"
+	args := append([]any{query}, whereArgs...)
+	if filter.Limit > 0 {
+		q += " LIMIT ? OFFSET ?"
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, args...)
+	if err != nil {
+		if isFTSQueryError(err) {
+			return nil, fmt.Errorf("TaskRepository.Search: %w: %w", domain.ErrInvalidSearchQuery, err)
+		}
+		return nil, fmt.Errorf("TaskRepository.Search: querying: %w", err)
+	}
+	defer rows.Close()
+
+	results := []domain.TaskSearchResult{}
+	for rows.Next() {
+		var res domain.TaskSearchResult
+		task, err := scanTask(rows, &res.Score, &res.Snippet)
+		if err != nil {
+			return nil, fmt.Errorf("TaskRepository.Search: scanning row: %w", err)
+		}
+		res.Task = task
+		results = append(results, res)
+	}
+
+	if err := rows.Err(); err != nil {
+		if isFTSQueryError(err) {
+			return nil, fmt.Errorf("TaskRepository.Search: %w: %w", domain.ErrInvalidSearchQuery, err)
+		}
+		return nil, fmt.Errorf("TaskRepository.Search: iterating rows: %w", err)
+	}
+
+	tasks := make([]domain.Task, len(results))
+	for i, res := range results {
+		tasks[i] = res.Task
+	}
+	if err := r.loadTags(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("TaskRepository.Search: %w", err)
+	}
+	for i := range results {
+		results[i].Task = tasks[i]
+	}
+
+	return results, nil
+}
+
+// GetByID retrieves a single task by its ID.
+func (r *TaskRepository) GetByID(ctx context.Context, id int64) (domain.Task, error) {
+	q := "SELECT " + taskColumns + " FROM tasks WHERE id = ? AND " + ownerCondition + " AND deleted_at IS NULL"
+	task, err := scanTask(conn(ctx, r.db).QueryRowContext(ctx, q, id, tenantOwner(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Task{}, fmt.Errorf("TaskRepository.GetByID: %w", domain.ErrTaskNotFound)
+	}
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.GetByID: scanning row: %w", err)
+	}
+
+	tags, err := r.tagsByTask(ctx, task.ID)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.GetByID: %w", err)
+	}
+	task.Tags = tags[task.ID]
+
+	return task, nil
+}
+
+// Create inserts a new task owned by the tenant of ctx and returns it as
+// stored.
+func (r *TaskRepository) Create(ctx context.Context, task domain.Task) (domain.Task, error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Create: beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if task.ProjectID != nil {
+		if err := checkProject(ctx, tx, *task.ProjectID); err != nil {
+			return domain.Task{}, fmt.Errorf("TaskRepository.Create: %w", err)
+		}
+	}
+	if task.ParentID != nil {
+		if err := checkParent(ctx, tx, 0, *task.ParentID); err != nil {
+			return domain.Task{}, fmt.Errorf("TaskRepository.Create: %w", err)
+		}
+	}
+
+	q := "INSERT INTO tasks (title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, owner_id) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, CASE WHEN ? THEN CURRENT_TIMESTAMP END, ?) RETURNING " + taskColumns
+	created, err := scanTask(tx.QueryRowContext(ctx, q,
+		task.Title, task.Description, task.Done, task.Priority,
+		nullableTimestamp(task.StartAt), nullableTimestamp(task.DueAt), task.ProjectID, task.ParentID, task.Recurrence, task.Done,
+		tenantOwner(ctx),
+	))
+	if isForeignKeyViolation(err) {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Create: %w", domain.ErrProjectNotFound)
+	}
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Create: inserting: %w", err)
+	}
+	if err := recordTaskEvent(ctx, tx, created.ID, domain.TaskEventCreated, createdChanges(created)); err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Create: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Create: committing: %w", err)
+	}
+
+	return created, nil
+}
+
+// Update replaces the mutable fields of an existing task.
+func (r *TaskRepository) Update(ctx context.Context, task domain.Task) (domain.Task, error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Update: beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := snapshotTask(ctx, tx, task.ID, task.Version)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Update: %w", err)
+	}
+	if task.ProjectID != nil {
+		if err := checkProject(ctx, tx, *task.ProjectID); err != nil {
+			return domain.Task{}, fmt.Errorf("TaskRepository.Update: %w", err)
+		}
+	}
+	if task.ParentID != nil {
+		if err := checkParent(ctx, tx, task.ID, *task.ParentID); err != nil {
+			return domain.Task{}, fmt.Errorf("TaskRepository.Update: %w", err)
+		}
+	}
+	if task.Done {
+		if err := checkCompletable(ctx, tx, task.ID); err != nil {
+			return domain.Task{}, fmt.Errorf("TaskRepository.Update: %w", err)
+		}
+	}
+
+	q := "UPDATE tasks SET title = ?, description = ?, done = ?, priority = ?, start_at = ?, due_at = ?, project_id = ?, parent_id = ?, recurrence = ?, " +
+		completedAtAssignment + ", " + taskTouch + " WHERE id = ? AND version = ? AND " + ownerCondition + " AND deleted_at IS NULL RETURNING " + taskColumns
+	updated, err := scanTask(tx.QueryRowContext(ctx, q,
+		task.Title, task.Description, task.Done, task.Priority,
+		nullableTimestamp(task.StartAt), nullableTimestamp(task.DueAt), task.ProjectID, task.ParentID, task.Recurrence, task.Done,
+		task.ID, before.Version, tenantOwner(ctx),
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Update: %w", domain.ErrTaskVersionConflict)
+	}
+	if isForeignKeyViolation(err) {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Update: %w", domain.ErrProjectNotFound)
+	}
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Update: updating: %w", err)
+	}
+	if err := recordUpdate(ctx, tx, before, updated); err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Update: %w", err)
+	}
+	if before.Done != updated.Done {
+		if err := touchDependents(ctx, tx, []int64{updated.ID}); err != nil {
+			return domain.Task{}, fmt.Errorf("TaskRepository.Update: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Update: committing: %w", err)
+	}
+
+	tags, err := r.tagsByTask(ctx, updated.ID)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Update: %w", err)
+	}
+	updated.Tags = tags[updated.ID]
+
+	return updated, nil
+}
+
+// Patch updates only the fields set in patch.
+func (r *TaskRepository) Patch(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error) {
+	var (
+		sets []string
+		args []any
+	)
+	if patch.Title != nil {
+		sets = append(sets, "title = ?")
+		args = append(args, *patch.Title)
+	}
+	if patch.Description != nil {
+		sets = append(sets, "description = ?")
+		args = append(args, *patch.Description)
+	}
+	if patch.Done != nil {
+		sets = append(sets, "done = ?", completedAtAssignment)
+		args = append(args, *patch.Done, *patch.Done)
+	}
+	if patch.Priority != nil {
+		sets = append(sets, "priority = ?")
+		args = append(args, *patch.Priority)
+	}
+	if patch.StartAt.Set {
+		sets = append(sets, "start_at = ?")
+		args = append(args, nullableTimestamp(patch.StartAt.Time))
+	}
+	if patch.DueAt.Set {
+		sets = append(sets, "due_at = ?")
+		args = append(args, nullableTimestamp(patch.DueAt.Time))
+	}
+	if patch.ProjectID.Set {
+		sets = append(sets, "project_id = ?")
+		args = append(args, patch.ProjectID.ID)
+	}
+	if patch.ParentID.Set {
+		sets = append(sets, "parent_id = ?")
+		args = append(args, patch.ParentID.ID)
+	}
+	if patch.Recurrence != nil {
+		sets = append(sets, "recurrence = ?")
+		args = append(args, *patch.Recurrence)
+	}
+	sets = append(sets, taskTouch)
+
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Patch: beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := snapshotTask(ctx, tx, id, patch.Version)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Patch: %w", err)
+	}
+	if patch.ProjectID.ID != nil {
+		if err := checkProject(ctx, tx, *patch.ProjectID.ID); err != nil {
+			return domain.Task{}, fmt.Errorf("TaskRepository.Patch: %w", err)
+		}
+	}
+	if patch.ParentID.ID != nil {
+		if err := checkParent(ctx, tx, id, *patch.ParentID.ID); err != nil {
+			return domain.Task{}, fmt.Errorf("TaskRepository.Patch: %w", err)
+		}
+	}
+	if patch.Done != nil && *patch.Done {
+		if err := checkCompletable(ctx, tx, id); err != nil {
+			return domain.Task{}, fmt.Errorf("TaskRepository.Patch: %w", err)
+		}
+	}
+
+	q := "UPDATE tasks SET " + strings.Join(sets, ", ") + " WHERE id = ? AND version = ? AND " + ownerCondition + " AND deleted_at IS NULL RETURNING " + taskColumns
+	patched, err := scanTask(tx.QueryRowContext(ctx, q, append(args, id, before.Version, tenantOwner(ctx))...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Patch: %w", domain.ErrTaskVersionConflict)
+	}
+	if isForeignKeyViolation(err) {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Patch: %w", domain.ErrProjectNotFound)
+	}
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Patch: updating: %w", err)
+	}
+	if err := recordUpdate(ctx, tx, before, patched); err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Patch: %w", err)
+	}
+	if before.Done != patched.Done {
+		if err := touchDependents(ctx, tx, []int64{patched.ID}); err != nil {
+			return domain.Task{}, fmt.Errorf("TaskRepository.Patch: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Patch: committing: %w", err)
+	}
+
+	tags, err := r.tagsByTask(ctx, patched.ID)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TaskRepository.Patch: %w", err)
+	}
+	patched.Tags = tags[patched.ID]
+
+	return patched, nil
+}
+
+// Delete moves a task and its subtasks to the trash. They are stamped with
+// the same deletion ID, which is how Restore brings them back together. A
+// non-zero version must match the task's; its subtasks are not checked.
+func (r *TaskRepository) Delete(ctx context.Context, id, version int64) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("TaskRepository.Delete: beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := snapshotTask(ctx, tx, id, version)
+	if err != nil {
+		return fmt.Errorf("TaskRepository.Delete: %w", err)
+	}
+	deletionID, err := nextDeletionID(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("TaskRepository.Delete: %w", err)
+	}
+
+	q := "WITH RECURSIVE subtree(task_id) AS (" +
+		"SELECT id FROM tasks WHERE id = ? AND version = ? AND " + ownerCondition + " AND deleted_at IS NULL " +
+		"UNION ALL SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.task_id WHERE tasks.deleted_at IS NULL" +
+		") UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP, deletion_id = ?, " + taskTouch + " WHERE id IN (SELECT task_id FROM subtree) RETURNING id"
+	ids, err := queryIDs(ctx, tx, q, id, before.Version, tenantOwner(ctx), deletionID)
+	if err != nil {
+		return fmt.Errorf("TaskRepository.Delete: deleting: %w", err)
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("TaskRepository.Delete: %w", domain.ErrTaskVersionConflict)
+	}
+	for _, deleted := range ids {
+		if err := recordTaskEvent(ctx, tx, deleted, domain.TaskEventDeleted, nil); err != nil {
+			return fmt.Errorf("TaskRepository.Delete: %w", err)
+		}
+	}
+	if err := touchDependents(ctx, tx, ids); err != nil {
+		return fmt.Errorf("TaskRepository.Delete: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("TaskRepository.Delete: committing: %w", err)
+	}
+
+	return nil
+}
+
+// nextDeletionID returns a deletion ID that no task carries yet, to stamp
+// the tasks one delete moves to the trash with. Writers are serialized, so it
+// stays unused until tx ends.
+func nextDeletionID(ctx context.Context, tx DBTX) (int64, error) {
+	var id int64
+	if err := tx.QueryRowContext(ctx, "SELECT IFNULL(MAX(deletion_id), 0) + 1 FROM tasks").Scan(&id); err != nil {
+		return 0, fmt.Errorf("reading deletion ID: %w", err)
+	}
+	return id, nil
+}
+
+// Subtree retrieves the task with the given ID and all of its descendants,
+// level by level, with siblings in creation order.
+func (r *TaskRepository) Subtree(ctx context.Context, id int64) ([]domain.Task, error) {
+	q := "WITH RECURSIVE subtree(task_id, depth) AS (" +
+		"SELECT id, 0 FROM tasks WHERE id = ? AND " + ownerCondition + " AND deleted_at IS NULL " +
+		"UNION ALL SELECT tasks.id, subtree.depth + 1 FROM tasks JOIN subtree ON tasks.parent_id = subtree.task_id WHERE tasks.deleted_at IS NULL" +
+		") SELECT " + taskColumns + " FROM subtree JOIN tasks ON tasks.id = subtree.task_id " +
+		"ORDER BY subtree.depth, created_at, id"
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, id, tenantOwner(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("TaskRepository.Subtree: querying: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []domain.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("TaskRepository.Subtree: scanning row: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("TaskRepository.Subtree: iterating rows: %w", err)
+	}
+
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("TaskRepository.Subtree: %w", domain.ErrTaskNotFound)
+	}
+
+	if err := r.loadTags(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("TaskRepository.Subtree: %w", err)
+	}
+
 	return tasks, nil
 }
+
+// CompleteDescendants marks every open descendant of the task as done,
+// stamping completed_at like any other completion. Like completing them one
+// by one, it fails with domain.ErrTaskBlocked and completes none of them
+// while one waits for an open task outside the subtree.
+func (r *TaskRepository) CompleteDescendants(ctx context.Context, id int64) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("TaskRepository.CompleteDescendants: beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	subtree := "WITH RECURSIVE subtree(task_id) AS (" +
+		"SELECT id FROM tasks WHERE parent_id = ? AND " + ownerCondition + " AND deleted_at IS NULL " +
+		"UNION ALL SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.task_id WHERE tasks.deleted_at IS NULL" +
+		") "
+
+	// Blockers inside the subtree get completed along with it.
+	check := subtree + "SELECT EXISTS (SELECT 1 FROM subtree JOIN tasks ON tasks.id = subtree.task_id " +
+		"JOIN task_dependencies ON task_dependencies.task_id = tasks.id " +
+		"JOIN tasks AS blockers ON blockers.id = task_dependencies.blocker_id " +
+		"WHERE NOT tasks.done AND NOT blockers.done AND blockers.deleted_at IS NULL " +
+		"AND blockers.id NOT IN (SELECT task_id FROM subtree))"
+	var blocked bool
+	if err := tx.QueryRowContext(ctx, check, id, tenantOwner(ctx)).Scan(&blocked); err != nil {
+		return fmt.Errorf("TaskRepository.CompleteDescendants: checking blockers: %w", err)
+	}
+	if blocked {
+		return fmt.Errorf("TaskRepository.CompleteDescendants: %w", domain.ErrTaskBlocked)
+	}
+
+	q := subtree + "UPDATE tasks SET done = ?, " + completedAtAssignment + ", " + taskTouch + " " +
+		"WHERE done = 0 AND id IN (SELECT task_id FROM subtree) RETURNING " + taskColumns
+	rows, err := tx.QueryContext(ctx, q, id, tenantOwner(ctx), true, true)
+	if err != nil {
+		return fmt.Errorf("TaskRepository.CompleteDescendants: updating: %w", err)
+	}
+	defer rows.Close()
+
+	var completed []domain.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return fmt.Errorf("TaskRepository.CompleteDescendants: scanning row: %w", err)
+		}
+		completed = append(completed, task)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("TaskRepository.CompleteDescendants: iterating rows: %w", err)
+	}
+	rows.Close()
+
+	// Only done and completed_at changed; the task was open before.
+	ids := make([]int64, len(completed))
+	for i, task := range completed {
+		before := task
+		before.Done, before.CompletedAt = false, nil
+		if err := recordUpdate(ctx, tx, before, task); err != nil {
+			return fmt.Errorf("TaskRepository.CompleteDescendants: %w", err)
+		}
+		ids[i] = task.ID
+	}
+	if err := touchDependents(ctx, tx, ids); err != nil {
+		return fmt.Errorf("TaskRepository.CompleteDescendants: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("TaskRepository.CompleteDescendants: committing: %w", err)
+	}
+
+	return nil
+}
+
+// AddBlocker records that the task cannot be completed before blockerID is
+// done. Adding an existing dependency changes nothing.
+func (r *TaskRepository) AddBlocker(ctx context.Context, taskID, blockerID int64) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("TaskRepository.AddBlocker: beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkTasksExist(ctx, tx, taskID, blockerID); err != nil {
+		return fmt.Errorf("TaskRepository.AddBlocker: %w", err)
+	}
+
+	// The new edge closes a cycle when the task is already among the
+	// blocker's direct or transitive blockers.
+	q := "WITH RECURSIVE chain(task_id) AS (" +
+		"SELECT ? UNION SELECT task_dependencies.blocker_id FROM task_dependencies " +
+		"JOIN chain ON task_dependencies.task_id = chain.task_id" +
+		") SELECT EXISTS (SELECT 1 FROM chain WHERE task_id = ?)"
+	var cycle bool
+	if err := tx.QueryRowContext(ctx, q, blockerID, taskID).Scan(&cycle); err != nil {
+		return fmt.Errorf("TaskRepository.AddBlocker: checking for cycles: %w", err)
+	}
+	if cycle {
+		return fmt.Errorf("TaskRepository.AddBlocker: %w", domain.ErrDependencyCycle)
+	}
+
+	res, err := tx.ExecContext(ctx,
+		"INSERT INTO task_dependencies (task_id, blocker_id) VALUES (?, ?) ON CONFLICT DO NOTHING", taskID, blockerID)
+	if err != nil {
+		return fmt.Errorf("TaskRepository.AddBlocker: inserting: %w", err)
+	}
+	if err := touchIfChanged(ctx, tx, res, taskID); err != nil {
+		return fmt.Errorf("TaskRepository.AddBlocker: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("TaskRepository.AddBlocker: committing: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveBlocker deletes the dependency of the task on blockerID. Removing a
+// missing dependency changes nothing.
+func (r *TaskRepository) RemoveBlocker(ctx context.Context, taskID, blockerID int64) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("TaskRepository.RemoveBlocker: beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkTasksExist(ctx, tx, taskID, blockerID); err != nil {
+		return fmt.Errorf("TaskRepository.RemoveBlocker: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx,
+		"DELETE FROM task_dependencies WHERE task_id = ? AND blocker_id = ?", taskID, blockerID)
+	if err != nil {
+		return fmt.Errorf("TaskRepository.RemoveBlocker: deleting: %w", err)
+	}
+	if err := touchIfChanged(ctx, tx, res, taskID); err != nil {
+		return fmt.Errorf("TaskRepository.RemoveBlocker: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("TaskRepository.RemoveBlocker: committing: %w", err)
+	}
+
+	return nil
+}
+
+// Blockers retrieves the tasks the task depends on, in creation order.
+func (r *TaskRepository) Blockers(ctx context.Context, taskID int64) ([]domain.Task, error) {
+	var exists bool
+	if err := conn(ctx, r.db).QueryRowContext(ctx, taskExistsQuery, taskID, tenantOwner(ctx)).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("TaskRepository.Blockers: checking existence: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("TaskRepository.Blockers: %w", domain.ErrTaskNotFound)
+	}
+
+	q := "SELECT " + taskColumns + " FROM tasks " +
+		"WHERE id IN (SELECT blocker_id FROM task_dependencies WHERE task_id = ?) AND " + ownerCondition + " AND deleted_at IS NULL ORDER BY created_at, id"
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, taskID, tenantOwner(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("TaskRepository.Blockers: querying: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []domain.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("TaskRepository.Blockers: scanning row: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("TaskRepository.Blockers: iterating rows: %w", err)
+	}
+
+	if err := r.loadTags(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("TaskRepository.Blockers: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// checkTasksExist returns domain.ErrTaskNotFound unless both tasks exist
+// outside the trash and belong to the tenant of ctx.
+func checkTasksExist(ctx context.Context, tx DBTX, taskID, otherID int64) error {
+	var taskExists, otherExists bool
+	err := tx.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND "+ownerCondition+" AND deleted_at IS NULL), "+
+			"EXISTS (SELECT 1 FROM tasks WHERE id = ? AND "+ownerCondition+" AND deleted_at IS NULL)",
+		taskID, tenantOwner(ctx), otherID, tenantOwner(ctx),
+	).Scan(&taskExists, &otherExists)
+	if err != nil {
+		return fmt.Errorf("checking existence: %w", err)
+	}
+	if !taskExists || !otherExists {
+		return domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+// touchIfChanged touches the task when res affected any row,
+// since its blocked flag may have changed.
+func touchIfChanged(ctx context.Context, tx DBTX, res sql.Result, taskID int64) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("reading affected rows: %w", err)
+	}
+	if n == 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE tasks SET "+taskTouch+" WHERE id = ? AND "+ownerCondition, taskID, tenantOwner(ctx)); err != nil {
+		return fmt.Errorf("touching task: %w", err)
+	}
+	return nil
+}
+
+// touchDependents touches the tasks outside the trash that blockerIDs block.
+// Their blocked flag follows whether the blockers are done or in the trash,
+// so it changes with them, and so must their version.
+func touchDependents(ctx context.Context, tx DBTX, blockerIDs []int64) error {
+	if len(blockerIDs) == 0 {
+		return nil
+	}
+	args := make([]any, 0, len(blockerIDs)+1)
+	for _, id := range blockerIDs {
+		args = append(args, id)
+	}
+	args = append(args, tenantOwner(ctx))
+	q := "UPDATE tasks SET " + taskTouch + " WHERE id IN (SELECT task_id FROM task_dependencies WHERE blocker_id IN (" +
+		placeholders(len(blockerIDs)) + ")) AND " + ownerCondition + " AND deleted_at IS NULL"
+	if _, err := tx.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("touching dependents: %w", err)
+	}
+	return nil
+}
+
+// checkCompletable returns domain.ErrTaskBlocked when the task is about to
+// become done while one of its blockers is still open. Tasks that are done
+// already are left alone, so editing them never fails on a reopened blocker.
+func checkCompletable(ctx context.Context, tx DBTX, id int64) error {
+	q := "SELECT NOT done AND EXISTS (SELECT 1 FROM task_dependencies JOIN tasks AS blockers " +
+		"ON blockers.id = task_dependencies.blocker_id WHERE task_dependencies.task_id = tasks.id " +
+		"AND NOT blockers.done AND blockers.deleted_at IS NULL) " +
+		"FROM tasks WHERE id = ? AND " + ownerCondition
+	var blocked bool
+	err := tx.QueryRowContext(ctx, q, id, tenantOwner(ctx)).Scan(&blocked)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("checking blockers: %w", err)
+	}
+	if blocked {
+		return domain.ErrTaskBlocked
+	}
+	return nil
+}
+
+// checkProject verifies, inside tx, that the project exists and belongs to
+// the tenant of ctx, so that tasks never point at another tenant's project.
+func checkProject(ctx context.Context, tx DBTX, projectID int64) error {
+	var exists bool
+	q := "SELECT EXISTS (SELECT 1 FROM projects WHERE id = ? AND " + ownerCondition + ")"
+	if err := tx.QueryRowContext(ctx, q, projectID, tenantOwner(ctx)).Scan(&exists); err != nil {
+		return fmt.Errorf("checking project: %w", err)
+	}
+	if !exists {
+		return domain.ErrProjectNotFound
+	}
+	return nil
+}
+
+// checkParent verifies, inside tx, that the task with the given ID (0 for a
+// new task) may be nested under parentID: the parent must exist outside the
+// trash and belong to the tenant of ctx, must not be
+// the task itself or one of its subtasks, and the resulting tree must not
+// exceed domain.MaxTaskDepth levels. Trees therefore never span tenants, and
+// walking them from a task of the tenant stays inside it.
+func checkParent(ctx context.Context, tx DBTX, id, parentID int64) error {
+	// Walk up from the parent, counting the levels above the task and
+	// watching for the task itself. The depth bound stops the walk early
+	// once the tree is known to be too deep.
+	ancestry := "WITH RECURSIVE ancestors(task_id, parent_id, depth) AS (" +
+		"SELECT id, parent_id, 1 FROM tasks WHERE id = ? AND " + ownerCondition + " AND deleted_at IS NULL " +
+		"UNION ALL SELECT tasks.id, tasks.parent_id, ancestors.depth + 1 FROM tasks " +
+		"JOIN ancestors ON tasks.id = ancestors.parent_id WHERE ancestors.depth <= ?" +
+		") SELECT COUNT(*), COALESCE(MAX(task_id = ?), 0) FROM ancestors"
+	var (
+		levels int
+		cycle  bool
+	)
+	if err := tx.QueryRowContext(ctx, ancestry, parentID, tenantOwner(ctx), domain.MaxTaskDepth, id).Scan(&levels, &cycle); err != nil {
+		return fmt.Errorf("reading ancestors: %w", err)
+	}
+	if levels == 0 {
+		return domain.ErrParentTaskNotFound
+	}
+	if cycle {
+		return domain.ErrTaskHierarchyCycle
+	}
+
+	// Count the levels the task brings along: itself plus its deepest subtask.
+	// Subtasks in the trash count too, since they may be restored.
+	height := 1
+	if id != 0 {
+		subtree := "WITH RECURSIVE subtree(task_id, depth) AS (" +
+			"SELECT id, 1 FROM tasks WHERE id = ? " +
+			"UNION ALL SELECT tasks.id, subtree.depth + 1 FROM tasks " +
+			"JOIN subtree ON tasks.parent_id = subtree.task_id WHERE subtree.depth <= ?" +
+			") SELECT COALESCE(MAX(depth), 1) FROM subtree"
+		if err := tx.QueryRowContext(ctx, subtree, id, domain.MaxTaskDepth).Scan(&height); err != nil {
+			return fmt.Errorf("reading subtree height: %w", err)
+		}
+	}
+
+	if levels+height > domain.MaxTaskDepth {
+		return domain.ErrTaskTooDeep
+	}
+	return nil
+}
+
+// SetTags replaces the tags of a task with the named ones, creating the
+// tenant's tags that do not exist yet. Names match the tenant's existing tags
+// ignoring case. The task is only touched when its tags change.
+func (r *TaskRepository) SetTags(ctx context.Context, id int64, names []string) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("TaskRepository.SetTags: beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, taskExistsQuery, id, tenantOwner(ctx)).Scan(&exists); err != nil {
+		return fmt.Errorf("TaskRepository.SetTags: checking existence: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("TaskRepository.SetTags: %w", domain.ErrTaskNotFound)
+	}
+
+	tagIDs := make([]int64, 0, len(names))
+	for _, name := range names {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO tags (owner_id, name) VALUES (?, ?) ON CONFLICT DO NOTHING", tenantOwner(ctx), name); err != nil {
+			return fmt.Errorf("TaskRepository.SetTags: creating tag: %w", err)
+		}
+		var tagID int64
+		if err := tx.QueryRowContext(ctx, "SELECT id FROM tags WHERE "+ownerCondition+" AND name = ?", tenantOwner(ctx), name).Scan(&tagID); err != nil {
+			return fmt.Errorf("TaskRepository.SetTags: reading tag: %w", err)
+		}
+		tagIDs = append(tagIDs, tagID)
+	}
+	slices.Sort(tagIDs)
+	tagIDs = slices.Compact(tagIDs)
+
+	current, err := r.taskTagIDs(ctx, tx, id)
+	if err != nil {
+		return fmt.Errorf("TaskRepository.SetTags: %w", err)
+	}
+	if !slices.Equal(current, tagIDs) {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM task_tags WHERE task_id = ?", id); err != nil {
+			return fmt.Errorf("TaskRepository.SetTags: detaching tags: %w", err)
+		}
+		for _, tagID := range tagIDs {
+			if _, err := tx.ExecContext(ctx, "INSERT INTO task_tags (task_id, tag_id) VALUES (?, ?)", id, tagID); err != nil {
+				return fmt.Errorf("TaskRepository.SetTags: attaching tag: %w", err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE tasks SET "+taskTouch+" WHERE id = ? AND "+ownerCondition, id, tenantOwner(ctx)); err != nil {
+			return fmt.Errorf("TaskRepository.SetTags: touching task: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("TaskRepository.SetTags: committing: %w", err)
+	}
+
+	return nil
+}
+
+// taskTagIDs returns the IDs of the tags attached to a task, in order.
+func (r *TaskRepository) taskTagIDs(ctx context.Context, tx DBTX, id int64) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT tag_id FROM task_tags WHERE task_id = ? ORDER BY tag_id", id)
+	if err != nil {
+		return nil, fmt.Errorf("querying tags: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var tagID int64
+		if err := rows.Scan(&tagID); err != nil {
+			return nil, fmt.Errorf("scanning tag: %w", err)
+		}
+		ids = append(ids, tagID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating tags: %w", err)
+	}
+	return ids, nil
+}
+
+// loadTags fills in the tags of every task with a single query.
+func (r *TaskRepository) loadTags(ctx context.Context, tasks []domain.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]any, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+
+	byTask, err := r.tagsByTask(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	for i := range tasks {
+		tasks[i].Tags = byTask[tasks[i].ID]
+	}
+	return nil
+}
+
+// tagsByTask returns the tags of the given tasks keyed by task ID.
+func (r *TaskRepository) tagsByTask(ctx context.Context, taskIDs ...any) (map[int64][]domain.Tag, error) {
+	q := "SELECT task_tags.task_id, tags.id, tags.name, tags.created_at, tags.updated_at " +
+		"FROM task_tags JOIN tags ON tags.id = task_tags.tag_id " +
+		"WHERE task_tags.task_id IN (" + placeholders(len(taskIDs)) + ") ORDER BY tags.name, tags.id"
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, taskIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("querying tags: %w", err)
+	}
+	defer rows.Close()
+
+	byTask := make(map[int64][]domain.Tag)
+	for rows.Next() {
+		var (
+			taskID int64
+			tag    domain.Tag
+		)
+		if err := rows.Scan(&taskID, &tag.ID, &tag.Name, &tag.CreatedAt, &tag.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scanning tag row: %w", err)
+		}
+		byTask[taskID] = append(byTask[taskID], tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating tag rows: %w", err)
+	}
+
+	return byTask, nil
+}
+
+// taskSortColumns maps sort fields to SQL expressions. Only values from this
+// map are ever interpolated into ORDER BY clauses.
+var taskSortColumns = map[domain.TaskSortField]string{
+	domain.TaskSortCreatedAt: "created_at",
+	domain.TaskSortUpdatedAt: "updated_at",
+	domain.TaskSortTitle:     "title COLLATE NOCASE",
+}
+
+// buildTaskWhere turns filter into a WHERE clause with positional arguments.
+// Tasks in the trash and tasks of other tenants than the one of ctx are
+// always left out.
+func buildTaskWhere(ctx context.Context, filter domain.TaskFilter) (string, []any) {
+	var (
+		conds = []string{ownerCondition, "deleted_at IS NULL"}
+		args  = []any{tenantOwner(ctx)}
+	)
+	if filter.Done != nil {
+		conds = append(conds, "done = ?")
+		args = append(args, *filter.Done)
+	}
+	if filter.CreatedAfter != nil {
+		conds = append(conds, "created_at > ?")
+		args = append(args, formatTimestamp(*filter.CreatedAfter))
+	}
+	if filter.CreatedBefore != nil {
+		conds = append(conds, "created_at < ?")
+		args = append(args, formatTimestamp(*filter.CreatedBefore))
+	}
+	if filter.UpdatedAfter != nil {
+		conds = append(conds, "updated_at > ?")
+		args = append(args, formatTimestamp(*filter.UpdatedAfter))
+	}
+	if filter.UpdatedBefore != nil {
+		conds = append(conds, "updated_at < ?")
+		args = append(args, formatTimestamp(*filter.UpdatedBefore))
+	}
+	if filter.ProjectID != nil {
+		conds = append(conds, "project_id = ?")
+		args = append(args, *filter.ProjectID)
+	}
+	if len(filter.Tags) > 0 {
+		cond, tagArgs := buildTagCondition(filter.Tags, filter.TagMode)
+		conds = append(conds, cond)
+		args = append(args, tagArgs...)
+	}
+
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// buildTagCondition selects the tasks carrying all or any of the named tags.
+// Names compare case-insensitively through the column's collation.
+func buildTagCondition(names []string, mode domain.TagMatchMode) (string, []any) {
+	args := make([]any, len(names))
+	for i, name := range names {
+		args[i] = name
+	}
+	cond := "id IN (SELECT task_tags.task_id FROM task_tags JOIN tags ON tags.id = task_tags.tag_id " +
+		"WHERE tags.name IN (" + placeholders(len(names)) + ")"
+	if mode == domain.TagMatchAny {
+		return cond + ")", args
+	}
+	return cond + " GROUP BY task_tags.task_id HAVING COUNT(*) = ?)", append(args, len(names))
+}
+
+// buildTaskSeek returns the keyset condition selecting the rows past c in
+// the direction it points to.
+func buildTaskSeek(c domain.TaskCursor) (string, []any, error) {
+	col, ok := taskSortColumns[c.Sort.Field]
+	if !ok {
+		return "", nil, domain.ErrInvalidCursor
+	}
+
+	var value any = c.Value
+	if c.Sort.Field != domain.TaskSortTitle {
+		t, err := time.Parse(time.RFC3339, c.Value)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %w", domain.ErrInvalidCursor, err)
+		}
+		value = formatTimestamp(t)
+	}
+
+	op := ">"
+	if c.Sort.Desc != c.Backward {
+		op = "<"
+	}
+	cond := "(" + col + " " + op + " ? OR (" + col + " = ? AND id " + op + " ?))"
+	return cond, []any{value, value, c.ID}, nil
+}
+
+func appendCondition(where, cond string) string {
+	if where == "" {
+		return " WHERE " + cond
+	}
+	return where + " AND " + cond
+}
+
+func buildTaskOrderBy(sort domain.TaskSort) string {
+	col, ok := taskSortColumns[sort.Field]
+	if !ok {
+		col = taskSortColumns[domain.TaskSortCreatedAt]
+	}
+	dir := " ASC"
+	if sort.Desc {
+		dir = " DESC"
+	}
+	return " ORDER BY " + col + dir + ", id" + dir
+}
+
+// placeholders returns n comma-separated bind parameters.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// isFTSQueryError reports whether err was caused by the syntax of an FTS5
+// MATCH expression rather than by the database itself.
+func isFTSQueryError(err error) bool {
+	msg := err.Error()
+	return strings.HasPrefix(msg, "fts5:") || strings.Contains(msg, "unterminated string") ||
+		strings.Contains(msg, "no such column")
+}
+
+// nullableTimestamp formats t for binding, mapping nil to NULL.
+func nullableTimestamp(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return formatTimestamp(*t)
+}
+
+func int64Ptr(n sql.NullInt64) *int64 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Int64
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// formatTimestamp renders t the way SQLite's CURRENT_TIMESTAMP stores it,
+// so that bound values compare correctly against stored columns.
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(time.DateTime)
+}
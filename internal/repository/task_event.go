@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+// taskEventFields lists the task fields recorded in events, by API name, in
+// the order they are compared.
+var taskEventFields = []string{
+	"title", "description", "done", "priority", "start_at", "due_at",
+	"project_id", "parent_id", "recurrence", "completed_at",
+}
+
+// fieldChangeJSON is the stored form of a domain.FieldChange.
+type fieldChangeJSON struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// taskEventValues returns the recorded fields of t as plain JSON values that
+// compare with ==.
+func taskEventValues(t domain.Task) map[string]any {
+	return map[string]any{
+		"title":        t.Title,
+		"description":  t.Description,
+		"done":         t.Done,
+		"priority":     t.Priority.String(),
+		"start_at":     eventTime(t.StartAt),
+		"due_at":       eventTime(t.DueAt),
+		"project_id":   eventID(t.ProjectID),
+		"parent_id":    eventID(t.ParentID),
+		"recurrence":   t.Recurrence,
+		"completed_at": eventTime(t.CompletedAt),
+	}
+}
+
+// diffTask returns the recorded fields that differ between before and after.
+func diffTask(before, after domain.Task) map[string]domain.FieldChange {
+	oldValues, newValues := taskEventValues(before), taskEventValues(after)
+	changes := make(map[string]domain.FieldChange)
+	for _, field := range taskEventFields {
+		if oldValues[field] != newValues[field] {
+			changes[field] = domain.FieldChange{Old: oldValues[field], New: newValues[field]}
+		}
+	}
+	return changes
+}
+
+// createdChanges lists every recorded field of a new task as set from nothing.
+func createdChanges(t domain.Task) map[string]domain.FieldChange {
+	values := taskEventValues(t)
+	changes := make(map[string]domain.FieldChange, len(values))
+	for field, value := range values {
+		changes[field] = domain.FieldChange{New: value}
+	}
+	return changes
+}
+
+// recordTaskEvent appends an event to the history of a task inside tx,
+// attributing it to the actor and request ID carried by ctx.
+func recordTaskEvent(ctx context.Context, tx DBTX, taskID int64, action domain.TaskEventAction, changes map[string]domain.FieldChange) error {
+	stored := make(map[string]fieldChangeJSON, len(changes))
+	for field, c := range changes {
+		stored[field] = fieldChangeJSON{Old: c.Old, New: c.New}
+	}
+	encoded, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("encoding event changes: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO task_events (task_id, action, changes, actor, request_id) VALUES (?, ?, ?, ?, ?)",
+		taskID, string(action), string(encoded), nullableString(domain.ActorFrom(ctx)), nullableString(domain.RequestIDFrom(ctx)),
+	)
+	if err != nil {
+		return fmt.Errorf("recording %s event: %w", action, err)
+	}
+	return nil
+}
+
+// snapshotTask reads the current state of a task of the tenant of ctx
+// outside the trash inside tx, returning domain.ErrTaskNotFound when there
+// is none and
+// domain.ErrTaskVersionConflict when version is non-zero and differs from
+// the task's.
+func snapshotTask(ctx context.Context, tx DBTX, id, version int64) (domain.Task, error) {
+	q := "SELECT " + taskColumns + " FROM tasks WHERE id = ? AND " + ownerCondition + " AND deleted_at IS NULL"
+	task, err := scanTask(tx.QueryRowContext(ctx, q, id, tenantOwner(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Task{}, domain.ErrTaskNotFound
+	}
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("reading task: %w", err)
+	}
+	if version != 0 && task.Version != version {
+		return domain.Task{}, domain.ErrTaskVersionConflict
+	}
+	return task, nil
+}
+
+// History retrieves a page of the change history of a task, newest first.
+// Tasks in the trash keep their history until they are purged.
+func (r *TaskRepository) History(ctx context.Context, taskID int64, limit, offset int) ([]domain.TaskEvent, error) {
+	var exists bool
+	q := "SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND " + ownerCondition + ")"
+	if err := conn(ctx, r.db).QueryRowContext(ctx, q, taskID, tenantOwner(ctx)).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("TaskRepository.History: checking existence: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("TaskRepository.History: %w", domain.ErrTaskNotFound)
+	}
+
+	q = "SELECT id, task_id, action, changes, actor, request_id, created_at FROM task_events " +
+		"WHERE task_id = ? ORDER BY id DESC LIMIT ? OFFSET ?"
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, taskID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("TaskRepository.History: querying: %w", err)
+	}
+	defer rows.Close()
+
+	events := []domain.TaskEvent{}
+	for rows.Next() {
+		var (
+			event            domain.TaskEvent
+			action, changes  string
+			actor, requestID sql.NullString
+		)
+		if err := rows.Scan(&event.ID, &event.TaskID, &action, &changes, &actor, &requestID, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("TaskRepository.History: scanning row: %w", err)
+		}
+
+		var stored map[string]fieldChangeJSON
+		if err := json.Unmarshal([]byte(changes), &stored); err != nil {
+			return nil, fmt.Errorf("TaskRepository.History: decoding changes of event %d: %w", event.ID, err)
+		}
+		event.Changes = make(map[string]domain.FieldChange, len(stored))
+		for field, c := range stored {
+			event.Changes[field] = domain.FieldChange{Old: c.Old, New: c.New}
+		}
+		event.Action = domain.TaskEventAction(action)
+		event.Actor = actor.String
+		event.RequestID = requestID.String
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("TaskRepository.History: iterating rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// CountHistory returns the number of events in the history of a task, which
+// is zero for the tasks of other tenants.
+func (r *TaskRepository) CountHistory(ctx context.Context, taskID int64) (int, error) {
+	q := "SELECT COUNT(*) FROM task_events JOIN tasks ON tasks.id = task_events.task_id " +
+		"WHERE task_events.task_id = ? AND tasks." + ownerCondition
+	var total int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, q, taskID, tenantOwner(ctx)).Scan(&total); err != nil {
+		return 0, fmt.Errorf("TaskRepository.CountHistory: querying: %w", err)
+	}
+
+	return total, nil
+}
+
+// eventTime renders an optional timestamp as RFC 3339, mapping nil to nil.
+func eventTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// eventID unwraps an optional ID, mapping nil to nil.
+func eventID(id *int64) any {
+	if id == nil {
+		return nil
+	}
+	return *id
+}
+
+// nullableString maps an empty string to NULL.
+func nullableString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// recordUpdate records an updated event with the fields that differ between
+// before and after, if any.
+func recordUpdate(ctx context.Context, tx DBTX, before, after domain.Task) error {
+	changes := diffTask(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+	return recordTaskEvent(ctx, tx, after.ID, domain.TaskEventUpdated, changes)
+}
+
+// queryIDs runs q inside tx and collects the single ID column it returns.
+func queryIDs(ctx context.Context, tx DBTX, q string, args ...any) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
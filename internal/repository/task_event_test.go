@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+func TestDiffTask(t *testing.T) {
+	dueAt := time.Date(2026, 10, 20, 17, 0, 0, 0, time.UTC)
+	projectID := int64(4)
+	before := domain.Task{ID: 1, Title: "Old", Priority: domain.PriorityLow}
+	after := domain.Task{ID: 1, Title: "New", Priority: domain.PriorityLow, DueAt: &dueAt, ProjectID: &projectID, UpdatedAt: time.Now()}
+
+	expected := map[string]domain.FieldChange{
+		"title":      {Old: "Old", New: "New"},
+		"due_at":     {Old: nil, New: "2026-10-20T17:00:00Z"},
+		"project_id": {Old: nil, New: int64(4)},
+	}
+	if got := diffTask(before, after); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected changes %v but got %v", expected, got)
+	}
+	if got := diffTask(after, after); len(got) != 0 {
+		t.Fatalf("expected no changes but got %v", got)
+	}
+}
+
+func TestRecordTaskEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO task_events (task_id, action, changes, actor, request_id) VALUES (?, ?, ?, ?, ?)")).
+		WithArgs(1, "updated", `{"done":{"old":false,"new":true}}`, "alice", "req-1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	ctx := domain.WithRequestID(domain.WithActor(context.Background(), "alice"), "req-1")
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := map[string]domain.FieldChange{"done": {Old: false, New: true}}
+	if err := recordTaskEvent(ctx, tx, 1, domain.TaskEventUpdated, changes); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTaskRepository_History(t *testing.T) {
+	t.Parallel()
+
+	exists := regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND owner_id IS ?)")
+	query := regexp.QuoteMeta("SELECT id, task_id, action, changes, actor, request_id, created_at FROM task_events " +
+		"WHERE task_id = ? ORDER BY id DESC LIMIT ? OFFSET ?")
+	columns := []string{"id", "task_id", "action", "changes", "actor", "request_id", "created_at"}
+	createdAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		setup       func(mock sqlmock.Sqlmock)
+		expected    []domain.TaskEvent
+		expectedErr error
+	}{
+		{
+			name: "should decode the events",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(exists).WithArgs(1, nil).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(query).WithArgs(1, 10, 0).WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(2, 1, "updated", `{"title":{"old":"Old","new":"New"}}`, "alice", "req-2", createdAt).
+					AddRow(1, 1, "created", `{}`, nil, nil, createdAt))
+			},
+			expected: []domain.TaskEvent{
+				{
+					ID: 2, TaskID: 1, Action: domain.TaskEventUpdated,
+					Changes: map[string]domain.FieldChange{"title": {Old: "Old", New: "New"}},
+					Actor:   "alice", RequestID: "req-2", CreatedAt: createdAt,
+				},
+				{ID: 1, TaskID: 1, Action: domain.TaskEventCreated, Changes: map[string]domain.FieldChange{}, CreatedAt: createdAt},
+			},
+		},
+		{
+			name: "should return not found for a missing task",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(exists).WithArgs(1, nil).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			expectedErr: domain.ErrTaskNotFound,
+		},
+		{
+			name: "should return error when query fails",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(exists).WithArgs(1, nil).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(query).WithArgs(1, 10, 0).WillReturnError(sql.ErrConnDone)
+			},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal("failed to create mock")
+			}
+			defer db.Close()
+			tc.setup(mock)
+
+			events, err := NewTaskRepository(db).History(t.Context(), 1, 10, 0)
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			} else if !reflect.DeepEqual(events, tc.expected) {
+				t.Fatalf("expected events %+v but got %+v", tc.expected, events)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestTaskRepository_CountHistory(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM task_events JOIN tasks ON tasks.id = task_events.task_id WHERE task_events.task_id = ? AND tasks.owner_id IS ?")).WithArgs(1, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	total, err := NewTaskRepository(db).CountHistory(t.Context(), 1)
+	if err != nil || total != 3 {
+		t.Fatalf("expected 3 events but got %d, %v", total, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -12,6 +16,62 @@ import (
 	"github.com/mkeOrt/tasks-go/internal/domain"
 )
 
+// taskColumnNames lists the columns selected by taskColumns.
+var taskColumnNames = []string{
+	"id", "title", "description", "done", "priority", "start_at", "due_at", "project_id", "parent_id", "recurrence", "completed_at", "created_at", "updated_at", "version", "blocked",
+}
+
+// checkBlockedQuery is the query refusing to complete a task with open blockers.
+var checkBlockedQuery = regexp.QuoteMeta("SELECT NOT done AND EXISTS (SELECT 1 FROM task_dependencies JOIN tasks AS blockers " +
+	"ON blockers.id = task_dependencies.blocker_id WHERE task_dependencies.task_id = tasks.id AND NOT blockers.done AND blockers.deleted_at IS NULL) FROM tasks WHERE id = ? AND owner_id IS ?")
+
+// checkProjectQuery is the query checking that a task's project belongs to
+// the tenant.
+var checkProjectQuery = regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM projects WHERE id = ? AND owner_id IS ?)")
+
+// taskBlockedSQL is the computed blocked column selected with every task.
+const taskBlockedSQL = "EXISTS (SELECT 1 FROM task_dependencies JOIN tasks AS blockers ON blockers.id = task_dependencies.blocker_id " +
+	"WHERE task_dependencies.task_id = tasks.id AND NOT blockers.done AND blockers.deleted_at IS NULL) AS blocked"
+
+// taskRow returns the values of a mocked task row with only the base fields set.
+func taskRow(id int64, title string, done bool, createdAt, updatedAt any) []driver.Value {
+	return []driver.Value{id, title, "", done, 0, nil, nil, nil, nil, "", nil, createdAt, updatedAt, 1, false}
+}
+
+// taskSnapshotQuery is the query reading a task before it is changed.
+var taskSnapshotQuery = regexp.QuoteMeta("SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " +
+	taskBlockedSQL + " FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL")
+
+// expectTaskEvent expects an event of the given action to be recorded for a task.
+func expectTaskEvent(mock sqlmock.Sqlmock, taskID driver.Value, action domain.TaskEventAction) *sqlmock.ExpectedExec {
+	q := "INSERT INTO task_events (task_id, action, changes, actor, request_id) VALUES (?, ?, ?, ?, ?)"
+	return mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(taskID, string(action), sqlmock.AnyArg(), nil, nil)
+}
+
+// expectTouchDependents expects the version bump of the tasks blocked by
+// blockerIDs, done once the blockers were completed, reopened or trashed.
+func expectTouchDependents(mock sqlmock.Sqlmock, blockerIDs ...driver.Value) *sqlmock.ExpectedExec {
+	q := "UPDATE tasks SET updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id IN (SELECT task_id FROM task_dependencies WHERE blocker_id IN ("
+	return mock.ExpectExec(regexp.QuoteMeta(q)).WithArgs(append(blockerIDs, nil)...)
+}
+
+// expectNextDeletionID expects the query picking the deletion ID that a
+// delete stamps its tasks with, and returns id.
+func expectNextDeletionID(mock sqlmock.Sqlmock, id int64) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT IFNULL(MAX(deletion_id), 0) + 1 FROM tasks")).
+		WillReturnRows(sqlmock.NewRows([]string{"deletion_id"}).AddRow(id))
+}
+
+// taskTagColumnNames lists the columns selected when loading the tags of tasks.
+var taskTagColumnNames = []string{"task_id", "id", "name", "created_at", "updated_at"}
+
+// expectTaskTags expects the single query loading the tags of the given tasks.
+func expectTaskTags(mock sqlmock.Sqlmock, taskIDs ...driver.Value) *sqlmock.ExpectedQuery {
+	q := "SELECT task_tags.task_id, tags.id, tags.name, tags.created_at, tags.updated_at " +
+		"FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE task_tags.task_id IN ("
+	return mock.ExpectQuery(regexp.QuoteMeta(q)).WithArgs(taskIDs...)
+}
+
 func TestNewTaskRepository(t *testing.T) {
 	t.Parallel()
 	db, _, err := sqlmock.New()
@@ -47,7 +107,7 @@ func TestTaskRepository_GetAll(t *testing.T) {
 		{
 			name: "should return error when query fails",
 			setup: func() {
-				mock.ExpectQuery("SELECT id, title, done, created_at, updated_at FROM tasks").
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL + " FROM tasks")).
 					WillReturnError(sql.ErrConnDone)
 			},
 			expected:    nil,
@@ -56,8 +116,8 @@ func TestTaskRepository_GetAll(t *testing.T) {
 		{
 			name: "should return empty list when db returns no rows",
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "done", "created_at", "updated_at"})
-				mock.ExpectQuery("SELECT id, title, done, created_at, updated_at FROM tasks").
+				rows := sqlmock.NewRows(taskColumnNames)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL + " FROM tasks")).
 					WillReturnRows(rows)
 			},
 			expected:    []domain.Task{},
@@ -66,27 +126,28 @@ func TestTaskRepository_GetAll(t *testing.T) {
 		{
 			name: "should return populated list when db returns rows",
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "done", "created_at", "updated_at"}).
-					AddRow(1, "Task 1", false, createdAt, updatedAt).
-					AddRow(2, "Task 2", true, createdAt, updatedAt)
+				rows := sqlmock.NewRows(taskColumnNames).
+					AddRow(taskRow(1, "Task 1", false, createdAt, updatedAt)...).
+					AddRow(taskRow(2, "Task 2", true, createdAt, updatedAt)...)
 
-				mock.ExpectQuery("SELECT id, title, done, created_at, updated_at FROM tasks").
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL + " FROM tasks")).
 					WillReturnRows(rows)
+				expectTaskTags(mock, 1, 2).WillReturnRows(sqlmock.NewRows(taskTagColumnNames))
 			},
 			expected: []domain.Task{
-				{ID: 1, Title: "Task 1", Done: false, CreatedAt: createdAt, UpdatedAt: updatedAt},
-				{ID: 2, Title: "Task 2", Done: true, CreatedAt: createdAt, UpdatedAt: updatedAt},
+				{ID: 1, Title: "Task 1", Done: false, CreatedAt: createdAt, UpdatedAt: updatedAt, Version: 1},
+				{ID: 2, Title: "Task 2", Done: true, CreatedAt: createdAt, UpdatedAt: updatedAt, Version: 1},
 			},
 			expectedErr: nil,
 		},
 		{
 			name: "should return error when rows iteration fails",
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "done", "created_at", "updated_at"}).
-					AddRow(1, "Task 1", false, createdAt, updatedAt).
+				rows := sqlmock.NewRows(taskColumnNames).
+					AddRow(taskRow(1, "Task 1", false, createdAt, updatedAt)...).
 					RowError(0, sql.ErrConnDone)
 
-				mock.ExpectQuery("SELECT id, title, done, created_at, updated_at FROM tasks").
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL + " FROM tasks")).
 					WillReturnRows(rows)
 			},
 			expected:    nil,
@@ -95,10 +156,10 @@ func TestTaskRepository_GetAll(t *testing.T) {
 		{
 			name: "should return error when scan fails",
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "done", "created_at", "updated_at"}).
-					AddRow(1, "Task 1", false, createdAt, "invalid-time")
+				rows := sqlmock.NewRows(taskColumnNames).
+					AddRow(taskRow(1, "Task 1", false, createdAt, "invalid-time")...)
 
-				mock.ExpectQuery("SELECT id, title, done, created_at, updated_at FROM tasks").
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL + " FROM tasks")).
 					WillReturnRows(rows)
 			},
 			expected:       nil,
@@ -110,7 +171,7 @@ func TestTaskRepository_GetAll(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setup()
 			repo := NewTaskRepository(db)
-			tasks, err := repo.GetAll(t.Context())
+			tasks, err := repo.GetAll(t.Context(), domain.TaskFilter{})
 
 			if tc.expectAnyError {
 				if err == nil {
@@ -135,3 +196,1401 @@ func TestTaskRepository_GetAll(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskRepository_GetAll_Filter(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	done := false
+	after := time.Date(2025, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+	columns := taskColumnNames
+	projectID := int64(4)
+
+	testCases := []struct {
+		name   string
+		filter domain.TaskFilter
+		query  string
+		args   []driver.Value
+	}{
+		{
+			name:   "should order by created_at by default",
+			filter: domain.TaskFilter{},
+			query:  "SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL + " FROM tasks WHERE owner_id IS ? AND deleted_at IS NULL ORDER BY created_at ASC, id ASC",
+		},
+		{
+			name: "should combine every filter with pagination",
+			filter: domain.TaskFilter{
+				Done:          &done,
+				CreatedAfter:  &after,
+				UpdatedBefore: &after,
+				Sort:          domain.TaskSort{Field: domain.TaskSortTitle, Desc: true},
+				Limit:         10,
+				Offset:        5,
+			},
+			query: "SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL + " FROM tasks WHERE owner_id IS ? AND deleted_at IS NULL AND done = ? AND created_at > ? AND updated_at < ? " +
+				"ORDER BY title COLLATE NOCASE DESC, id DESC LIMIT ? OFFSET ?",
+			args: []driver.Value{nil, false, "2025-01-02 02:04:05", "2025-01-02 02:04:05", 10, 5},
+		},
+		{
+			name: "should seek past a forward cursor",
+			filter: domain.TaskFilter{
+				Sort:   domain.TaskSort{Field: domain.TaskSortCreatedAt, Desc: true},
+				Limit:  3,
+				Offset: 9,
+				Cursor: &domain.TaskCursor{
+					Sort:  domain.TaskSort{Field: domain.TaskSortCreatedAt, Desc: true},
+					Value: "2025-01-02T03:04:05Z",
+					ID:    8,
+				},
+			},
+			query: "SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL + " FROM tasks WHERE owner_id IS ? AND deleted_at IS NULL AND (created_at < ? OR (created_at = ? AND id < ?)) " +
+				"ORDER BY created_at DESC, id DESC LIMIT ?",
+			args: []driver.Value{nil, "2025-01-02 03:04:05", "2025-01-02 03:04:05", 8, 3},
+		},
+		{
+			name: "should seek before a backward cursor in reverse order",
+			filter: domain.TaskFilter{
+				Done:  &done,
+				Sort:  domain.TaskSort{Field: domain.TaskSortTitle},
+				Limit: 3,
+				Cursor: &domain.TaskCursor{
+					Sort:     domain.TaskSort{Field: domain.TaskSortTitle},
+					Value:    "m",
+					ID:       8,
+					Backward: true,
+				},
+			},
+			query: "SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL + " FROM tasks WHERE owner_id IS ? AND deleted_at IS NULL AND done = ? AND " +
+				"(title COLLATE NOCASE < ? OR (title COLLATE NOCASE = ? AND id < ?)) ORDER BY title COLLATE NOCASE DESC, id DESC LIMIT ?",
+			args: []driver.Value{nil, false, "m", "m", 8, 3},
+		},
+		{
+			name:   "should sort by updated_at ascending",
+			filter: domain.TaskFilter{Sort: domain.TaskSort{Field: domain.TaskSortUpdatedAt}, Limit: 1},
+			query:  "SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL + " FROM tasks WHERE owner_id IS ? AND deleted_at IS NULL ORDER BY updated_at ASC, id ASC LIMIT ? OFFSET ?",
+			args:   []driver.Value{nil, 1, 0},
+		},
+		{
+			name:   "should filter by project",
+			filter: domain.TaskFilter{Done: &done, ProjectID: &projectID},
+			query: "SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL + " FROM tasks " +
+				"WHERE owner_id IS ? AND deleted_at IS NULL AND done = ? AND project_id = ? ORDER BY created_at ASC, id ASC",
+			args: []driver.Value{nil, false, 4},
+		},
+		{
+			name:   "should match any of the tags",
+			filter: domain.TaskFilter{Tags: []string{"backend", "urgent"}, TagMode: domain.TagMatchAny},
+			query: "SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL + " FROM tasks " +
+				"WHERE owner_id IS ? AND deleted_at IS NULL AND id IN (SELECT task_tags.task_id FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE tags.name IN (?, ?)) " +
+				"ORDER BY created_at ASC, id ASC",
+			args: []driver.Value{nil, "backend", "urgent"},
+		},
+		{
+			name:   "should match all of the tags",
+			filter: domain.TaskFilter{Done: &done, Tags: []string{"backend", "urgent"}, TagMode: domain.TagMatchAll},
+			query: "SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL + " FROM tasks " +
+				"WHERE owner_id IS ? AND deleted_at IS NULL AND done = ? AND id IN (SELECT task_tags.task_id FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE tags.name IN (?, ?) " +
+				"GROUP BY task_tags.task_id HAVING COUNT(*) = ?) ORDER BY created_at ASC, id ASC",
+			args: []driver.Value{nil, false, "backend", "urgent", 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expectation := mock.ExpectQuery("^" + regexp.QuoteMeta(tc.query) + "$").WillReturnRows(sqlmock.NewRows(columns))
+			if tc.args != nil {
+				expectation.WithArgs(tc.args...)
+			}
+
+			if _, err := NewTaskRepository(db).GetAll(t.Context(), tc.filter); err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTaskRepository_GetAll_BackwardCursorOrder(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	sort := domain.TaskSort{Field: domain.TaskSortTitle}
+	rows := sqlmock.NewRows(taskColumnNames).
+		AddRow(taskRow(3, "c", false, time.Now(), time.Now())...).
+		AddRow(taskRow(2, "b", false, time.Now(), time.Now())...)
+	mock.ExpectQuery("SELECT").WillReturnRows(rows)
+	expectTaskTags(mock, 2, 3).WillReturnRows(sqlmock.NewRows(taskTagColumnNames))
+
+	tasks, err := NewTaskRepository(db).GetAll(t.Context(), domain.TaskFilter{
+		Sort:   sort,
+		Limit:  2,
+		Cursor: &domain.TaskCursor{Sort: sort, Value: "d", ID: 4, Backward: true},
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].ID != 2 || tasks[1].ID != 3 {
+		t.Fatalf("expected tasks restored to display order, got %v", tasks)
+	}
+}
+
+func TestTaskRepository_GetAll_InvalidCursor(t *testing.T) {
+	t.Parallel()
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	sort := domain.TaskSort{Field: domain.TaskSortUpdatedAt}
+	_, err = NewTaskRepository(db).GetAll(t.Context(), domain.TaskFilter{
+		Sort:   sort,
+		Cursor: &domain.TaskCursor{Sort: sort, Value: "not-a-time", ID: 1},
+	})
+	if !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Fatalf("expected error %v but got %v", domain.ErrInvalidCursor, err)
+	}
+}
+
+func TestTaskRepository_Count(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	done := true
+
+	t.Run("should count matching tasks ignoring pagination", func(t *testing.T) {
+		mock.ExpectQuery("^"+regexp.QuoteMeta("SELECT COUNT(*) FROM tasks WHERE owner_id IS ? AND deleted_at IS NULL AND done = ?")+"$").
+			WithArgs(nil, true).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+
+		total, err := NewTaskRepository(db).Count(t.Context(), domain.TaskFilter{Done: &done, Limit: 5, Offset: 5})
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if total != 12 {
+			t.Fatalf("expected total 12 but got %d", total)
+		}
+	})
+
+	t.Run("should return error when query fails", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM tasks")).WillReturnError(sql.ErrConnDone)
+
+		if _, err := NewTaskRepository(db).Count(t.Context(), domain.TaskFilter{}); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTaskRepository_Search(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	done := false
+	columns := append(taskColumnNames, "score", "snippet")
+	query := regexp.QuoteMeta("SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL + ", score, snippet FROM (" +
+		"SELECT rowid AS match_id, -bm25(tasks_fts) AS score, snippet(tasks_fts, -1, '<mark>', '</mark>', '…', 16) AS snippet " +
+		"FROM tasks_fts WHERE tasks_fts MATCH ?) JOIN tasks ON tasks.id = match_id WHERE owner_id IS ? AND deleted_at IS NULL AND done = ? " +
+		"ORDER BY score DESC, id ASC LIMIT ? OFFSET ?")
+
+	t.Run("should return ranked results", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).AddRow(append(taskRow(2, "Send invoice", false, now, now), 1.25, "Send <mark>invoice</mark>")...)
+		mock.ExpectQuery(query).WithArgs("invoice", nil, false, 10, 0).WillReturnRows(rows)
+		expectTaskTags(mock, 2).WillReturnRows(sqlmock.NewRows(taskTagColumnNames).AddRow(2, 4, "billing", now, now))
+
+		results, err := NewTaskRepository(db).Search(t.Context(), "invoice", domain.TaskFilter{Done: &done, Limit: 10})
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		expected := []domain.TaskSearchResult{{
+			Task: domain.Task{
+				ID:        2,
+				Title:     "Send invoice",
+				CreatedAt: now,
+				UpdatedAt: now,
+				Version:   1,
+				Tags:      []domain.Tag{{ID: 4, Name: "billing", CreatedAt: now, UpdatedAt: now}},
+			},
+			Score:   1.25,
+			Snippet: "Send <mark>invoice</mark>",
+		}}
+		if !reflect.DeepEqual(results, expected) {
+			t.Fatalf("expected results %v but got %v", expected, results)
+		}
+	})
+
+	t.Run("should report invalid query syntax", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(errors.New(`fts5: syntax error near "AND"`))
+
+		_, err := NewTaskRepository(db).Search(t.Context(), "AND", domain.TaskFilter{Done: &done, Limit: 10})
+		if !errors.Is(err, domain.ErrInvalidSearchQuery) {
+			t.Fatalf("expected error %v but got %v", domain.ErrInvalidSearchQuery, err)
+		}
+	})
+
+	t.Run("should return error when query fails", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
+
+		_, err := NewTaskRepository(db).Search(t.Context(), "invoice", domain.TaskFilter{Done: &done, Limit: 10})
+		if !errors.Is(err, sql.ErrConnDone) || errors.Is(err, domain.ErrInvalidSearchQuery) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTaskRepository_GetByID(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	createdAt := time.Now()
+	updatedAt := time.Now()
+	dueAt := time.Date(2026, 10, 2, 17, 30, 0, 0, time.UTC)
+	projectID := int64(4)
+	parentID := int64(8)
+	query := regexp.QuoteMeta("SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL + " FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL")
+
+	testCases := []struct {
+		name        string
+		setup       func()
+		expected    domain.Task
+		expectedErr error
+	}{
+		{
+			name: "should return task when it exists",
+			setup: func() {
+				rows := sqlmock.NewRows(taskColumnNames).
+					AddRow(taskRow(1, "Task 1", true, createdAt, updatedAt)...)
+				mock.ExpectQuery(query).WithArgs(1, nil).WillReturnRows(rows)
+				expectTaskTags(mock, 1).WillReturnRows(sqlmock.NewRows(taskTagColumnNames))
+			},
+			expected: domain.Task{ID: 1, Title: "Task 1", Done: true, CreatedAt: createdAt, UpdatedAt: updatedAt, Version: 1},
+		},
+		{
+			name: "should scan optional fields when they are set",
+			setup: func() {
+				rows := sqlmock.NewRows(taskColumnNames).
+					AddRow(1, "Task 1", "Details", true, 3, dueAt, dueAt, 4, 8, "FREQ=DAILY", dueAt, createdAt, updatedAt, 6, true)
+				mock.ExpectQuery(query).WithArgs(1, nil).WillReturnRows(rows)
+				expectTaskTags(mock, 1).WillReturnRows(sqlmock.NewRows(taskTagColumnNames).
+					AddRow(1, 2, "backend", createdAt, createdAt).
+					AddRow(1, 5, "urgent", createdAt, createdAt))
+			},
+			expected: domain.Task{
+				ID:          1,
+				Title:       "Task 1",
+				Description: "Details",
+				Done:        true,
+				Priority:    domain.PriorityHigh,
+				StartAt:     &dueAt,
+				DueAt:       &dueAt,
+				ProjectID:   &projectID,
+				ParentID:    &parentID,
+				Recurrence:  "FREQ=DAILY",
+				Blocked:     true,
+				CompletedAt: &dueAt,
+				CreatedAt:   createdAt,
+				UpdatedAt:   updatedAt,
+				Version:     6,
+				Tags: []domain.Tag{
+					{ID: 2, Name: "backend", CreatedAt: createdAt, UpdatedAt: createdAt},
+					{ID: 5, Name: "urgent", CreatedAt: createdAt, UpdatedAt: createdAt},
+				},
+			},
+		},
+		{
+			name: "should return not found when no row matches",
+			setup: func() {
+				mock.ExpectQuery(query).WithArgs(1, nil).WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: domain.ErrTaskNotFound,
+		},
+		{
+			name: "should return error when query fails",
+			setup: func() {
+				mock.ExpectQuery(query).WithArgs(1, nil).WillReturnError(sql.ErrConnDone)
+			},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setup()
+			repo := NewTaskRepository(db)
+			task, err := repo.GetByID(t.Context(), 1)
+
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if !reflect.DeepEqual(task, tc.expected) {
+				t.Fatalf("expected task %v but got %v", tc.expected, task)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTaskRepository_Create(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	createdAt := time.Now()
+	query := regexp.QuoteMeta("INSERT INTO tasks (title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, owner_id) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, CASE WHEN ? THEN CURRENT_TIMESTAMP END, ?) RETURNING id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL)
+
+	t.Run("should return created task", func(t *testing.T) {
+		rows := sqlmock.NewRows(taskColumnNames).
+			AddRow(taskRow(7, "New", false, createdAt, createdAt)...)
+		mock.ExpectBegin()
+		mock.ExpectQuery(query).WithArgs("New", "", false, domain.PriorityNone, nil, nil, nil, nil, "", false, nil).WillReturnRows(rows)
+		expectTaskEvent(mock, 7, domain.TaskEventCreated).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		task, err := NewTaskRepository(db).Create(t.Context(), domain.Task{Title: "New"})
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		expected := domain.Task{ID: 7, Title: "New", CreatedAt: createdAt, UpdatedAt: createdAt, Version: 1}
+		if !reflect.DeepEqual(task, expected) {
+			t.Fatalf("expected task %v but got %v", expected, task)
+		}
+	})
+
+	t.Run("should return error when insert fails", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(query).WithArgs("New", "", false, domain.PriorityNone, nil, nil, nil, nil, "", false, nil).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		if _, err := NewTaskRepository(db).Create(t.Context(), domain.Task{Title: "New"}); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	t.Run("should return project not found for a missing project", func(t *testing.T) {
+		projectID := int64(9)
+		mock.ExpectBegin()
+		mock.ExpectQuery(checkProjectQuery).WithArgs(projectID, nil).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectRollback()
+
+		_, err := NewTaskRepository(db).Create(t.Context(), domain.Task{Title: "New", ProjectID: &projectID})
+		if !errors.Is(err, domain.ErrProjectNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrProjectNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTaskRepository_Update(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	query := regexp.QuoteMeta("UPDATE tasks SET title = ?, description = ?, done = ?, priority = ?, start_at = ?, due_at = ?, project_id = ?, parent_id = ?, recurrence = ?, " +
+		"completed_at = CASE WHEN ? THEN COALESCE(completed_at, CURRENT_TIMESTAMP) END, " +
+		"updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND owner_id IS ? AND deleted_at IS NULL RETURNING id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL)
+
+	t.Run("should return updated task", func(t *testing.T) {
+		rows := sqlmock.NewRows(taskColumnNames).
+			AddRow(taskRow(3, "Renamed", true, now, now)...)
+		mock.ExpectBegin()
+		mock.ExpectQuery(taskSnapshotQuery).WithArgs(3, nil).WillReturnRows(sqlmock.NewRows(taskColumnNames).AddRow(taskRow(3, "Old", false, now, now)...))
+		mock.ExpectQuery(checkBlockedQuery).WithArgs(3, nil).WillReturnRows(sqlmock.NewRows([]string{"blocked"}).AddRow(false))
+		mock.ExpectQuery(query).WithArgs("Renamed", "", true, domain.PriorityNone, nil, nil, nil, nil, "", true, 3, 1, nil).WillReturnRows(rows)
+		expectTaskEvent(mock, 3, domain.TaskEventUpdated).WillReturnResult(sqlmock.NewResult(1, 1))
+		expectTouchDependents(mock, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		expectTaskTags(mock, 3).WillReturnRows(sqlmock.NewRows(taskTagColumnNames))
+
+		task, err := NewTaskRepository(db).Update(t.Context(), domain.Task{ID: 3, Title: "Renamed", Done: true})
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if task.Title != "Renamed" || !task.Done {
+			t.Fatalf("unexpected task %v", task)
+		}
+	})
+
+	t.Run("should return not found when no row matches", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(taskSnapshotQuery).WithArgs(3, nil).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := NewTaskRepository(db).Update(t.Context(), domain.Task{ID: 3, Title: "Renamed", Done: true})
+		if !errors.Is(err, domain.ErrTaskNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskNotFound, err)
+		}
+	})
+
+	t.Run("should refuse a stale version", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(taskSnapshotQuery).WithArgs(3, nil).WillReturnRows(sqlmock.NewRows(taskColumnNames).AddRow(taskRow(3, "Old", false, now, now)...))
+		mock.ExpectRollback()
+
+		_, err := NewTaskRepository(db).Update(t.Context(), domain.Task{ID: 3, Title: "Renamed", Version: 2})
+		if !errors.Is(err, domain.ErrTaskVersionConflict) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskVersionConflict, err)
+		}
+	})
+
+	t.Run("should refuse a version changed concurrently", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(taskSnapshotQuery).WithArgs(3, nil).WillReturnRows(sqlmock.NewRows(taskColumnNames).AddRow(taskRow(3, "Old", false, now, now)...))
+		mock.ExpectQuery(query).WithArgs("Renamed", "", false, domain.PriorityNone, nil, nil, nil, nil, "", false, 3, 1, nil).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := NewTaskRepository(db).Update(t.Context(), domain.Task{ID: 3, Title: "Renamed", Version: 1})
+		if !errors.Is(err, domain.ErrTaskVersionConflict) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskVersionConflict, err)
+		}
+	})
+
+	t.Run("should refuse to complete a blocked task", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(taskSnapshotQuery).WithArgs(3, nil).WillReturnRows(sqlmock.NewRows(taskColumnNames).AddRow(taskRow(3, "Old", false, now, now)...))
+		mock.ExpectQuery(checkBlockedQuery).WithArgs(3, nil).WillReturnRows(sqlmock.NewRows([]string{"blocked"}).AddRow(true))
+		mock.ExpectRollback()
+
+		_, err := NewTaskRepository(db).Update(t.Context(), domain.Task{ID: 3, Title: "Renamed", Done: true})
+		if !errors.Is(err, domain.ErrTaskBlocked) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskBlocked, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTaskRepository_Patch(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	title := "Patched"
+	description := "Details"
+	done := true
+	priority := domain.PriorityHigh
+	startAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	dueAt := time.Date(2026, 10, 2, 17, 30, 0, 0, time.UTC)
+	projectID := int64(2)
+	recurrence := "FREQ=WEEKLY;BYDAY=MO"
+	completedAt := "completed_at = CASE WHEN ? THEN COALESCE(completed_at, CURRENT_TIMESTAMP) END"
+
+	testCases := []struct {
+		name  string
+		patch domain.TaskPatch
+		query string
+		args  []driver.Value
+	}{
+		{
+			name:  "should update only title",
+			patch: domain.TaskPatch{Title: &title},
+			query: "UPDATE tasks SET title = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND owner_id IS ? AND deleted_at IS NULL",
+			args:  []driver.Value{title, 5, 1, nil},
+		},
+		{
+			name:  "should stamp completed_at when updating done",
+			patch: domain.TaskPatch{Done: &done},
+			query: "UPDATE tasks SET done = ?, " + completedAt + ", updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND owner_id IS ? AND deleted_at IS NULL",
+			args:  []driver.Value{done, done, 5, 1, nil},
+		},
+		{
+			name:  "should clear a date set to null",
+			patch: domain.TaskPatch{DueAt: domain.NullableTime{Set: true}},
+			query: "UPDATE tasks SET due_at = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND owner_id IS ? AND deleted_at IS NULL",
+			args:  []driver.Value{nil, 5, 1, nil},
+		},
+		{
+			name:  "should move the task to a project",
+			patch: domain.TaskPatch{ProjectID: domain.NullableID{Set: true, ID: &projectID}},
+			query: "UPDATE tasks SET project_id = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND owner_id IS ? AND deleted_at IS NULL",
+			args:  []driver.Value{projectID, 5, 1, nil},
+		},
+		{
+			name:  "should replace the recurrence",
+			patch: domain.TaskPatch{Recurrence: &recurrence},
+			query: "UPDATE tasks SET recurrence = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND owner_id IS ? AND deleted_at IS NULL",
+			args:  []driver.Value{recurrence, 5, 1, nil},
+		},
+		{
+			name: "should update every field",
+			patch: domain.TaskPatch{
+				Title:       &title,
+				Description: &description,
+				Done:        &done,
+				Priority:    &priority,
+				StartAt:     domain.NullableTime{Set: true, Time: &startAt},
+				DueAt:       domain.NullableTime{Set: true, Time: &dueAt},
+			},
+			query: "UPDATE tasks SET title = ?, description = ?, done = ?, " + completedAt +
+				", priority = ?, start_at = ?, due_at = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND owner_id IS ? AND deleted_at IS NULL",
+			args: []driver.Value{title, description, done, done, priority, "2026-10-01 09:00:00", "2026-10-02 17:30:00", 5, 1, nil},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rows := sqlmock.NewRows(taskColumnNames).
+				AddRow(taskRow(5, title, tc.patch.Done != nil, now, now)...)
+			mock.ExpectBegin()
+			mock.ExpectQuery(taskSnapshotQuery).WithArgs(5, nil).WillReturnRows(sqlmock.NewRows(taskColumnNames).AddRow(taskRow(5, "Old", false, now, now)...))
+			if tc.patch.ProjectID.ID != nil {
+				mock.ExpectQuery(checkProjectQuery).WithArgs(*tc.patch.ProjectID.ID, nil).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			}
+			if tc.patch.Done != nil && *tc.patch.Done {
+				mock.ExpectQuery(checkBlockedQuery).WithArgs(5, nil).WillReturnRows(sqlmock.NewRows([]string{"blocked"}).AddRow(false))
+			}
+			mock.ExpectQuery(regexp.QuoteMeta(tc.query)).WithArgs(tc.args...).WillReturnRows(rows)
+			expectTaskEvent(mock, 5, domain.TaskEventUpdated).WillReturnResult(sqlmock.NewResult(1, 1))
+			if tc.patch.Done != nil {
+				expectTouchDependents(mock, 5).WillReturnResult(sqlmock.NewResult(0, 0))
+			}
+			mock.ExpectCommit()
+			expectTaskTags(mock, 5).WillReturnRows(sqlmock.NewRows(taskTagColumnNames))
+
+			if _, err := NewTaskRepository(db).Patch(t.Context(), 5, tc.patch); err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+		})
+	}
+
+	t.Run("should return not found when no row matches", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(taskSnapshotQuery).WithArgs(5, nil).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := NewTaskRepository(db).Patch(t.Context(), 5, domain.TaskPatch{Title: &title})
+		if !errors.Is(err, domain.ErrTaskNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTaskRepository_Delete(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	query := regexp.QuoteMeta("WITH RECURSIVE subtree(task_id) AS (SELECT id FROM tasks WHERE id = ? AND version = ? AND owner_id IS ? AND deleted_at IS NULL " +
+		"UNION ALL SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.task_id WHERE tasks.deleted_at IS NULL) " +
+		"UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP, deletion_id = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id IN (SELECT task_id FROM subtree) RETURNING id")
+	snapshot := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery(taskSnapshotQuery).WithArgs(1, nil)
+	}
+	current := func() *sqlmock.Rows {
+		return sqlmock.NewRows(taskColumnNames).AddRow(taskRow(1, "Task", false, now, now)...)
+	}
+
+	testCases := []struct {
+		name        string
+		version     int64
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name: "should move the task and its subtasks to the trash",
+			setup: func() {
+				mock.ExpectBegin()
+				snapshot().WillReturnRows(current())
+				expectNextDeletionID(mock, 6)
+				mock.ExpectQuery(query).WithArgs(1, 1, nil, 6).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+				expectTaskEvent(mock, 1, domain.TaskEventDeleted).WillReturnResult(sqlmock.NewResult(1, 1))
+				expectTaskEvent(mock, 2, domain.TaskEventDeleted).WillReturnResult(sqlmock.NewResult(2, 1))
+				expectTouchDependents(mock, 1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "should return not found for a missing task",
+			setup: func() {
+				mock.ExpectBegin()
+				snapshot().WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			expectedErr: domain.ErrTaskNotFound,
+		},
+		{
+			name:    "should refuse a stale version",
+			version: 2,
+			setup: func() {
+				mock.ExpectBegin()
+				snapshot().WillReturnRows(current())
+				mock.ExpectRollback()
+			},
+			expectedErr: domain.ErrTaskVersionConflict,
+		},
+		{
+			name:    "should refuse a version changed concurrently",
+			version: 1,
+			setup: func() {
+				mock.ExpectBegin()
+				snapshot().WillReturnRows(current())
+				expectNextDeletionID(mock, 6)
+				mock.ExpectQuery(query).WithArgs(1, 1, nil, 6).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			expectedErr: domain.ErrTaskVersionConflict,
+		},
+		{
+			name: "should return error when the update fails",
+			setup: func() {
+				mock.ExpectBegin()
+				snapshot().WillReturnRows(current())
+				expectNextDeletionID(mock, 6)
+				mock.ExpectQuery(query).WithArgs(1, 1, nil, 6).WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setup()
+			err := NewTaskRepository(db).Delete(t.Context(), 1, tc.version)
+
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTaskRepository_Patch_Parent(t *testing.T) {
+	t.Parallel()
+
+	ancestry := regexp.QuoteMeta("WITH RECURSIVE ancestors(task_id, parent_id, depth) AS (")
+	height := regexp.QuoteMeta("WITH RECURSIVE subtree(task_id, depth) AS (")
+	update := regexp.QuoteMeta("UPDATE tasks SET parent_id = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND owner_id IS ? AND deleted_at IS NULL")
+	parentID := int64(2)
+
+	testCases := []struct {
+		name        string
+		levels      int
+		cycle       bool
+		height      int
+		expectedErr error
+	}{
+		{name: "should move the task under the parent", levels: 2, height: 2},
+		{name: "should reject a missing parent", levels: 0, expectedErr: domain.ErrParentTaskNotFound},
+		{name: "should reject a subtask as parent", levels: 3, cycle: true, expectedErr: domain.ErrTaskHierarchyCycle},
+		{name: "should reject a tree deeper than the maximum", levels: domain.MaxTaskDepth - 1, height: 2, expectedErr: domain.ErrTaskTooDeep},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal("failed to create mock")
+			}
+			defer db.Close()
+
+			now := time.Now()
+			mock.ExpectBegin()
+			mock.ExpectQuery(taskSnapshotQuery).WithArgs(5, nil).WillReturnRows(sqlmock.NewRows(taskColumnNames).AddRow(taskRow(5, "Child", false, now, now)...))
+			mock.ExpectQuery(ancestry).WithArgs(parentID, nil, domain.MaxTaskDepth, 5).
+				WillReturnRows(sqlmock.NewRows([]string{"levels", "cycle"}).AddRow(tc.levels, tc.cycle))
+			if tc.levels > 0 && !tc.cycle {
+				mock.ExpectQuery(height).WithArgs(5, domain.MaxTaskDepth).
+					WillReturnRows(sqlmock.NewRows([]string{"height"}).AddRow(tc.height))
+			}
+			if tc.expectedErr == nil {
+				moved := taskRow(5, "Child", false, now, now)
+				moved[8] = parentID
+				mock.ExpectQuery(update).WithArgs(parentID, 5, 1, nil).WillReturnRows(sqlmock.NewRows(taskColumnNames).AddRow(moved...))
+				expectTaskEvent(mock, 5, domain.TaskEventUpdated).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+				expectTaskTags(mock, 5).WillReturnRows(sqlmock.NewRows(taskTagColumnNames))
+			} else {
+				mock.ExpectRollback()
+			}
+
+			patch := domain.TaskPatch{ParentID: domain.NullableID{Set: true, ID: &parentID}}
+			_, err = NewTaskRepository(db).Patch(t.Context(), 5, patch)
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestTaskRepository_Subtree(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	query := regexp.QuoteMeta("WITH RECURSIVE subtree(task_id, depth) AS (SELECT id, 0 FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL " +
+		"UNION ALL SELECT tasks.id, subtree.depth + 1 FROM tasks JOIN subtree ON tasks.parent_id = subtree.task_id WHERE tasks.deleted_at IS NULL) " +
+		"SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL + " " +
+		"FROM subtree JOIN tasks ON tasks.id = subtree.task_id ORDER BY subtree.depth, created_at, id")
+
+	t.Run("should return the task followed by its descendants", func(t *testing.T) {
+		child := taskRow(2, "Child", false, now, now)
+		child[8] = 1
+		mock.ExpectQuery(query).WithArgs(1, nil).WillReturnRows(sqlmock.NewRows(taskColumnNames).
+			AddRow(taskRow(1, "Root", false, now, now)...).
+			AddRow(child...))
+		expectTaskTags(mock, 1, 2).WillReturnRows(sqlmock.NewRows(taskTagColumnNames))
+
+		tasks, err := NewTaskRepository(db).Subtree(t.Context(), 1)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if len(tasks) != 2 || tasks[1].ParentID == nil || *tasks[1].ParentID != 1 {
+			t.Fatalf("unexpected tasks %v", tasks)
+		}
+	})
+
+	t.Run("should return not found for a missing task", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(9, nil).WillReturnRows(sqlmock.NewRows(taskColumnNames))
+
+		if _, err := NewTaskRepository(db).Subtree(t.Context(), 9); !errors.Is(err, domain.ErrTaskNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTaskRepository_CompleteDescendants(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	subtree := "WITH RECURSIVE subtree(task_id) AS (SELECT id FROM tasks WHERE parent_id = ? AND owner_id IS ? AND deleted_at IS NULL " +
+		"UNION ALL SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.task_id WHERE tasks.deleted_at IS NULL) "
+	check := regexp.QuoteMeta(subtree + "SELECT EXISTS (SELECT 1 FROM subtree JOIN tasks ON tasks.id = subtree.task_id " +
+		"JOIN task_dependencies ON task_dependencies.task_id = tasks.id JOIN tasks AS blockers ON blockers.id = task_dependencies.blocker_id " +
+		"WHERE NOT tasks.done AND NOT blockers.done AND blockers.deleted_at IS NULL AND blockers.id NOT IN (SELECT task_id FROM subtree))")
+	query := regexp.QuoteMeta(subtree +
+		"UPDATE tasks SET done = ?, completed_at = CASE WHEN ? THEN COALESCE(completed_at, CURRENT_TIMESTAMP) END, " +
+		"updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE done = 0 AND id IN (SELECT task_id FROM subtree) RETURNING id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " + taskBlockedSQL)
+	now := time.Now()
+
+	t.Run("should complete the open descendants", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(check).WithArgs(1, nil).WillReturnRows(sqlmock.NewRows([]string{"blocked"}).AddRow(false))
+		mock.ExpectQuery(query).WithArgs(1, nil, true, true).WillReturnRows(sqlmock.NewRows(taskColumnNames).
+			AddRow(taskRow(2, "Child", true, now, now)...).
+			AddRow(taskRow(3, "Grandchild", true, now, now)...))
+		expectTaskEvent(mock, 2, domain.TaskEventUpdated).WillReturnResult(sqlmock.NewResult(1, 1))
+		expectTaskEvent(mock, 3, domain.TaskEventUpdated).WillReturnResult(sqlmock.NewResult(2, 1))
+		expectTouchDependents(mock, 2, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		if err := NewTaskRepository(db).CompleteDescendants(t.Context(), 1); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	})
+
+	t.Run("should refuse while a descendant is blocked", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(check).WithArgs(1, nil).WillReturnRows(sqlmock.NewRows([]string{"blocked"}).AddRow(true))
+		mock.ExpectRollback()
+
+		if err := NewTaskRepository(db).CompleteDescendants(t.Context(), 1); !errors.Is(err, domain.ErrTaskBlocked) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskBlocked, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTaskRepository_AddBlocker(t *testing.T) {
+	t.Parallel()
+
+	exists := regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL), EXISTS (SELECT 1 FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL)")
+	cycle := regexp.QuoteMeta("WITH RECURSIVE chain(task_id) AS (SELECT ? UNION SELECT task_dependencies.blocker_id FROM task_dependencies " +
+		"JOIN chain ON task_dependencies.task_id = chain.task_id) SELECT EXISTS (SELECT 1 FROM chain WHERE task_id = ?)")
+	insert := regexp.QuoteMeta("INSERT INTO task_dependencies (task_id, blocker_id) VALUES (?, ?) ON CONFLICT DO NOTHING")
+	touch := regexp.QuoteMeta("UPDATE tasks SET updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND owner_id IS ?")
+
+	testCases := []struct {
+		name        string
+		setup       func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "should add the dependency and touch the task",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(exists).WithArgs(1, nil, 2, nil).WillReturnRows(sqlmock.NewRows([]string{"task", "blocker"}).AddRow(true, true))
+				mock.ExpectQuery(cycle).WithArgs(2, 1).WillReturnRows(sqlmock.NewRows([]string{"cycle"}).AddRow(false))
+				mock.ExpectExec(insert).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(touch).WithArgs(1, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "should leave an existing dependency alone",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(exists).WithArgs(1, nil, 2, nil).WillReturnRows(sqlmock.NewRows([]string{"task", "blocker"}).AddRow(true, true))
+				mock.ExpectQuery(cycle).WithArgs(2, 1).WillReturnRows(sqlmock.NewRows([]string{"cycle"}).AddRow(false))
+				mock.ExpectExec(insert).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "should refuse a dependency cycle",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(exists).WithArgs(1, nil, 2, nil).WillReturnRows(sqlmock.NewRows([]string{"task", "blocker"}).AddRow(true, true))
+				mock.ExpectQuery(cycle).WithArgs(2, 1).WillReturnRows(sqlmock.NewRows([]string{"cycle"}).AddRow(true))
+				mock.ExpectRollback()
+			},
+			expectedErr: domain.ErrDependencyCycle,
+		},
+		{
+			name: "should return not found for a missing blocker",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(exists).WithArgs(1, nil, 2, nil).WillReturnRows(sqlmock.NewRows([]string{"task", "blocker"}).AddRow(true, false))
+				mock.ExpectRollback()
+			},
+			expectedErr: domain.ErrTaskNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal("failed to create mock")
+			}
+			defer db.Close()
+			tc.setup(mock)
+
+			err = NewTaskRepository(db).AddBlocker(t.Context(), 1, 2)
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestTaskRepository_RemoveBlocker(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL), EXISTS (SELECT 1 FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL)")).
+		WithArgs(1, nil, 2, nil).WillReturnRows(sqlmock.NewRows([]string{"task", "blocker"}).AddRow(true, true))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM task_dependencies WHERE task_id = ? AND blocker_id = ?")).
+		WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tasks SET updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND owner_id IS ?")).
+		WithArgs(1, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := NewTaskRepository(db).RemoveBlocker(t.Context(), 1, 2); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTaskRepository_Blockers(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	exists := regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL)")
+	query := regexp.QuoteMeta("SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " +
+		taskBlockedSQL + " FROM tasks WHERE id IN (SELECT blocker_id FROM task_dependencies WHERE task_id = ?) AND owner_id IS ? AND deleted_at IS NULL ORDER BY created_at, id")
+
+	t.Run("should return the blockers", func(t *testing.T) {
+		mock.ExpectQuery(exists).WithArgs(1, nil).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery(query).WithArgs(1, nil).WillReturnRows(sqlmock.NewRows(taskColumnNames).
+			AddRow(taskRow(2, "Blocker", false, now, now)...))
+		expectTaskTags(mock, 2).WillReturnRows(sqlmock.NewRows(taskTagColumnNames))
+
+		tasks, err := NewTaskRepository(db).Blockers(t.Context(), 1)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if len(tasks) != 1 || tasks[0].ID != 2 {
+			t.Fatalf("unexpected blockers %v", tasks)
+		}
+	})
+
+	t.Run("should return not found for a missing task", func(t *testing.T) {
+		mock.ExpectQuery(exists).WithArgs(9, nil).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		if _, err := NewTaskRepository(db).Blockers(t.Context(), 9); !errors.Is(err, domain.ErrTaskNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTaskRepository_SetTags(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	exists := regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL)")
+	insertTag := regexp.QuoteMeta("INSERT INTO tags (owner_id, name) VALUES (?, ?) ON CONFLICT DO NOTHING")
+	selectTag := regexp.QuoteMeta("SELECT id FROM tags WHERE owner_id IS ? AND name = ?")
+	current := regexp.QuoteMeta("SELECT tag_id FROM task_tags WHERE task_id = ? ORDER BY tag_id")
+	detach := regexp.QuoteMeta("DELETE FROM task_tags WHERE task_id = ?")
+	attach := regexp.QuoteMeta("INSERT INTO task_tags (task_id, tag_id) VALUES (?, ?)")
+	touch := regexp.QuoteMeta("UPDATE tasks SET updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND owner_id IS ?")
+
+	expectTags := func(names map[string]int64, order ...string) {
+		for _, name := range order {
+			mock.ExpectExec(insertTag).WithArgs(nil, name).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery(selectTag).WithArgs(nil, name).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(names[name]))
+		}
+	}
+
+	t.Run("should replace changed tags and touch the task", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(exists).WithArgs(1, nil).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		expectTags(map[string]int64{"home": 5, "Home": 5, "work": 2}, "home", "work", "Home")
+		mock.ExpectQuery(current).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"tag_id"}).AddRow(2))
+		mock.ExpectExec(detach).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(attach).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(attach).WithArgs(1, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(touch).WithArgs(1, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		if err := NewTaskRepository(db).SetTags(t.Context(), 1, []string{"home", "work", "Home"}); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	})
+
+	t.Run("should leave unchanged tags alone", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(exists).WithArgs(1, nil).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		expectTags(map[string]int64{"work": 2}, "work")
+		mock.ExpectQuery(current).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"tag_id"}).AddRow(2))
+		mock.ExpectCommit()
+
+		if err := NewTaskRepository(db).SetTags(t.Context(), 1, []string{"work"}); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	})
+
+	t.Run("should return not found for a missing task", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(exists).WithArgs(9, nil).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectRollback()
+
+		if err := NewTaskRepository(db).SetTags(t.Context(), 9, []string{"work"}); !errors.Is(err, domain.ErrTaskNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestTaskRepository_Isolation runs every method as tenant 7 against task 5,
+// which belongs to another tenant, and checks that each statement is bound to
+// tenant 7 and that the task behaves as if it did not exist.
+func TestTaskRepository_Isolation(t *testing.T) {
+	t.Parallel()
+
+	const tenant = 7
+	exists := regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL)")
+	bothExist := regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL), " +
+		"EXISTS (SELECT 1 FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL)")
+	noRows := func() *sqlmock.Rows { return sqlmock.NewRows(taskColumnNames) }
+	falseRow := func() *sqlmock.Rows { return sqlmock.NewRows([]string{"exists"}).AddRow(false) }
+
+	testCases := []struct {
+		name  string
+		setup func(mock sqlmock.Sqlmock)
+		call  func(ctx context.Context, r *TaskRepository) error
+		// expectedErr is nil for the methods that list or count, which
+		// find nothing instead of failing.
+		expectedErr error
+	}{
+		{
+			name: "GetAll",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM tasks WHERE owner_id IS ? AND deleted_at IS NULL")).WithArgs(tenant).WillReturnRows(noRows())
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				tasks, err := r.GetAll(ctx, domain.TaskFilter{})
+				if err == nil && len(tasks) != 0 {
+					return fmt.Errorf("unexpected tasks %v", tasks)
+				}
+				return err
+			},
+		},
+		{
+			name: "Count",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM tasks WHERE owner_id IS ? AND deleted_at IS NULL")).WithArgs(tenant).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				total, err := r.Count(ctx, domain.TaskFilter{})
+				if err == nil && total != 0 {
+					return fmt.Errorf("unexpected total %d", total)
+				}
+				return err
+			},
+		},
+		{
+			name: "Search",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("JOIN tasks ON tasks.id = match_id WHERE owner_id IS ? AND deleted_at IS NULL")).WithArgs("invoice", tenant).
+					WillReturnRows(sqlmock.NewRows(append(taskColumnNames, "score", "snippet")))
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				results, err := r.Search(ctx, "invoice", domain.TaskFilter{})
+				if err == nil && len(results) != 0 {
+					return fmt.Errorf("unexpected results %v", results)
+				}
+				return err
+			},
+		},
+		{
+			name: "GetByID",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(taskSnapshotQuery).WithArgs(5, tenant).WillReturnRows(noRows())
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				_, err := r.GetByID(ctx, 5)
+				return err
+			},
+			expectedErr: domain.ErrTaskNotFound,
+		},
+		{
+			name: "Create",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO tasks")).WithArgs("New", "", false, domain.PriorityNone, nil, nil, nil, nil, "", false, tenant).
+					WillReturnRows(noRows().AddRow(taskRow(8, "New", false, time.Now(), time.Now())...))
+				expectTaskEvent(mock, 8, domain.TaskEventCreated).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				_, err := r.Create(ctx, domain.Task{Title: "New"})
+				return err
+			},
+		},
+		{
+			name: "Create under a parent of another tenant",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, parent_id, 1 FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL")).
+					WithArgs(5, tenant, domain.MaxTaskDepth, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"levels", "cycle"}).AddRow(0, false))
+				mock.ExpectRollback()
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				parentID := int64(5)
+				_, err := r.Create(ctx, domain.Task{Title: "New", ParentID: &parentID})
+				return err
+			},
+			expectedErr: domain.ErrParentTaskNotFound,
+		},
+		{
+			name: "Create in a project of another tenant",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(checkProjectQuery).WithArgs(2, tenant).WillReturnRows(falseRow())
+				mock.ExpectRollback()
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				projectID := int64(2)
+				_, err := r.Create(ctx, domain.Task{Title: "New", ProjectID: &projectID})
+				return err
+			},
+			expectedErr: domain.ErrProjectNotFound,
+		},
+		{
+			name: "Patch into a project of another tenant",
+			setup: func(mock sqlmock.Sqlmock) {
+				// Task 6 belongs to tenant 7; project 2 does not.
+				now := time.Now()
+				mock.ExpectBegin()
+				mock.ExpectQuery(taskSnapshotQuery).WithArgs(6, tenant).WillReturnRows(sqlmock.NewRows(taskColumnNames).AddRow(taskRow(6, "Mine", false, now, now)...))
+				mock.ExpectQuery(checkProjectQuery).WithArgs(2, tenant).WillReturnRows(falseRow())
+				mock.ExpectRollback()
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				projectID := int64(2)
+				_, err := r.Patch(ctx, 6, domain.TaskPatch{ProjectID: domain.NullableID{Set: true, ID: &projectID}})
+				return err
+			},
+			expectedErr: domain.ErrProjectNotFound,
+		},
+		{
+			name: "Update",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(taskSnapshotQuery).WithArgs(5, tenant).WillReturnRows(noRows())
+				mock.ExpectRollback()
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				_, err := r.Update(ctx, domain.Task{ID: 5, Title: "Mine now"})
+				return err
+			},
+			expectedErr: domain.ErrTaskNotFound,
+		},
+		{
+			name: "Patch",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(taskSnapshotQuery).WithArgs(5, tenant).WillReturnRows(noRows())
+				mock.ExpectRollback()
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				title := "Mine now"
+				_, err := r.Patch(ctx, 5, domain.TaskPatch{Title: &title})
+				return err
+			},
+			expectedErr: domain.ErrTaskNotFound,
+		},
+		{
+			name: "Delete",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(taskSnapshotQuery).WithArgs(5, tenant).WillReturnRows(noRows())
+				mock.ExpectRollback()
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				return r.Delete(ctx, 5, 0)
+			},
+			expectedErr: domain.ErrTaskNotFound,
+		},
+		{
+			name: "Subtree",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, 0 FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL")).WithArgs(5, tenant).WillReturnRows(noRows())
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				_, err := r.Subtree(ctx, 5)
+				return err
+			},
+			expectedErr: domain.ErrTaskNotFound,
+		},
+		{
+			name: "CompleteDescendants",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM tasks WHERE parent_id = ? AND owner_id IS ? AND deleted_at IS NULL")).
+					WithArgs(5, tenant).WillReturnRows(sqlmock.NewRows([]string{"blocked"}).AddRow(false))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM tasks WHERE parent_id = ? AND owner_id IS ? AND deleted_at IS NULL")).
+					WithArgs(5, tenant, true, true).WillReturnRows(noRows())
+				mock.ExpectCommit()
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				return r.CompleteDescendants(ctx, 5)
+			},
+		},
+		{
+			name: "AddBlocker",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(bothExist).WithArgs(1, tenant, 5, tenant).
+					WillReturnRows(sqlmock.NewRows([]string{"task", "blocker"}).AddRow(true, false))
+				mock.ExpectRollback()
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				return r.AddBlocker(ctx, 1, 5)
+			},
+			expectedErr: domain.ErrTaskNotFound,
+		},
+		{
+			name: "RemoveBlocker",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(bothExist).WithArgs(5, tenant, 1, tenant).
+					WillReturnRows(sqlmock.NewRows([]string{"task", "blocker"}).AddRow(false, true))
+				mock.ExpectRollback()
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				return r.RemoveBlocker(ctx, 5, 1)
+			},
+			expectedErr: domain.ErrTaskNotFound,
+		},
+		{
+			name: "Blockers",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(exists).WithArgs(5, tenant).WillReturnRows(falseRow())
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				_, err := r.Blockers(ctx, 5)
+				return err
+			},
+			expectedErr: domain.ErrTaskNotFound,
+		},
+		{
+			name: "SetTags",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(exists).WithArgs(5, tenant).WillReturnRows(falseRow())
+				mock.ExpectRollback()
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				return r.SetTags(ctx, 5, []string{"work"})
+			},
+			expectedErr: domain.ErrTaskNotFound,
+		},
+		{
+			name: "History",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND owner_id IS ?)")).WithArgs(5, tenant).WillReturnRows(falseRow())
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				_, err := r.History(ctx, 5, 10, 0)
+				return err
+			},
+			expectedErr: domain.ErrTaskNotFound,
+		},
+		{
+			name: "CountHistory",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("WHERE task_events.task_id = ? AND tasks.owner_id IS ?")).WithArgs(5, tenant).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+			call: func(ctx context.Context, r *TaskRepository) error {
+				total, err := r.CountHistory(ctx, 5)
+				if err == nil && total != 0 {
+					return fmt.Errorf("unexpected total %d", total)
+				}
+				return err
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal("failed to create mock")
+			}
+			defer db.Close()
+			tc.setup(mock)
+
+			err = tc.call(domain.WithTenant(t.Context(), tenant), NewTaskRepository(db))
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
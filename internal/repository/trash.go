@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+// TrashRepository implements domain.TrashRepository on top of the tasks
+// table, working only with the rows whose deleted_at is set. Like
+// TaskRepository, it only reaches the tasks of the tenant of the context,
+// except when purging expired tasks, which it does for every tenant.
+type TrashRepository struct {
+	db    *sql.DB
+	tasks *TaskRepository
+}
+
+// NewTrashRepository creates a new TrashRepository.
+func NewTrashRepository(db *sql.DB) *TrashRepository {
+	return &TrashRepository{db: db, tasks: NewTaskRepository(db)}
+}
+
+// List retrieves the tasks in the trash, most recently deleted first.
+func (r *TrashRepository) List(ctx context.Context) ([]domain.TrashedTask, error) {
+	q := "SELECT " + taskColumns + ", deleted_at FROM tasks WHERE " + ownerCondition + " AND deleted_at IS NOT NULL ORDER BY deleted_at DESC, id DESC"
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, tenantOwner(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("TrashRepository.List: querying: %w", err)
+	}
+	defer rows.Close()
+
+	trashed := []domain.TrashedTask{}
+	for rows.Next() {
+		var deletedAt time.Time
+		task, err := scanTask(rows, &deletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("TrashRepository.List: scanning row: %w", err)
+		}
+		trashed = append(trashed, domain.TrashedTask{Task: task, DeletedAt: deletedAt})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("TrashRepository.List: iterating rows: %w", err)
+	}
+
+	tasks := make([]domain.Task, len(trashed))
+	for i, t := range trashed {
+		tasks[i] = t.Task
+	}
+	if err := r.tasks.loadTags(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("TrashRepository.List: %w", err)
+	}
+	for i := range trashed {
+		trashed[i].Task = tasks[i]
+	}
+
+	return trashed, nil
+}
+
+// Restore takes a task out of the trash along with the subtasks that were
+// deleted with it, recognised by their deletion ID. Subtasks deleted
+// separately beforehand stay in the trash. A subtask cannot be
+// restored while its parent is still in the trash.
+func (r *TrashRepository) Restore(ctx context.Context, id int64) (domain.Task, error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TrashRepository.Restore: beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var (
+		deletionID    sql.NullInt64
+		parentTrashed bool
+	)
+	err = tx.QueryRowContext(ctx,
+		"SELECT deletion_id, EXISTS (SELECT 1 FROM tasks AS parents WHERE parents.id = tasks.parent_id AND parents.deleted_at IS NOT NULL) "+
+			"FROM tasks WHERE id = ? AND "+ownerCondition+" AND deleted_at IS NOT NULL", id, tenantOwner(ctx),
+	).Scan(&deletionID, &parentTrashed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Task{}, fmt.Errorf("TrashRepository.Restore: %w", domain.ErrTaskNotFound)
+	}
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TrashRepository.Restore: reading task: %w", err)
+	}
+	if parentTrashed {
+		return domain.Task{}, fmt.Errorf("TrashRepository.Restore: %w", domain.ErrParentTaskDeleted)
+	}
+
+	q := "WITH RECURSIVE subtree(task_id) AS (" +
+		"SELECT ? UNION ALL SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.task_id WHERE tasks.deletion_id = ?" +
+		") UPDATE tasks SET deleted_at = NULL, deletion_id = NULL, " + taskTouch + " WHERE id IN (SELECT task_id FROM subtree) RETURNING id"
+	ids, err := queryIDs(ctx, tx, q, id, deletionID)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TrashRepository.Restore: restoring: %w", err)
+	}
+	for _, restored := range ids {
+		if err := recordTaskEvent(ctx, tx, restored, domain.TaskEventRestored, nil); err != nil {
+			return domain.Task{}, fmt.Errorf("TrashRepository.Restore: %w", err)
+		}
+	}
+	if err := touchDependents(ctx, tx, ids); err != nil {
+		return domain.Task{}, fmt.Errorf("TrashRepository.Restore: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Task{}, fmt.Errorf("TrashRepository.Restore: committing: %w", err)
+	}
+
+	task, err := r.tasks.GetByID(ctx, id)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TrashRepository.Restore: %w", err)
+	}
+
+	return task, nil
+}
+
+// Purge permanently deletes a task in the trash. Its subtasks, which are
+// always in the trash with it, go through the parent_id cascade.
+func (r *TrashRepository) Purge(ctx context.Context, id int64) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		"DELETE FROM tasks WHERE id = ? AND "+ownerCondition+" AND deleted_at IS NOT NULL", id, tenantOwner(ctx))
+	if err != nil {
+		return fmt.Errorf("TrashRepository.Purge: deleting: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("TrashRepository.Purge: reading affected rows: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("TrashRepository.Purge: %w", domain.ErrTaskNotFound)
+	}
+
+	return nil
+}
+
+// PurgeDeletedBefore permanently deletes the tasks deleted before cutoff.
+func (r *TrashRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM tasks WHERE deleted_at < ?", formatTimestamp(cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("TrashRepository.PurgeDeletedBefore: deleting: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("TrashRepository.PurgeDeletedBefore: reading affected rows: %w", err)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+func TestTrashRepository_List(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	columns := append(taskColumnNames, "deleted_at")
+	query := regexp.QuoteMeta("SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " +
+		taskBlockedSQL + ", deleted_at FROM tasks WHERE owner_id IS ? AND deleted_at IS NOT NULL ORDER BY deleted_at DESC, id DESC")
+
+	t.Run("should return the deleted tasks with their tags", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(nil).WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(append(taskRow(4, "Old", false, now, now), now)...))
+		expectTaskTags(mock, 4).WillReturnRows(sqlmock.NewRows(taskTagColumnNames).AddRow(4, 1, "backend", now, now))
+
+		trashed, err := NewTrashRepository(db).List(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if len(trashed) != 1 || trashed[0].Task.ID != 4 || !trashed[0].DeletedAt.Equal(now) || len(trashed[0].Task.Tags) != 1 {
+			t.Fatalf("unexpected trash %v", trashed)
+		}
+	})
+
+	t.Run("should return empty list", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(nil).WillReturnRows(sqlmock.NewRows(columns))
+
+		trashed, err := NewTrashRepository(db).List(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if trashed == nil || len(trashed) != 0 {
+			t.Fatalf("expected empty list but got %v", trashed)
+		}
+	})
+
+	t.Run("should return error when query fails", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(nil).WillReturnError(sql.ErrConnDone)
+
+		if _, err := NewTrashRepository(db).List(t.Context()); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTrashRepository_Restore(t *testing.T) {
+	t.Parallel()
+
+	deletedAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	selectTask := regexp.QuoteMeta("SELECT deletion_id, EXISTS (SELECT 1 FROM tasks AS parents WHERE parents.id = tasks.parent_id AND parents.deleted_at IS NOT NULL) " +
+		"FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NOT NULL")
+	restore := regexp.QuoteMeta("WITH RECURSIVE subtree(task_id) AS (SELECT ? UNION ALL SELECT tasks.id FROM tasks " +
+		"JOIN subtree ON tasks.parent_id = subtree.task_id WHERE tasks.deletion_id = ?) " +
+		"UPDATE tasks SET deleted_at = NULL, deletion_id = NULL, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id IN (SELECT task_id FROM subtree) RETURNING id")
+	getByID := regexp.QuoteMeta("SELECT id, title, description, done, priority, start_at, due_at, project_id, parent_id, recurrence, completed_at, created_at, updated_at, version, " +
+		taskBlockedSQL + " FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NULL")
+
+	testCases := []struct {
+		name        string
+		setup       func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "should restore the task and the subtasks deleted with it",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectTask).WithArgs(3, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"deletion_id", "parent_trashed"}).AddRow(6, false))
+				mock.ExpectQuery(restore).WithArgs(3, 6).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(4))
+				expectTaskEvent(mock, 3, domain.TaskEventRestored).WillReturnResult(sqlmock.NewResult(1, 1))
+				expectTaskEvent(mock, 4, domain.TaskEventRestored).WillReturnResult(sqlmock.NewResult(2, 1))
+				expectTouchDependents(mock, 3, 4).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+				mock.ExpectQuery(getByID).WithArgs(3, nil).
+					WillReturnRows(sqlmock.NewRows(taskColumnNames).AddRow(taskRow(3, "Back", false, deletedAt, deletedAt)...))
+				expectTaskTags(mock, 3).WillReturnRows(sqlmock.NewRows(taskTagColumnNames))
+			},
+		},
+		{
+			name: "should refuse while the parent is in the trash",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectTask).WithArgs(3, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"deletion_id", "parent_trashed"}).AddRow(6, true))
+				mock.ExpectRollback()
+			},
+			expectedErr: domain.ErrParentTaskDeleted,
+		},
+		{
+			name: "should return not found for a task outside the trash",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectTask).WithArgs(3, nil).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			expectedErr: domain.ErrTaskNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal("failed to create mock")
+			}
+			defer db.Close()
+			tc.setup(mock)
+
+			task, err := NewTrashRepository(db).Restore(t.Context(), 3)
+			if tc.expectedErr == nil && (err != nil || task.ID != 3) {
+				t.Fatalf("expected restored task 3 but got %v, %v", task, err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestTrashRepository_Purge(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	query := regexp.QuoteMeta("DELETE FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NOT NULL")
+
+	t.Run("should delete a task in the trash", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(3, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		if err := NewTrashRepository(db).Purge(t.Context(), 3); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	})
+
+	t.Run("should return not found for a task outside the trash", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(3, nil).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		if err := NewTrashRepository(db).Purge(t.Context(), 3); !errors.Is(err, domain.ErrTaskNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTrashRepository_PurgeDeletedBefore(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	cutoff := time.Date(2026, 9, 18, 9, 0, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM tasks WHERE deleted_at < ?")).
+		WithArgs("2026-09-18 09:00:00").
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	n, err := NewTrashRepository(db).PurgeDeletedBefore(t.Context(), cutoff)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if n != 4 {
+		t.Fatalf("expected 4 purged tasks but got %d", n)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTrashRepository_Isolation(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	ctx := domain.WithTenant(t.Context(), 7)
+	repo := NewTrashRepository(db)
+
+	t.Run("should list only the trash of the tenant", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("FROM tasks WHERE owner_id IS ? AND deleted_at IS NOT NULL")).WithArgs(7).
+			WillReturnRows(sqlmock.NewRows(append(taskColumnNames, "deleted_at")))
+
+		trashed, err := repo.List(ctx)
+		if err != nil || len(trashed) != 0 {
+			t.Fatalf("expected empty trash but got %v, %v", trashed, err)
+		}
+	})
+
+	t.Run("should not restore a task of another tenant", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NOT NULL")).WithArgs(3, 7).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		if _, err := repo.Restore(ctx, 3); !errors.Is(err, domain.ErrTaskNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskNotFound, err)
+		}
+	})
+
+	t.Run("should not purge a task of another tenant", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM tasks WHERE id = ? AND owner_id IS ? AND deleted_at IS NOT NULL")).WithArgs(3, 7).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		if err := repo.Purge(ctx, 3); !errors.Is(err, domain.ErrTaskNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskNotFound, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DBTX is the part of *sql.DB and *sql.Tx the repositories run statements
+// on, so the same code serves both.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// txKey is the context key of the transaction a unit of work runs in.
+type txKey struct{}
+
+// txState is the transaction shared by every unit of work nested in it.
+type txState struct {
+	tx         *sql.Tx
+	savepoints int
+}
+
+// Transactor runs units of work in SQLite transactions. It implements
+// domain.Transactor.
+type Transactor struct {
+	db *sql.DB
+}
+
+// NewTransactor creates a new Transactor.
+func NewTransactor(db *sql.DB) *Transactor {
+	return &Transactor{db: db}
+}
+
+// WithinTx runs fn in a transaction and commits it when fn succeeds. The
+// repositories join the transaction when called with the context fn
+// receives. Inside another WithinTx, fn runs in a savepoint instead, and a
+// failure only undoes its own changes.
+func (t *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := begin(ctx, t.db)
+	if err != nil {
+		return fmt.Errorf("Transactor.WithinTx: begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx.context(ctx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Transactor.WithinTx: commit: %w", err)
+	}
+	return nil
+}
+
+// conn returns the transaction ctx carries, or db outside of one.
+func conn(ctx context.Context, db *sql.DB) DBTX {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+	return db
+}
+
+// txn is a unit of work: a transaction of its own, or a savepoint of the
+// transaction ctx already carries. Rollback after Commit is a no-op, so it
+// can be deferred like on *sql.Tx.
+type txn struct {
+	*sql.Tx
+	state     *txState
+	savepoint string
+	done      bool
+}
+
+// begin starts a unit of work on db, nested in the transaction of ctx when
+// there is one.
+func begin(ctx context.Context, db *sql.DB) (*txn, error) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.savepoints++
+		name := fmt.Sprintf("sp_%d", state.savepoints)
+		if _, err := state.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+			return nil, err
+		}
+		return &txn{Tx: state.tx, state: state, savepoint: name}, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &txn{Tx: tx, state: &txState{tx: tx}}, nil
+}
+
+// context returns ctx carrying the transaction of t.
+func (t *txn) context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, txKey{}, t.state)
+}
+
+// Commit commits the transaction, or releases the savepoint into the
+// enclosing transaction.
+func (t *txn) Commit() error {
+	if t.savepoint == "" {
+		return t.Tx.Commit()
+	}
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	_, err := t.Tx.Exec("RELEASE " + t.savepoint)
+	return err
+}
+
+// Rollback undoes the transaction, or the changes made since the savepoint.
+func (t *txn) Rollback() error {
+	if t.savepoint == "" {
+		return t.Tx.Rollback()
+	}
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	if _, err := t.Tx.Exec("ROLLBACK TO " + t.savepoint); err != nil {
+		return err
+	}
+	_, err := t.Tx.Exec("RELEASE " + t.savepoint)
+	return err
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestTransactor_WithinTx(t *testing.T) {
+	t.Parallel()
+	errFailed := errors.New("failed")
+	touch := func(ctx context.Context, r *ReminderRepository) error {
+		_, err := conn(ctx, r.db).ExecContext(ctx, "UPDATE reminders SET fired_at = NULL")
+		return err
+	}
+
+	testCases := []struct {
+		name        string
+		run         func(tx *Transactor, r *ReminderRepository) error
+		expect      func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "should commit when fn succeeds",
+			run: func(tx *Transactor, r *ReminderRepository) error {
+				return tx.WithinTx(t.Context(), func(ctx context.Context) error { return touch(ctx, r) })
+			},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE reminders").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "should roll back when fn fails",
+			run: func(tx *Transactor, r *ReminderRepository) error {
+				return tx.WithinTx(t.Context(), func(ctx context.Context) error {
+					if err := touch(ctx, r); err != nil {
+						return err
+					}
+					return errFailed
+				})
+			},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE reminders").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectRollback()
+			},
+			expectedErr: errFailed,
+		},
+		{
+			name: "should release the savepoint of a nested unit of work",
+			run: func(tx *Transactor, r *ReminderRepository) error {
+				return tx.WithinTx(t.Context(), func(ctx context.Context) error {
+					return tx.WithinTx(ctx, func(ctx context.Context) error { return touch(ctx, r) })
+				})
+			},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE reminders").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("RELEASE sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "should only undo a failed nested unit of work",
+			run: func(tx *Transactor, r *ReminderRepository) error {
+				return tx.WithinTx(t.Context(), func(ctx context.Context) error {
+					err := tx.WithinTx(ctx, func(ctx context.Context) error { return errFailed })
+					if !errors.Is(err, errFailed) {
+						t.Errorf("expected error %v but got %v", errFailed, err)
+					}
+					return tx.WithinTx(ctx, func(ctx context.Context) error { return touch(ctx, r) })
+				})
+			},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ROLLBACK TO sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("RELEASE sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE reminders").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("RELEASE sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal("failed to create mock")
+			}
+			defer db.Close()
+			tc.expect(mock)
+
+			err = tc.run(NewTransactor(db), NewReminderRepository(db))
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestTransactor_WithinTx_RepositoriesJoin(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE tasks").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM tags").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("RELEASE sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err = NewTransactor(db).WithinTx(t.Context(), func(ctx context.Context) error {
+		return NewTagRepository(db).Delete(ctx, 1)
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+const userColumns = "id, email, name, created_at, updated_at"
+
+// UserRepository implements domain.UserRepository.
+type UserRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository creates a new UserRepository.
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// Create inserts a new user and returns it as stored.
+func (r *UserRepository) Create(ctx context.Context, user domain.User, passwordHash string) (domain.User, error) {
+	q := "INSERT INTO users (email, name, password_hash) VALUES (?, ?, ?) RETURNING " + userColumns
+	var created domain.User
+	err := conn(ctx, r.db).QueryRowContext(ctx, q, user.Email, user.Name, passwordHash).
+		Scan(&created.ID, &created.Email, &created.Name, &created.CreatedAt, &created.UpdatedAt)
+	if isUniqueViolation(err) {
+		return domain.User{}, fmt.Errorf("UserRepository.Create: %w", domain.ErrUserAlreadyExists)
+	}
+	if err != nil {
+		return domain.User{}, fmt.Errorf("UserRepository.Create: inserting: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetByEmail retrieves a user and its password hash by email, ignoring case.
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (domain.User, string, error) {
+	q := "SELECT " + userColumns + ", password_hash FROM users WHERE email = ?"
+	var (
+		user         domain.User
+		passwordHash string
+	)
+	err := conn(ctx, r.db).QueryRowContext(ctx, q, email).
+		Scan(&user.ID, &user.Email, &user.Name, &user.CreatedAt, &user.UpdatedAt, &passwordHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, "", fmt.Errorf("UserRepository.GetByEmail: %w", domain.ErrUserNotFound)
+	}
+	if err != nil {
+		return domain.User{}, "", fmt.Errorf("UserRepository.GetByEmail: querying: %w", err)
+	}
+
+	return user, passwordHash, nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mattn/go-sqlite3"
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+var userColumnNames = []string{"id", "email", "name", "created_at", "updated_at"}
+
+func TestUserRepository_Create(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	query := regexp.QuoteMeta("INSERT INTO users (email, name, password_hash) VALUES (?, ?, ?) RETURNING id, email, name, created_at, updated_at")
+	user := domain.User{Email: "ada@example.com", Name: "Ada"}
+
+	t.Run("should store the password hash", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs("ada@example.com", "Ada", "$argon2id$hash").
+			WillReturnRows(sqlmock.NewRows(userColumnNames).AddRow(1, "ada@example.com", "Ada", now, now))
+
+		created, err := NewUserRepository(db).Create(t.Context(), user, "$argon2id$hash")
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		expected := domain.User{ID: 1, Email: "ada@example.com", Name: "Ada", CreatedAt: now, UpdatedAt: now}
+		if !reflect.DeepEqual(created, expected) {
+			t.Fatalf("expected user %v but got %v", expected, created)
+		}
+	})
+
+	t.Run("should report a taken email", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WillReturnError(sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique})
+
+		if _, err := NewUserRepository(db).Create(t.Context(), user, "$argon2id$hash"); !errors.Is(err, domain.ErrUserAlreadyExists) {
+			t.Fatalf("expected error %v but got %v", domain.ErrUserAlreadyExists, err)
+		}
+	})
+
+	t.Run("should return error when insert fails", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
+
+		if _, err := NewUserRepository(db).Create(t.Context(), user, "$argon2id$hash"); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUserRepository_GetByEmail(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal("failed to create mock")
+	}
+	defer db.Close()
+
+	now := time.Now()
+	query := regexp.QuoteMeta("SELECT id, email, name, created_at, updated_at, password_hash FROM users WHERE email = ?")
+
+	t.Run("should return the user and its password hash", func(t *testing.T) {
+		rows := sqlmock.NewRows(append(userColumnNames, "password_hash")).
+			AddRow(1, "ada@example.com", "Ada", now, now, "$argon2id$hash")
+		mock.ExpectQuery(query).WithArgs("ADA@example.com").WillReturnRows(rows)
+
+		user, hash, err := NewUserRepository(db).GetByEmail(t.Context(), "ADA@example.com")
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if user.ID != 1 || user.Email != "ada@example.com" || hash != "$argon2id$hash" {
+			t.Fatalf("unexpected user %v with hash %q", user, hash)
+		}
+	})
+
+	t.Run("should return not found", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(sql.ErrNoRows)
+
+		if _, _, err := NewUserRepository(db).GetByEmail(t.Context(), "bob@example.com"); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrUserNotFound, err)
+		}
+	})
+
+	t.Run("should return error when query fails", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
+
+		if _, _, err := NewUserRepository(db).GetByEmail(t.Context(), "ada@example.com"); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("expected error %v but got %v", sql.ErrConnDone, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	t.Run("should run jobs right away and on every tick", func(t *testing.T) {
+		var runs atomic.Int32
+		s := New(logger)
+		s.Every("count", 10*time.Millisecond, func(ctx context.Context) error {
+			runs.Add(1)
+			return errors.New("failures do not stop the job")
+		})
+		s.Start()
+
+		deadline := time.Now().Add(time.Second)
+		for runs.Load() < 3 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if err := s.Stop(t.Context()); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if runs.Load() < 3 {
+			t.Fatalf("expected at least 3 runs but got %d", runs.Load())
+		}
+	})
+
+	t.Run("should wait for running jobs to return", func(t *testing.T) {
+		started := make(chan struct{})
+		var finished atomic.Bool
+		s := New(logger)
+		s.Every("slow", time.Hour, func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			time.Sleep(20 * time.Millisecond)
+			finished.Store(true)
+			return ctx.Err()
+		})
+		s.Start()
+		<-started
+
+		if err := s.Stop(t.Context()); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if !finished.Load() {
+			t.Fatal("expected Stop to wait for the job")
+		}
+	})
+
+	t.Run("should give up waiting when the context ends", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		s := New(logger)
+		s.Every("stuck", time.Hour, func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+		s.Start()
+		<-started
+
+		ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+		defer cancel()
+		if err := s.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected error %v but got %v", context.DeadlineExceeded, err)
+		}
+	})
+
+	t.Run("should do nothing when stopped before starting", func(t *testing.T) {
+		if err := New(logger).Stop(t.Context()); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	})
+}
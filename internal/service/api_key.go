@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+// MaxAPIKeyNameLength is the maximum number of characters in an API key
+// name.
+const MaxAPIKeyNameLength = 100
+
+// apiKeyPrefixLength is how many characters of a key, after
+// domain.APIKeyPrefix, are kept in clear to tell keys apart.
+const apiKeyPrefixLength = 8
+
+// apiKeyTouchInterval is how stale last_used_at may grow before a request
+// stamps it again, so that busy keys do not write on every request.
+const apiKeyTouchInterval = time.Minute
+
+// APIKeyService provides business logic for API keys.
+type APIKeyService struct {
+	repo domain.APIKeyRepository
+	now  func() time.Time
+}
+
+// NewAPIKeyService creates a new APIKeyService.
+func NewAPIKeyService(repo domain.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// GetAll returns the API keys of a user.
+func (s *APIKeyService) GetAll(ctx context.Context, userID int64) ([]domain.APIKey, error) {
+	keys, err := s.repo.GetAll(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyService.GetAll: %w: %w", domain.ErrAPIKeyRetrievalFailed, err)
+	}
+	return keys, nil
+}
+
+// Create stores a new API key of key.User and returns it with the secret
+// key. Like calendar feed tokens, the key cannot be recovered later.
+func (s *APIKeyService) Create(ctx context.Context, key domain.APIKey) (domain.APIKey, string, error) {
+	key.Name = strings.TrimSpace(key.Name)
+	if err := s.validate(key); err != nil {
+		return domain.APIKey{}, "", fmt.Errorf("APIKeyService.Create: %w", err)
+	}
+
+	scopes := make([]domain.APIKeyScope, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	key.Scopes = scopes
+
+	token, err := newToken()
+	if err != nil {
+		return domain.APIKey{}, "", fmt.Errorf("APIKeyService.Create: %w: %w", domain.ErrAPIKeyCreationFailed, err)
+	}
+	secret := domain.APIKeyPrefix + token
+	key.Prefix = secret[:len(domain.APIKeyPrefix)+apiKeyPrefixLength]
+
+	created, err := s.repo.Create(ctx, key, hashToken(secret))
+	if err != nil {
+		return domain.APIKey{}, "", fmt.Errorf("APIKeyService.Create: %w: %w", domain.ErrAPIKeyCreationFailed, err)
+	}
+	return created, secret, nil
+}
+
+// Delete removes an API key of a user, so it stops working.
+func (s *APIKeyService) Delete(ctx context.Context, userID, id int64) error {
+	err := s.repo.Delete(ctx, userID, id)
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return fmt.Errorf("APIKeyService.Delete: %w", err)
+	}
+	if err != nil {
+		return fmt.Errorf("APIKeyService.Delete: %w: %w", domain.ErrAPIKeyDeletionFailed, err)
+	}
+	return nil
+}
+
+// Authenticate returns the live API key secret is, with its user, and
+// records that it was used, to the nearest apiKeyTouchInterval. Malformed,
+// unknown and expired keys fail with domain.ErrInvalidAPIKey.
+func (s *APIKeyService) Authenticate(ctx context.Context, secret string) (domain.APIKey, error) {
+	if !strings.HasPrefix(secret, domain.APIKeyPrefix) {
+		return domain.APIKey{}, fmt.Errorf("APIKeyService.Authenticate: %w", domain.ErrInvalidAPIKey)
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+	key, err := s.repo.Get(ctx, hashToken(secret))
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return domain.APIKey{}, fmt.Errorf("APIKeyService.Authenticate: %w", domain.ErrInvalidAPIKey)
+	}
+	if err != nil {
+		return domain.APIKey{}, fmt.Errorf("APIKeyService.Authenticate: %w: %w", domain.ErrAPIKeyAuthenticationFailed, err)
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(now) {
+		return domain.APIKey{}, fmt.Errorf("APIKeyService.Authenticate: %w", domain.ErrInvalidAPIKey)
+	}
+
+	if key.LastUsedAt != nil && now.Sub(*key.LastUsedAt) < apiKeyTouchInterval {
+		return key, nil
+	}
+	if err := s.repo.Touch(ctx, key.ID, now); err != nil {
+		return domain.APIKey{}, fmt.Errorf("APIKeyService.Authenticate: %w: %w", domain.ErrAPIKeyAuthenticationFailed, err)
+	}
+	key.LastUsedAt = &now
+	return key, nil
+}
+
+func (s *APIKeyService) validate(key domain.APIKey) error {
+	v := validation.New()
+	if v.Required("name", key.Name) {
+		v.MaxLength("name", key.Name, MaxAPIKeyNameLength)
+	}
+	if len(key.Scopes) == 0 {
+		v.Add("scopes", validation.CodeRequired, "scopes must name at least one scope")
+	}
+	for i, scope := range key.Scopes {
+		if !scope.Valid() {
+			v.Add(fmt.Sprintf("scopes[%d]", i), validation.CodeInvalidValue,
+				fmt.Sprintf("scope must be %s or %s", domain.ScopeTasksRead, domain.ScopeTasksWrite))
+		}
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(s.now()) {
+		v.Add("expires_at", validation.CodeInvalidValue, "expires_at must be in the future")
+	}
+	return v.Err()
+}
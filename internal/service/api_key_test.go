@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+type mockAPIKeyRepository struct {
+	getAllFunc func(ctx context.Context, userID int64) ([]domain.APIKey, error)
+	createFunc func(ctx context.Context, key domain.APIKey, keyHash string) (domain.APIKey, error)
+	deleteFunc func(ctx context.Context, userID, id int64) error
+	getFunc    func(ctx context.Context, keyHash string) (domain.APIKey, error)
+	touchFunc  func(ctx context.Context, id int64, at time.Time) error
+}
+
+func (m *mockAPIKeyRepository) GetAll(ctx context.Context, userID int64) ([]domain.APIKey, error) {
+	return m.getAllFunc(ctx, userID)
+}
+
+func (m *mockAPIKeyRepository) Create(ctx context.Context, key domain.APIKey, keyHash string) (domain.APIKey, error) {
+	return m.createFunc(ctx, key, keyHash)
+}
+
+func (m *mockAPIKeyRepository) Delete(ctx context.Context, userID, id int64) error {
+	return m.deleteFunc(ctx, userID, id)
+}
+
+func (m *mockAPIKeyRepository) Get(ctx context.Context, keyHash string) (domain.APIKey, error) {
+	return m.getFunc(ctx, keyHash)
+}
+
+func (m *mockAPIKeyRepository) Touch(ctx context.Context, id int64, at time.Time) error {
+	return m.touchFunc(ctx, id, at)
+}
+
+func TestAPIKeyService_Create(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	testCases := []struct {
+		name           string
+		key            domain.APIKey
+		repoErr        error
+		expectedScopes []domain.APIKeyScope
+		expectedErr    error
+		expectedFields []string
+	}{
+		{
+			name:           "should create a key with deduplicated scopes",
+			key:            domain.APIKey{Name: " CI ", Scopes: []domain.APIKeyScope{domain.ScopeTasksRead, domain.ScopeTasksWrite, domain.ScopeTasksRead}, ExpiresAt: &future},
+			expectedScopes: []domain.APIKeyScope{domain.ScopeTasksRead, domain.ScopeTasksWrite},
+		},
+		{
+			name:           "should reject invalid input",
+			key:            domain.APIKey{Scopes: []domain.APIKeyScope{"tasks:admin"}, ExpiresAt: &past},
+			expectedErr:    domain.ErrValidationFailed,
+			expectedFields: []string{"name", "scopes[0]", "expires_at"},
+		},
+		{name: "should require a scope", key: domain.APIKey{Name: "CI"}, expectedErr: domain.ErrValidationFailed, expectedFields: []string{"scopes"}},
+		{name: "should wrap storage failures", key: domain.APIKey{Name: "CI", Scopes: []domain.APIKeyScope{domain.ScopeTasksRead}}, repoErr: errors.New("db down"), expectedErr: domain.ErrAPIKeyCreationFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var storedHash string
+			repo := &mockAPIKeyRepository{
+				createFunc: func(ctx context.Context, key domain.APIKey, keyHash string) (domain.APIKey, error) {
+					if key.Name != "CI" {
+						t.Errorf("expected a trimmed name, got %q", key.Name)
+					}
+					if !reflect.DeepEqual(key.Scopes, tc.expectedScopes) && tc.repoErr == nil {
+						t.Errorf("expected scopes %v, got %v", tc.expectedScopes, key.Scopes)
+					}
+					storedHash = keyHash
+					key.ID = 1
+					return key, tc.repoErr
+				},
+			}
+			svc := NewAPIKeyService(repo)
+			svc.now = func() time.Time { return now }
+
+			key, secret, err := svc.Create(t.Context(), tc.key)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+			if tc.expectedFields != nil {
+				var fields []string
+				for _, f := range validation.Fields(err) {
+					fields = append(fields, f.Field)
+				}
+				if !reflect.DeepEqual(fields, tc.expectedFields) {
+					t.Errorf("expected fields %v, got %v", tc.expectedFields, fields)
+				}
+			}
+			if err != nil {
+				return
+			}
+
+			if !strings.HasPrefix(secret, domain.APIKeyPrefix) || !strings.HasPrefix(secret, key.Prefix) || len(key.Prefix) != len(domain.APIKeyPrefix)+8 {
+				t.Errorf("unexpected key %q with prefix %q", secret, key.Prefix)
+			}
+			if storedHash != hashToken(secret) {
+				t.Error("expected the key to be stored by its hash")
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Second), now.Add(time.Hour)
+	recently, earlier := now.Add(-30*time.Second), now.Add(-2*time.Minute)
+	errDB := errors.New("db down")
+
+	testCases := []struct {
+		name         string
+		secret       string
+		expiresAt    *time.Time
+		lastUsedAt   *time.Time
+		getErr       error
+		touchErr     error
+		expectedUsed *time.Time
+		expectTouch  bool
+		expectedErr  error
+	}{
+		{name: "should accept a live key", secret: "tsk_good", expectedUsed: &now, expectTouch: true},
+		{name: "should accept a key before it expires", secret: "tsk_good", expiresAt: &future, expectedUsed: &now, expectTouch: true},
+		{name: "should record the use of a key last used a while ago", secret: "tsk_good", lastUsedAt: &earlier, expectedUsed: &now, expectTouch: true},
+		{name: "should not record the use of a key used recently", secret: "tsk_good", lastUsedAt: &recently, expectedUsed: &recently},
+		{name: "should reject a key without the prefix", secret: "good", expectedErr: domain.ErrInvalidAPIKey},
+		{name: "should reject an unknown key", secret: "tsk_unknown", getErr: domain.ErrAPIKeyNotFound, expectedErr: domain.ErrInvalidAPIKey},
+		{name: "should reject an expired key", secret: "tsk_good", expiresAt: &past, expectedErr: domain.ErrInvalidAPIKey},
+		{name: "should report lookup failures", secret: "tsk_good", getErr: errDB, expectedErr: domain.ErrAPIKeyAuthenticationFailed},
+		{name: "should report failures to record use", secret: "tsk_good", touchErr: errDB, expectedErr: domain.ErrAPIKeyAuthenticationFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var touched *time.Time
+			repo := &mockAPIKeyRepository{
+				getFunc: func(ctx context.Context, keyHash string) (domain.APIKey, error) {
+					if keyHash != hashToken(tc.secret) {
+						t.Errorf("expected the key to be looked up by its hash")
+					}
+					return domain.APIKey{ID: 1, User: domain.User{ID: 7}, ExpiresAt: tc.expiresAt, LastUsedAt: tc.lastUsedAt}, tc.getErr
+				},
+				touchFunc: func(ctx context.Context, id int64, at time.Time) error {
+					touched = &at
+					return tc.touchErr
+				},
+			}
+			svc := NewAPIKeyService(repo)
+			svc.now = func() time.Time { return now }
+
+			key, err := svc.Authenticate(t.Context(), tc.secret)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if key.User.ID != 7 || !key.LastUsedAt.Equal(*tc.expectedUsed) {
+				t.Errorf("expected the key of user 7 last used at %v, got %+v", *tc.expectedUsed, key)
+			}
+			if (touched != nil) != tc.expectTouch || touched != nil && !touched.Equal(now) {
+				t.Errorf("expected touch %v at %v, got %v", tc.expectTouch, now, touched)
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_Delete(t *testing.T) {
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "should delete the key"},
+		{name: "should pass not found through", repoErr: domain.ErrAPIKeyNotFound, expectedErr: domain.ErrAPIKeyNotFound},
+		{name: "should wrap storage failures", repoErr: errors.New("db down"), expectedErr: domain.ErrAPIKeyDeletionFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockAPIKeyRepository{
+				deleteFunc: func(ctx context.Context, userID, id int64) error {
+					if userID != 7 || id != 1 {
+						t.Errorf("unexpected key %d of user %d", id, userID)
+					}
+					return tc.repoErr
+				},
+			}
+
+			if err := NewAPIKeyService(repo).Delete(t.Context(), 7, 1); !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
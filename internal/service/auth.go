@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/password"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+const (
+	// MaxEmailLength is the maximum number of characters in an email
+	// address, as limited by SMTP.
+	MaxEmailLength = 254
+	// MaxUserNameLength is the maximum number of characters in a user name.
+	MaxUserNameLength = 100
+	// MinPasswordLength is the minimum number of characters in a password.
+	MinPasswordLength = 8
+	// MaxPasswordLength is the maximum number of characters in a password.
+	// It keeps the cost of hashing a request bounded.
+	MaxPasswordLength = 256
+)
+
+// AuthService provides business logic for user accounts and sessions.
+type AuthService struct {
+	users    domain.UserRepository
+	sessions domain.SessionRepository
+	ttl      time.Duration
+	params   password.Params
+	now      func() time.Time
+
+	// dummyHash is verified against when an email is unknown, so that
+	// failed sign-ins take as long whether or not the account exists.
+	dummyOnce sync.Once
+	dummyHash string
+}
+
+// NewAuthService creates a new AuthService whose sessions last ttl.
+func NewAuthService(users domain.UserRepository, sessions domain.SessionRepository, ttl time.Duration) *AuthService {
+	return &AuthService{
+		users:    users,
+		sessions: sessions,
+		ttl:      ttl,
+		params:   password.DefaultParams,
+		now:      time.Now,
+	}
+}
+
+// Register creates a user with the given password and signs it in,
+// returning the new session and its secret token.
+func (s *AuthService) Register(ctx context.Context, user domain.User, pw string) (domain.Session, string, error) {
+	user.Email = strings.TrimSpace(user.Email)
+	user.Name = strings.TrimSpace(user.Name)
+	if err := validateRegistration(user, pw); err != nil {
+		return domain.Session{}, "", fmt.Errorf("AuthService.Register: %w", err)
+	}
+
+	hash, err := password.Hash(pw, s.params)
+	if err != nil {
+		return domain.Session{}, "", fmt.Errorf("AuthService.Register: %w: %w", domain.ErrUserCreationFailed, err)
+	}
+	created, err := s.users.Create(ctx, user, hash)
+	if errors.Is(err, domain.ErrUserAlreadyExists) {
+		return domain.Session{}, "", fmt.Errorf("AuthService.Register: %w", err)
+	}
+	if err != nil {
+		return domain.Session{}, "", fmt.Errorf("AuthService.Register: %w: %w", domain.ErrUserCreationFailed, err)
+	}
+
+	session, token, err := s.startSession(ctx, created)
+	if err != nil {
+		return domain.Session{}, "", fmt.Errorf("AuthService.Register: %w", err)
+	}
+	return session, token, nil
+}
+
+// Login checks an email and password and starts a session for the user,
+// returning it with its secret token. An unknown email and a wrong
+// password both fail with domain.ErrInvalidCredentials.
+func (s *AuthService) Login(ctx context.Context, email, pw string) (domain.Session, string, error) {
+	user, err := s.checkCredentials(ctx, email, pw)
+	if errors.Is(err, domain.ErrInvalidCredentials) {
+		return domain.Session{}, "", fmt.Errorf("AuthService.Login: %w", err)
+	}
+	if err != nil {
+		return domain.Session{}, "", fmt.Errorf("AuthService.Login: %w: %w", domain.ErrSessionFailed, err)
+	}
+
+	session, token, err := s.startSession(ctx, user)
+	if err != nil {
+		return domain.Session{}, "", fmt.Errorf("AuthService.Login: %w", err)
+	}
+	return session, token, nil
+}
+
+// checkCredentials returns the user with the given email and password. An
+// unknown email and a wrong password both fail with
+// domain.ErrInvalidCredentials, and take as long.
+func (s *AuthService) checkCredentials(ctx context.Context, email, pw string) (domain.User, error) {
+	user, hash, err := s.users.GetByEmail(ctx, strings.TrimSpace(email))
+	if errors.Is(err, domain.ErrUserNotFound) {
+		s.dummyOnce.Do(func() {
+			s.dummyHash, _ = password.Hash("", s.params)
+		})
+		_, _ = password.Verify(pw, s.dummyHash)
+		return domain.User{}, domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	ok, err := password.Verify(pw, hash)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if !ok {
+		return domain.User{}, domain.ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// Logout ends the session reached with token. Ending a session that does
+// not exist, or no longer does, is not an error.
+func (s *AuthService) Logout(ctx context.Context, token string) error {
+	if token == "" {
+		return nil
+	}
+	err := s.sessions.Delete(ctx, hashToken(token))
+	if err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+		return fmt.Errorf("AuthService.Logout: %w: %w", domain.ErrSessionFailed, err)
+	}
+	return nil
+}
+
+// Authenticate returns the live session reached with token. Unknown and
+// expired tokens fail with domain.ErrUnauthenticated.
+func (s *AuthService) Authenticate(ctx context.Context, token string) (domain.Session, error) {
+	if token == "" {
+		return domain.Session{}, fmt.Errorf("AuthService.Authenticate: %w", domain.ErrUnauthenticated)
+	}
+	session, err := s.sessions.Get(ctx, hashToken(token), s.now())
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		return domain.Session{}, fmt.Errorf("AuthService.Authenticate: %w", domain.ErrUnauthenticated)
+	}
+	if err != nil {
+		return domain.Session{}, fmt.Errorf("AuthService.Authenticate: %w: %w", domain.ErrSessionFailed, err)
+	}
+	return session, nil
+}
+
+// PurgeExpired deletes the sessions that have expired and reports how many
+// there were.
+func (s *AuthService) PurgeExpired(ctx context.Context) (int64, error) {
+	n, err := s.sessions.DeleteExpired(ctx, s.now())
+	if err != nil {
+		return 0, fmt.Errorf("AuthService.PurgeExpired: %w: %w", domain.ErrSessionFailed, err)
+	}
+	return n, nil
+}
+
+// startSession stores a new session of user and returns it with its token.
+func (s *AuthService) startSession(ctx context.Context, user domain.User) (domain.Session, string, error) {
+	token, err := newToken()
+	if err != nil {
+		return domain.Session{}, "", fmt.Errorf("%w: %w", domain.ErrSessionFailed, err)
+	}
+	now := s.now().UTC().Truncate(time.Second)
+	session := domain.Session{User: user, CreatedAt: now, ExpiresAt: now.Add(s.ttl)}
+	if err := s.sessions.Create(ctx, session, hashToken(token)); err != nil {
+		return domain.Session{}, "", fmt.Errorf("%w: %w", domain.ErrSessionFailed, err)
+	}
+	return session, token, nil
+}
+
+func validateRegistration(user domain.User, pw string) error {
+	v := validation.New()
+	if v.Required("email", user.Email) && v.MaxLength("email", user.Email, MaxEmailLength) {
+		if addr, err := mail.ParseAddress(user.Email); err != nil || addr.Address != user.Email {
+			v.Add("email", validation.CodeInvalidValue, "email must be a valid email address")
+		}
+	}
+	v.MaxLength("name", user.Name, MaxUserNameLength)
+	if utf8.RuneCountInString(pw) < MinPasswordLength {
+		v.Add("password", validation.CodeTooShort, fmt.Sprintf("password must be at least %d characters", MinPasswordLength))
+	} else {
+		v.MaxLength("password", pw, MaxPasswordLength)
+	}
+	return v.Err()
+}
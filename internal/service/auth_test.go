@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/password"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+type mockUserRepository struct {
+	createFunc     func(ctx context.Context, user domain.User, passwordHash string) (domain.User, error)
+	getByEmailFunc func(ctx context.Context, email string) (domain.User, string, error)
+}
+
+func (m *mockUserRepository) Create(ctx context.Context, user domain.User, passwordHash string) (domain.User, error) {
+	return m.createFunc(ctx, user, passwordHash)
+}
+
+func (m *mockUserRepository) GetByEmail(ctx context.Context, email string) (domain.User, string, error) {
+	return m.getByEmailFunc(ctx, email)
+}
+
+// memorySessions is a domain.SessionRepository kept in a map.
+type memorySessions struct {
+	sessions  map[string]domain.Session
+	createErr error
+}
+
+func (m *memorySessions) Create(ctx context.Context, session domain.Session, tokenHash string) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	m.sessions[tokenHash] = session
+	return nil
+}
+
+func (m *memorySessions) Get(ctx context.Context, tokenHash string, now time.Time) (domain.Session, error) {
+	session, ok := m.sessions[tokenHash]
+	if !ok || !session.ExpiresAt.After(now) {
+		return domain.Session{}, domain.ErrSessionNotFound
+	}
+	return session, nil
+}
+
+func (m *memorySessions) Delete(ctx context.Context, tokenHash string) error {
+	if _, ok := m.sessions[tokenHash]; !ok {
+		return domain.ErrSessionNotFound
+	}
+	delete(m.sessions, tokenHash)
+	return nil
+}
+
+func (m *memorySessions) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	var n int64
+	for hash, session := range m.sessions {
+		if !session.ExpiresAt.After(now) {
+			delete(m.sessions, hash)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// testPasswordParams keep hashing fast in tests.
+var testPasswordParams = password.Params{Memory: 64, Time: 1, Threads: 1}
+
+func newTestAuthService(users domain.UserRepository, sessions *memorySessions, now time.Time) *AuthService {
+	svc := NewAuthService(users, sessions, time.Hour)
+	svc.params = testPasswordParams
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestAuthService_Register(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		user           domain.User
+		password       string
+		createErr      error
+		expectedErr    error
+		expectedFields []string
+	}{
+		{name: "should register and sign in", user: domain.User{Email: " ada@example.com ", Name: "Ada"}, password: "correct horse"},
+		{
+			name:           "should reject invalid input",
+			user:           domain.User{Email: "Ada <ada@example.com>"},
+			password:       "short",
+			expectedErr:    domain.ErrValidationFailed,
+			expectedFields: []string{"email", "password"},
+		},
+		{name: "should reject a missing email", password: "correct horse", expectedErr: domain.ErrValidationFailed, expectedFields: []string{"email"}},
+		{name: "should report a taken email", user: domain.User{Email: "ada@example.com"}, password: "correct horse", createErr: domain.ErrUserAlreadyExists, expectedErr: domain.ErrUserAlreadyExists},
+		{name: "should wrap storage failures", user: domain.User{Email: "ada@example.com"}, password: "correct horse", createErr: errors.New("db down"), expectedErr: domain.ErrUserCreationFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var storedHash string
+			users := &mockUserRepository{
+				createFunc: func(ctx context.Context, user domain.User, passwordHash string) (domain.User, error) {
+					if user.Email != "ada@example.com" {
+						t.Errorf("expected a trimmed email, got %q", user.Email)
+					}
+					storedHash = passwordHash
+					user.ID = 7
+					return user, tc.createErr
+				},
+			}
+			sessions := &memorySessions{sessions: map[string]domain.Session{}}
+			svc := newTestAuthService(users, sessions, now)
+
+			session, token, err := svc.Register(t.Context(), tc.user, tc.password)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+			if tc.expectedFields != nil {
+				var fields []string
+				for _, f := range validation.Fields(err) {
+					fields = append(fields, f.Field)
+				}
+				if !reflect.DeepEqual(fields, tc.expectedFields) {
+					t.Errorf("expected fields %v, got %v", tc.expectedFields, fields)
+				}
+			}
+			if err != nil {
+				return
+			}
+
+			if ok, _ := password.Verify(tc.password, storedHash); !ok {
+				t.Error("expected the stored hash to verify the password")
+			}
+			expected := domain.Session{User: domain.User{ID: 7, Email: "ada@example.com", Name: "Ada"}, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+			if !reflect.DeepEqual(session, expected) {
+				t.Errorf("expected session %+v, got %+v", expected, session)
+			}
+			if _, ok := sessions.sessions[hashToken(token)]; !ok {
+				t.Error("expected the session to be stored under the hash of its token")
+			}
+		})
+	}
+}
+
+func TestAuthService_Login(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	hash, err := password.Hash("correct horse", testPasswordParams)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	errDB := errors.New("db down")
+
+	testCases := []struct {
+		name        string
+		email       string
+		password    string
+		sessionErr  error
+		expectedErr error
+	}{
+		{name: "should sign in with the right password", email: "ADA@example.com", password: "correct horse"},
+		{name: "should reject a wrong password", email: "ada@example.com", password: "wrong horse", expectedErr: domain.ErrInvalidCredentials},
+		{name: "should reject an unknown email the same way", email: "bob@example.com", password: "correct horse", expectedErr: domain.ErrInvalidCredentials},
+		{name: "should report a failure to store the session", email: "ada@example.com", password: "correct horse", sessionErr: errDB, expectedErr: domain.ErrSessionFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			users := &mockUserRepository{
+				getByEmailFunc: func(ctx context.Context, email string) (domain.User, string, error) {
+					if email == "bob@example.com" {
+						return domain.User{}, "", domain.ErrUserNotFound
+					}
+					return domain.User{ID: 7, Email: "ada@example.com"}, hash, nil
+				},
+			}
+			sessions := &memorySessions{sessions: map[string]domain.Session{}, createErr: tc.sessionErr}
+			svc := newTestAuthService(users, sessions, now)
+
+			session, token, err := svc.Login(t.Context(), tc.email, tc.password)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				if len(sessions.sessions) != 0 {
+					t.Error("expected no session to be stored")
+				}
+				return
+			}
+			if session.User.ID != 7 || token == "" {
+				t.Errorf("unexpected session %+v with token %q", session, token)
+			}
+		})
+	}
+}
+
+func TestAuthService_Sessions(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	sessions := &memorySessions{sessions: map[string]domain.Session{}}
+	svc := newTestAuthService(nil, sessions, now)
+
+	_, token, err := svc.startSession(t.Context(), domain.User{ID: 7})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	session, err := svc.Authenticate(t.Context(), token)
+	if err != nil || session.User.ID != 7 {
+		t.Fatalf("expected the session of user 7, got %+v and %v", session, err)
+	}
+	for _, bad := range []string{"", "unknown"} {
+		if _, err := svc.Authenticate(t.Context(), bad); !errors.Is(err, domain.ErrUnauthenticated) {
+			t.Errorf("expected error %v for token %q but got %v", domain.ErrUnauthenticated, bad, err)
+		}
+	}
+
+	svc.now = func() time.Time { return now.Add(time.Hour) }
+	if _, err := svc.Authenticate(t.Context(), token); !errors.Is(err, domain.ErrUnauthenticated) {
+		t.Errorf("expected an expired session to fail with %v but got %v", domain.ErrUnauthenticated, err)
+	}
+	if n, err := svc.PurgeExpired(t.Context()); err != nil || n != 1 {
+		t.Errorf("expected one expired session to be purged, got %d and %v", n, err)
+	}
+
+	svc.now = func() time.Time { return now }
+	_, token, _ = svc.startSession(t.Context(), domain.User{ID: 7})
+	if err := svc.Logout(t.Context(), token); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if _, err := svc.Authenticate(t.Context(), token); !errors.Is(err, domain.ErrUnauthenticated) {
+		t.Errorf("expected a signed-out session to fail with %v but got %v", domain.ErrUnauthenticated, err)
+	}
+	if err := svc.Logout(t.Context(), token); err != nil {
+		t.Errorf("expected signing out twice to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/jwt"
+)
+
+// accessClaims are the claims of an access token. The user travels in the
+// token so that verifying it needs no database lookup.
+type accessClaims struct {
+	jwt.Claims
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+// TokenService issues and verifies bearer tokens for clients that cannot
+// keep a cookie: short-lived JWT access tokens, and single-use refresh
+// tokens that are rotated on every refresh.
+type TokenService struct {
+	auth       *AuthService
+	tokens     domain.RefreshTokenRepository
+	tx         domain.Transactor
+	keys       *jwt.Keyset
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+// NewTokenService creates a new TokenService that checks credentials with
+// auth and signs access tokens with keys in the name of issuer.
+func NewTokenService(
+	auth *AuthService,
+	tokens domain.RefreshTokenRepository,
+	tx domain.Transactor,
+	keys *jwt.Keyset,
+	issuer string,
+	accessTTL, refreshTTL time.Duration,
+) *TokenService {
+	return &TokenService{
+		auth:       auth,
+		tokens:     tokens,
+		tx:         tx,
+		keys:       keys,
+		issuer:     issuer,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		now:        time.Now,
+	}
+}
+
+// Issue checks an email and password and returns a new pair of tokens that
+// starts a refresh token family.
+func (s *TokenService) Issue(ctx context.Context, email, pw string) (domain.TokenPair, error) {
+	user, err := s.auth.checkCredentials(ctx, email, pw)
+	if errors.Is(err, domain.ErrInvalidCredentials) {
+		return domain.TokenPair{}, fmt.Errorf("TokenService.Issue: %w", err)
+	}
+	if err != nil {
+		return domain.TokenPair{}, fmt.Errorf("TokenService.Issue: %w: %w", domain.ErrTokenFailed, err)
+	}
+
+	family, err := newToken()
+	if err != nil {
+		return domain.TokenPair{}, fmt.Errorf("TokenService.Issue: %w: %w", domain.ErrTokenFailed, err)
+	}
+	pair, err := s.issue(ctx, user, family)
+	if err != nil {
+		return domain.TokenPair{}, fmt.Errorf("TokenService.Issue: %w: %w", domain.ErrTokenFailed, err)
+	}
+	return pair, nil
+}
+
+// Refresh trades a refresh token for a new pair in the same family. Each
+// refresh token works once: presenting one again means it leaked, so the
+// whole family is revoked and the call fails with
+// domain.ErrRefreshTokenReused. Unknown and expired tokens fail with
+// domain.ErrInvalidToken.
+func (s *TokenService) Refresh(ctx context.Context, token string) (domain.TokenPair, error) {
+	now := s.now()
+	stored, err := s.tokens.Get(ctx, hashToken(token))
+	if errors.Is(err, domain.ErrRefreshTokenNotFound) {
+		return domain.TokenPair{}, fmt.Errorf("TokenService.Refresh: %w", domain.ErrInvalidToken)
+	}
+	if err != nil {
+		return domain.TokenPair{}, fmt.Errorf("TokenService.Refresh: %w: %w", domain.ErrTokenFailed, err)
+	}
+	if !stored.ExpiresAt.After(now) {
+		return domain.TokenPair{}, fmt.Errorf("TokenService.Refresh: %w", domain.ErrInvalidToken)
+	}
+
+	var pair domain.TokenPair
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.tokens.MarkUsed(ctx, stored.ID, now); err != nil {
+			return err
+		}
+		pair, err = s.issue(ctx, stored.User, stored.Family)
+		return err
+	})
+	if errors.Is(err, domain.ErrRefreshTokenReused) {
+		if err := s.tokens.DeleteFamily(ctx, stored.Family); err != nil {
+			return domain.TokenPair{}, fmt.Errorf("TokenService.Refresh: %w: %w", domain.ErrTokenFailed, err)
+		}
+		return domain.TokenPair{}, fmt.Errorf("TokenService.Refresh: %w", domain.ErrRefreshTokenReused)
+	}
+	if err != nil {
+		return domain.TokenPair{}, fmt.Errorf("TokenService.Refresh: %w: %w", domain.ErrTokenFailed, err)
+	}
+	return pair, nil
+}
+
+// Revoke ends the refresh token family of token, signing its client out once
+// its access token expires. Revoking an unknown token is not an error.
+func (s *TokenService) Revoke(ctx context.Context, token string) error {
+	stored, err := s.tokens.Get(ctx, hashToken(token))
+	if errors.Is(err, domain.ErrRefreshTokenNotFound) {
+		return nil
+	}
+	if err == nil {
+		err = s.tokens.DeleteFamily(ctx, stored.Family)
+	}
+	if err != nil {
+		return fmt.Errorf("TokenService.Revoke: %w: %w", domain.ErrTokenFailed, err)
+	}
+	return nil
+}
+
+// VerifyAccessToken returns the user an access token was issued to. Tokens
+// that are malformed, forged, expired or from another issuer fail with
+// domain.ErrInvalidToken.
+func (s *TokenService) VerifyAccessToken(ctx context.Context, token string) (domain.User, error) {
+	var claims accessClaims
+	if err := s.keys.Parse(token, s.now(), &claims); err != nil {
+		return domain.User{}, fmt.Errorf("TokenService.VerifyAccessToken: %w: %w", domain.ErrInvalidToken, err)
+	}
+	id, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if claims.Issuer != s.issuer || err != nil || id <= 0 {
+		return domain.User{}, fmt.Errorf("TokenService.VerifyAccessToken: %w", domain.ErrInvalidToken)
+	}
+	return domain.User{ID: id, Email: claims.Email, Name: claims.Name}, nil
+}
+
+// PurgeExpired deletes the refresh tokens that have expired and reports how
+// many there were.
+func (s *TokenService) PurgeExpired(ctx context.Context) (int64, error) {
+	n, err := s.tokens.DeleteExpired(ctx, s.now())
+	if err != nil {
+		return 0, fmt.Errorf("TokenService.PurgeExpired: %w: %w", domain.ErrTokenFailed, err)
+	}
+	return n, nil
+}
+
+// issue signs an access token for user and stores a new refresh token in
+// family.
+func (s *TokenService) issue(ctx context.Context, user domain.User, family string) (domain.TokenPair, error) {
+	now := s.now().UTC().Truncate(time.Second)
+	jti, err := newToken()
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+	pair := domain.TokenPair{
+		User:             user,
+		AccessExpiresAt:  now.Add(s.accessTTL),
+		RefreshExpiresAt: now.Add(s.refreshTTL),
+	}
+
+	pair.AccessToken, err = s.keys.Sign(accessClaims{
+		Claims: jwt.Claims{
+			Issuer:    s.issuer,
+			Subject:   strconv.FormatInt(user.ID, 10),
+			ID:        jti,
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			ExpiresAt: pair.AccessExpiresAt.Unix(),
+		},
+		Email: user.Email,
+		Name:  user.Name,
+	})
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	pair.RefreshToken, err = newToken()
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+	refresh := domain.RefreshToken{User: user, Family: family, CreatedAt: now, ExpiresAt: pair.RefreshExpiresAt}
+	if err := s.tokens.Create(ctx, refresh, hashToken(pair.RefreshToken)); err != nil {
+		return domain.TokenPair{}, err
+	}
+	return pair, nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/jwt"
+	"github.com/mkeOrt/tasks-go/internal/password"
+)
+
+// memoryRefreshTokens is a domain.RefreshTokenRepository kept in a map.
+type memoryRefreshTokens struct {
+	tokens map[string]domain.RefreshToken
+	nextID int64
+}
+
+func (m *memoryRefreshTokens) Create(ctx context.Context, token domain.RefreshToken, tokenHash string) error {
+	m.nextID++
+	token.ID = m.nextID
+	m.tokens[tokenHash] = token
+	return nil
+}
+
+func (m *memoryRefreshTokens) Get(ctx context.Context, tokenHash string) (domain.RefreshToken, error) {
+	token, ok := m.tokens[tokenHash]
+	if !ok {
+		return domain.RefreshToken{}, domain.ErrRefreshTokenNotFound
+	}
+	return token, nil
+}
+
+func (m *memoryRefreshTokens) MarkUsed(ctx context.Context, id int64, now time.Time) error {
+	for hash, token := range m.tokens {
+		if token.ID == id {
+			if token.UsedAt != nil {
+				return domain.ErrRefreshTokenReused
+			}
+			token.UsedAt = &now
+			m.tokens[hash] = token
+		}
+	}
+	return nil
+}
+
+func (m *memoryRefreshTokens) DeleteFamily(ctx context.Context, family string) error {
+	for hash, token := range m.tokens {
+		if token.Family == family {
+			delete(m.tokens, hash)
+		}
+	}
+	return nil
+}
+
+func (m *memoryRefreshTokens) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	var n int64
+	for hash, token := range m.tokens {
+		if !token.ExpiresAt.After(now) {
+			delete(m.tokens, hash)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func newTestTokenService(t *testing.T, now *time.Time) (*TokenService, *memoryRefreshTokens) {
+	t.Helper()
+	hash, err := password.Hash("correct horse", testPasswordParams)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	users := &mockUserRepository{
+		getByEmailFunc: func(ctx context.Context, email string) (domain.User, string, error) {
+			if email != "ada@example.com" {
+				return domain.User{}, "", domain.ErrUserNotFound
+			}
+			return domain.User{ID: 7, Email: "ada@example.com", Name: "Ada"}, hash, nil
+		},
+	}
+	key, err := jwt.NewHS256Key("k1", []byte(strings.Repeat("s", jwt.MinHS256SecretLength)))
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	keys, err := jwt.NewKeyset(30*time.Second, key)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	tokens := &memoryRefreshTokens{tokens: map[string]domain.RefreshToken{}}
+	svc := NewTokenService(newTestAuthService(users, nil, *now), tokens, &fakeTransactor{}, keys, "tasks", 15*time.Minute, 24*time.Hour)
+	svc.now = func() time.Time { return *now }
+	return svc, tokens
+}
+
+func TestTokenService_Issue(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	svc, tokens := newTestTokenService(t, &now)
+
+	if _, err := svc.Issue(t.Context(), "ada@example.com", "wrong horse"); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Fatalf("expected error %v but got %v", domain.ErrInvalidCredentials, err)
+	}
+
+	pair, err := svc.Issue(t.Context(), "ada@example.com", "correct horse")
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if !pair.AccessExpiresAt.Equal(now.Add(15*time.Minute)) || !pair.RefreshExpiresAt.Equal(now.Add(24*time.Hour)) {
+		t.Errorf("unexpected expiries %v and %v", pair.AccessExpiresAt, pair.RefreshExpiresAt)
+	}
+	if _, ok := tokens.tokens[hashToken(pair.RefreshToken)]; !ok {
+		t.Error("expected the refresh token to be stored under its hash")
+	}
+
+	user, err := svc.VerifyAccessToken(t.Context(), pair.AccessToken)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if user != (domain.User{ID: 7, Email: "ada@example.com", Name: "Ada"}) {
+		t.Errorf("unexpected user %+v", user)
+	}
+}
+
+func TestTokenService_VerifyAccessToken(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	svc, _ := newTestTokenService(t, &now)
+	pair, err := svc.Issue(t.Context(), "ada@example.com", "correct horse")
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	foreign, _ := svc.keys.Sign(accessClaims{Claims: jwt.Claims{Issuer: "elsewhere", Subject: "7", ExpiresAt: now.Add(time.Hour).Unix()}})
+	anonymous, _ := svc.keys.Sign(accessClaims{Claims: jwt.Claims{Issuer: "tasks", ExpiresAt: now.Add(time.Hour).Unix()}})
+
+	testCases := []struct {
+		name  string
+		token string
+		at    time.Duration
+	}{
+		{name: "should reject garbage", token: "garbage"},
+		{name: "should reject a refresh token", token: pair.RefreshToken},
+		{name: "should reject another issuer", token: foreign},
+		{name: "should reject a token without subject", token: anonymous},
+		{name: "should reject an expired token", token: pair.AccessToken, at: 16 * time.Minute},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc.now = func() time.Time { return now.Add(tc.at) }
+			if _, err := svc.VerifyAccessToken(t.Context(), tc.token); !errors.Is(err, domain.ErrInvalidToken) {
+				t.Fatalf("expected error %v but got %v", domain.ErrInvalidToken, err)
+			}
+		})
+	}
+}
+
+func TestTokenService_Refresh(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	svc, tokens := newTestTokenService(t, &now)
+	first, err := svc.Issue(t.Context(), "ada@example.com", "correct horse")
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	second, err := svc.Refresh(t.Context(), first.RefreshToken)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.User.ID != 7 {
+		t.Fatalf("expected a new pair for user 7, got %+v", second)
+	}
+	if !second.RefreshExpiresAt.Equal(now.Add(24 * time.Hour)) {
+		t.Errorf("expected the refresh expiry to slide, got %v", second.RefreshExpiresAt)
+	}
+	if tokens.tokens[hashToken(first.RefreshToken)].Family != tokens.tokens[hashToken(second.RefreshToken)].Family {
+		t.Error("expected the new refresh token to join the family of the old one")
+	}
+
+	// Replaying the first token means it leaked: the whole family goes,
+	// including the token the legitimate client holds now.
+	if _, err := svc.Refresh(t.Context(), first.RefreshToken); !errors.Is(err, domain.ErrRefreshTokenReused) {
+		t.Fatalf("expected error %v but got %v", domain.ErrRefreshTokenReused, err)
+	}
+	if _, err := svc.Refresh(t.Context(), second.RefreshToken); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Fatalf("expected the family to be revoked, got %v", err)
+	}
+
+	third, _ := svc.Issue(t.Context(), "ada@example.com", "correct horse")
+	now = now.Add(24 * time.Hour)
+	if _, err := svc.Refresh(t.Context(), third.RefreshToken); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Fatalf("expected an expired token to fail with %v but got %v", domain.ErrInvalidToken, err)
+	}
+	if n, err := svc.PurgeExpired(t.Context()); err != nil || n != 1 {
+		t.Errorf("expected one expired token to be purged, got %d and %v", n, err)
+	}
+}
+
+func TestTokenService_Revoke(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	svc, tokens := newTestTokenService(t, &now)
+	pair, _ := svc.Issue(t.Context(), "ada@example.com", "correct horse")
+	other, _ := svc.Issue(t.Context(), "ada@example.com", "correct horse")
+
+	if err := svc.Revoke(t.Context(), pair.RefreshToken); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if _, err := svc.Refresh(t.Context(), pair.RefreshToken); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Fatalf("expected a revoked token to fail with %v but got %v", domain.ErrInvalidToken, err)
+	}
+	if _, ok := tokens.tokens[hashToken(other.RefreshToken)]; !ok {
+		t.Error("expected other families to survive")
+	}
+	if err := svc.Revoke(t.Context(), pair.RefreshToken); err != nil {
+		t.Errorf("expected revoking twice to succeed, got %v", err)
+	}
+}
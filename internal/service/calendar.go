@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+// MaxCalendarFeedNameLength is the maximum number of characters in a
+// calendar feed name.
+const MaxCalendarFeedNameLength = 100
+
+// TaskExporter streams every task matching a filter.
+type TaskExporter interface {
+	Export(ctx context.Context, filter domain.TaskFilter, fn func(domain.Task) error) error
+}
+
+// CalendarService provides business logic for iCalendar feeds of the tasks.
+type CalendarService struct {
+	repo  domain.CalendarFeedRepository
+	tasks TaskExporter
+	now   func() time.Time
+}
+
+// NewCalendarService creates a new CalendarService whose feeds list the
+// tasks streamed by tasks.
+func NewCalendarService(repo domain.CalendarFeedRepository, tasks TaskExporter) *CalendarService {
+	return &CalendarService{
+		repo:  repo,
+		tasks: tasks,
+		now:   time.Now,
+	}
+}
+
+// GetAll returns every calendar feed.
+func (s *CalendarService) GetAll(ctx context.Context) ([]domain.CalendarFeed, error) {
+	feeds, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("CalendarService.GetAll: %w: %w", domain.ErrCalendarFeedRetrievalFailed, err)
+	}
+	return feeds, nil
+}
+
+// Create stores a new calendar feed and returns it with its secret token.
+// The token cannot be recovered later: a lost token is replaced by deleting
+// the feed and creating another.
+func (s *CalendarService) Create(ctx context.Context, feed domain.CalendarFeed) (domain.CalendarFeed, string, error) {
+	v := validation.New()
+	if v.Required("name", feed.Name) {
+		v.MaxLength("name", feed.Name, MaxCalendarFeedNameLength)
+	}
+	if err := v.Err(); err != nil {
+		return domain.CalendarFeed{}, "", fmt.Errorf("CalendarService.Create: %w", err)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return domain.CalendarFeed{}, "", fmt.Errorf("CalendarService.Create: %w: %w", domain.ErrCalendarFeedCreationFailed, err)
+	}
+
+	created, err := s.repo.Create(ctx, feed, hashToken(token))
+	if err != nil {
+		return domain.CalendarFeed{}, "", fmt.Errorf("CalendarService.Create: %w: %w", domain.ErrCalendarFeedCreationFailed, err)
+	}
+	return created, token, nil
+}
+
+// Delete removes a calendar feed, so its URL stops working.
+func (s *CalendarService) Delete(ctx context.Context, id int64) error {
+	err := s.repo.Delete(ctx, id)
+	if errors.Is(err, domain.ErrCalendarFeedNotFound) {
+		return fmt.Errorf("CalendarService.Delete: %w", err)
+	}
+	if err != nil {
+		return fmt.Errorf("CalendarService.Delete: %w: %w", domain.ErrCalendarFeedDeletionFailed, err)
+	}
+	return nil
+}
+
+// Open returns the feed reached with token, which is the only credential a
+// feed request carries. Unknown tokens are reported as
+// domain.ErrCalendarFeedNotFound.
+func (s *CalendarService) Open(ctx context.Context, token string) (domain.CalendarFeed, error) {
+	if token == "" {
+		return domain.CalendarFeed{}, fmt.Errorf("CalendarService.Open: %w", domain.ErrCalendarFeedNotFound)
+	}
+	feed, err := s.repo.Use(ctx, hashToken(token), s.now())
+	if errors.Is(err, domain.ErrCalendarFeedNotFound) {
+		return domain.CalendarFeed{}, fmt.Errorf("CalendarService.Open: %w", err)
+	}
+	if err != nil {
+		return domain.CalendarFeed{}, fmt.Errorf("CalendarService.Open: %w: %w", domain.ErrCalendarFeedRetrievalFailed, err)
+	}
+	return feed, nil
+}
+
+// Tasks calls fn with every task of the feed, which holds the tasks of its
+// owner that have a due time, done or not. The feed decides the tenant, so
+// its contents do not depend on who fetches it; a feed without an owner
+// holds the tasks without one.
+func (s *CalendarService) Tasks(ctx context.Context, feed domain.CalendarFeed, fn func(domain.Task) error) error {
+	if feed.OwnerID != nil {
+		ctx = domain.WithTenant(ctx, *feed.OwnerID)
+	} else {
+		ctx = domain.WithoutTenant(ctx)
+	}
+	err := s.tasks.Export(ctx, domain.TaskFilter{}, func(task domain.Task) error {
+		if task.DueAt == nil {
+			return nil
+		}
+		return fn(task)
+	})
+	if err != nil {
+		return fmt.Errorf("CalendarService.Tasks: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+type mockCalendarFeedRepository struct {
+	getAllFunc func(ctx context.Context) ([]domain.CalendarFeed, error)
+	createFunc func(ctx context.Context, feed domain.CalendarFeed, tokenHash string) (domain.CalendarFeed, error)
+	deleteFunc func(ctx context.Context, id int64) error
+	useFunc    func(ctx context.Context, tokenHash string, at time.Time) (domain.CalendarFeed, error)
+}
+
+func (m *mockCalendarFeedRepository) GetAll(ctx context.Context) ([]domain.CalendarFeed, error) {
+	return m.getAllFunc(ctx)
+}
+
+func (m *mockCalendarFeedRepository) Create(ctx context.Context, feed domain.CalendarFeed, tokenHash string) (domain.CalendarFeed, error) {
+	return m.createFunc(ctx, feed, tokenHash)
+}
+
+func (m *mockCalendarFeedRepository) Delete(ctx context.Context, id int64) error {
+	return m.deleteFunc(ctx, id)
+}
+
+func (m *mockCalendarFeedRepository) Use(ctx context.Context, tokenHash string, at time.Time) (domain.CalendarFeed, error) {
+	return m.useFunc(ctx, tokenHash, at)
+}
+
+type taskExporterFunc func(ctx context.Context, filter domain.TaskFilter, fn func(domain.Task) error) error
+
+func (f taskExporterFunc) Export(ctx context.Context, filter domain.TaskFilter, fn func(domain.Task) error) error {
+	return f(ctx, filter, fn)
+}
+
+func TestCalendarService_CreateAndOpen(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	hashes := map[string]domain.CalendarFeed{}
+	repo := &mockCalendarFeedRepository{
+		createFunc: func(ctx context.Context, feed domain.CalendarFeed, tokenHash string) (domain.CalendarFeed, error) {
+			feed.ID = int64(len(hashes) + 1)
+			hashes[tokenHash] = feed
+			return feed, nil
+		},
+		useFunc: func(ctx context.Context, tokenHash string, at time.Time) (domain.CalendarFeed, error) {
+			if !at.Equal(now) {
+				t.Errorf("expected the feed to be stamped at %v, got %v", now, at)
+			}
+			feed, ok := hashes[tokenHash]
+			if !ok {
+				return domain.CalendarFeed{}, domain.ErrCalendarFeedNotFound
+			}
+			return feed, nil
+		},
+	}
+	svc := NewCalendarService(repo, nil)
+	svc.now = func() time.Time { return now }
+
+	feed, token, err := svc.Create(t.Context(), domain.CalendarFeed{Name: "Phone"})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	_, other, _ := svc.Create(t.Context(), domain.CalendarFeed{Name: "Laptop"})
+	if len(token) != 43 || token == other {
+		t.Fatalf("expected distinct 256-bit tokens, got %q and %q", token, other)
+	}
+	if _, stored := hashes[token]; stored {
+		t.Fatal("expected the token itself not to be stored")
+	}
+
+	opened, err := svc.Open(t.Context(), token)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if !reflect.DeepEqual(opened, feed) {
+		t.Fatalf("expected feed %v but got %v", feed, opened)
+	}
+
+	for _, bad := range []string{"", token[:42], "nope"} {
+		if _, err := svc.Open(t.Context(), bad); !errors.Is(err, domain.ErrCalendarFeedNotFound) {
+			t.Fatalf("expected error %v for token %q but got %v", domain.ErrCalendarFeedNotFound, bad, err)
+		}
+	}
+}
+
+func TestCalendarService_Create_Validation(t *testing.T) {
+	repo := &mockCalendarFeedRepository{
+		createFunc: func(ctx context.Context, feed domain.CalendarFeed, tokenHash string) (domain.CalendarFeed, error) {
+			t.Fatal("expected no call to the repository")
+			return domain.CalendarFeed{}, nil
+		},
+	}
+
+	_, _, err := NewCalendarService(repo, nil).Create(t.Context(), domain.CalendarFeed{})
+	if !errors.Is(err, domain.ErrValidationFailed) {
+		t.Fatalf("expected error %v but got %v", domain.ErrValidationFailed, err)
+	}
+}
+
+func TestCalendarService_Errors(t *testing.T) {
+	dbErr := errors.New("db down")
+	repo := &mockCalendarFeedRepository{
+		getAllFunc: func(ctx context.Context) ([]domain.CalendarFeed, error) { return nil, dbErr },
+		createFunc: func(ctx context.Context, feed domain.CalendarFeed, tokenHash string) (domain.CalendarFeed, error) {
+			return domain.CalendarFeed{}, dbErr
+		},
+		deleteFunc: func(ctx context.Context, id int64) error {
+			if id == 2 {
+				return domain.ErrCalendarFeedNotFound
+			}
+			return dbErr
+		},
+		useFunc: func(ctx context.Context, tokenHash string, at time.Time) (domain.CalendarFeed, error) {
+			return domain.CalendarFeed{}, dbErr
+		},
+	}
+	svc := NewCalendarService(repo, nil)
+
+	_, err := svc.GetAll(t.Context())
+	assertErrorIs(t, err, domain.ErrCalendarFeedRetrievalFailed)
+	_, _, err = svc.Create(t.Context(), domain.CalendarFeed{Name: "Phone"})
+	assertErrorIs(t, err, domain.ErrCalendarFeedCreationFailed)
+	assertErrorIs(t, svc.Delete(t.Context(), 1), domain.ErrCalendarFeedDeletionFailed)
+	assertErrorIs(t, svc.Delete(t.Context(), 2), domain.ErrCalendarFeedNotFound)
+	_, err = svc.Open(t.Context(), "token")
+	assertErrorIs(t, err, domain.ErrCalendarFeedRetrievalFailed)
+}
+
+func TestCalendarService_Tasks(t *testing.T) {
+	due := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	exporter := taskExporterFunc(func(ctx context.Context, filter domain.TaskFilter, fn func(domain.Task) error) error {
+		for _, task := range []domain.Task{{ID: 1, DueAt: &due}, {ID: 2}, {ID: 3, DueAt: &due, Done: true}} {
+			if err := fn(task); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	var ids []int64
+	err := NewCalendarService(nil, exporter).Tasks(t.Context(), domain.CalendarFeed{ID: 1}, func(task domain.Task) error {
+		ids = append(ids, task.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if !reflect.DeepEqual(ids, []int64{1, 3}) {
+		t.Fatalf("expected the tasks with a due time, got %v", ids)
+	}
+}
+
+func TestCalendarService_Tasks_Owner(t *testing.T) {
+	var tenants []any
+	exporter := taskExporterFunc(func(ctx context.Context, filter domain.TaskFilter, fn func(domain.Task) error) error {
+		if tenant, ok := domain.TenantFrom(ctx); ok {
+			tenants = append(tenants, tenant)
+		} else {
+			tenants = append(tenants, nil)
+		}
+		return nil
+	})
+	svc := NewCalendarService(nil, exporter)
+	owner := int64(7)
+
+	// The feed brings its owner, whoever opens its URL: the request of a
+	// signed-in user must not scope an ownerless feed to that user.
+	ctx := domain.WithTenant(t.Context(), 9)
+	for _, feed := range []domain.CalendarFeed{{ID: 1, OwnerID: &owner}, {ID: 2}} {
+		if err := svc.Tasks(ctx, feed, func(domain.Task) error { return nil }); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	}
+	if !reflect.DeepEqual(tenants, []any{int64(7), nil}) {
+		t.Fatalf("expected the owner's tasks, then the ownerless ones, got tenants %v", tenants)
+	}
+}
+
+func assertErrorIs(t *testing.T, err, target error) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Fatalf("expected error %v but got %v", target, err)
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+// MaxProjectNameLength is the maximum number of characters in a project name.
+const MaxProjectNameLength = 100
+
+// ProjectService provides business logic for projects.
+type ProjectService struct {
+	repo       domain.ProjectRepository
+	deleteMode domain.ProjectDeleteMode
+}
+
+// NewProjectService creates a new ProjectService. deleteMode decides what
+// happens to the tasks of deleted projects.
+func NewProjectService(repo domain.ProjectRepository, deleteMode domain.ProjectDeleteMode) *ProjectService {
+	return &ProjectService{
+		repo:       repo,
+		deleteMode: deleteMode,
+	}
+}
+
+// GetAll returns every project.
+func (s *ProjectService) GetAll(ctx context.Context) ([]domain.Project, error) {
+	projects, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ProjectService.GetAll: %w: %w", domain.ErrProjectRetrievalFailed, err)
+	}
+	return projects, nil
+}
+
+// GetByID returns the project with the given ID.
+func (s *ProjectService) GetByID(ctx context.Context, id int64) (domain.Project, error) {
+	project, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrProjectNotFound) {
+		return domain.Project{}, fmt.Errorf("ProjectService.GetByID: %w", err)
+	}
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("ProjectService.GetByID: %w: %w", domain.ErrProjectRetrievalFailed, err)
+	}
+	return project, nil
+}
+
+// Create stores a new project.
+func (s *ProjectService) Create(ctx context.Context, project domain.Project) (domain.Project, error) {
+	if err := validateProject(project); err != nil {
+		return domain.Project{}, fmt.Errorf("ProjectService.Create: %w", err)
+	}
+	created, err := s.repo.Create(ctx, project)
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("ProjectService.Create: %w: %w", domain.ErrProjectCreationFailed, err)
+	}
+	return created, nil
+}
+
+// Update replaces the name and description of an existing project.
+func (s *ProjectService) Update(ctx context.Context, project domain.Project) (domain.Project, error) {
+	if err := validateProject(project); err != nil {
+		return domain.Project{}, fmt.Errorf("ProjectService.Update: %w", err)
+	}
+	updated, err := s.repo.Update(ctx, project)
+	if errors.Is(err, domain.ErrProjectNotFound) {
+		return domain.Project{}, fmt.Errorf("ProjectService.Update: %w", err)
+	}
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("ProjectService.Update: %w: %w", domain.ErrProjectUpdateFailed, err)
+	}
+	return updated, nil
+}
+
+// Delete removes a project, handling its tasks according to the configured
+// delete mode.
+func (s *ProjectService) Delete(ctx context.Context, id int64) error {
+	err := s.repo.Delete(ctx, id, s.deleteMode)
+	if errors.Is(err, domain.ErrProjectNotFound) || errors.Is(err, domain.ErrProjectNotEmpty) ||
+		errors.Is(err, domain.ErrInboxProjectDelete) {
+		return fmt.Errorf("ProjectService.Delete: %w", err)
+	}
+	if err != nil {
+		return fmt.Errorf("ProjectService.Delete: %w: %w", domain.ErrProjectDeletionFailed, err)
+	}
+	return nil
+}
+
+func validateProject(project domain.Project) error {
+	v := validation.New()
+	if v.Required("name", project.Name) {
+		v.MaxLength("name", project.Name, MaxProjectNameLength)
+	}
+	v.MaxLength("description", project.Description, MaxDescriptionLength)
+	return v.Err()
+}
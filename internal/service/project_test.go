@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+type mockProjectRepository struct {
+	getAllFunc  func(ctx context.Context) ([]domain.Project, error)
+	getByIDFunc func(ctx context.Context, id int64) (domain.Project, error)
+	createFunc  func(ctx context.Context, project domain.Project) (domain.Project, error)
+	updateFunc  func(ctx context.Context, project domain.Project) (domain.Project, error)
+	deleteFunc  func(ctx context.Context, id int64, mode domain.ProjectDeleteMode) error
+}
+
+func (m *mockProjectRepository) GetAll(ctx context.Context) ([]domain.Project, error) {
+	return m.getAllFunc(ctx)
+}
+
+func (m *mockProjectRepository) GetByID(ctx context.Context, id int64) (domain.Project, error) {
+	return m.getByIDFunc(ctx, id)
+}
+
+func (m *mockProjectRepository) Create(ctx context.Context, project domain.Project) (domain.Project, error) {
+	return m.createFunc(ctx, project)
+}
+
+func (m *mockProjectRepository) Update(ctx context.Context, project domain.Project) (domain.Project, error) {
+	return m.updateFunc(ctx, project)
+}
+
+func (m *mockProjectRepository) Delete(ctx context.Context, id int64, mode domain.ProjectDeleteMode) error {
+	return m.deleteFunc(ctx, id, mode)
+}
+
+func TestProjectService_GetByID(t *testing.T) {
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "should return project"},
+		{name: "should keep not found error", repoErr: domain.ErrProjectNotFound, expectedErr: domain.ErrProjectNotFound},
+		{name: "should wrap repository failure", repoErr: errors.New("db down"), expectedErr: domain.ErrProjectRetrievalFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockProjectRepository{
+				getByIDFunc: func(ctx context.Context, id int64) (domain.Project, error) {
+					return domain.Project{ID: id, Name: "Work"}, tc.repoErr
+				},
+			}
+
+			_, err := NewProjectService(repo, domain.ProjectDeleteRestrict).GetByID(t.Context(), 1)
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestProjectService_Delete(t *testing.T) {
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "should delete project"},
+		{name: "should keep not found error", repoErr: domain.ErrProjectNotFound, expectedErr: domain.ErrProjectNotFound},
+		{name: "should keep not empty error", repoErr: domain.ErrProjectNotEmpty, expectedErr: domain.ErrProjectNotEmpty},
+		{name: "should keep inbox error", repoErr: domain.ErrInboxProjectDelete, expectedErr: domain.ErrInboxProjectDelete},
+		{name: "should wrap repository failure", repoErr: errors.New("db down"), expectedErr: domain.ErrProjectDeletionFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockProjectRepository{
+				deleteFunc: func(ctx context.Context, id int64, mode domain.ProjectDeleteMode) error {
+					if mode != domain.ProjectDeleteInbox {
+						t.Errorf("expected mode %q, got %q", domain.ProjectDeleteInbox, mode)
+					}
+					return tc.repoErr
+				},
+			}
+
+			err := NewProjectService(repo, domain.ProjectDeleteInbox).Delete(t.Context(), 1)
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestProjectService_Validation(t *testing.T) {
+	repo := &mockProjectRepository{
+		createFunc: func(ctx context.Context, project domain.Project) (domain.Project, error) {
+			t.Fatal("repository should not be called with invalid input")
+			return domain.Project{}, nil
+		},
+	}
+	svc := NewProjectService(repo, domain.ProjectDeleteRestrict)
+
+	testCases := []struct {
+		name    string
+		project domain.Project
+		field   string
+		code    string
+	}{
+		{name: "empty name", project: domain.Project{}, field: "name", code: validation.CodeRequired},
+		{name: "oversized name", project: domain.Project{Name: strings.Repeat("a", MaxProjectNameLength+1)}, field: "name", code: validation.CodeTooLong},
+		{name: "oversized description", project: domain.Project{Name: "Work", Description: strings.Repeat("a", MaxDescriptionLength+1)}, field: "description", code: validation.CodeTooLong},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.Create(t.Context(), tc.project)
+			if !errors.Is(err, domain.ErrValidationFailed) {
+				t.Fatalf("expected error %v but got %v", domain.ErrValidationFailed, err)
+			}
+			fields := validation.Fields(err)
+			if len(fields) != 1 || fields[0].Field != tc.field || fields[0].Code != tc.code {
+				t.Fatalf("unexpected fields %v", fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+// reminderBatchSize is the most reminders fired by a single Dispatch.
+// Anything left over fires on the next run.
+const reminderBatchSize = 100
+
+// ReminderService fires task reminders a fixed offset before tasks are due.
+type ReminderService struct {
+	repo     domain.ReminderRepository
+	notifier domain.Notifier
+	offset   time.Duration
+	now      func() time.Time
+}
+
+// NewReminderService creates a new ReminderService whose reminders fire
+// offset before the tasks' due time.
+func NewReminderService(repo domain.ReminderRepository, notifier domain.Notifier, offset time.Duration) *ReminderService {
+	return &ReminderService{
+		repo:     repo,
+		notifier: notifier,
+		offset:   offset,
+		now:      time.Now,
+	}
+}
+
+// Dispatch schedules the reminders of tasks with an upcoming due time and
+// fires those whose time has come. Each reminder is marked fired before it
+// is sent, so it fires at most once even across restarts or overlapping
+// runs; a reminder whose delivery fails is not retried.
+func (s *ReminderService) Dispatch(ctx context.Context) error {
+	now := s.now()
+	if err := s.repo.Schedule(ctx, s.offset, now); err != nil {
+		return fmt.Errorf("ReminderService.Dispatch: %w", err)
+	}
+
+	reminders, err := s.repo.Pending(ctx, now, reminderBatchSize)
+	if err != nil {
+		return fmt.Errorf("ReminderService.Dispatch: %w", err)
+	}
+
+	var errs []error
+	for _, reminder := range reminders {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("ReminderService.Dispatch: %w", err)
+		}
+
+		claimed, err := s.repo.MarkFired(ctx, reminder.ID, now)
+		if err != nil {
+			return fmt.Errorf("ReminderService.Dispatch: %w", err)
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.notifier.Notify(ctx, reminder); err != nil {
+			errs = append(errs, fmt.Errorf("notifying reminder %d: %w", reminder.ID, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("ReminderService.Dispatch: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+type mockReminderRepository struct {
+	scheduleFunc  func(ctx context.Context, offset time.Duration, now time.Time) error
+	pendingFunc   func(ctx context.Context, now time.Time, limit int) ([]domain.Reminder, error)
+	markFiredFunc func(ctx context.Context, id int64, at time.Time) (bool, error)
+}
+
+func (m *mockReminderRepository) Schedule(ctx context.Context, offset time.Duration, now time.Time) error {
+	return m.scheduleFunc(ctx, offset, now)
+}
+
+func (m *mockReminderRepository) Pending(ctx context.Context, now time.Time, limit int) ([]domain.Reminder, error) {
+	return m.pendingFunc(ctx, now, limit)
+}
+
+func (m *mockReminderRepository) MarkFired(ctx context.Context, id int64, at time.Time) (bool, error) {
+	return m.markFiredFunc(ctx, id, at)
+}
+
+type mockNotifier struct {
+	notifyFunc func(ctx context.Context, reminder domain.Reminder) error
+}
+
+func (m *mockNotifier) Notify(ctx context.Context, reminder domain.Reminder) error {
+	return m.notifyFunc(ctx, reminder)
+}
+
+func TestReminderService_Dispatch(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	pending := []domain.Reminder{{ID: 1, TaskID: 10}, {ID: 2, TaskID: 20}, {ID: 3, TaskID: 30}}
+
+	t.Run("should notify only the reminders it claims", func(t *testing.T) {
+		var events []string
+		repo := &mockReminderRepository{
+			scheduleFunc: func(ctx context.Context, offset time.Duration, at time.Time) error {
+				if offset != 15*time.Minute || !at.Equal(now) {
+					t.Fatalf("unexpected schedule args %v, %v", offset, at)
+				}
+				return nil
+			},
+			pendingFunc: func(ctx context.Context, at time.Time, limit int) ([]domain.Reminder, error) {
+				return pending, nil
+			},
+			markFiredFunc: func(ctx context.Context, id int64, at time.Time) (bool, error) {
+				events = append(events, fmt.Sprintf("claim %d", id))
+				return id != 2, nil
+			},
+		}
+		notifier := &mockNotifier{notifyFunc: func(ctx context.Context, reminder domain.Reminder) error {
+			events = append(events, fmt.Sprintf("notify %d", reminder.ID))
+			return nil
+		}}
+
+		svc := NewReminderService(repo, notifier, 15*time.Minute)
+		svc.now = func() time.Time { return now }
+		if err := svc.Dispatch(t.Context()); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+
+		expected := []string{"claim 1", "notify 1", "claim 2", "claim 3", "notify 3"}
+		if !reflect.DeepEqual(events, expected) {
+			t.Fatalf("expected events %v but got %v", expected, events)
+		}
+	})
+
+	t.Run("should keep notifying after a delivery fails", func(t *testing.T) {
+		errDelivery := errors.New("smtp down")
+		notified := 0
+		repo := &mockReminderRepository{
+			scheduleFunc: func(ctx context.Context, offset time.Duration, at time.Time) error { return nil },
+			pendingFunc: func(ctx context.Context, at time.Time, limit int) ([]domain.Reminder, error) {
+				return pending, nil
+			},
+			markFiredFunc: func(ctx context.Context, id int64, at time.Time) (bool, error) { return true, nil },
+		}
+		notifier := &mockNotifier{notifyFunc: func(ctx context.Context, reminder domain.Reminder) error {
+			notified++
+			if reminder.ID == 1 {
+				return errDelivery
+			}
+			return nil
+		}}
+
+		err := NewReminderService(repo, notifier, time.Hour).Dispatch(t.Context())
+		if !errors.Is(err, errDelivery) {
+			t.Fatalf("expected error %v but got %v", errDelivery, err)
+		}
+		if notified != len(pending) {
+			t.Fatalf("expected %d notifications but got %d", len(pending), notified)
+		}
+	})
+
+	t.Run("should stop when claiming fails", func(t *testing.T) {
+		errDB := errors.New("db down")
+		repo := &mockReminderRepository{
+			scheduleFunc: func(ctx context.Context, offset time.Duration, at time.Time) error { return nil },
+			pendingFunc: func(ctx context.Context, at time.Time, limit int) ([]domain.Reminder, error) {
+				return pending, nil
+			},
+			markFiredFunc: func(ctx context.Context, id int64, at time.Time) (bool, error) { return false, errDB },
+		}
+		notifier := &mockNotifier{notifyFunc: func(ctx context.Context, reminder domain.Reminder) error {
+			t.Fatal("expected no notification")
+			return nil
+		}}
+
+		if err := NewReminderService(repo, notifier, time.Hour).Dispatch(t.Context()); !errors.Is(err, errDB) {
+			t.Fatalf("expected error %v but got %v", errDB, err)
+		}
+	})
+
+	t.Run("should return error when scheduling fails", func(t *testing.T) {
+		errDB := errors.New("db down")
+		repo := &mockReminderRepository{
+			scheduleFunc: func(ctx context.Context, offset time.Duration, at time.Time) error { return errDB },
+		}
+
+		if err := NewReminderService(repo, &mockNotifier{}, time.Hour).Dispatch(t.Context()); !errors.Is(err, errDB) {
+			t.Fatalf("expected error %v but got %v", errDB, err)
+		}
+	})
+}
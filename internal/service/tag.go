@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+// MaxTagNameLength is the maximum number of characters in a tag name.
+const MaxTagNameLength = 50
+
+// TagService provides business logic for tags.
+type TagService struct {
+	repo domain.TagRepository
+}
+
+// NewTagService creates a new TagService.
+func NewTagService(repo domain.TagRepository) *TagService {
+	return &TagService{
+		repo: repo,
+	}
+}
+
+// GetAll returns every tag, ordered by name.
+func (s *TagService) GetAll(ctx context.Context) ([]domain.Tag, error) {
+	tags, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("TagService.GetAll: %w: %w", domain.ErrTagRetrievalFailed, err)
+	}
+	return tags, nil
+}
+
+// GetByID returns the tag with the given ID.
+func (s *TagService) GetByID(ctx context.Context, id int64) (domain.Tag, error) {
+	tag, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrTagNotFound) {
+		return domain.Tag{}, fmt.Errorf("TagService.GetByID: %w", err)
+	}
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("TagService.GetByID: %w: %w", domain.ErrTagRetrievalFailed, err)
+	}
+	return tag, nil
+}
+
+// Create stores a new tag.
+func (s *TagService) Create(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+	if err := validateTag(tag); err != nil {
+		return domain.Tag{}, fmt.Errorf("TagService.Create: %w", err)
+	}
+	created, err := s.repo.Create(ctx, tag)
+	if errors.Is(err, domain.ErrTagAlreadyExists) {
+		return domain.Tag{}, fmt.Errorf("TagService.Create: %w", err)
+	}
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("TagService.Create: %w: %w", domain.ErrTagCreationFailed, err)
+	}
+	return created, nil
+}
+
+// Update renames an existing tag.
+func (s *TagService) Update(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+	if err := validateTag(tag); err != nil {
+		return domain.Tag{}, fmt.Errorf("TagService.Update: %w", err)
+	}
+	updated, err := s.repo.Update(ctx, tag)
+	if errors.Is(err, domain.ErrTagNotFound) || errors.Is(err, domain.ErrTagAlreadyExists) {
+		return domain.Tag{}, fmt.Errorf("TagService.Update: %w", err)
+	}
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("TagService.Update: %w: %w", domain.ErrTagUpdateFailed, err)
+	}
+	return updated, nil
+}
+
+// Delete removes a tag and detaches it from every task.
+func (s *TagService) Delete(ctx context.Context, id int64) error {
+	err := s.repo.Delete(ctx, id)
+	if errors.Is(err, domain.ErrTagNotFound) {
+		return fmt.Errorf("TagService.Delete: %w", err)
+	}
+	if err != nil {
+		return fmt.Errorf("TagService.Delete: %w: %w", domain.ErrTagDeletionFailed, err)
+	}
+	return nil
+}
+
+// Attach adds a tag to a task.
+func (s *TagService) Attach(ctx context.Context, taskID, tagID int64) error {
+	return assignmentErr("TagService.Attach", s.repo.Attach(ctx, taskID, tagID))
+}
+
+// Detach removes a tag from a task.
+func (s *TagService) Detach(ctx context.Context, taskID, tagID int64) error {
+	return assignmentErr("TagService.Detach", s.repo.Detach(ctx, taskID, tagID))
+}
+
+func assignmentErr(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, domain.ErrTaskNotFound) || errors.Is(err, domain.ErrTagNotFound) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return fmt.Errorf("%s: %w: %w", op, domain.ErrTagUpdateFailed, err)
+}
+
+// validateTag checks a tag name. Commas are rejected because the task list
+// takes several tags as a comma-separated query parameter.
+func validateTag(tag domain.Tag) error {
+	v := validation.New()
+	validateTagName(v, "name", tag.Name)
+	return v.Err()
+}
+
+// validateTagName records in v the problems of a tag name held in field.
+func validateTagName(v *validation.Validator, field, name string) {
+	if v.Required(field, name) && v.MaxLength(field, name, MaxTagNameLength) {
+		if strings.Contains(name, ",") {
+			v.Add(field, validation.CodeInvalidValue, field+" must not contain commas")
+		} else if strings.TrimSpace(name) != name {
+			v.Add(field, validation.CodeInvalidValue, field+" must not start or end with spaces")
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+type mockTagRepository struct {
+	getAllFunc  func(ctx context.Context) ([]domain.Tag, error)
+	getByIDFunc func(ctx context.Context, id int64) (domain.Tag, error)
+	createFunc  func(ctx context.Context, tag domain.Tag) (domain.Tag, error)
+	updateFunc  func(ctx context.Context, tag domain.Tag) (domain.Tag, error)
+	deleteFunc  func(ctx context.Context, id int64) error
+	attachFunc  func(ctx context.Context, taskID, tagID int64) error
+	detachFunc  func(ctx context.Context, taskID, tagID int64) error
+}
+
+func (m *mockTagRepository) GetAll(ctx context.Context) ([]domain.Tag, error) {
+	return m.getAllFunc(ctx)
+}
+
+func (m *mockTagRepository) GetByID(ctx context.Context, id int64) (domain.Tag, error) {
+	return m.getByIDFunc(ctx, id)
+}
+
+func (m *mockTagRepository) Create(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+	return m.createFunc(ctx, tag)
+}
+
+func (m *mockTagRepository) Update(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+	return m.updateFunc(ctx, tag)
+}
+
+func (m *mockTagRepository) Delete(ctx context.Context, id int64) error {
+	return m.deleteFunc(ctx, id)
+}
+
+func (m *mockTagRepository) Attach(ctx context.Context, taskID, tagID int64) error {
+	return m.attachFunc(ctx, taskID, tagID)
+}
+
+func (m *mockTagRepository) Detach(ctx context.Context, taskID, tagID int64) error {
+	return m.detachFunc(ctx, taskID, tagID)
+}
+
+func TestTagService_GetAll(t *testing.T) {
+	repo := &mockTagRepository{
+		getAllFunc: func(ctx context.Context) ([]domain.Tag, error) {
+			return nil, errors.New("db down")
+		},
+	}
+
+	_, err := NewTagService(repo).GetAll(t.Context())
+	if !errors.Is(err, domain.ErrTagRetrievalFailed) {
+		t.Fatalf("expected error %v but got %v", domain.ErrTagRetrievalFailed, err)
+	}
+}
+
+func TestTagService_Create(t *testing.T) {
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "should return created tag"},
+		{name: "should keep duplicate name error", repoErr: domain.ErrTagAlreadyExists, expectedErr: domain.ErrTagAlreadyExists},
+		{name: "should wrap repository failure", repoErr: errors.New("db down"), expectedErr: domain.ErrTagCreationFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTagRepository{
+				createFunc: func(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+					tag.ID = 1
+					return tag, tc.repoErr
+				},
+			}
+
+			_, err := NewTagService(repo).Create(t.Context(), domain.Tag{Name: "backend"})
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestTagService_Update(t *testing.T) {
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "should return updated tag"},
+		{name: "should keep not found error", repoErr: domain.ErrTagNotFound, expectedErr: domain.ErrTagNotFound},
+		{name: "should keep duplicate name error", repoErr: domain.ErrTagAlreadyExists, expectedErr: domain.ErrTagAlreadyExists},
+		{name: "should wrap repository failure", repoErr: errors.New("db down"), expectedErr: domain.ErrTagUpdateFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTagRepository{
+				updateFunc: func(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+					return tag, tc.repoErr
+				},
+			}
+
+			_, err := NewTagService(repo).Update(t.Context(), domain.Tag{ID: 1, Name: "backend"})
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestTagService_Delete(t *testing.T) {
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "should delete tag"},
+		{name: "should keep not found error", repoErr: domain.ErrTagNotFound, expectedErr: domain.ErrTagNotFound},
+		{name: "should wrap repository failure", repoErr: errors.New("db down"), expectedErr: domain.ErrTagDeletionFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTagRepository{
+				deleteFunc: func(ctx context.Context, id int64) error {
+					return tc.repoErr
+				},
+			}
+
+			err := NewTagService(repo).Delete(t.Context(), 1)
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestTagService_Attach(t *testing.T) {
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "should attach tag"},
+		{name: "should keep task not found error", repoErr: domain.ErrTaskNotFound, expectedErr: domain.ErrTaskNotFound},
+		{name: "should keep tag not found error", repoErr: domain.ErrTagNotFound, expectedErr: domain.ErrTagNotFound},
+		{name: "should wrap repository failure", repoErr: errors.New("db down"), expectedErr: domain.ErrTagUpdateFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTagRepository{
+				attachFunc: func(ctx context.Context, taskID, tagID int64) error {
+					if taskID != 1 || tagID != 2 {
+						t.Errorf("unexpected ids %d, %d", taskID, tagID)
+					}
+					return tc.repoErr
+				},
+			}
+
+			err := NewTagService(repo).Attach(t.Context(), 1, 2)
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestTagService_Validation(t *testing.T) {
+	repo := &mockTagRepository{
+		createFunc: func(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+			t.Fatal("repository should not be called with invalid input")
+			return domain.Tag{}, nil
+		},
+	}
+	svc := NewTagService(repo)
+
+	testCases := []struct {
+		name string
+		tag  string
+		code string
+	}{
+		{name: "empty name", tag: "", code: validation.CodeRequired},
+		{name: "oversized name", tag: strings.Repeat("a", MaxTagNameLength+1), code: validation.CodeTooLong},
+		{name: "name with a comma", tag: "a,b", code: validation.CodeInvalidValue},
+		{name: "name with surrounding spaces", tag: " backend", code: validation.CodeInvalidValue},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.Create(t.Context(), domain.Tag{Name: tc.tag})
+			if !errors.Is(err, domain.ErrValidationFailed) {
+				t.Fatalf("expected error %v but got %v", domain.ErrValidationFailed, err)
+			}
+			fields := validation.Fields(err)
+			if len(fields) != 1 || fields[0].Field != "name" || fields[0].Code != tc.code {
+				t.Fatalf("unexpected fields %v", fields)
+			}
+		})
+	}
+}
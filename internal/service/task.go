@@ -2,28 +2,714 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/recurrence"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+const (
+	// MaxTitleLength is the maximum number of characters in a task title.
+	MaxTitleLength = 200
+	// MaxDescriptionLength is the maximum number of characters in a task description.
+	MaxDescriptionLength = 5000
+	// MaxSearchQueryLength is the maximum number of characters in a search query.
+	MaxSearchQueryLength = 256
 )
 
 // TaskService provides business logic for tasks.
 type TaskService struct {
-	repo domain.TaskRepository
+	repo             domain.TaskRepository
+	tx               domain.Transactor
+	completeChildren bool
 }
 
-// NewTaskService creates a new TaskService.
-func NewTaskService(repo domain.TaskRepository) *TaskService {
+// NewTaskService creates a new TaskService. Batches run in transactions of
+// tx. When completeChildren is set, completing a task also completes all of
+// its subtasks.
+func NewTaskService(repo domain.TaskRepository, tx domain.Transactor, completeChildren bool) *TaskService {
 	return &TaskService{
-		repo: repo,
+		repo:             repo,
+		tx:               tx,
+		completeChildren: completeChildren,
 	}
 }
 
-// GetAll returns all tasks from the repository.
-func (s *TaskService) GetAll(ctx context.Context) ([]domain.Task, error) {
-	tasks, err := s.repo.GetAll(ctx)
+// GetAll returns the page of tasks matching filter along with the total match
+// count and the cursors of the neighbouring pages.
+func (s *TaskService) GetAll(ctx context.Context, filter domain.TaskFilter) (domain.TaskPage, error) {
+	filter = filter.WithDefaults()
+	if filter.Cursor != nil && filter.Cursor.Sort != filter.Sort {
+		return domain.TaskPage{}, fmt.Errorf("TaskService.GetAll: %w", domain.ErrInvalidCursor)
+	}
+
+	// Fetch one extra row to learn whether another page follows.
+	query := filter
+	query.Limit++
+	tasks, err := s.repo.GetAll(ctx, query)
+	if err != nil {
+		return domain.TaskPage{}, fmt.Errorf("TaskService.GetAll: %w: %w", domain.ErrTaskRetrievalFailed, err)
+	}
+
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+	hasMore := len(tasks) > filter.Limit
+	if hasMore {
+		if backward {
+			tasks = tasks[1:]
+		} else {
+			tasks = tasks[:filter.Limit]
+		}
+	}
+
+	total, err := s.repo.Count(ctx, filter)
+	if err != nil {
+		return domain.TaskPage{}, fmt.Errorf("TaskService.GetAll: %w: %w", domain.ErrTaskRetrievalFailed, err)
+	}
+
+	page := domain.TaskPage{Tasks: tasks, Total: total, Limit: filter.Limit, Offset: filter.Offset}
+	if len(tasks) == 0 {
+		return page, nil
+	}
+
+	// Paging backward always leaves a page after this one; paging forward
+	// leaves one before it unless we started from the top of the list.
+	hasNext := hasMore || backward
+	hasPrev := backward && hasMore || !backward && (filter.Cursor != nil || filter.Offset > 0)
+	if hasNext {
+		next := domain.NewTaskCursor(tasks[len(tasks)-1], filter.Sort, false)
+		page.NextCursor = &next
+	}
+	if hasPrev {
+		prev := domain.NewTaskCursor(tasks[0], filter.Sort, true)
+		page.PrevCursor = &prev
+	}
+	return page, nil
+}
+
+// Search returns the tasks matching a full-text query, best matches first.
+func (s *TaskService) Search(ctx context.Context, query string, filter domain.TaskFilter) ([]domain.TaskSearchResult, error) {
+	v := validation.New()
+	if v.Required("q", query) {
+		v.MaxLength("q", query, MaxSearchQueryLength)
+	}
+	if err := v.Err(); err != nil {
+		return nil, fmt.Errorf("TaskService.Search: %w", err)
+	}
+
+	results, err := s.repo.Search(ctx, query, filter.WithDefaults())
+	if errors.Is(err, domain.ErrInvalidSearchQuery) {
+		return nil, fmt.Errorf("TaskService.Search: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("TaskService.Search: %w: %w", domain.ErrTaskRetrievalFailed, err)
+	}
+	return results, nil
+}
+
+// Export calls fn with every task matching filter, in the filter's order,
+// reading them from storage a page at a time. The limit, offset and cursor
+// of filter are ignored. An error from fn stops the export.
+func (s *TaskService) Export(ctx context.Context, filter domain.TaskFilter, fn func(domain.Task) error) error {
+	filter.Limit, filter.Offset, filter.Cursor = domain.MaxTaskLimit, 0, nil
+	filter = filter.WithDefaults()
+
+	for {
+		tasks, err := s.repo.GetAll(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("TaskService.Export: %w: %w", domain.ErrTaskRetrievalFailed, err)
+		}
+		for _, task := range tasks {
+			if err := fn(task); err != nil {
+				return fmt.Errorf("TaskService.Export: %w", err)
+			}
+		}
+		if len(tasks) < filter.Limit {
+			return nil
+		}
+		next := domain.NewTaskCursor(tasks[len(tasks)-1], filter.Sort, false)
+		filter.Cursor = &next
+	}
+}
+
+// GetByID returns the task with the given ID.
+func (s *TaskService) GetByID(ctx context.Context, id int64) (domain.Task, error) {
+	task, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrTaskNotFound) {
+		return domain.Task{}, fmt.Errorf("TaskService.GetByID: %w", err)
+	}
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TaskService.GetByID: %w: %w", domain.ErrTaskRetrievalFailed, err)
+	}
+	return task, nil
+}
+
+// Create stores a new task.
+func (s *TaskService) Create(ctx context.Context, task domain.Task) (domain.Task, error) {
+	if err := validateTask(task); err != nil {
+		return domain.Task{}, fmt.Errorf("TaskService.Create: %w", err)
+	}
+	task.Recurrence = canonicalRecurrence(task.Recurrence)
+	created, err := s.repo.Create(ctx, task)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TaskService.Create: %w", taskWriteErr(err, domain.ErrTaskCreationFailed))
+	}
+	return created, nil
+}
+
+// Update replaces an existing task. Completing a recurring task spawns its
+// next occurrence.
+func (s *TaskService) Update(ctx context.Context, task domain.Task) (domain.Task, error) {
+	if err := validateTask(task); err != nil {
+		return domain.Task{}, fmt.Errorf("TaskService.Update: %w", err)
+	}
+	task.Recurrence = canonicalRecurrence(task.Recurrence)
+
+	// The stored task is read in the same unit of work as the write, the
+	// cascade and the spawn: of two concurrent completions only the first
+	// sees the task open and spawns, and a failed spawn undoes the
+	// completion.
+	var updated domain.Task
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var wasDone bool
+		if task.Done {
+			current, err := s.repo.GetByID(ctx, task.ID)
+			if err != nil {
+				return err
+			}
+			wasDone = current.Done
+		}
+		var err error
+		if updated, err = s.repo.Update(ctx, task); err != nil {
+			return err
+		}
+		if task.Done {
+			return s.afterCompletion(ctx, updated, wasDone)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TaskService.Update: %w", taskWriteErr(err, domain.ErrTaskUpdateFailed))
+	}
+	return updated, nil
+}
+
+// Patch applies a partial update to an existing task. Completing a
+// recurring task spawns its next occurrence.
+func (s *TaskService) Patch(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error) {
+	if err := validatePatch(patch); err != nil {
+		return domain.Task{}, fmt.Errorf("TaskService.Patch: %w", err)
+	}
+	if patch.Recurrence != nil {
+		rule := canonicalRecurrence(*patch.Recurrence)
+		patch.Recurrence = &rule
+	}
+
+	completing := patch.Done != nil && *patch.Done
+	var patched domain.Task
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var current domain.Task
+		if completing || patch.StartAt.Set || patch.DueAt.Set || patch.Recurrence != nil {
+			var err error
+			if current, err = s.repo.GetByID(ctx, id); err != nil {
+				return err
+			}
+			if err := validatePatched(current, patch); err != nil {
+				return err
+			}
+		}
+		var err error
+		if patched, err = s.repo.Patch(ctx, id, patch); err != nil {
+			return err
+		}
+		if completing {
+			return s.afterCompletion(ctx, patched, current.Done)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("TaskService.GetAll: %w: %w", domain.ErrTaskRetrievalFailed, err)
+		return domain.Task{}, fmt.Errorf("TaskService.Patch: %w", taskWriteErr(err, domain.ErrTaskUpdateFailed))
+	}
+	return patched, nil
+}
+
+// Occurrences returns up to n occurrences of the task's recurrence, starting
+// with its current due date. A task that does not repeat has none.
+func (s *TaskService) Occurrences(ctx context.Context, id int64, n int) ([]time.Time, error) {
+	task, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrTaskNotFound) {
+		return nil, fmt.Errorf("TaskService.Occurrences: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("TaskService.Occurrences: %w: %w", domain.ErrTaskRetrievalFailed, err)
+	}
+	if task.Recurrence == "" || task.DueAt == nil {
+		return []time.Time{}, nil
+	}
+
+	rule, err := recurrence.Parse(task.Recurrence)
+	if err != nil {
+		return nil, fmt.Errorf("TaskService.Occurrences: %w: %w", domain.ErrTaskRetrievalFailed, err)
+	}
+	return rule.Occurrences(*task.DueAt, n), nil
+}
+
+// GetTree returns the task with the given ID with its subtasks nested below it.
+func (s *TaskService) GetTree(ctx context.Context, id int64) (domain.TaskNode, error) {
+	tasks, err := s.repo.Subtree(ctx, id)
+	if errors.Is(err, domain.ErrTaskNotFound) {
+		return domain.TaskNode{}, fmt.Errorf("TaskService.GetTree: %w", err)
+	}
+	if err != nil {
+		return domain.TaskNode{}, fmt.Errorf("TaskService.GetTree: %w: %w", domain.ErrTaskRetrievalFailed, err)
+	}
+	return domain.BuildTaskTree(id, tasks), nil
+}
+
+// AddBlocker makes the task wait for blockerID to be done before it can be
+// completed.
+func (s *TaskService) AddBlocker(ctx context.Context, taskID, blockerID int64) error {
+	if err := s.repo.AddBlocker(ctx, taskID, blockerID); err != nil {
+		return fmt.Errorf("TaskService.AddBlocker: %w", dependencyWriteErr(err))
+	}
+	return nil
+}
+
+// RemoveBlocker drops the dependency of the task on blockerID.
+func (s *TaskService) RemoveBlocker(ctx context.Context, taskID, blockerID int64) error {
+	if err := s.repo.RemoveBlocker(ctx, taskID, blockerID); err != nil {
+		return fmt.Errorf("TaskService.RemoveBlocker: %w", dependencyWriteErr(err))
+	}
+	return nil
+}
+
+// Blockers returns the tasks the task with the given ID depends on.
+func (s *TaskService) Blockers(ctx context.Context, id int64) ([]domain.Task, error) {
+	tasks, err := s.repo.Blockers(ctx, id)
+	if errors.Is(err, domain.ErrTaskNotFound) {
+		return nil, fmt.Errorf("TaskService.Blockers: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("TaskService.Blockers: %w: %w", domain.ErrTaskRetrievalFailed, err)
 	}
 	return tasks, nil
 }
+
+// History returns a page of the change history of the task with the given
+// ID, newest first. A zero limit selects domain.DefaultHistoryLimit.
+func (s *TaskService) History(ctx context.Context, id int64, limit, offset int) (domain.TaskEventPage, error) {
+	if limit <= 0 {
+		limit = domain.DefaultHistoryLimit
+	}
+
+	events, err := s.repo.History(ctx, id, limit, offset)
+	if errors.Is(err, domain.ErrTaskNotFound) {
+		return domain.TaskEventPage{}, fmt.Errorf("TaskService.History: %w", err)
+	}
+	if err != nil {
+		return domain.TaskEventPage{}, fmt.Errorf("TaskService.History: %w: %w", domain.ErrTaskRetrievalFailed, err)
+	}
+
+	total, err := s.repo.CountHistory(ctx, id)
+	if err != nil {
+		return domain.TaskEventPage{}, fmt.Errorf("TaskService.History: %w: %w", domain.ErrTaskRetrievalFailed, err)
+	}
+
+	return domain.TaskEventPage{Events: events, Total: total, Limit: limit, Offset: offset}, nil
+}
+
+// completeSubtasks completes the descendants of a task that was just marked
+// done, when the service is configured to cascade completions.
+func (s *TaskService) completeSubtasks(ctx context.Context, id int64) error {
+	if !s.completeChildren {
+		return nil
+	}
+	return s.repo.CompleteDescendants(ctx, id)
+}
+
+// afterCompletion runs the follow-up work of a write that marked task done.
+// wasDone reports whether it was done already, in which case no new
+// occurrence is spawned.
+func (s *TaskService) afterCompletion(ctx context.Context, task domain.Task, wasDone bool) error {
+	if err := s.completeSubtasks(ctx, task.ID); err != nil {
+		return err
+	}
+	if wasDone {
+		return nil
+	}
+	next, ok := nextOccurrence(task)
+	if !ok {
+		return nil
+	}
+	if _, err := s.repo.Create(ctx, next); err != nil {
+		return fmt.Errorf("spawning next occurrence: %w", err)
+	}
+	return nil
+}
+
+// nextOccurrence returns the task that continues the series of a recurring
+// task, due at the occurrence after the task's own due date. Its start date
+// keeps the same distance from the due date, and a COUNT is decremented so
+// the series still ends after the same number of tasks. It reports false
+// when the series is over.
+func nextOccurrence(task domain.Task) (domain.Task, bool) {
+	if task.Recurrence == "" || task.DueAt == nil {
+		return domain.Task{}, false
+	}
+	rule, err := recurrence.Parse(task.Recurrence)
+	if err != nil {
+		return domain.Task{}, false
+	}
+	occurrences := rule.Occurrences(*task.DueAt, 2)
+	if len(occurrences) < 2 {
+		return domain.Task{}, false
+	}
+	if rule.Count > 0 {
+		rule.Count--
+	}
+
+	due := occurrences[1]
+	next := domain.Task{
+		Title:       task.Title,
+		Description: task.Description,
+		Priority:    task.Priority,
+		DueAt:       &due,
+		ProjectID:   task.ProjectID,
+		ParentID:    task.ParentID,
+		Recurrence:  rule.String(),
+	}
+	if task.StartAt != nil {
+		start := task.StartAt.Add(due.Sub(*task.DueAt))
+		next.StartAt = &start
+	}
+	return next, true
+}
+
+// Delete moves a task and its subtasks to the trash. A non-zero version
+// must match the task's current version.
+func (s *TaskService) Delete(ctx context.Context, id, version int64) error {
+	err := s.repo.Delete(ctx, id, version)
+	if errors.Is(err, domain.ErrTaskNotFound) || errors.Is(err, domain.ErrTaskVersionConflict) {
+		return fmt.Errorf("TaskService.Delete: %w", err)
+	}
+	if err != nil {
+		return fmt.Errorf("TaskService.Delete: %w: %w", domain.ErrTaskDeletionFailed, err)
+	}
+	return nil
+}
+
+// errBatchAborted rolls back a batch whose changes are not to be kept.
+var errBatchAborted = errors.New("batch aborted")
+
+// Batch runs ops in order in a single transaction. When continueOnError is
+// unset the batch is all-or-nothing: the first failure rolls every change
+// back and the remaining operations are skipped. Otherwise a failed
+// operation only undoes its own changes and the batch goes on.
+func (s *TaskService) Batch(ctx context.Context, ops []domain.TaskOperation, continueOnError bool) (domain.TaskBatchResult, error) {
+	if err := validateBatch(ops, domain.MaxBatchOperations); err != nil {
+		return domain.TaskBatchResult{}, fmt.Errorf("TaskService.Batch: %w", err)
+	}
+
+	batch, err := s.runOperations(ctx, ops, !continueOnError, !continueOnError, false)
+	if err != nil {
+		return domain.TaskBatchResult{}, fmt.Errorf("TaskService.Batch: %w: %w", domain.ErrTaskBatchFailed, err)
+	}
+	return batch, nil
+}
+
+// Import runs the operations of an import file in a single transaction.
+// Every operation runs so that all failing rows are reported, but nothing
+// is committed unless all of them succeed. A dry run reports the same
+// results and always rolls back.
+func (s *TaskService) Import(ctx context.Context, ops []domain.TaskOperation, dryRun bool) (domain.TaskBatchResult, error) {
+	if err := validateBatch(ops, domain.MaxImportRows); err != nil {
+		return domain.TaskBatchResult{}, fmt.Errorf("TaskService.Import: %w", err)
+	}
+
+	batch, err := s.runOperations(ctx, ops, false, true, dryRun)
+	if err != nil {
+		return domain.TaskBatchResult{}, fmt.Errorf("TaskService.Import: %w: %w", domain.ErrTaskImportFailed, err)
+	}
+	return batch, nil
+}
+
+// runOperations runs ops in order in one transaction, each in a savepoint of
+// its own. stopOnError skips the operations after the first failure. The
+// transaction is rolled back when an operation failed and rollbackOnError
+// is set, or when dryRun is.
+func (s *TaskService) runOperations(ctx context.Context, ops []domain.TaskOperation, stopOnError, rollbackOnError, dryRun bool) (domain.TaskBatchResult, error) {
+	results := make([]domain.TaskOperationResult, len(ops))
+	for i := range results {
+		results[i].Err = domain.ErrTaskOperationSkipped
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		failed := false
+		for i, op := range ops {
+			if op.ParentOp != nil {
+				// An operation whose parent failed stays skipped.
+				parent := results[*op.ParentOp]
+				if parent.Err != nil {
+					continue
+				}
+				op.Task.ParentID = &parent.Task.ID
+			}
+			var task domain.Task
+			err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+				var err error
+				if op, err = s.resolveMissing(ctx, op); err != nil {
+					return err
+				}
+				task, err = s.apply(ctx, op)
+				return err
+			})
+			results[i] = domain.TaskOperationResult{Task: task, Err: err, Created: op.Kind != ops[i].Kind}
+			if err != nil {
+				failed = true
+				if stopOnError {
+					break
+				}
+			}
+		}
+		if failed && rollbackOnError || dryRun {
+			return errBatchAborted
+		}
+		return nil
+	})
+	if errors.Is(err, errBatchAborted) {
+		return domain.TaskBatchResult{Results: results}, nil
+	}
+	if err != nil {
+		return domain.TaskBatchResult{}, err
+	}
+	return domain.TaskBatchResult{Results: results, Committed: true}, nil
+}
+
+// resolveMissing turns a patch with CreateMissing into the create of its
+// task when the task it names does not exist.
+func (s *TaskService) resolveMissing(ctx context.Context, op domain.TaskOperation) (domain.TaskOperation, error) {
+	if op.Kind != domain.TaskOperationPatch || !op.CreateMissing {
+		return op, nil
+	}
+	_, err := s.repo.GetByID(ctx, op.ID)
+	if errors.Is(err, domain.ErrTaskNotFound) {
+		op.Kind, op.ID, op.Version = domain.TaskOperationCreate, 0, 0
+		return op, nil
+	}
+	if err != nil {
+		return op, fmt.Errorf("%w: %w", domain.ErrTaskUpdateFailed, err)
+	}
+	return op, nil
+}
+
+// apply runs a single batch operation, then sets the tags it names.
+func (s *TaskService) apply(ctx context.Context, op domain.TaskOperation) (domain.Task, error) {
+	task, err := s.write(ctx, op)
+	if err != nil || op.Tags == nil || op.Kind == domain.TaskOperationDelete {
+		return task, err
+	}
+	return s.setTags(ctx, task.ID, op.Tags)
+}
+
+// write runs the task write of an operation.
+func (s *TaskService) write(ctx context.Context, op domain.TaskOperation) (domain.Task, error) {
+	switch op.Kind {
+	case domain.TaskOperationCreate:
+		return s.Create(ctx, op.Task)
+	case domain.TaskOperationUpdate:
+		task := op.Task
+		task.ID, task.Version = op.ID, op.Version
+		return s.Update(ctx, task)
+	case domain.TaskOperationPatch:
+		patch := op.Patch
+		patch.Version = op.Version
+		return s.Patch(ctx, op.ID, patch)
+	default:
+		return domain.Task{}, s.Delete(ctx, op.ID, op.Version)
+	}
+}
+
+// setTags replaces the tags of a task with the named ones and returns the
+// task as it is afterwards.
+func (s *TaskService) setTags(ctx context.Context, id int64, names []string) (domain.Task, error) {
+	v := validation.New()
+	for i, name := range names {
+		validateTagName(v, fmt.Sprintf("tags[%d]", i), name)
+	}
+	if err := v.Err(); err != nil {
+		return domain.Task{}, fmt.Errorf("TaskService.setTags: %w", err)
+	}
+
+	err := s.repo.SetTags(ctx, id, names)
+	if errors.Is(err, domain.ErrTaskNotFound) {
+		return domain.Task{}, fmt.Errorf("TaskService.setTags: %w", err)
+	}
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TaskService.setTags: %w: %w", domain.ErrTaskUpdateFailed, err)
+	}
+	return s.GetByID(ctx, id)
+}
+
+// validateBatch checks that ops holds between one and max well-formed
+// operations.
+func validateBatch(ops []domain.TaskOperation, max int) error {
+	v := validation.New()
+	if len(ops) == 0 {
+		v.Add("operations", validation.CodeRequired, "operations must not be empty")
+	}
+	if len(ops) > max {
+		v.Add("operations", validation.CodeTooLong, fmt.Sprintf("operations must not contain more than %d entries", max))
+	}
+	for i, op := range ops {
+		field := fmt.Sprintf("operations[%d]", i)
+		if !op.Kind.Valid() {
+			v.Add(field+".op", validation.CodeInvalidValue, "op must be one of create, update, patch or delete")
+			continue
+		}
+		if op.Kind != domain.TaskOperationCreate && op.ID <= 0 {
+			v.Add(field+".id", validation.CodeRequired, "id must be a positive integer")
+		}
+		if op.ParentOp != nil {
+			if op.Kind != domain.TaskOperationCreate {
+				v.Add(field+".parent_op", validation.CodeInvalidValue, "parent_op only applies to create operations")
+			} else if p := *op.ParentOp; p < 0 || p >= i || ops[p].Kind == domain.TaskOperationDelete {
+				v.Add(field+".parent_op", validation.CodeInvalidValue, "parent_op must name an earlier create, update or patch")
+			}
+		}
+	}
+	return v.Err()
+}
+
+func validateTask(task domain.Task) error {
+	v := validation.New()
+	validateTitle(v, task.Title)
+	v.MaxLength("description", task.Description, MaxDescriptionLength)
+	validatePriority(v, task.Priority)
+	validateSchedule(v, task.StartAt, task.DueAt)
+	validateRecurrence(v, task.Recurrence, task.DueAt)
+	return v.Err()
+}
+
+// validatePatch checks the fields present in patch. The start/due ordering
+// is checked against the stored task by the caller, since either side may
+// be missing from the patch.
+func validatePatch(patch domain.TaskPatch) error {
+	v := validation.New()
+	if patch.Title != nil {
+		validateTitle(v, *patch.Title)
+	}
+	if patch.Description != nil {
+		v.MaxLength("description", *patch.Description, MaxDescriptionLength)
+	}
+	if patch.Priority != nil {
+		validatePriority(v, *patch.Priority)
+	}
+	return v.Err()
+}
+
+// validatePatched checks the start/due ordering and the recurrence once
+// patch is applied to the stored task.
+func validatePatched(current domain.Task, patch domain.TaskPatch) error {
+	startAt, dueAt, rule := current.StartAt, current.DueAt, current.Recurrence
+	if patch.StartAt.Set {
+		startAt = patch.StartAt.Time
+	}
+	if patch.DueAt.Set {
+		dueAt = patch.DueAt.Time
+	}
+	if patch.Recurrence != nil {
+		rule = *patch.Recurrence
+	}
+
+	v := validation.New()
+	validateSchedule(v, startAt, dueAt)
+	validateRecurrence(v, rule, dueAt)
+	return v.Err()
+}
+
+// taskWriteErr classifies an error returned by a task write. A missing task,
+// hierarchy conflicts, open blockers, stale versions and validation errors
+// are kept as they are, references to a missing project or parent become
+// validation errors, and anything else is wrapped in failed.
+func taskWriteErr(err, failed error) error {
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound),
+		errors.Is(err, domain.ErrTaskHierarchyCycle),
+		errors.Is(err, domain.ErrTaskTooDeep),
+		errors.Is(err, domain.ErrTaskBlocked),
+		errors.Is(err, domain.ErrTaskVersionConflict),
+		errors.Is(err, domain.ErrValidationFailed):
+		return err
+	case errors.Is(err, domain.ErrProjectNotFound):
+		return unknownReferenceErr("project_id", "project_id does not refer to an existing project")
+	case errors.Is(err, domain.ErrParentTaskNotFound):
+		return unknownReferenceErr("parent_id", "parent_id does not refer to an existing task")
+	default:
+		return fmt.Errorf("%w: %w", failed, err)
+	}
+}
+
+// dependencyWriteErr keeps a missing task or a dependency cycle as it is and
+// wraps anything else in domain.ErrTaskUpdateFailed.
+func dependencyWriteErr(err error) error {
+	if errors.Is(err, domain.ErrTaskNotFound) || errors.Is(err, domain.ErrDependencyCycle) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", domain.ErrTaskUpdateFailed, err)
+}
+
+// unknownReferenceErr reports an ID field that refers to nothing. It is a
+// validation failure rather than a 404, since the task itself was found.
+func unknownReferenceErr(field, msg string) error {
+	v := validation.New()
+	v.Add(field, validation.CodeInvalidValue, msg)
+	return v.Err()
+}
+
+func validatePriority(v *validation.Validator, p domain.Priority) {
+	if !p.Valid() {
+		v.Add("priority", validation.CodeInvalidValue, "priority must be one of none, low, medium, high or urgent")
+	}
+}
+
+func validateSchedule(v *validation.Validator, startAt, dueAt *time.Time) {
+	if startAt != nil && dueAt != nil && startAt.After(*dueAt) {
+		v.Add("start_at", validation.CodeInvalidValue, "start_at must not be after due_at")
+	}
+}
+
+// validateRecurrence checks that rule is a supported RRULE and that the
+// task has the due date its occurrences are computed from.
+func validateRecurrence(v *validation.Validator, rule string, dueAt *time.Time) {
+	if rule == "" {
+		return
+	}
+	if _, err := recurrence.Parse(rule); err != nil {
+		v.Add("recurrence", validation.CodeInvalidValue, err.Error())
+		return
+	}
+	if dueAt == nil {
+		v.Add("recurrence", validation.CodeInvalidValue, "a recurring task requires due_at")
+	}
+}
+
+// canonicalRecurrence returns a valid rule in canonical form, so equal rules
+// are stored identically.
+func canonicalRecurrence(rule string) string {
+	if rule == "" {
+		return ""
+	}
+	parsed, err := recurrence.Parse(rule)
+	if err != nil {
+		return rule
+	}
+	return parsed.String()
+}
+
+func validateTitle(v *validation.Validator, title string) {
+	if v.Required("title", title) {
+		v.MaxLength("title", title, MaxTitleLength)
+	}
+}
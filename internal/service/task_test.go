@@ -3,23 +3,107 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
 )
 
 type mockTaskRepository struct {
-	getAllFunc func(ctx context.Context) ([]domain.Task, error)
+	getAllFunc              func(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error)
+	countFunc               func(ctx context.Context, filter domain.TaskFilter) (int, error)
+	searchFunc              func(ctx context.Context, query string, filter domain.TaskFilter) ([]domain.TaskSearchResult, error)
+	getByIDFunc             func(ctx context.Context, id int64) (domain.Task, error)
+	createFunc              func(ctx context.Context, task domain.Task) (domain.Task, error)
+	updateFunc              func(ctx context.Context, task domain.Task) (domain.Task, error)
+	patchFunc               func(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error)
+	deleteFunc              func(ctx context.Context, id, version int64) error
+	subtreeFunc             func(ctx context.Context, id int64) ([]domain.Task, error)
+	completeDescendantsFunc func(ctx context.Context, id int64) error
+	addBlockerFunc          func(ctx context.Context, taskID, blockerID int64) error
+	removeBlockerFunc       func(ctx context.Context, taskID, blockerID int64) error
+	blockersFunc            func(ctx context.Context, taskID int64) ([]domain.Task, error)
+	historyFunc             func(ctx context.Context, taskID int64, limit, offset int) ([]domain.TaskEvent, error)
+	countHistoryFunc        func(ctx context.Context, taskID int64) (int, error)
+	setTagsFunc             func(ctx context.Context, id int64, names []string) error
 }
 
-func (m *mockTaskRepository) GetAll(ctx context.Context) ([]domain.Task, error) {
-	return m.getAllFunc(ctx)
+func (m *mockTaskRepository) GetAll(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+	return m.getAllFunc(ctx, filter)
+}
+
+func (m *mockTaskRepository) Count(ctx context.Context, filter domain.TaskFilter) (int, error) {
+	if m.countFunc == nil {
+		return 0, nil
+	}
+	return m.countFunc(ctx, filter)
+}
+
+func (m *mockTaskRepository) Search(ctx context.Context, query string, filter domain.TaskFilter) ([]domain.TaskSearchResult, error) {
+	return m.searchFunc(ctx, query, filter)
+}
+
+func (m *mockTaskRepository) GetByID(ctx context.Context, id int64) (domain.Task, error) {
+	if m.getByIDFunc == nil {
+		return domain.Task{ID: id}, nil
+	}
+	return m.getByIDFunc(ctx, id)
+}
+
+func (m *mockTaskRepository) Create(ctx context.Context, task domain.Task) (domain.Task, error) {
+	return m.createFunc(ctx, task)
+}
+
+func (m *mockTaskRepository) Update(ctx context.Context, task domain.Task) (domain.Task, error) {
+	return m.updateFunc(ctx, task)
+}
+
+func (m *mockTaskRepository) Patch(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error) {
+	return m.patchFunc(ctx, id, patch)
+}
+
+func (m *mockTaskRepository) Delete(ctx context.Context, id, version int64) error {
+	return m.deleteFunc(ctx, id, version)
+}
+
+func (m *mockTaskRepository) Subtree(ctx context.Context, id int64) ([]domain.Task, error) {
+	return m.subtreeFunc(ctx, id)
+}
+
+func (m *mockTaskRepository) CompleteDescendants(ctx context.Context, id int64) error {
+	return m.completeDescendantsFunc(ctx, id)
+}
+
+func (m *mockTaskRepository) AddBlocker(ctx context.Context, taskID, blockerID int64) error {
+	return m.addBlockerFunc(ctx, taskID, blockerID)
+}
+
+func (m *mockTaskRepository) RemoveBlocker(ctx context.Context, taskID, blockerID int64) error {
+	return m.removeBlockerFunc(ctx, taskID, blockerID)
+}
+
+func (m *mockTaskRepository) Blockers(ctx context.Context, taskID int64) ([]domain.Task, error) {
+	return m.blockersFunc(ctx, taskID)
+}
+
+func (m *mockTaskRepository) History(ctx context.Context, taskID int64, limit, offset int) ([]domain.TaskEvent, error) {
+	return m.historyFunc(ctx, taskID, limit, offset)
+}
+
+func (m *mockTaskRepository) CountHistory(ctx context.Context, taskID int64) (int, error) {
+	return m.countHistoryFunc(ctx, taskID)
+}
+
+func (m *mockTaskRepository) SetTags(ctx context.Context, id int64, names []string) error {
+	return m.setTagsFunc(ctx, id, names)
 }
 
 func TestNewTaskService(t *testing.T) {
-	s := NewTaskService(nil)
+	s := NewTaskService(nil, nil, false)
 	if s == nil {
 		t.Fatal("expected service to be initialized")
 	}
@@ -36,7 +120,7 @@ func TestTaskService_GetAll(t *testing.T) {
 			name: "should return error when repository fails",
 			setup: func() *mockTaskRepository {
 				return &mockTaskRepository{
-					getAllFunc: func(ctx context.Context) ([]domain.Task, error) {
+					getAllFunc: func(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
 						return nil, domain.ErrTaskRetrievalFailed
 					},
 				}
@@ -48,7 +132,7 @@ func TestTaskService_GetAll(t *testing.T) {
 			name: "should return empty tasks list",
 			setup: func() *mockTaskRepository {
 				return &mockTaskRepository{
-					getAllFunc: func(ctx context.Context) ([]domain.Task, error) {
+					getAllFunc: func(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
 						return []domain.Task{}, nil
 					},
 				}
@@ -60,7 +144,7 @@ func TestTaskService_GetAll(t *testing.T) {
 			name: "should return populated tasks list",
 			setup: func() *mockTaskRepository {
 				return &mockTaskRepository{
-					getAllFunc: func(ctx context.Context) ([]domain.Task, error) {
+					getAllFunc: func(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
 						return []domain.Task{
 							{
 								ID:        1,
@@ -89,8 +173,8 @@ func TestTaskService_GetAll(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := tc.setup()
-			svc := NewTaskService(repo)
-			tasks, err := svc.GetAll(t.Context())
+			svc := NewTaskService(repo, &fakeTransactor{}, false)
+			page, err := svc.GetAll(t.Context(), domain.TaskFilter{})
 
 			if tc.expectedErr != nil {
 				if err == nil {
@@ -105,8 +189,1326 @@ func TestTaskService_GetAll(t *testing.T) {
 				}
 			}
 
-			if !reflect.DeepEqual(tasks, tc.expected) {
-				t.Fatalf("expected tasks %v but got %v", tc.expected, tasks)
+			if !reflect.DeepEqual(page.Tasks, tc.expected) {
+				t.Fatalf("expected tasks %v but got %v", tc.expected, page.Tasks)
+			}
+		})
+	}
+}
+
+func TestTaskService_GetAll_Pagination(t *testing.T) {
+	t.Run("should apply defaults and return total", func(t *testing.T) {
+		var got domain.TaskFilter
+		repo := &mockTaskRepository{
+			getAllFunc: func(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+				got = filter
+				return []domain.Task{{ID: 1}}, nil
+			},
+			countFunc: func(ctx context.Context, filter domain.TaskFilter) (int, error) {
+				return 42, nil
+			},
+		}
+		page, err := NewTaskService(repo, &fakeTransactor{}, false).GetAll(t.Context(), domain.TaskFilter{Offset: 10})
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if got.Limit != domain.DefaultTaskLimit+1 || got.Sort.Field != domain.TaskSortCreatedAt {
+			t.Fatalf("expected defaults plus a look-ahead row, got %+v", got)
+		}
+		if page.Total != 42 || page.Limit != domain.DefaultTaskLimit || page.Offset != 10 {
+			t.Fatalf("unexpected page metadata %+v", page)
+		}
+	})
+
+	t.Run("should wrap count failure", func(t *testing.T) {
+		repo := &mockTaskRepository{
+			getAllFunc: func(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+				return []domain.Task{}, nil
+			},
+			countFunc: func(ctx context.Context, filter domain.TaskFilter) (int, error) {
+				return 0, errors.New("db down")
+			},
+		}
+		_, err := NewTaskService(repo, &fakeTransactor{}, false).GetAll(t.Context(), domain.TaskFilter{})
+		if !errors.Is(err, domain.ErrTaskRetrievalFailed) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskRetrievalFailed, err)
+		}
+	})
+}
+
+func TestTaskService_GetAll_Cursors(t *testing.T) {
+	sort := domain.TaskSort{Field: domain.TaskSortTitle}
+	tasksByID := func(ids ...int64) []domain.Task {
+		tasks := make([]domain.Task, len(ids))
+		for i, id := range ids {
+			tasks[i] = domain.Task{ID: id, Title: string(rune('a' + id))}
+		}
+		return tasks
+	}
+
+	testCases := []struct {
+		name         string
+		filter       domain.TaskFilter
+		repoTasks    []domain.Task
+		expectedIDs  []int64
+		expectedNext int64
+		expectedPrev int64
+	}{
+		{
+			name:         "first page with more rows",
+			filter:       domain.TaskFilter{Sort: sort, Limit: 2},
+			repoTasks:    tasksByID(1, 2, 3),
+			expectedIDs:  []int64{1, 2},
+			expectedNext: 2,
+		},
+		{
+			name:        "single page",
+			filter:      domain.TaskFilter{Sort: sort, Limit: 5},
+			repoTasks:   tasksByID(1, 2),
+			expectedIDs: []int64{1, 2},
+		},
+		{
+			name:         "offset page links back",
+			filter:       domain.TaskFilter{Sort: sort, Limit: 2, Offset: 2},
+			repoTasks:    tasksByID(3, 4),
+			expectedIDs:  []int64{3, 4},
+			expectedPrev: 3,
+		},
+		{
+			name:         "forward cursor in the middle",
+			filter:       domain.TaskFilter{Sort: sort, Limit: 2, Cursor: &domain.TaskCursor{Sort: sort, ID: 2}},
+			repoTasks:    tasksByID(3, 4, 5),
+			expectedIDs:  []int64{3, 4},
+			expectedNext: 4,
+			expectedPrev: 3,
+		},
+		{
+			name:         "backward cursor in the middle",
+			filter:       domain.TaskFilter{Sort: sort, Limit: 2, Cursor: &domain.TaskCursor{Sort: sort, ID: 5, Backward: true}},
+			repoTasks:    tasksByID(2, 3, 4),
+			expectedIDs:  []int64{3, 4},
+			expectedNext: 4,
+			expectedPrev: 3,
+		},
+		{
+			name:         "backward cursor reaching the start",
+			filter:       domain.TaskFilter{Sort: sort, Limit: 2, Cursor: &domain.TaskCursor{Sort: sort, ID: 3, Backward: true}},
+			repoTasks:    tasksByID(1, 2),
+			expectedIDs:  []int64{1, 2},
+			expectedNext: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTaskRepository{
+				getAllFunc: func(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+					return tc.repoTasks, nil
+				},
+			}
+			page, err := NewTaskService(repo, &fakeTransactor{}, false).GetAll(t.Context(), tc.filter)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+
+			var ids []int64
+			for _, task := range page.Tasks {
+				ids = append(ids, task.ID)
+			}
+			if !reflect.DeepEqual(ids, tc.expectedIDs) {
+				t.Fatalf("expected ids %v but got %v", tc.expectedIDs, ids)
+			}
+			assertCursor(t, "next", page.NextCursor, tc.expectedNext, false)
+			assertCursor(t, "prev", page.PrevCursor, tc.expectedPrev, true)
+		})
+	}
+
+	t.Run("should reject cursor issued for another sort", func(t *testing.T) {
+		repo := &mockTaskRepository{}
+		filter := domain.TaskFilter{Sort: sort, Cursor: &domain.TaskCursor{Sort: domain.TaskSort{Field: domain.TaskSortCreatedAt}}}
+		if _, err := NewTaskService(repo, &fakeTransactor{}, false).GetAll(t.Context(), filter); !errors.Is(err, domain.ErrInvalidCursor) {
+			t.Fatalf("expected error %v but got %v", domain.ErrInvalidCursor, err)
+		}
+	})
+}
+
+func assertCursor(t *testing.T, name string, cur *domain.TaskCursor, expectedID int64, backward bool) {
+	t.Helper()
+	if expectedID == 0 {
+		if cur != nil {
+			t.Fatalf("expected no %s cursor but got %+v", name, cur)
+		}
+		return
+	}
+	if cur == nil || cur.ID != expectedID || cur.Backward != backward {
+		t.Fatalf("expected %s cursor at %d (backward=%v) but got %+v", name, expectedID, backward, cur)
+	}
+}
+
+func TestTaskService_Search(t *testing.T) {
+	testCases := []struct {
+		name        string
+		query       string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "should return results", query: "invoice"},
+		{name: "should reject blank query", query: "  ", expectedErr: domain.ErrValidationFailed},
+		{name: "should reject oversized query", query: strings.Repeat("a", MaxSearchQueryLength+1), expectedErr: domain.ErrValidationFailed},
+		{name: "should keep invalid syntax error", query: `"open`, repoErr: domain.ErrInvalidSearchQuery, expectedErr: domain.ErrInvalidSearchQuery},
+		{name: "should wrap repository failure", query: "invoice", repoErr: errors.New("db down"), expectedErr: domain.ErrTaskRetrievalFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTaskRepository{
+				searchFunc: func(ctx context.Context, query string, filter domain.TaskFilter) ([]domain.TaskSearchResult, error) {
+					if filter.Limit != domain.DefaultTaskLimit {
+						t.Errorf("expected default limit, got %d", filter.Limit)
+					}
+					if tc.repoErr != nil {
+						return nil, tc.repoErr
+					}
+					return []domain.TaskSearchResult{{Task: domain.Task{ID: 1}, Score: 1.5, Snippet: "<mark>invoice</mark>"}}, nil
+				},
+			}
+			results, err := NewTaskService(repo, &fakeTransactor{}, false).Search(t.Context(), tc.query, domain.TaskFilter{})
+
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if len(results) != 1 || results[0].Score != 1.5 {
+				t.Fatalf("unexpected results %v", results)
+			}
+		})
+	}
+}
+
+func TestTaskService_GetByID(t *testing.T) {
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "should return task", repoErr: nil},
+		{name: "should keep not found error", repoErr: domain.ErrTaskNotFound, expectedErr: domain.ErrTaskNotFound},
+		{name: "should wrap repository failure", repoErr: errors.New("db down"), expectedErr: domain.ErrTaskRetrievalFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTaskRepository{
+				getByIDFunc: func(ctx context.Context, id int64) (domain.Task, error) {
+					if tc.repoErr != nil {
+						return domain.Task{}, tc.repoErr
+					}
+					return domain.Task{ID: id, Title: "task"}, nil
+				},
+			}
+			task, err := NewTaskService(repo, &fakeTransactor{}, false).GetByID(t.Context(), 4)
+
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if task.ID != 4 {
+				t.Fatalf("expected task 4 but got %v", task)
+			}
+		})
+	}
+}
+
+func TestTaskService_Create(t *testing.T) {
+	t.Run("should return created task", func(t *testing.T) {
+		repo := &mockTaskRepository{
+			createFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+				task.ID = 1
+				return task, nil
+			},
+		}
+		task, err := NewTaskService(repo, &fakeTransactor{}, false).Create(t.Context(), domain.Task{Title: "new"})
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if task.ID != 1 || task.Title != "new" {
+			t.Fatalf("unexpected task %v", task)
+		}
+	})
+
+	t.Run("should wrap repository failure", func(t *testing.T) {
+		repo := &mockTaskRepository{
+			createFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+				return domain.Task{}, errors.New("db down")
+			},
+		}
+		_, err := NewTaskService(repo, &fakeTransactor{}, false).Create(t.Context(), domain.Task{Title: "new"})
+		if !errors.Is(err, domain.ErrTaskCreationFailed) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskCreationFailed, err)
+		}
+	})
+
+	t.Run("should report an unknown project as a validation error", func(t *testing.T) {
+		repo := &mockTaskRepository{
+			createFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+				return domain.Task{}, fmt.Errorf("insert: %w", domain.ErrProjectNotFound)
+			},
+		}
+		projectID := int64(9)
+		_, err := NewTaskService(repo, &fakeTransactor{}, false).Create(t.Context(), domain.Task{Title: "new", ProjectID: &projectID})
+		if !errors.Is(err, domain.ErrValidationFailed) {
+			t.Fatalf("expected error %v but got %v", domain.ErrValidationFailed, err)
+		}
+		fields := validation.Fields(err)
+		if len(fields) != 1 || fields[0].Field != "project_id" {
+			t.Fatalf("unexpected fields %v", fields)
+		}
+	})
+}
+
+func TestTaskService_Update(t *testing.T) {
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "should return updated task", repoErr: nil},
+		{name: "should keep not found error", repoErr: domain.ErrTaskNotFound, expectedErr: domain.ErrTaskNotFound},
+		{name: "should keep version conflict", repoErr: domain.ErrTaskVersionConflict, expectedErr: domain.ErrTaskVersionConflict},
+		{name: "should wrap repository failure", repoErr: errors.New("db down"), expectedErr: domain.ErrTaskUpdateFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTaskRepository{
+				updateFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+					return task, tc.repoErr
+				},
+			}
+			_, err := NewTaskService(repo, &fakeTransactor{}, false).Update(t.Context(), domain.Task{ID: 1, Title: "x"})
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestTaskService_Patch(t *testing.T) {
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "should return patched task", repoErr: nil},
+		{name: "should keep not found error", repoErr: domain.ErrTaskNotFound, expectedErr: domain.ErrTaskNotFound},
+		{name: "should keep hierarchy cycle error", repoErr: domain.ErrTaskHierarchyCycle, expectedErr: domain.ErrTaskHierarchyCycle},
+		{name: "should keep too deep error", repoErr: domain.ErrTaskTooDeep, expectedErr: domain.ErrTaskTooDeep},
+		{name: "should keep blocked error", repoErr: domain.ErrTaskBlocked, expectedErr: domain.ErrTaskBlocked},
+		{name: "should report a missing parent as a validation error", repoErr: domain.ErrParentTaskNotFound, expectedErr: domain.ErrValidationFailed},
+		{name: "should wrap repository failure", repoErr: errors.New("db down"), expectedErr: domain.ErrTaskUpdateFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTaskRepository{
+				patchFunc: func(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error) {
+					return domain.Task{ID: id}, tc.repoErr
+				},
+			}
+			done := true
+			_, err := NewTaskService(repo, &fakeTransactor{}, false).Patch(t.Context(), 1, domain.TaskPatch{Done: &done})
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestTaskService_CompleteChildren(t *testing.T) {
+	done, open := true, false
+
+	testCases := []struct {
+		name             string
+		completeChildren bool
+		patch            domain.TaskPatch
+		expectCascade    bool
+	}{
+		{name: "should complete subtasks when enabled", completeChildren: true, patch: domain.TaskPatch{Done: &done}, expectCascade: true},
+		{name: "should leave subtasks when disabled", patch: domain.TaskPatch{Done: &done}},
+		{name: "should leave subtasks when reopening", completeChildren: true, patch: domain.TaskPatch{Done: &open}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cascaded := false
+			repo := &mockTaskRepository{
+				patchFunc: func(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error) {
+					return domain.Task{ID: id, Done: *patch.Done}, nil
+				},
+				completeDescendantsFunc: func(ctx context.Context, id int64) error {
+					if id != 1 {
+						t.Errorf("expected id 1, got %d", id)
+					}
+					cascaded = true
+					return nil
+				},
+			}
+			if _, err := NewTaskService(repo, &fakeTransactor{}, tc.completeChildren).Patch(t.Context(), 1, tc.patch); err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if cascaded != tc.expectCascade {
+				t.Fatalf("expected cascade %v, got %v", tc.expectCascade, cascaded)
+			}
+		})
+	}
+
+	t.Run("should wrap cascade failure", func(t *testing.T) {
+		repo := &mockTaskRepository{
+			updateFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+				return task, nil
+			},
+			completeDescendantsFunc: func(ctx context.Context, id int64) error {
+				return errors.New("db down")
+			},
+		}
+		_, err := NewTaskService(repo, &fakeTransactor{}, true).Update(t.Context(), domain.Task{ID: 1, Title: "x", Done: true})
+		if !errors.Is(err, domain.ErrTaskUpdateFailed) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskUpdateFailed, err)
+		}
+	})
+
+	t.Run("should undo the completion when a subtask is blocked", func(t *testing.T) {
+		repo := &mockTaskRepository{
+			patchFunc: func(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error) {
+				return domain.Task{ID: id, Done: true}, nil
+			},
+			completeDescendantsFunc: func(ctx context.Context, id int64) error {
+				return domain.ErrTaskBlocked
+			},
+		}
+		tx := &fakeTransactor{}
+		_, err := NewTaskService(repo, tx, true).Patch(t.Context(), 1, domain.TaskPatch{Done: &done})
+		if !errors.Is(err, domain.ErrTaskBlocked) || errors.Is(err, domain.ErrTaskUpdateFailed) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskBlocked, err)
+		}
+		if tx.rolled != 1 || tx.committed != 0 {
+			t.Fatalf("expected the completion to roll back, got %d commits and %d rollbacks", tx.committed, tx.rolled)
+		}
+	})
+}
+
+func TestTaskService_Recurrence(t *testing.T) {
+	due := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	start := due.Add(-2 * time.Hour)
+	projectID := int64(3)
+	done := true
+
+	testCases := []struct {
+		name       string
+		stored     domain.Task
+		rule       string
+		expectNext *domain.Task
+	}{
+		{
+			name:   "should spawn the next occurrence",
+			stored: domain.Task{ID: 1},
+			rule:   "FREQ=WEEKLY;BYDAY=MO,TH",
+			expectNext: &domain.Task{
+				Title: "On-call", Priority: domain.PriorityHigh, ProjectID: &projectID,
+				StartAt: ptr(start.AddDate(0, 0, 3)), DueAt: ptr(due.AddDate(0, 0, 3)), Recurrence: "FREQ=WEEKLY;BYDAY=MO,TH",
+			},
+		},
+		{
+			name:   "should count down the remaining occurrences",
+			stored: domain.Task{ID: 1},
+			rule:   "FREQ=DAILY;COUNT=3",
+			expectNext: &domain.Task{
+				Title: "On-call", Priority: domain.PriorityHigh, ProjectID: &projectID,
+				StartAt: ptr(start.AddDate(0, 0, 1)), DueAt: ptr(due.AddDate(0, 0, 1)), Recurrence: "FREQ=DAILY;COUNT=2",
+			},
+		},
+		{name: "should end the series after the last occurrence", stored: domain.Task{ID: 1}, rule: "FREQ=DAILY;COUNT=1"},
+		{name: "should end the series at UNTIL", stored: domain.Task{ID: 1}, rule: "FREQ=DAILY;UNTIL=20261019T235959Z"},
+		{name: "should not spawn again for a task that was done", stored: domain.Task{ID: 1, Done: true}, rule: "FREQ=DAILY"},
+		{name: "should not spawn for a one-off task", stored: domain.Task{ID: 1}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var spawned *domain.Task
+			repo := &mockTaskRepository{
+				getByIDFunc: func(ctx context.Context, id int64) (domain.Task, error) {
+					return tc.stored, nil
+				},
+				patchFunc: func(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error) {
+					return domain.Task{
+						ID: id, Title: "On-call", Done: true, Priority: domain.PriorityHigh, ProjectID: &projectID,
+						StartAt: &start, DueAt: &due, Recurrence: tc.rule,
+					}, nil
+				},
+				createFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+					spawned = &task
+					return task, nil
+				},
+			}
+			if _, err := NewTaskService(repo, &fakeTransactor{}, false).Patch(t.Context(), 1, domain.TaskPatch{Done: &done}); err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if !reflect.DeepEqual(spawned, tc.expectNext) {
+				t.Fatalf("expected next occurrence %+v but got %+v", tc.expectNext, spawned)
+			}
+		})
+	}
+
+	t.Run("should undo the completion when the spawn fails", func(t *testing.T) {
+		tx := &fakeTransactor{}
+		repo := &mockTaskRepository{
+			getByIDFunc: func(ctx context.Context, id int64) (domain.Task, error) {
+				if tx.depth == 0 {
+					t.Error("expected the task to be read inside the unit of work")
+				}
+				return domain.Task{ID: id}, nil
+			},
+			updateFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+				return task, nil
+			},
+			createFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+				return domain.Task{}, errors.New("db down")
+			},
+		}
+		_, err := NewTaskService(repo, tx, false).Update(t.Context(), domain.Task{ID: 1, Title: "T", Done: true, DueAt: &due, Recurrence: "FREQ=DAILY"})
+		if !errors.Is(err, domain.ErrTaskUpdateFailed) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskUpdateFailed, err)
+		}
+		if tx.rolled != 1 || tx.committed != 0 {
+			t.Fatalf("expected the completion to roll back, got %d commits and %d rollbacks", tx.committed, tx.rolled)
+		}
+	})
+
+	t.Run("should store the canonical rule", func(t *testing.T) {
+		repo := &mockTaskRepository{
+			createFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+				if task.Recurrence != "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO" {
+					t.Errorf("unexpected recurrence %q", task.Recurrence)
+				}
+				return task, nil
+			},
+		}
+		if _, err := NewTaskService(repo, &fakeTransactor{}, false).Create(t.Context(), domain.Task{Title: "T", DueAt: &due, Recurrence: "rrule:freq=weekly;byday=mo;interval=2"}); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	})
+}
+
+func TestTaskService_Occurrences(t *testing.T) {
+	due := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		stored      domain.Task
+		repoErr     error
+		expected    []time.Time
+		expectedErr error
+	}{
+		{
+			name:     "should list the upcoming occurrences",
+			stored:   domain.Task{DueAt: &due, Recurrence: "FREQ=WEEKLY"},
+			expected: []time.Time{due, due.AddDate(0, 0, 7), due.AddDate(0, 0, 14)},
+		},
+		{name: "should return none for a one-off task", stored: domain.Task{DueAt: &due}, expected: []time.Time{}},
+		{name: "should keep not found error", repoErr: domain.ErrTaskNotFound, expectedErr: domain.ErrTaskNotFound},
+		{name: "should wrap repository failure", repoErr: errors.New("db down"), expectedErr: domain.ErrTaskRetrievalFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTaskRepository{
+				getByIDFunc: func(ctx context.Context, id int64) (domain.Task, error) {
+					return tc.stored, tc.repoErr
+				},
+			}
+			got, err := NewTaskService(repo, &fakeTransactor{}, false).Occurrences(t.Context(), 1, 3)
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected occurrences %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestTaskService_GetTree(t *testing.T) {
+	parent := func(id int64) *int64 { return &id }
+
+	t.Run("should nest subtasks under their parents", func(t *testing.T) {
+		repo := &mockTaskRepository{
+			subtreeFunc: func(ctx context.Context, id int64) ([]domain.Task, error) {
+				return []domain.Task{
+					{ID: 1, ParentID: parent(9)},
+					{ID: 2, ParentID: parent(1)},
+					{ID: 3, ParentID: parent(1)},
+					{ID: 4, ParentID: parent(3)},
+				}, nil
+			},
+		}
+		tree, err := NewTaskService(repo, &fakeTransactor{}, false).GetTree(t.Context(), 1)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		expected := domain.TaskNode{
+			Task: domain.Task{ID: 1, ParentID: parent(9)},
+			Children: []domain.TaskNode{
+				{Task: domain.Task{ID: 2, ParentID: parent(1)}, Children: []domain.TaskNode{}},
+				{Task: domain.Task{ID: 3, ParentID: parent(1)}, Children: []domain.TaskNode{
+					{Task: domain.Task{ID: 4, ParentID: parent(3)}, Children: []domain.TaskNode{}},
+				}},
+			},
+		}
+		if !reflect.DeepEqual(tree, expected) {
+			t.Fatalf("expected tree %+v but got %+v", expected, tree)
+		}
+	})
+
+	t.Run("should keep not found error", func(t *testing.T) {
+		repo := &mockTaskRepository{
+			subtreeFunc: func(ctx context.Context, id int64) ([]domain.Task, error) {
+				return nil, domain.ErrTaskNotFound
+			},
+		}
+		if _, err := NewTaskService(repo, &fakeTransactor{}, false).GetTree(t.Context(), 1); !errors.Is(err, domain.ErrTaskNotFound) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskNotFound, err)
+		}
+	})
+}
+
+func TestTaskService_AddBlocker(t *testing.T) {
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "should add blocker"},
+		{name: "should keep not found error", repoErr: domain.ErrTaskNotFound, expectedErr: domain.ErrTaskNotFound},
+		{name: "should keep cycle error", repoErr: domain.ErrDependencyCycle, expectedErr: domain.ErrDependencyCycle},
+		{name: "should wrap repository failure", repoErr: errors.New("db down"), expectedErr: domain.ErrTaskUpdateFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTaskRepository{
+				addBlockerFunc: func(ctx context.Context, taskID, blockerID int64) error {
+					if taskID != 1 || blockerID != 2 {
+						t.Errorf("expected ids 1 and 2, got %d and %d", taskID, blockerID)
+					}
+					return tc.repoErr
+				},
+			}
+			err := NewTaskService(repo, &fakeTransactor{}, false).AddBlocker(t.Context(), 1, 2)
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestTaskService_Blockers(t *testing.T) {
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "should return blockers"},
+		{name: "should keep not found error", repoErr: domain.ErrTaskNotFound, expectedErr: domain.ErrTaskNotFound},
+		{name: "should wrap repository failure", repoErr: errors.New("db down"), expectedErr: domain.ErrTaskRetrievalFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTaskRepository{
+				blockersFunc: func(ctx context.Context, taskID int64) ([]domain.Task, error) {
+					return []domain.Task{{ID: 2}}, tc.repoErr
+				},
+			}
+			_, err := NewTaskService(repo, &fakeTransactor{}, false).Blockers(t.Context(), 1)
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestTaskService_History(t *testing.T) {
+	testCases := []struct {
+		name          string
+		limit         int
+		repoErr       error
+		expectedLimit int
+		expectedErr   error
+	}{
+		{name: "should apply the default limit", expectedLimit: domain.DefaultHistoryLimit},
+		{name: "should keep the requested limit", limit: 5, expectedLimit: 5},
+		{name: "should keep not found error", repoErr: domain.ErrTaskNotFound, expectedErr: domain.ErrTaskNotFound},
+		{name: "should wrap repository failure", repoErr: errors.New("db down"), expectedErr: domain.ErrTaskRetrievalFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotLimit int
+			repo := &mockTaskRepository{
+				historyFunc: func(ctx context.Context, taskID int64, limit, offset int) ([]domain.TaskEvent, error) {
+					gotLimit = limit
+					return []domain.TaskEvent{{ID: 2, TaskID: taskID}}, tc.repoErr
+				},
+				countHistoryFunc: func(ctx context.Context, taskID int64) (int, error) {
+					return 7, nil
+				},
+			}
+			page, err := NewTaskService(repo, &fakeTransactor{}, false).History(t.Context(), 1, tc.limit, 3)
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if gotLimit != tc.expectedLimit || page.Limit != tc.expectedLimit || page.Offset != 3 || page.Total != 7 || len(page.Events) != 1 {
+				t.Fatalf("unexpected page %+v for limit %d", page, gotLimit)
+			}
+		})
+	}
+}
+
+func TestTaskService_Delete(t *testing.T) {
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "should delete task", repoErr: nil},
+		{name: "should keep not found error", repoErr: domain.ErrTaskNotFound, expectedErr: domain.ErrTaskNotFound},
+		{name: "should keep version conflict", repoErr: domain.ErrTaskVersionConflict, expectedErr: domain.ErrTaskVersionConflict},
+		{name: "should wrap repository failure", repoErr: errors.New("db down"), expectedErr: domain.ErrTaskDeletionFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTaskRepository{
+				deleteFunc: func(ctx context.Context, id, version int64) error {
+					if version != 3 {
+						t.Errorf("expected version 3, got %d", version)
+					}
+					return tc.repoErr
+				},
+			}
+			err := NewTaskService(repo, &fakeTransactor{}, false).Delete(t.Context(), 1, 3)
+			if tc.expectedErr == nil && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+// fakeTransactor runs units of work in place and records how they ended.
+type fakeTransactor struct {
+	depth     int
+	commitErr error
+	committed int
+	rolled    int
+}
+
+func (f *fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	f.depth++
+	err := fn(ctx)
+	f.depth--
+	if err == nil && f.depth == 0 {
+		err = f.commitErr
+	}
+	if err != nil {
+		f.rolled++
+		return err
+	}
+	f.committed++
+	return nil
+}
+
+func TestTaskService_Batch(t *testing.T) {
+	ops := []domain.TaskOperation{
+		{Kind: domain.TaskOperationCreate, Task: domain.Task{Title: "new"}},
+		{Kind: domain.TaskOperationPatch, ID: 2, Version: 4, Patch: domain.TaskPatch{Title: ptr("renamed")}},
+		{Kind: domain.TaskOperationDelete, ID: 3, Version: 5},
+	}
+	errDB := errors.New("db down")
+
+	testCases := []struct {
+		name              string
+		continueOnError   bool
+		patchErr          error
+		commitErr         error
+		expectedErrs      []error
+		expectedCommitted bool
+		expectedDeletes   int
+		expectedErr       error
+	}{
+		{
+			name:              "should run every operation and commit",
+			expectedErrs:      []error{nil, nil, nil},
+			expectedCommitted: true,
+			expectedDeletes:   1,
+		},
+		{
+			name:            "should skip the rest of an atomic batch after a failure",
+			patchErr:        domain.ErrTaskVersionConflict,
+			expectedErrs:    []error{nil, domain.ErrTaskVersionConflict, domain.ErrTaskOperationSkipped},
+			expectedDeletes: 0,
+		},
+		{
+			name:              "should continue past a failure",
+			continueOnError:   true,
+			patchErr:          domain.ErrTaskNotFound,
+			expectedErrs:      []error{nil, domain.ErrTaskNotFound, nil},
+			expectedCommitted: true,
+			expectedDeletes:   1,
+		},
+		{
+			name:        "should fail when the transaction cannot commit",
+			commitErr:   errDB,
+			expectedErr: domain.ErrTaskBatchFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			deletes := 0
+			repo := &mockTaskRepository{
+				createFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+					task.ID = 1
+					return task, nil
+				},
+				patchFunc: func(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error) {
+					if id != 2 || patch.Version != 4 {
+						t.Errorf("expected task 2 at version 4, got %d at %d", id, patch.Version)
+					}
+					return domain.Task{ID: id, Title: *patch.Title}, tc.patchErr
+				},
+				deleteFunc: func(ctx context.Context, id, version int64) error {
+					if id != 3 || version != 5 {
+						t.Errorf("expected task 3 at version 5, got %d at %d", id, version)
+					}
+					deletes++
+					return nil
+				},
+			}
+			tx := &fakeTransactor{commitErr: tc.commitErr}
+
+			batch, err := NewTaskService(repo, tx, false).Batch(t.Context(), ops, tc.continueOnError)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if batch.Committed != tc.expectedCommitted {
+				t.Errorf("expected committed %v, got %v", tc.expectedCommitted, batch.Committed)
+			}
+			if deletes != tc.expectedDeletes {
+				t.Errorf("expected %d deletes, got %d", tc.expectedDeletes, deletes)
+			}
+			if len(batch.Results) != len(ops) {
+				t.Fatalf("expected %d results, got %d", len(ops), len(batch.Results))
+			}
+			for i, expected := range tc.expectedErrs {
+				if got := batch.Results[i].Err; !errors.Is(got, expected) || (expected == nil && got != nil) {
+					t.Errorf("result %d: expected error %v but got %v", i, expected, got)
+				}
+			}
+			if tc.expectedErrs[0] == nil && batch.Results[0].Task.ID != 1 {
+				t.Errorf("expected the created task in the first result, got %+v", batch.Results[0].Task)
+			}
+		})
+	}
+}
+
+func TestTaskService_Import(t *testing.T) {
+	ops := []domain.TaskOperation{
+		{Kind: domain.TaskOperationCreate, Task: domain.Task{Title: "new"}},
+		{Kind: domain.TaskOperationPatch, ID: 2, Patch: domain.TaskPatch{Title: ptr("renamed")}},
+		{Kind: domain.TaskOperationCreate, Task: domain.Task{Title: "last"}},
+	}
+
+	testCases := []struct {
+		name              string
+		dryRun            bool
+		patchErr          error
+		expectedCommitted bool
+		expectedCreates   int
+	}{
+		{name: "should commit an import without failures", expectedCommitted: true, expectedCreates: 2},
+		{name: "should run every row but roll back when one fails", patchErr: domain.ErrTaskNotFound, expectedCreates: 2},
+		{name: "should roll back a dry run", dryRun: true, expectedCreates: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			creates := 0
+			repo := &mockTaskRepository{
+				createFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+					creates++
+					return task, nil
+				},
+				patchFunc: func(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error) {
+					return domain.Task{ID: id}, tc.patchErr
+				},
+			}
+			tx := &fakeTransactor{}
+
+			batch, err := NewTaskService(repo, tx, false).Import(t.Context(), ops, tc.dryRun)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if batch.Committed != tc.expectedCommitted {
+				t.Errorf("expected committed %v, got %v", tc.expectedCommitted, batch.Committed)
+			}
+			if creates != tc.expectedCreates {
+				t.Errorf("expected %d creates, got %d", tc.expectedCreates, creates)
+			}
+			if !errors.Is(batch.Results[1].Err, tc.patchErr) || tc.patchErr == nil && batch.Results[1].Err != nil {
+				t.Errorf("expected error %v for the second row, got %v", tc.patchErr, batch.Results[1].Err)
+			}
+		})
+	}
+}
+
+func TestTaskService_Import_ParentOp(t *testing.T) {
+	parent := 0
+	ops := []domain.TaskOperation{
+		{Kind: domain.TaskOperationCreate, Task: domain.Task{Title: "parent"}},
+		{Kind: domain.TaskOperationCreate, Task: domain.Task{Title: "child"}, ParentOp: &parent},
+	}
+
+	testCases := []struct {
+		name           string
+		parentErr      error
+		expectedErrs   []error
+		expectedCreate int
+	}{
+		{name: "should create the child under the created parent", expectedErrs: []error{nil, nil}, expectedCreate: 2},
+		{
+			name:           "should skip the child of a failed parent",
+			parentErr:      domain.ErrTaskCreationFailed,
+			expectedErrs:   []error{domain.ErrTaskCreationFailed, domain.ErrTaskOperationSkipped},
+			expectedCreate: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			creates := 0
+			repo := &mockTaskRepository{
+				createFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+					creates++
+					if task.Title == "parent" {
+						task.ID = 7
+						return task, tc.parentErr
+					}
+					if task.ParentID == nil || *task.ParentID != 7 {
+						t.Errorf("expected the child to have parent 7, got %v", task.ParentID)
+					}
+					task.ID = 8
+					return task, nil
+				},
+			}
+
+			batch, err := NewTaskService(repo, &fakeTransactor{}, false).Import(t.Context(), ops, false)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if creates != tc.expectedCreate {
+				t.Errorf("expected %d creates, got %d", tc.expectedCreate, creates)
+			}
+			for i, expected := range tc.expectedErrs {
+				if got := batch.Results[i].Err; !errors.Is(got, expected) || (expected == nil && got != nil) {
+					t.Errorf("result %d: expected error %v but got %v", i, expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskService_Import_CreateMissing(t *testing.T) {
+	title := "Renamed"
+	op := domain.TaskOperation{
+		Kind: domain.TaskOperationPatch, ID: 9, Version: 2, CreateMissing: true,
+		Task:  domain.Task{Title: title},
+		Patch: domain.TaskPatch{Title: &title},
+	}
+
+	testCases := []struct {
+		name          string
+		getErr        error
+		expectCreated bool
+		expectedErr   error
+	}{
+		{name: "should patch a task that exists"},
+		{name: "should create a task that does not exist", getErr: domain.ErrTaskNotFound, expectCreated: true},
+		{name: "should wrap storage failures", getErr: errors.New("db down"), expectedErr: domain.ErrTaskUpdateFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var created, patched bool
+			repo := &mockTaskRepository{
+				getByIDFunc: func(ctx context.Context, id int64) (domain.Task, error) {
+					return domain.Task{ID: id}, tc.getErr
+				},
+				createFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+					created = true
+					task.ID = 12
+					return task, nil
+				},
+				patchFunc: func(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error) {
+					patched = true
+					return domain.Task{ID: id, Title: *patch.Title}, nil
+				},
+			}
+
+			batch, err := NewTaskService(repo, &fakeTransactor{}, false).Import(t.Context(), []domain.TaskOperation{op}, false)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			res := batch.Results[0]
+			if !errors.Is(res.Err, tc.expectedErr) || tc.expectedErr == nil && res.Err != nil {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, res.Err)
+			}
+			if tc.expectedErr != nil {
+				return
+			}
+			if created != tc.expectCreated || patched == tc.expectCreated || res.Created != tc.expectCreated {
+				t.Fatalf("expected created %v, got create %v, patch %v and result %v", tc.expectCreated, created, patched, res.Created)
+			}
+		})
+	}
+}
+
+func TestTaskService_Import_Tags(t *testing.T) {
+	testCases := []struct {
+		name         string
+		tags         []string
+		setErr       error
+		expectedSet  []string
+		expectedErr  error
+		expectedTags []domain.Tag
+	}{
+		{name: "should leave tags alone when the row names none", expectedTags: nil},
+		{
+			name:         "should replace the tags of the written task",
+			tags:         []string{"home", "errands"},
+			expectedSet:  []string{"home", "errands"},
+			expectedTags: []domain.Tag{{Name: "errands"}, {Name: "home"}},
+		},
+		{name: "should clear the tags", tags: []string{}, expectedSet: []string{}},
+		{name: "should reject invalid tag names", tags: []string{"a,b"}, expectedErr: domain.ErrValidationFailed},
+		{name: "should wrap storage failures", tags: []string{"home"}, expectedSet: []string{"home"}, setErr: errors.New("db down"), expectedErr: domain.ErrTaskUpdateFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var set []string
+			repo := &mockTaskRepository{
+				createFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+					task.ID = 4
+					return task, nil
+				},
+				setTagsFunc: func(ctx context.Context, id int64, names []string) error {
+					if id != 4 {
+						t.Errorf("expected tags set on task 4, got %d", id)
+					}
+					set = names
+					return tc.setErr
+				},
+				getByIDFunc: func(ctx context.Context, id int64) (domain.Task, error) {
+					return domain.Task{ID: id, Title: "new", Tags: tc.expectedTags}, nil
+				},
+			}
+			op := domain.TaskOperation{Kind: domain.TaskOperationCreate, Task: domain.Task{Title: "new"}, Tags: tc.tags}
+
+			batch, err := NewTaskService(repo, &fakeTransactor{}, false).Import(t.Context(), []domain.TaskOperation{op}, false)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if !reflect.DeepEqual(set, tc.expectedSet) {
+				t.Errorf("expected tags %v to be set, got %v", tc.expectedSet, set)
+			}
+			res := batch.Results[0]
+			if !errors.Is(res.Err, tc.expectedErr) || tc.expectedErr == nil && res.Err != nil {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, res.Err)
+			}
+			if res.Err == nil && !reflect.DeepEqual(res.Task.Tags, tc.expectedTags) {
+				t.Errorf("expected tags %v, got %v", tc.expectedTags, res.Task.Tags)
+			}
+		})
+	}
+}
+
+func TestTaskService_Export(t *testing.T) {
+	page := func(from, n int) []domain.Task {
+		tasks := make([]domain.Task, n)
+		for i := range tasks {
+			tasks[i] = domain.Task{ID: int64(from + i)}
+		}
+		return tasks
+	}
+	done := true
+
+	var calls []domain.TaskFilter
+	repo := &mockTaskRepository{
+		getAllFunc: func(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+			calls = append(calls, filter)
+			if filter.Cursor == nil {
+				return page(1, domain.MaxTaskLimit), nil
+			}
+			return page(domain.MaxTaskLimit+1, 3), nil
+		},
+	}
+
+	var ids []int64
+	err := NewTaskService(repo, &fakeTransactor{}, false).Export(t.Context(), domain.TaskFilter{Done: &done, Limit: 5, Offset: 10}, func(task domain.Task) error {
+		ids = append(ids, task.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if len(ids) != domain.MaxTaskLimit+3 || ids[len(ids)-1] != domain.MaxTaskLimit+3 {
+		t.Fatalf("expected every task in order, got %d ending with %d", len(ids), ids[len(ids)-1])
+	}
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(calls))
+	}
+	first, second := calls[0], calls[1]
+	if first.Done != &done || first.Limit != domain.MaxTaskLimit || first.Offset != 0 || first.Cursor != nil {
+		t.Errorf("unexpected first page filter %+v", first)
+	}
+	if second.Cursor == nil || second.Cursor.ID != domain.MaxTaskLimit {
+		t.Errorf("expected the second page to follow the last task, got %+v", second.Cursor)
+	}
+
+	errStop := errors.New("client gone")
+	err = NewTaskService(repo, &fakeTransactor{}, false).Export(t.Context(), domain.TaskFilter{}, func(domain.Task) error { return errStop })
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expected error %v but got %v", errStop, err)
+	}
+}
+
+func TestTaskService_Batch_Validation(t *testing.T) {
+	testCases := []struct {
+		name   string
+		ops    []domain.TaskOperation
+		fields []string
+	}{
+		{name: "should reject an empty batch", ops: nil, fields: []string{"operations"}},
+		{name: "should reject too many operations", ops: make([]domain.TaskOperation, domain.MaxBatchOperations+1), fields: []string{"operations"}},
+		{
+			name: "should reject unknown kinds and missing IDs",
+			ops: []domain.TaskOperation{
+				{Kind: "archive", ID: 1},
+				{Kind: domain.TaskOperationDelete},
+				{Kind: domain.TaskOperationCreate},
+			},
+			fields: []string{"operations[0].op", "operations[1].id"},
+		},
+		{
+			name: "should reject parent references that are not earlier writes",
+			ops: []domain.TaskOperation{
+				{Kind: domain.TaskOperationCreate, ParentOp: ptr(0)},
+				{Kind: domain.TaskOperationPatch, ID: 1, ParentOp: ptr(0)},
+				{Kind: domain.TaskOperationDelete, ID: 1},
+				{Kind: domain.TaskOperationCreate, ParentOp: ptr(2)},
+			},
+			fields: []string{"operations[0].parent_op", "operations[1].parent_op", "operations[3].parent_op"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx := &fakeTransactor{}
+			_, err := NewTaskService(&mockTaskRepository{}, tx, false).Batch(t.Context(), tc.ops, false)
+			if !errors.Is(err, domain.ErrValidationFailed) {
+				t.Fatalf("expected error %v but got %v", domain.ErrValidationFailed, err)
+			}
+			var fields []string
+			for _, f := range validation.Fields(err) {
+				fields = append(fields, f.Field)
+			}
+			if !reflect.DeepEqual(fields[:len(tc.fields)], tc.fields) {
+				t.Errorf("expected fields %v, got %v", tc.fields, fields)
+			}
+			if tx.committed+tx.rolled != 0 {
+				t.Error("expected no transaction for an invalid batch")
+			}
+		})
+	}
+}
+
+func TestTaskService_Validation(t *testing.T) {
+	startAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	repo := &mockTaskRepository{
+		createFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+			t.Fatal("repository should not be called with invalid input")
+			return domain.Task{}, nil
+		},
+		updateFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+			t.Fatal("repository should not be called with invalid input")
+			return domain.Task{}, nil
+		},
+		patchFunc: func(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error) {
+			t.Fatal("repository should not be called with invalid input")
+			return domain.Task{}, nil
+		},
+		getByIDFunc: func(ctx context.Context, id int64) (domain.Task, error) {
+			return domain.Task{ID: id, Title: "Stored", StartAt: &startAt}, nil
+		},
+	}
+	svc := NewTaskService(repo, &fakeTransactor{}, false)
+	longTitle := strings.Repeat("a", MaxTitleLength+1)
+	longDescription := strings.Repeat("a", MaxDescriptionLength+1)
+	blank := " "
+	unknown := domain.Priority(42)
+	dueAt := startAt.Add(-time.Hour)
+
+	testCases := []struct {
+		name  string
+		call  func() error
+		field string
+		code  string
+	}{
+		{
+			name:  "create with empty title",
+			call:  func() error { _, err := svc.Create(t.Context(), domain.Task{}); return err },
+			field: "title",
+			code:  validation.CodeRequired,
+		},
+		{
+			name:  "update with oversized title",
+			call:  func() error { _, err := svc.Update(t.Context(), domain.Task{ID: 1, Title: longTitle}); return err },
+			field: "title",
+			code:  validation.CodeTooLong,
+		},
+		{
+			name:  "patch with blank title",
+			call:  func() error { _, err := svc.Patch(t.Context(), 1, domain.TaskPatch{Title: &blank}); return err },
+			field: "title",
+			code:  validation.CodeRequired,
+		},
+		{
+			name: "create with oversized description",
+			call: func() error {
+				_, err := svc.Create(t.Context(), domain.Task{Title: "T", Description: longDescription})
+				return err
+			},
+			field: "description",
+			code:  validation.CodeTooLong,
+		},
+		{
+			name:  "patch with unknown priority",
+			call:  func() error { _, err := svc.Patch(t.Context(), 1, domain.TaskPatch{Priority: &unknown}); return err },
+			field: "priority",
+			code:  validation.CodeInvalidValue,
+		},
+		{
+			name: "create starting after its due date",
+			call: func() error {
+				_, err := svc.Create(t.Context(), domain.Task{Title: "T", StartAt: &startAt, DueAt: &dueAt})
+				return err
+			},
+			field: "start_at",
+			code:  validation.CodeInvalidValue,
+		},
+		{
+			name: "create with an unsupported recurrence",
+			call: func() error {
+				_, err := svc.Create(t.Context(), domain.Task{Title: "T", DueAt: &startAt, Recurrence: "FREQ=HOURLY"})
+				return err
+			},
+			field: "recurrence",
+			code:  validation.CodeInvalidValue,
+		},
+		{
+			name: "create recurring without a due date",
+			call: func() error {
+				_, err := svc.Create(t.Context(), domain.Task{Title: "T", Recurrence: "FREQ=DAILY"})
+				return err
+			},
+			field: "recurrence",
+			code:  validation.CodeInvalidValue,
+		},
+		{
+			name: "patch recurrence onto a task without a due date",
+			call: func() error {
+				rule := "FREQ=WEEKLY"
+				_, err := svc.Patch(t.Context(), 1, domain.TaskPatch{Recurrence: &rule})
+				return err
+			},
+			field: "recurrence",
+			code:  validation.CodeInvalidValue,
+		},
+		{
+			name: "patch due date before the stored start date",
+			call: func() error {
+				_, err := svc.Patch(t.Context(), 1, domain.TaskPatch{DueAt: domain.NullableTime{Set: true, Time: &dueAt}})
+				return err
+			},
+			field: "start_at",
+			code:  validation.CodeInvalidValue,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call()
+			if !errors.Is(err, domain.ErrValidationFailed) {
+				t.Fatalf("expected error %v but got %v", domain.ErrValidationFailed, err)
+			}
+			fields := validation.Fields(err)
+			if len(fields) != 1 || fields[0].Field != tc.field || fields[0].Code != tc.code {
+				t.Fatalf("unexpected fields %v", fields)
 			}
 		})
 	}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+// TrashService provides business logic for deleted tasks.
+type TrashService struct {
+	repo      domain.TrashRepository
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewTrashService creates a new TrashService. Deleted tasks are purged once
+// they have been in the trash for longer than retention; zero keeps them
+// until purged by hand.
+func NewTrashService(repo domain.TrashRepository, retention time.Duration) *TrashService {
+	return &TrashService{
+		repo:      repo,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// List returns the tasks in the trash, most recently deleted first.
+func (s *TrashService) List(ctx context.Context) ([]domain.TrashedTask, error) {
+	tasks, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("TrashService.List: %w: %w", domain.ErrTaskRetrievalFailed, err)
+	}
+	return tasks, nil
+}
+
+// Restore takes a task and the subtasks deleted with it out of the trash.
+func (s *TrashService) Restore(ctx context.Context, id int64) (domain.Task, error) {
+	task, err := s.repo.Restore(ctx, id)
+	if errors.Is(err, domain.ErrTaskNotFound) || errors.Is(err, domain.ErrParentTaskDeleted) {
+		return domain.Task{}, fmt.Errorf("TrashService.Restore: %w", err)
+	}
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("TrashService.Restore: %w: %w", domain.ErrTaskUpdateFailed, err)
+	}
+	return task, nil
+}
+
+// Purge permanently deletes a task in the trash and its subtasks.
+func (s *TrashService) Purge(ctx context.Context, id int64) error {
+	err := s.repo.Purge(ctx, id)
+	if errors.Is(err, domain.ErrTaskNotFound) {
+		return fmt.Errorf("TrashService.Purge: %w", err)
+	}
+	if err != nil {
+		return fmt.Errorf("TrashService.Purge: %w: %w", domain.ErrTaskDeletionFailed, err)
+	}
+	return nil
+}
+
+// PurgeExpired permanently deletes the tasks kept in the trash for longer
+// than the retention period and reports how many there were. It does
+// nothing when retention is disabled.
+func (s *TrashService) PurgeExpired(ctx context.Context) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	n, err := s.repo.PurgeDeletedBefore(ctx, s.now().Add(-s.retention))
+	if err != nil {
+		return 0, fmt.Errorf("TrashService.PurgeExpired: %w: %w", domain.ErrTaskDeletionFailed, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+type mockTrashRepository struct {
+	listFunc               func(ctx context.Context) ([]domain.TrashedTask, error)
+	restoreFunc            func(ctx context.Context, id int64) (domain.Task, error)
+	purgeFunc              func(ctx context.Context, id int64) error
+	purgeDeletedBeforeFunc func(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+func (m *mockTrashRepository) List(ctx context.Context) ([]domain.TrashedTask, error) {
+	return m.listFunc(ctx)
+}
+
+func (m *mockTrashRepository) Restore(ctx context.Context, id int64) (domain.Task, error) {
+	return m.restoreFunc(ctx, id)
+}
+
+func (m *mockTrashRepository) Purge(ctx context.Context, id int64) error {
+	return m.purgeFunc(ctx, id)
+}
+
+func (m *mockTrashRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return m.purgeDeletedBeforeFunc(ctx, cutoff)
+}
+
+func TestTrashService_Restore(t *testing.T) {
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "should keep not found", repoErr: domain.ErrTaskNotFound, expectedErr: domain.ErrTaskNotFound},
+		{name: "should keep a deleted parent", repoErr: domain.ErrParentTaskDeleted, expectedErr: domain.ErrParentTaskDeleted},
+		{name: "should wrap other failures", repoErr: errors.New("db down"), expectedErr: domain.ErrTaskUpdateFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTrashRepository{
+				restoreFunc: func(ctx context.Context, id int64) (domain.Task, error) {
+					return domain.Task{}, tc.repoErr
+				},
+			}
+
+			_, err := NewTrashService(repo, 0).Restore(t.Context(), 1)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestTrashService_Purge(t *testing.T) {
+	repo := &mockTrashRepository{
+		purgeFunc: func(ctx context.Context, id int64) error {
+			return errors.New("db down")
+		},
+	}
+
+	if err := NewTrashService(repo, 0).Purge(t.Context(), 1); !errors.Is(err, domain.ErrTaskDeletionFailed) {
+		t.Fatalf("expected error %v but got %v", domain.ErrTaskDeletionFailed, err)
+	}
+}
+
+func TestTrashService_PurgeExpired(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	t.Run("should purge the tasks deleted before the retention period", func(t *testing.T) {
+		repo := &mockTrashRepository{
+			purgeDeletedBeforeFunc: func(ctx context.Context, cutoff time.Time) (int64, error) {
+				if expected := now.Add(-30 * 24 * time.Hour); !cutoff.Equal(expected) {
+					t.Fatalf("expected cutoff %v but got %v", expected, cutoff)
+				}
+				return 2, nil
+			},
+		}
+
+		svc := NewTrashService(repo, 30*24*time.Hour)
+		svc.now = func() time.Time { return now }
+		n, err := svc.PurgeExpired(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if n != 2 {
+			t.Fatalf("expected 2 purged tasks but got %d", n)
+		}
+	})
+
+	t.Run("should keep everything without a retention period", func(t *testing.T) {
+		repo := &mockTrashRepository{
+			purgeDeletedBeforeFunc: func(ctx context.Context, cutoff time.Time) (int64, error) {
+				t.Fatal("expected no purge")
+				return 0, nil
+			},
+		}
+
+		if _, err := NewTrashService(repo, 0).PurgeExpired(t.Context()); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	})
+
+	t.Run("should wrap failures", func(t *testing.T) {
+		repo := &mockTrashRepository{
+			purgeDeletedBeforeFunc: func(ctx context.Context, cutoff time.Time) (int64, error) {
+				return 0, errors.New("db down")
+			},
+		}
+
+		if _, err := NewTrashService(repo, time.Hour).PurgeExpired(t.Context()); !errors.Is(err, domain.ErrTaskDeletionFailed) {
+			t.Fatalf("expected error %v but got %v", domain.ErrTaskDeletionFailed, err)
+		}
+	})
+}
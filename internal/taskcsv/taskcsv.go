@@ -0,0 +1,350 @@
+// Package taskcsv reads and writes tasks as RFC 4180 CSV with a header row.
+//
+// Exports carry every column in Columns. Imports read the columns in Fields:
+// a row with the id of an existing task updates that task, and only the
+// columns present in the file, while any other row creates a task. An empty
+// cell clears optional fields and resets the others to their defaults.
+package taskcsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+// Columns are the columns of an export, in order.
+var Columns = []string{
+	"id", "title", "description", "done", "priority", "start_at", "due_at", "completed_at",
+	"project_id", "parent_id", "recurrence", "tags", "blocked", "version", "created_at", "updated_at",
+}
+
+// Fields are the columns an import reads. The other export columns are
+// maintained by storage and ignored. tags replaces the tags of the task
+// with the comma-separated names, and version, when present, makes the
+// update of a row conditional on it, like If-Match.
+var Fields = []string{
+	"id", "title", "description", "done", "priority", "start_at", "due_at",
+	"project_id", "parent_id", "recurrence", "tags", "version",
+}
+
+// dateLayout is accepted besides RFC 3339 for the dates of an import, as
+// spreadsheets tend to produce it. It is read as midnight UTC.
+const dateLayout = "2006-01-02"
+
+// Writer writes tasks as CSV.
+type Writer struct {
+	csv *csv.Writer
+}
+
+// NewWriter creates a Writer on w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{csv: csv.NewWriter(w)}
+}
+
+// WriteHeader writes the header row.
+func (w *Writer) WriteHeader() error {
+	return w.csv.Write(Columns)
+}
+
+// Write writes the row of a task.
+func (w *Writer) Write(t domain.Task) error {
+	tags := make([]string, len(t.Tags))
+	for i, tag := range t.Tags {
+		tags[i] = tag.Name
+	}
+	return w.csv.Write([]string{
+		strconv.FormatInt(t.ID, 10),
+		t.Title,
+		t.Description,
+		strconv.FormatBool(t.Done),
+		t.Priority.String(),
+		formatTime(t.StartAt),
+		formatTime(t.DueAt),
+		formatTime(t.CompletedAt),
+		formatID(t.ProjectID),
+		formatID(t.ParentID),
+		t.Recurrence,
+		strings.Join(tags, ","),
+		strconv.FormatBool(t.Blocked),
+		strconv.FormatInt(t.Version, 10),
+		formatTime(&t.CreatedAt),
+		formatTime(&t.UpdatedAt),
+	})
+}
+
+// Flush writes any buffered rows and reports the first write error.
+func (w *Writer) Flush() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// Mapping names the column each field is read from, by its header. Fields
+// it leaves out are read from the column named after them, ignoring case,
+// when there is one.
+type Mapping map[string]string
+
+// Row is an import row and the operation that applies it.
+type Row struct {
+	// Line is the line of the row in the file; the header is line 1.
+	Line int
+	Op   domain.TaskOperation
+}
+
+// Read parses an import. Every problem is reported at once in a
+// domain.ErrInvalidRequest validation error, with malformed cells named
+// rows[<line>].<field>.
+func Read(r io.Reader, mapping Mapping) ([]Row, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		v := validation.New()
+		v.Add("body", validation.CodeRequired, "the CSV must start with a header row")
+		return nil, v.RequestErr()
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	columns, err := resolveColumns(header, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	v := validation.New()
+	var rows []Row
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			v.Add("body", validation.CodeInvalidValue, fmt.Sprintf("line %d: %v", parseErr.Line, parseErr.Err))
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == domain.MaxImportRows {
+			v.Add("body", validation.CodeTooLong, fmt.Sprintf("the CSV must not have more than %d rows", domain.MaxImportRows))
+			break
+		}
+		line, _ := cr.FieldPos(0)
+		rows = append(rows, Row{Line: line, Op: parseRow(v, fmt.Sprintf("rows[%d].", line), columns, record)})
+	}
+
+	if err := v.RequestErr(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// resolveColumns returns the index of the column each present field is read
+// from.
+func resolveColumns(header []string, mapping Mapping) (map[string]int, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, ok := index[key]; !ok {
+			index[key] = i
+		}
+	}
+
+	v := validation.New()
+	columns := make(map[string]int)
+	for _, field := range slices.Sorted(maps.Keys(mapping)) {
+		name := mapping[field]
+		if !slices.Contains(Fields, field) {
+			v.Add("map."+field, validation.CodeUnknownField, field+" is not a field that can be imported")
+			continue
+		}
+		i, ok := index[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			v.Add("map."+field, validation.CodeInvalidValue, fmt.Sprintf("the CSV has no column named %q", name))
+			continue
+		}
+		columns[field] = i
+	}
+	for _, field := range Fields {
+		if _, mapped := mapping[field]; mapped {
+			continue
+		}
+		if i, ok := index[field]; ok {
+			columns[field] = i
+		}
+	}
+	if v.Valid() && len(columns) == 0 {
+		v.Add("body", validation.CodeRequired, "the CSV has none of the columns "+strings.Join(Fields, ", "))
+	}
+
+	if err := v.RequestErr(); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// parseRow maps a record to a create, or to a patch of the columns present
+// when it has an id, which creates the task when the id is unknown.
+// Malformed cells are recorded in v under prefix.
+func parseRow(v *validation.Validator, prefix string, columns map[string]int, record []string) domain.TaskOperation {
+	cell := func(field string) (string, bool) {
+		i, ok := columns[field]
+		if !ok {
+			return "", false
+		}
+		return strings.TrimSpace(record[i]), true
+	}
+	p := cellParser{v: v, prefix: prefix}
+
+	var op domain.TaskOperation
+	if raw, ok := cell("id"); ok && raw != "" {
+		op.Kind = domain.TaskOperationPatch
+		if id := p.id("id", raw); id != nil {
+			op.ID = *id
+		}
+	} else {
+		op.Kind = domain.TaskOperationCreate
+	}
+	if raw, ok := cell("version"); ok && raw != "" {
+		if version := p.id("version", raw); version != nil {
+			op.Version = *version
+		}
+	}
+
+	var task domain.Task
+	patch := &op.Patch
+	if raw, ok := cell("title"); ok {
+		task.Title = raw
+		patch.Title = &task.Title
+	}
+	if raw, ok := cell("description"); ok {
+		task.Description = raw
+		patch.Description = &task.Description
+	}
+	if raw, ok := cell("done"); ok {
+		task.Done = p.bool("done", raw)
+		patch.Done = &task.Done
+	}
+	if raw, ok := cell("priority"); ok {
+		task.Priority = p.priority("priority", raw)
+		patch.Priority = &task.Priority
+	}
+	if raw, ok := cell("start_at"); ok {
+		task.StartAt = p.time("start_at", raw)
+		patch.StartAt = domain.NullableTime{Set: true, Time: task.StartAt}
+	}
+	if raw, ok := cell("due_at"); ok {
+		task.DueAt = p.time("due_at", raw)
+		patch.DueAt = domain.NullableTime{Set: true, Time: task.DueAt}
+	}
+	if raw, ok := cell("project_id"); ok {
+		task.ProjectID = p.id("project_id", raw)
+		patch.ProjectID = domain.NullableID{Set: true, ID: task.ProjectID}
+	}
+	if raw, ok := cell("parent_id"); ok {
+		task.ParentID = p.id("parent_id", raw)
+		patch.ParentID = domain.NullableID{Set: true, ID: task.ParentID}
+	}
+	if raw, ok := cell("recurrence"); ok {
+		task.Recurrence = raw
+		patch.Recurrence = &task.Recurrence
+	}
+	if raw, ok := cell("tags"); ok {
+		op.Tags = []string{}
+		for name := range strings.SplitSeq(raw, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				op.Tags = append(op.Tags, name)
+			}
+		}
+	}
+
+	op.Task = task
+	if op.Kind == domain.TaskOperationCreate {
+		op.Patch = domain.TaskPatch{}
+	} else {
+		op.CreateMissing = true
+	}
+	return op
+}
+
+// cellParser parses the cells of a row, recording malformed ones.
+type cellParser struct {
+	v      *validation.Validator
+	prefix string
+}
+
+func (p cellParser) invalid(field, message string) {
+	p.v.Add(p.prefix+field, validation.CodeInvalidValue, message)
+}
+
+func (p cellParser) bool(field, raw string) bool {
+	switch strings.ToLower(raw) {
+	case "", "false", "0", "no":
+		return false
+	case "true", "1", "yes":
+		return true
+	}
+	p.invalid(field, field+" must be true or false")
+	return false
+}
+
+func (p cellParser) priority(field, raw string) domain.Priority {
+	if raw == "" {
+		return domain.PriorityNone
+	}
+	priority, ok := domain.ParsePriority(strings.ToLower(raw))
+	if !ok {
+		p.invalid(field, field+" must be one of none, low, medium, high or urgent")
+	}
+	return priority
+}
+
+func (p cellParser) time(field, raw string) *time.Time {
+	if raw == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		t, err = time.Parse(dateLayout, raw)
+	}
+	if err != nil {
+		p.invalid(field, field+" must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+		return nil
+	}
+	return &t
+}
+
+func (p cellParser) id(field, raw string) *int64 {
+	if raw == "" {
+		return nil
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		p.invalid(field, field+" must be a positive integer")
+		return nil
+	}
+	return &id
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatID(id *int64) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatInt(*id, 10)
+}
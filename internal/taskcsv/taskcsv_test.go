@@ -0,0 +1,168 @@
+package taskcsv
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+func TestWriter(t *testing.T) {
+	created := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 10, 20, 17, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	projectID := int64(3)
+
+	var b strings.Builder
+	w := NewWriter(&b)
+	if err := w.WriteHeader(); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	tasks := []domain.Task{
+		{
+			ID: 1, Title: "Plan, then \"ship\"", Description: "two\nlines", Priority: domain.PriorityHigh,
+			DueAt: &due, ProjectID: &projectID, Recurrence: "FREQ=WEEKLY",
+			Tags:      []domain.Tag{{Name: "backend"}, {Name: "urgent"}},
+			Version:   2,
+			CreatedAt: created, UpdatedAt: created,
+		},
+		{ID: 2, Title: "Done", Done: true, CompletedAt: &created, Blocked: true, Version: 1, CreatedAt: created, UpdatedAt: created},
+	}
+	for _, task := range tasks {
+		if err := w.Write(task); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	expected := "id,title,description,done,priority,start_at,due_at,completed_at,project_id,parent_id,recurrence,tags,blocked,version,created_at,updated_at\n" +
+		"1,\"Plan, then \"\"ship\"\"\",\"two\nlines\",false,high,,2026-10-20T15:30:00Z,,3,,FREQ=WEEKLY,\"backend,urgent\",false,2,2026-10-18T09:00:00Z,2026-10-18T09:00:00Z\n" +
+		"2,Done,,true,none,,,2026-10-18T09:00:00Z,,,,,true,1,2026-10-18T09:00:00Z,2026-10-18T09:00:00Z\n"
+	if b.String() != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b.String())
+	}
+}
+
+func TestRead(t *testing.T) {
+	due := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	title, description := "Renamed", ""
+	done := true
+	high := domain.PriorityHigh
+
+	input := "\ufeffTask Name,Notes,Due,done,priority,id,version,tags\n" +
+		"Write report,,2026-10-20,no,,,,\"home, errands\"\n" +
+		"Renamed,,,yes,HIGH,7,3,\n"
+
+	rows, err := Read(strings.NewReader(input), Mapping{"title": "task name", "description": "Notes", "due_at": "Due"})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	expected := []Row{
+		{Line: 2, Op: domain.TaskOperation{
+			Kind: domain.TaskOperationCreate,
+			Task: domain.Task{Title: "Write report", DueAt: &due},
+			Tags: []string{"home", "errands"},
+		}},
+		{Line: 3, Op: domain.TaskOperation{
+			Kind: domain.TaskOperationPatch, ID: 7, Version: 3, CreateMissing: true,
+			Task: domain.Task{Title: title, Done: done, Priority: high},
+			Tags: []string{},
+			Patch: domain.TaskPatch{
+				Title:       &title,
+				Description: &description,
+				Done:        &done,
+				Priority:    &high,
+				DueAt:       domain.NullableTime{Set: true},
+			},
+		}},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("expected rows %+v but got %+v", expected, rows)
+	}
+}
+
+func TestRead_RoundTrip(t *testing.T) {
+	created := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	parentID := int64(1)
+	task := domain.Task{
+		ID: 2, Title: "Child", Priority: domain.PriorityLow, StartAt: &created, ParentID: &parentID,
+		Tags: []domain.Tag{{Name: "backend"}, {Name: "urgent"}}, Version: 4, CreatedAt: created, UpdatedAt: created,
+	}
+
+	var b strings.Builder
+	w := NewWriter(&b)
+	_ = w.WriteHeader()
+	_ = w.Write(task)
+	if err := w.Flush(); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	rows, err := Read(strings.NewReader(b.String()), nil)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 row but got %d", len(rows))
+	}
+	op := rows[0].Op
+	if op.Kind != domain.TaskOperationPatch || op.ID != 2 || op.Version != 4 {
+		t.Fatalf("expected a patch of task 2 at version 4, got %+v", op)
+	}
+	p := op.Patch
+	if *p.Title != "Child" || *p.Priority != domain.PriorityLow || !p.StartAt.Time.Equal(created) ||
+		*p.ParentID.ID != 1 || !p.ProjectID.Set || p.ProjectID.ID != nil || p.DueAt.Time != nil {
+		t.Fatalf("unexpected patch %+v", p)
+	}
+	if !reflect.DeepEqual(op.Tags, []string{"backend", "urgent"}) {
+		t.Fatalf("expected tags [backend urgent], got %v", op.Tags)
+	}
+	if !op.CreateMissing || op.Task.Title != "Child" || *op.Task.ParentID != 1 {
+		t.Fatalf("expected the task to create when missing, got %+v", op.Task)
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		mapping Mapping
+		fields  []string
+	}{
+		{name: "should require a header", input: "", fields: []string{"body"}},
+		{name: "should require a known column", input: "name,notes\nx,y\n", fields: []string{"body"}},
+		{
+			name:    "should reject mappings to unknown fields and missing columns",
+			input:   "title\nx\n",
+			mapping: Mapping{"owner": "title", "due_at": "Deadline"},
+			fields:  []string{"map.due_at", "map.owner"},
+		},
+		{
+			name:   "should report every malformed cell",
+			input:  "id,title,done,priority,due_at,project_id\n0,a,maybe,extreme,next week,-1\n,b,true,low,,\n",
+			fields: []string{"rows[2].id", "rows[2].done", "rows[2].priority", "rows[2].due_at", "rows[2].project_id"},
+		},
+		{name: "should reject a row with the wrong number of cells", input: "title,done\na,true\nb\n", fields: []string{"body"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tc.input), tc.mapping)
+			if !errors.Is(err, domain.ErrInvalidRequest) {
+				t.Fatalf("expected error %v but got %v", domain.ErrInvalidRequest, err)
+			}
+			var fields []string
+			for _, f := range validation.Fields(err) {
+				fields = append(fields, f.Field)
+			}
+			if !reflect.DeepEqual(fields, tc.fields) {
+				t.Fatalf("expected fields %v but got %v", tc.fields, fields)
+			}
+		})
+	}
+}
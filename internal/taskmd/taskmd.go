@@ -0,0 +1,238 @@
+// Package taskmd reads and writes tasks as GitHub-flavored Markdown task
+// lists, where "- [ ] title" is an open task and "- [x] title" a finished
+// one.
+//
+// Nesting carries the parent of a task: an item indented under another is
+// its subtask. Only titles and completion have a Markdown form; titles are
+// written verbatim, so Markdown inside them renders wherever the list is
+// pasted.
+package taskmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+// ContentType is the media type of a task list.
+const ContentType = "text/markdown; charset=utf-8"
+
+// indent nests an item under the one before it.
+const indent = "  "
+
+// maxLineBytes is the longest line Read accepts.
+const maxLineBytes = 64 << 10
+
+// Write writes tasks as a task list. A task whose parent is also in tasks
+// is nested under it and the others are top-level items; siblings keep the
+// order of tasks.
+func Write(w io.Writer, tasks []domain.Task) error {
+	listed := make(map[int64]bool, len(tasks))
+	for _, t := range tasks {
+		listed[t.ID] = true
+	}
+	children := make(map[int64][]domain.Task)
+	var roots []domain.Task
+	for _, t := range tasks {
+		if t.ParentID != nil && *t.ParentID != t.ID && listed[*t.ParentID] {
+			children[*t.ParentID] = append(children[*t.ParentID], t)
+		} else {
+			roots = append(roots, t)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	written := make(map[int64]bool, len(tasks))
+	var write func(t domain.Task, depth int)
+	write = func(t domain.Task, depth int) {
+		if written[t.ID] {
+			return
+		}
+		written[t.ID] = true
+		// Errors stick to bw and surface from Flush.
+		_, _ = bw.WriteString(strings.Repeat(indent, depth) + Item(t) + "\n")
+		for _, c := range children[t.ID] {
+			write(c, depth+1)
+		}
+	}
+	for _, t := range roots {
+		write(t, 0)
+	}
+	// Tasks whose parents form a cycle are reached from no root.
+	for _, t := range tasks {
+		write(t, 0)
+	}
+	return bw.Flush()
+}
+
+// Item formats a task as a list item, without indentation or a line break.
+func Item(t domain.Task) string {
+	box := "[ ]"
+	if t.Done {
+		box = "[x]"
+	}
+	return "- " + box + " " + strings.Join(strings.Fields(t.Title), " ")
+}
+
+// Row is an import line and the operation that applies it.
+type Row struct {
+	// Line is the line number in the file, starting at 1.
+	Line int
+	Op   domain.TaskOperation
+}
+
+// listItem is a list item enclosing the lines that follow it.
+type listItem struct {
+	// column is where the item's marker starts.
+	column int
+	// row is the index of the item's row, or -1 for an item without a
+	// checkbox.
+	row int
+}
+
+// Read parses a Markdown document, creating a task for each task list item
+// in it. An item nested under another task item, even through plain list
+// items, becomes its subtask through domain.TaskOperation.ParentOp. Other
+// content, fenced code blocks included, is skipped. Problems with the file
+// itself are reported in a domain.ErrInvalidRequest validation error.
+func Read(r io.Reader) ([]Row, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 4096), maxLineBytes)
+
+	v := validation.New()
+	var rows []Row
+	var open []listItem
+	fence := ""
+	line := 0
+	for sc.Scan() {
+		line++
+		text := sc.Text()
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		trimmed := strings.TrimLeft(text, " \t")
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		if trimmed == "" {
+			continue
+		}
+
+		column := indentWidth(text)
+		task, isItem := parseItem(trimmed)
+		if !isItem {
+			// Unindented text such as a heading or paragraph ends the list.
+			if column == 0 {
+				open = open[:0]
+			}
+			continue
+		}
+		for len(open) > 0 && open[len(open)-1].column >= column {
+			open = open[:len(open)-1]
+		}
+
+		row := -1
+		if task != nil {
+			if len(rows) == domain.MaxImportRows {
+				v.Add("body", validation.CodeTooLong, fmt.Sprintf("the file must not have more than %d tasks", domain.MaxImportRows))
+				break
+			}
+			op := domain.TaskOperation{Kind: domain.TaskOperationCreate, Task: *task}
+			for i := len(open) - 1; i >= 0; i-- {
+				if parent := open[i].row; parent >= 0 {
+					op.ParentOp = &parent
+					break
+				}
+			}
+			rows = append(rows, Row{Line: line, Op: op})
+			row = len(rows) - 1
+		}
+		open = append(open, listItem{column: column, row: row})
+	}
+	if err := sc.Err(); errors.Is(err, bufio.ErrTooLong) {
+		v.Add("body", validation.CodeTooLong, fmt.Sprintf("line %d is longer than %d bytes", line+1, maxLineBytes))
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := v.RequestErr(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// parseItem reads a line without its indentation as a list item. The task
+// is nil for an item without a checkbox.
+func parseItem(s string) (*domain.Task, bool) {
+	rest, ok := cutMarker(s)
+	if !ok {
+		return nil, false
+	}
+	body := strings.TrimLeft(rest, " \t")
+	if len(body) < 3 || body[0] != '[' || body[2] != ']' || len(body) > 3 && body[3] != ' ' && body[3] != '\t' {
+		return nil, true
+	}
+	switch body[1] {
+	case ' ':
+		return &domain.Task{Title: strings.TrimSpace(body[3:])}, true
+	case 'x', 'X':
+		return &domain.Task{Title: strings.TrimSpace(body[3:]), Done: true}, true
+	}
+	return nil, true
+}
+
+// cutMarker removes a bullet (-, * or +) or ordered list marker (1. or 1))
+// from the start of s. The marker must be followed by whitespace or end the
+// line.
+func cutMarker(s string) (string, bool) {
+	n := 0
+	switch {
+	case s == "":
+		return "", false
+	case s[0] == '-' || s[0] == '*' || s[0] == '+':
+		n = 1
+	default:
+		for n < len(s) && n < 9 && s[n] >= '0' && s[n] <= '9' {
+			n++
+		}
+		if n == 0 || n == len(s) || s[n] != '.' && s[n] != ')' {
+			return "", false
+		}
+		n++
+	}
+	rest := s[n:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return "", false
+	}
+	return rest, true
+}
+
+// indentWidth returns the column where the text of a line starts, with tabs
+// advancing to the next multiple of four.
+func indentWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return width
+		}
+	}
+	return width
+}
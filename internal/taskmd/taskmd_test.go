@@ -0,0 +1,128 @@
+package taskmd
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestWrite(t *testing.T) {
+	tasks := []domain.Task{
+		{ID: 1, Title: "Release"},
+		{ID: 4, Title: "Outside  the\nlist", ParentID: ptr(int64(99))},
+		{ID: 2, Title: "Write notes", ParentID: ptr(int64(1)), Done: true},
+		{ID: 3, Title: "Proofread", ParentID: ptr(int64(2))},
+		{ID: 5, Title: "Tag", ParentID: ptr(int64(1))},
+		{ID: 6, Title: "Loop A", ParentID: ptr(int64(7))},
+		{ID: 7, Title: "Loop B", ParentID: ptr(int64(6))},
+	}
+
+	var b strings.Builder
+	if err := Write(&b, tasks); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	expected := "- [ ] Release\n" +
+		"  - [x] Write notes\n" +
+		"    - [ ] Proofread\n" +
+		"  - [ ] Tag\n" +
+		"- [ ] Outside the list\n" +
+		"- [ ] Loop A\n" +
+		"  - [ ] Loop B\n"
+	if b.String() != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b.String())
+	}
+}
+
+func TestRead(t *testing.T) {
+	input := "\ufeff# Launch\n" +
+		"\n" +
+		"Some notes.\n" +
+		"- [ ] Release\n" +
+		"  - [X] Write notes\n" +
+		"\t- plain item\n" +
+		"\t\t1. [ ] Proofread  \n" +
+		"    continuation text\n" +
+		"  - [ ] Tag\n" +
+		"```\n" +
+		"- [ ] in a code block\n" +
+		"```\n" +
+		"## Later\n" +
+		"  * [ ] Unrelated\n" +
+		"- [y] not a task\n" +
+		"- [ ]no space\n" +
+		"---\n" +
+		"+ [x]\n"
+
+	rows, err := Read(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	create := func(title string, done bool, parent *int) domain.TaskOperation {
+		return domain.TaskOperation{Kind: domain.TaskOperationCreate, Task: domain.Task{Title: title, Done: done}, ParentOp: parent}
+	}
+	expected := []Row{
+		{Line: 4, Op: create("Release", false, nil)},
+		{Line: 5, Op: create("Write notes", true, ptr(0))},
+		{Line: 7, Op: create("Proofread", false, ptr(1))},
+		{Line: 9, Op: create("Tag", false, ptr(0))},
+		{Line: 14, Op: create("Unrelated", false, nil)},
+		{Line: 18, Op: create("", true, nil)},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("expected rows %+v but got %+v", expected, rows)
+	}
+}
+
+func TestRead_RoundTrip(t *testing.T) {
+	tasks := []domain.Task{
+		{ID: 1, Title: "Release"},
+		{ID: 2, Title: "Write *notes*", ParentID: ptr(int64(1)), Done: true},
+		{ID: 3, Title: "Proofread", ParentID: ptr(int64(2))},
+		{ID: 4, Title: "Celebrate"},
+	}
+
+	var b strings.Builder
+	if err := Write(&b, tasks); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	rows, err := Read(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if len(rows) != len(tasks) {
+		t.Fatalf("expected %d rows but got %d", len(tasks), len(rows))
+	}
+
+	// Rows are created in order, so row i becomes task i+1.
+	for i, task := range tasks {
+		op := rows[i].Op
+		got := domain.Task{ID: int64(i + 1), Title: op.Task.Title, Done: op.Task.Done}
+		if op.ParentOp != nil {
+			got.ParentID = ptr(int64(*op.ParentOp + 1))
+		}
+		if !reflect.DeepEqual(got, task) {
+			t.Errorf("expected task %+v to survive a round trip, got %+v", task, got)
+		}
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	_, err := Read(strings.NewReader("- [ ] " + strings.Repeat("a", maxLineBytes)))
+	if !errors.Is(err, domain.ErrInvalidRequest) {
+		t.Fatalf("expected error %v but got %v", domain.ErrInvalidRequest, err)
+	}
+	fields := validation.Fields(err)
+	if len(fields) != 1 || fields[0].Field != "body" {
+		t.Fatalf("expected a body error, got %+v", fields)
+	}
+}
@@ -0,0 +1,319 @@
+package todotxt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+// Extension keys holding the task fields todo.txt has no syntax for. due and
+// t (threshold, the start date) are common todo.txt extensions; finished
+// tasks keep their priority in pri, as todo.txt drops the (A) marker.
+const (
+	KeyID         = "id"
+	KeyDue        = "due"
+	KeyStart      = "t"
+	KeyRecurrence = "rrule"
+	KeyParent     = "parent"
+	KeyPriority   = "pri"
+)
+
+// maxLineBytes is the longest line Read accepts.
+const maxLineBytes = 64 << 10
+
+// priorities maps task priorities to todo.txt letters. Letters after D read
+// as low.
+var priorities = map[domain.Priority]byte{
+	domain.PriorityUrgent: 'A',
+	domain.PriorityHigh:   'B',
+	domain.PriorityMedium: 'C',
+	domain.PriorityLow:    'D',
+}
+
+// Catalog holds the projects and tags that +project and @context tokens
+// name.
+type Catalog struct {
+	Projects []domain.Project
+	Tags     []domain.Tag
+}
+
+// Encode maps a task to an item. Projects and tags are written as tokens of
+// their names; the description has no todo.txt counterpart and is left out.
+func Encode(t domain.Task, catalog Catalog) Item {
+	it := Item{
+		Done:        t.Done,
+		CompletedOn: date(t.CompletedAt),
+		CreatedOn:   date(&t.CreatedAt),
+		Text:        t.Title,
+	}
+	if letter, ok := priorities[t.Priority]; ok && !t.Done {
+		it.Priority = letter
+	}
+	if t.ProjectID != nil {
+		for _, p := range catalog.Projects {
+			if p.ID == *t.ProjectID {
+				it.Projects = []string{Token(p.Name)}
+			}
+		}
+	}
+	for _, tag := range t.Tags {
+		it.Contexts = append(it.Contexts, Token(tag.Name))
+	}
+
+	ext := func(key, value string) {
+		it.Extensions = append(it.Extensions, Extension{Key: key, Value: value})
+	}
+	if t.ID != 0 {
+		ext(KeyID, strconv.FormatInt(t.ID, 10))
+	}
+	if t.DueAt != nil {
+		ext(KeyDue, formatTime(*t.DueAt))
+	}
+	if t.StartAt != nil {
+		ext(KeyStart, formatTime(*t.StartAt))
+	}
+	if t.Recurrence != "" {
+		ext(KeyRecurrence, t.Recurrence)
+	}
+	if t.ParentID != nil {
+		ext(KeyParent, strconv.FormatInt(*t.ParentID, 10))
+	}
+	if letter, ok := priorities[t.Priority]; ok && t.Done {
+		ext(KeyPriority, string(letter))
+	}
+	return it
+}
+
+// Writer writes tasks as todo.txt lines.
+type Writer struct {
+	w       *bufio.Writer
+	catalog Catalog
+}
+
+// NewWriter creates a Writer on w naming projects and tags from catalog.
+func NewWriter(w io.Writer, catalog Catalog) *Writer {
+	return &Writer{w: bufio.NewWriter(w), catalog: catalog}
+}
+
+// Write writes the line of a task.
+func (w *Writer) Write(t domain.Task) error {
+	_, err := w.w.WriteString(Encode(t, w.catalog).String() + "\n")
+	return err
+}
+
+// Flush writes any buffered lines.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Row is an import line and the operation that applies it.
+type Row struct {
+	// Line is the line number in the file, starting at 1.
+	Line int
+	Op   domain.TaskOperation
+}
+
+// Read parses an import, skipping blank lines. A line with an id:N
+// extension replaces every field of that task todo.txt can express, its
+// tags included; any other line creates a task. +project must name a
+// project in catalog, while @context names a tag that is created when
+// missing. Every problem is reported at once in a domain.ErrInvalidRequest
+// validation error, with malformed tokens named lines[<line>].<field>.
+func Read(r io.Reader, catalog Catalog) ([]Row, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 4096), maxLineBytes)
+
+	v := validation.New()
+	var rows []Row
+	line := 0
+	for sc.Scan() {
+		line++
+		text := sc.Text()
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if len(rows) == domain.MaxImportRows {
+			v.Add("body", validation.CodeTooLong, fmt.Sprintf("the file must not have more than %d tasks", domain.MaxImportRows))
+			break
+		}
+		rows = append(rows, Row{Line: line, Op: decode(v, fmt.Sprintf("lines[%d].", line), Parse(text), catalog)})
+	}
+	if err := sc.Err(); errors.Is(err, bufio.ErrTooLong) {
+		v.Add("body", validation.CodeTooLong, fmt.Sprintf("line %d is longer than %d bytes", line+1, maxLineBytes))
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := v.RequestErr(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// decode maps an item to a create, or to a patch of the task named by its
+// id extension. Malformed tokens are recorded in v under prefix.
+func decode(v *validation.Validator, prefix string, it Item, catalog Catalog) domain.TaskOperation {
+	invalid := func(field, message string) {
+		v.Add(prefix+field, validation.CodeInvalidValue, message)
+	}
+	id := func(key string) *int64 {
+		raw, ok := it.Extension(key)
+		if !ok {
+			return nil
+		}
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n <= 0 {
+			invalid(key, key+" must be a positive integer")
+			return nil
+		}
+		return &n
+	}
+	timestamp := func(key string) *time.Time {
+		raw, ok := it.Extension(key)
+		if !ok {
+			return nil
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			t, err = time.Parse(dateLayout, raw)
+		}
+		if err != nil {
+			invalid(key, key+" must be a YYYY-MM-DD date or an RFC 3339 timestamp")
+			return nil
+		}
+		return &t
+	}
+
+	task := domain.Task{
+		Title:    it.Text,
+		Done:     it.Done,
+		Priority: priority(it.Priority),
+		StartAt:  timestamp(KeyStart),
+		DueAt:    timestamp(KeyDue),
+		ParentID: id(KeyParent),
+	}
+	if raw, ok := it.Extension(KeyPriority); ok && it.Priority == 0 {
+		if len(raw) != 1 || raw[0] < 'A' || raw[0] > 'Z' {
+			invalid(KeyPriority, KeyPriority+" must be a letter from A to Z")
+		}
+		task.Priority = priority(raw[0])
+	}
+	if raw, ok := it.Extension(KeyRecurrence); ok {
+		task.Recurrence = raw
+	}
+
+	switch len(it.Projects) {
+	case 0:
+	case 1:
+		task.ProjectID = findProject(catalog.Projects, it.Projects[0])
+		if task.ProjectID == nil {
+			invalid("project", fmt.Sprintf("there is no project named %q", it.Projects[0]))
+		}
+	default:
+		invalid("project", "a task can belong to only one project")
+	}
+
+	tags := make([]string, 0, len(it.Contexts))
+	for _, c := range it.Contexts {
+		name := findTag(catalog.Tags, c)
+		if !containsFold(tags, name) {
+			tags = append(tags, name)
+		}
+	}
+
+	taskID := id(KeyID)
+	if taskID == nil {
+		op := domain.TaskOperation{Kind: domain.TaskOperationCreate, Task: task}
+		if len(tags) > 0 {
+			op.Tags = tags
+		}
+		return op
+	}
+	return domain.TaskOperation{
+		Kind: domain.TaskOperationPatch,
+		ID:   *taskID,
+		Patch: domain.TaskPatch{
+			Title:      &task.Title,
+			Done:       &task.Done,
+			Priority:   &task.Priority,
+			StartAt:    domain.NullableTime{Set: true, Time: task.StartAt},
+			DueAt:      domain.NullableTime{Set: true, Time: task.DueAt},
+			ProjectID:  domain.NullableID{Set: true, ID: task.ProjectID},
+			ParentID:   domain.NullableID{Set: true, ID: task.ParentID},
+			Recurrence: &task.Recurrence,
+		},
+		Tags: tags,
+	}
+}
+
+// findProject returns the ID of the project whose name token matches
+// token, ignoring case.
+func findProject(projects []domain.Project, token string) *int64 {
+	for _, p := range projects {
+		if strings.EqualFold(Token(p.Name), token) {
+			return &p.ID
+		}
+	}
+	return nil
+}
+
+// findTag returns the name of the tag whose name token matches token,
+// ignoring case, or token itself for a tag that does not exist yet.
+func findTag(tags []domain.Tag, token string) string {
+	for _, t := range tags {
+		if strings.EqualFold(Token(t.Name), token) {
+			return t.Name
+		}
+	}
+	return token
+}
+
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func priority(letter byte) domain.Priority {
+	for p, l := range priorities {
+		if l == letter {
+			return p
+		}
+	}
+	if letter > 'D' {
+		return domain.PriorityLow
+	}
+	return domain.PriorityNone
+}
+
+// date returns the UTC date of t.
+func date(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	d := t.UTC().Truncate(24 * time.Hour)
+	return &d
+}
+
+// formatTime writes a time as a date when it is midnight UTC, and as an RFC
+// 3339 timestamp otherwise so no precision is lost.
+func formatTime(t time.Time) string {
+	t = t.UTC()
+	if t.Equal(t.Truncate(24 * time.Hour)) {
+		return t.Format(dateLayout)
+	}
+	return t.Format(time.RFC3339)
+}
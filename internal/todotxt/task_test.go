@@ -0,0 +1,172 @@
+package todotxt
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+var catalog = Catalog{
+	Projects: []domain.Project{{ID: 1, Name: "Inbox"}, {ID: 3, Name: "Home Improvement"}},
+	Tags:     []domain.Tag{{ID: 1, Name: "phone"}, {ID: 2, Name: "deep work"}},
+}
+
+func TestWriter(t *testing.T) {
+	created := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	completed := time.Date(2026, 10, 18, 23, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	due := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2026, 10, 19, 8, 30, 0, 0, time.UTC)
+	projectID, parentID := int64(3), int64(1)
+
+	var b strings.Builder
+	w := NewWriter(&b, catalog)
+	tasks := []domain.Task{
+		{
+			ID: 2, Title: "Fix the shelf", Description: "not exported", Priority: domain.PriorityHigh,
+			StartAt: &start, DueAt: &due, ProjectID: &projectID, ParentID: &parentID, Recurrence: "FREQ=WEEKLY;BYDAY=MO",
+			Tags:      []domain.Tag{{Name: "deep work"}, {Name: "phone"}},
+			CreatedAt: created,
+		},
+		{ID: 3, Title: "Done", Done: true, Priority: domain.PriorityUrgent, CompletedAt: &completed, CreatedAt: created},
+	}
+	for _, task := range tasks {
+		if err := w.Write(task); err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	expected := "(B) 2026-10-01 Fix the shelf +Home_Improvement @deep_work @phone id:2 due:2026-10-20 t:2026-10-19T08:30:00Z rrule:FREQ=WEEKLY;BYDAY=MO parent:1\n" +
+		"x 2026-10-18 2026-10-01 Done id:3 pri:A\n"
+	if b.String() != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b.String())
+	}
+}
+
+func TestRead(t *testing.T) {
+	due := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	projectID := int64(3)
+	title, done, priority, recurrence := "Fix it", true, domain.PriorityMedium, ""
+
+	input := "\ufeff(E) New task @Phone @new @NEW +home_improvement due:2026-10-20\n" +
+		"\n" +
+		"x 2026-10-18 Fix it id:7 pri:C\n"
+
+	rows, err := Read(strings.NewReader(input), catalog)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	expected := []Row{
+		{Line: 1, Op: domain.TaskOperation{
+			Kind: domain.TaskOperationCreate,
+			Task: domain.Task{Title: "New task", Priority: domain.PriorityLow, DueAt: &due, ProjectID: &projectID},
+			Tags: []string{"phone", "new"},
+		}},
+		{Line: 3, Op: domain.TaskOperation{
+			Kind: domain.TaskOperationPatch, ID: 7,
+			Patch: domain.TaskPatch{
+				Title: &title, Done: &done, Priority: &priority,
+				StartAt: domain.NullableTime{Set: true}, DueAt: domain.NullableTime{Set: true},
+				ProjectID: domain.NullableID{Set: true}, ParentID: domain.NullableID{Set: true},
+				Recurrence: &recurrence,
+			},
+			Tags: []string{},
+		}},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("expected rows %+v but got %+v", expected, rows)
+	}
+}
+
+func TestRead_RoundTrip(t *testing.T) {
+	created := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	completed := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 10, 20, 17, 45, 0, 0, time.UTC)
+	start := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	projectID, parentID := int64(3), int64(1)
+
+	tasks := []domain.Task{
+		{
+			ID: 2, Title: "Fix the shelf", Priority: domain.PriorityUrgent, StartAt: &start, DueAt: &due,
+			ProjectID: &projectID, ParentID: &parentID, Recurrence: "FREQ=DAILY;INTERVAL=2",
+			Tags: []domain.Tag{{Name: "deep work"}, {Name: "phone"}}, CreatedAt: created,
+		},
+		{ID: 3, Title: "Finished", Done: true, Priority: domain.PriorityLow, CompletedAt: &completed, CreatedAt: created},
+		{ID: 4, Title: "Nothing special", CreatedAt: created},
+	}
+
+	var b strings.Builder
+	w := NewWriter(&b, catalog)
+	for _, task := range tasks {
+		_ = w.Write(task)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	rows, err := Read(strings.NewReader(b.String()), catalog)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if len(rows) != len(tasks) {
+		t.Fatalf("expected %d rows but got %d", len(tasks), len(rows))
+	}
+	for i, task := range tasks {
+		op := rows[i].Op
+		if op.Kind != domain.TaskOperationPatch || op.ID != task.ID {
+			t.Fatalf("expected a patch of task %d, got %+v", task.ID, op)
+		}
+		p := op.Patch
+		got := domain.Task{
+			ID: op.ID, Title: *p.Title, Done: *p.Done, Priority: *p.Priority,
+			StartAt: p.StartAt.Time, DueAt: p.DueAt.Time, ProjectID: p.ProjectID.ID, ParentID: p.ParentID.ID,
+			Recurrence: *p.Recurrence, CompletedAt: task.CompletedAt, CreatedAt: task.CreatedAt,
+		}
+		for _, name := range op.Tags {
+			got.Tags = append(got.Tags, domain.Tag{Name: name})
+		}
+		if !reflect.DeepEqual(got, task) {
+			t.Errorf("expected task %+v to survive a round trip, got %+v", task, got)
+		}
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		fields []string
+	}{
+		{
+			name:   "should report every malformed token",
+			input:  "ok\nbad id:0 due:soon t:2026-02-30 parent:x pri:7 +nowhere\n",
+			fields: []string{"lines[2].t", "lines[2].due", "lines[2].parent", "lines[2].pri", "lines[2].project", "lines[2].id"},
+		},
+		{name: "should reject several projects", input: "task +Inbox +home_improvement\n", fields: []string{"lines[1].project"}},
+		{name: "should reject overlong lines", input: strings.Repeat("a", maxLineBytes+1), fields: []string{"body"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tc.input), catalog)
+			if !errors.Is(err, domain.ErrInvalidRequest) {
+				t.Fatalf("expected error %v but got %v", domain.ErrInvalidRequest, err)
+			}
+			var fields []string
+			for _, f := range validation.Fields(err) {
+				fields = append(fields, f.Field)
+			}
+			if !reflect.DeepEqual(fields, tc.fields) {
+				t.Fatalf("expected fields %v but got %v", tc.fields, fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Package todotxt reads and writes tasks in the todo.txt format described at
+// https://github.com/todotxt/todo.txt.
+//
+// A line holds an optional completion marker "x", a priority "(A)" to "(Z)",
+// completion and creation dates, and then the description, where +project,
+// @context and key:value tokens may appear anywhere. Parse and Item.String
+// deal with the format alone; Encode and Read map it to tasks.
+package todotxt
+
+import (
+	"strings"
+	"time"
+)
+
+// dateLayout is the layout of todo.txt dates.
+const dateLayout = "2006-01-02"
+
+// Item is one line of a todo.txt file.
+type Item struct {
+	Done bool
+	// Priority is the letter A to Z, or 0 for none.
+	Priority byte
+	// CompletedOn and CreatedOn are dates, held as midnight UTC.
+	CompletedOn *time.Time
+	CreatedOn   *time.Time
+	// Text is the description without its projects, contexts and
+	// extensions, with runs of spaces collapsed.
+	Text     string
+	Projects []string
+	Contexts []string
+	// Extensions are the key:value tokens, in the order they appear.
+	Extensions []Extension
+}
+
+// Extension is a key:value token.
+type Extension struct {
+	Key   string
+	Value string
+}
+
+// Extension returns the value of the first extension with the given key.
+func (it Item) Extension(key string) (string, bool) {
+	for _, ext := range it.Extensions {
+		if ext.Key == key {
+			return ext.Value, true
+		}
+	}
+	return "", false
+}
+
+// Parse parses a line. Parsing never fails: tokens that are not what they
+// look like, such as a malformed date, are kept as description text.
+func Parse(line string) Item {
+	var it Item
+	fields := strings.Fields(line)
+
+	if len(fields) > 0 && fields[0] == "x" {
+		it.Done = true
+		fields = fields[1:]
+	}
+	if len(fields) > 0 {
+		if p, ok := parsePriority(fields[0]); ok {
+			it.Priority = p
+			fields = fields[1:]
+		}
+	}
+	if len(fields) > 0 {
+		if d, ok := parseDate(fields[0]); ok {
+			fields = fields[1:]
+			// A finished task's first date is when it was completed, and the
+			// creation date follows; an open task only has a creation date.
+			if it.Done {
+				it.CompletedOn = d
+				if len(fields) > 0 {
+					if d, ok := parseDate(fields[0]); ok {
+						it.CreatedOn = d
+						fields = fields[1:]
+					}
+				}
+			} else {
+				it.CreatedOn = d
+			}
+		}
+	}
+
+	var text []string
+	for _, f := range fields {
+		switch {
+		case len(f) > 1 && f[0] == '+':
+			it.Projects = append(it.Projects, f[1:])
+		case len(f) > 1 && f[0] == '@':
+			it.Contexts = append(it.Contexts, f[1:])
+		default:
+			if ext, ok := parseExtension(f); ok {
+				it.Extensions = append(it.Extensions, ext)
+			} else {
+				text = append(text, f)
+			}
+		}
+	}
+	it.Text = strings.Join(text, " ")
+	return it
+}
+
+// String formats the item as a line, without a line break. Projects,
+// contexts and extensions follow the description.
+func (it Item) String() string {
+	var parts []string
+	if it.Done {
+		parts = append(parts, "x")
+	}
+	if it.Priority != 0 {
+		parts = append(parts, "("+string(it.Priority)+")")
+	}
+	if it.Done && it.CompletedOn != nil {
+		parts = append(parts, it.CompletedOn.Format(dateLayout))
+	}
+	// A creation date without a completion date would be read back as
+	// the completion date.
+	if it.CreatedOn != nil && (!it.Done || it.CompletedOn != nil) {
+		parts = append(parts, it.CreatedOn.Format(dateLayout))
+	}
+	if it.Text != "" {
+		parts = append(parts, strings.Join(strings.Fields(it.Text), " "))
+	}
+	for _, p := range it.Projects {
+		parts = append(parts, "+"+p)
+	}
+	for _, c := range it.Contexts {
+		parts = append(parts, "@"+c)
+	}
+	for _, ext := range it.Extensions {
+		parts = append(parts, ext.Key+":"+ext.Value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Token turns a name into something that can follow + or @ and survive
+// Parse: the whitespace inside it becomes underscores.
+func Token(name string) string {
+	return strings.Join(strings.Fields(name), "_")
+}
+
+func parsePriority(s string) (byte, bool) {
+	if len(s) == 3 && s[0] == '(' && s[2] == ')' && s[1] >= 'A' && s[1] <= 'Z' {
+		return s[1], true
+	}
+	return 0, false
+}
+
+func parseDate(s string) (*time.Time, bool) {
+	d, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return nil, false
+	}
+	return &d, true
+}
+
+// parseExtension reads a key:value token. Keys are letters, digits, dashes
+// and underscores, which keeps URLs and times such as 10:30 in the text.
+func parseExtension(s string) (Extension, bool) {
+	key, value, ok := strings.Cut(s, ":")
+	if !ok || key == "" || value == "" || strings.HasPrefix(value, "//") {
+		return Extension{}, false
+	}
+	hasLetter := false
+	for _, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+			hasLetter = true
+		case r >= '0' && r <= '9' || r == '-' || r == '_':
+		default:
+			return Extension{}, false
+		}
+	}
+	return Extension{Key: key, Value: value}, hasLetter
+}
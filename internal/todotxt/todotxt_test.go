@@ -0,0 +1,115 @@
+package todotxt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func day(year int, month time.Month, d int) *time.Time {
+	t := time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		line     string
+		expected Item
+	}{
+		{
+			name: "should parse an open task",
+			line: "(A) 2026-10-01 Call Mom +Family @phone due:2026-10-20 about the trip",
+			expected: Item{
+				Priority: 'A', CreatedOn: day(2026, 10, 1),
+				Text:       "Call Mom about the trip",
+				Projects:   []string{"Family"},
+				Contexts:   []string{"phone"},
+				Extensions: []Extension{{Key: "due", Value: "2026-10-20"}},
+			},
+		},
+		{
+			name:     "should parse a finished task with both dates",
+			line:     "x 2026-10-18 2026-10-01 Review PR pri:B",
+			expected: Item{Done: true, CompletedOn: day(2026, 10, 18), CreatedOn: day(2026, 10, 1), Text: "Review PR", Extensions: []Extension{{Key: "pri", Value: "B"}}},
+		},
+		{
+			name:     "should parse a finished task with only a completion date",
+			line:     "x 2026-10-18 Done",
+			expected: Item{Done: true, CompletedOn: day(2026, 10, 18), Text: "Done"},
+		},
+		{
+			name:     "should keep markers out of place as text",
+			line:     "Buy milk (B) x 2026-10-01",
+			expected: Item{Text: "Buy milk (B) x 2026-10-01"},
+		},
+		{
+			name:     "should not take a lowercase x without a space as done",
+			line:     "xylophone lessons",
+			expected: Item{Text: "xylophone lessons"},
+		},
+		{
+			name:     "should keep lookalikes as text",
+			line:     "(a) meet at 10:30 see https://example.com + @ :x 2026-13-40 +",
+			expected: Item{Text: "(a) meet at 10:30 see https://example.com + @ :x 2026-13-40 +"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			item := Parse(tc.line)
+			if !reflect.DeepEqual(item, tc.expected) {
+				t.Fatalf("expected %+v but got %+v", tc.expected, item)
+			}
+		})
+	}
+}
+
+func TestItem_String(t *testing.T) {
+	testCases := []struct {
+		name     string
+		item     Item
+		expected string
+	}{
+		{
+			name: "should put extensions after the description",
+			item: Item{
+				Priority: 'B', CreatedOn: day(2026, 10, 1), Text: "Call  Mom",
+				Projects: []string{"Family"}, Contexts: []string{"phone", "home"},
+				Extensions: []Extension{{Key: "due", Value: "2026-10-20"}},
+			},
+			expected: "(B) 2026-10-01 Call Mom +Family @phone @home due:2026-10-20",
+		},
+		{
+			name:     "should write both dates of a finished task",
+			item:     Item{Done: true, CompletedOn: day(2026, 10, 18), CreatedOn: day(2026, 10, 1), Text: "Review"},
+			expected: "x 2026-10-18 2026-10-01 Review",
+		},
+		{
+			name:     "should drop a creation date that would read as a completion date",
+			item:     Item{Done: true, CreatedOn: day(2026, 10, 1), Text: "Review"},
+			expected: "x Review",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.item.String(); got != tc.expected {
+				t.Fatalf("expected %q but got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestParse_RoundTrip(t *testing.T) {
+	lines := []string{
+		"(A) 2026-10-01 Call Mom +Family @phone due:2026-10-20",
+		"x 2026-10-18 2026-10-01 Review PR @work id:4 pri:B",
+		"Plain task",
+	}
+	for _, line := range lines {
+		if got := Parse(line).String(); got != line {
+			t.Errorf("expected %q to survive a round trip, got %q", line, got)
+		}
+	}
+}
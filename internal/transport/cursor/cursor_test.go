@@ -0,0 +1,62 @@
+package cursor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+func TestCodec_RoundTrip(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+	cur := domain.TaskCursor{
+		Sort:     domain.TaskSort{Field: domain.TaskSortUpdatedAt, Desc: true},
+		Value:    "2025-01-02T03:04:05Z",
+		ID:       42,
+		Backward: true,
+	}
+
+	token, err := codec.Encode(cur)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	got, err := codec.Decode(token)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if got != cur {
+		t.Fatalf("expected cursor %+v but got %+v", cur, got)
+	}
+}
+
+func TestCodec_Decode_Invalid(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+	token, err := codec.Encode(domain.TaskCursor{Sort: domain.TaskSort{Field: domain.TaskSortTitle}, Value: "a", ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, sig, _ := strings.Cut(token, ".")
+	other, _ := NewCodec([]byte("other")).Encode(domain.TaskCursor{Sort: domain.TaskSort{Field: domain.TaskSortTitle}, Value: "a", ID: 1})
+
+	testCases := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty token", token: ""},
+		{name: "missing signature", token: body},
+		{name: "tampered body", token: "x" + body + "." + sig},
+		{name: "tampered signature", token: body + "." + sig[:len(sig)-2] + "AA"},
+		{name: "signed with another key", token: other},
+		{name: "not base64", token: "!!!.???"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := codec.Decode(tc.token); !errors.Is(err, domain.ErrInvalidCursor) {
+				t.Fatalf("expected error %v but got %v", domain.ErrInvalidCursor, err)
+			}
+		})
+	}
+}
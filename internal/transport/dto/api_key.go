@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+// APIKeyDTO is a data transfer object for APIKey.
+type APIKeyDTO struct {
+	ID         int64    `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  *string  `json:"expires_at"`
+	CreatedAt  string   `json:"created_at"`
+	LastUsedAt *string  `json:"last_used_at"`
+}
+
+// APIKeysResponse is the response for a list of API keys.
+type APIKeysResponse struct {
+	Keys []APIKeyDTO `json:"api_keys"`
+}
+
+// CreatedAPIKeyDTO is the response for a new API key. Key is only ever
+// shown here.
+type CreatedAPIKeyDTO struct {
+	APIKeyDTO
+	Key string `json:"key"`
+}
+
+// APIKeyRequest is the request body for creating an API key. A key without
+// expires_at does not expire.
+type APIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// ToDomain maps the request to a domain API key of user.
+func (r APIKeyRequest) ToDomain(user domain.User) domain.APIKey {
+	scopes := make([]domain.APIKeyScope, len(r.Scopes))
+	for i, s := range r.Scopes {
+		scopes[i] = domain.APIKeyScope(s)
+	}
+	return domain.APIKey{User: user, Name: r.Name, Scopes: scopes, ExpiresAt: r.ExpiresAt}
+}
+
+// MapAPIKeyToDTO maps a domain API key to a DTO.
+func MapAPIKeyToDTO(k domain.APIKey) APIKeyDTO {
+	scopes := make([]string, len(k.Scopes))
+	for i, s := range k.Scopes {
+		scopes[i] = string(s)
+	}
+	return APIKeyDTO{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  formatOptionalTime(k.ExpiresAt),
+		CreatedAt:  formatTime(k.CreatedAt),
+		LastUsedAt: formatOptionalTime(k.LastUsedAt),
+	}
+}
+
+// MapAPIKeysToDTO maps domain API keys to DTOs.
+func MapAPIKeysToDTO(keys []domain.APIKey) []APIKeyDTO {
+	dtos := make([]APIKeyDTO, len(keys))
+	for i, k := range keys {
+		dtos[i] = MapAPIKeyToDTO(k)
+	}
+	return dtos
+}
@@ -0,0 +1,88 @@
+package dto
+
+import "github.com/mkeOrt/tasks-go/internal/domain"
+
+// UserDTO is a data transfer object for User. The timestamps are left out
+// when unknown, as for users signed in with an access token, which does not
+// carry them.
+type UserDTO struct {
+	ID        int64  `json:"id"`
+	Email     string `json:"email"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// SessionDTO is the response for a sign-in. The session token itself only
+// travels in the cookie.
+type SessionDTO struct {
+	User      UserDTO `json:"user"`
+	ExpiresAt string  `json:"expires_at"`
+}
+
+// TokenDTO is the response for a bearer token grant. Send the access token
+// as "Authorization: Bearer"; trade the refresh token, which works once,
+// for the next pair before the access token expires.
+type TokenDTO struct {
+	User             UserDTO `json:"user"`
+	TokenType        string  `json:"token_type"`
+	AccessToken      string  `json:"access_token"`
+	AccessExpiresAt  string  `json:"access_expires_at"`
+	RefreshToken     string  `json:"refresh_token"`
+	RefreshExpiresAt string  `json:"refresh_expires_at"`
+}
+
+// RegisterRequest is the request body for creating an account.
+type RegisterRequest struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// ToDomain maps the request to a domain user; the password is passed on
+// separately.
+func (r RegisterRequest) ToDomain() domain.User {
+	return domain.User{Email: r.Email, Name: r.Name}
+}
+
+// LoginRequest is the request body for signing in.
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// RefreshTokenRequest is the request body for refreshing or revoking
+// bearer tokens.
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// MapUserToDTO maps a domain user to a DTO.
+func MapUserToDTO(u domain.User) UserDTO {
+	dto := UserDTO{ID: u.ID, Email: u.Email, Name: u.Name}
+	if !u.CreatedAt.IsZero() {
+		dto.CreatedAt = formatTime(u.CreatedAt)
+		dto.UpdatedAt = formatTime(u.UpdatedAt)
+	}
+	return dto
+}
+
+// MapSessionToDTO maps a domain session to a DTO.
+func MapSessionToDTO(s domain.Session) SessionDTO {
+	return SessionDTO{
+		User:      MapUserToDTO(s.User),
+		ExpiresAt: formatTime(s.ExpiresAt),
+	}
+}
+
+// MapTokenPairToDTO maps a domain token pair to a DTO.
+func MapTokenPairToDTO(p domain.TokenPair) TokenDTO {
+	return TokenDTO{
+		User:             MapUserToDTO(p.User),
+		TokenType:        "Bearer",
+		AccessToken:      p.AccessToken,
+		AccessExpiresAt:  formatTime(p.AccessExpiresAt),
+		RefreshToken:     p.RefreshToken,
+		RefreshExpiresAt: formatTime(p.RefreshExpiresAt),
+	}
+}
@@ -0,0 +1,55 @@
+package dto
+
+import "github.com/mkeOrt/tasks-go/internal/domain"
+
+// CalendarFeedDTO is a data transfer object for CalendarFeed.
+type CalendarFeedDTO struct {
+	ID         int64   `json:"id"`
+	Name       string  `json:"name"`
+	CreatedAt  string  `json:"created_at"`
+	LastUsedAt *string `json:"last_used_at"`
+}
+
+// CalendarFeedsResponse is the response for a list of calendar feeds.
+type CalendarFeedsResponse struct {
+	Feeds []CalendarFeedDTO `json:"feeds"`
+}
+
+// CreatedCalendarFeedDTO is the response for a new calendar feed. Token and
+// URL are only ever shown here.
+type CreatedCalendarFeedDTO struct {
+	CalendarFeedDTO
+	Token string `json:"token"`
+	// URL is the path calendar clients subscribe to, relative to the API's
+	// origin.
+	URL string `json:"url"`
+}
+
+// CalendarFeedRequest is the request body for creating a calendar feed.
+type CalendarFeedRequest struct {
+	Name string `json:"name"`
+}
+
+// ToDomain maps the request to a domain calendar feed.
+func (r CalendarFeedRequest) ToDomain() domain.CalendarFeed {
+	return domain.CalendarFeed{Name: r.Name}
+}
+
+// MapCalendarFeedToDTO maps a domain calendar feed to a DTO.
+func MapCalendarFeedToDTO(f domain.CalendarFeed) CalendarFeedDTO {
+	return CalendarFeedDTO{
+		ID:         f.ID,
+		Name:       f.Name,
+		CreatedAt:  formatTime(f.CreatedAt),
+		LastUsedAt: formatOptionalTime(f.LastUsedAt),
+	}
+}
+
+// MapCalendarFeedsToDTO maps domain calendar feeds to DTOs.
+func MapCalendarFeedsToDTO(feeds []domain.CalendarFeed) []CalendarFeedDTO {
+	dtos := make([]CalendarFeedDTO, len(feeds))
+	for i, f := range feeds {
+		dtos[i] = MapCalendarFeedToDTO(f)
+	}
+	return dtos
+}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+// Nullable is a JSON field that tells an absent key apart from an explicit
+// null. Set is true whenever the key was present.
+type Nullable[T any] struct {
+	Set   bool
+	Value *T
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	n.Value = &v
+	return nil
+}
+
+// DescribeType implements validation.TypeDescriber.
+func (Nullable[T]) DescribeType() string {
+	return validation.DescribeType(reflect.TypeFor[T]()) + " or null"
+}
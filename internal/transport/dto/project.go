@@ -0,0 +1,50 @@
+package dto
+
+import "github.com/mkeOrt/tasks-go/internal/domain"
+
+// ProjectDTO is a data transfer object for Project.
+type ProjectDTO struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Inbox       bool   `json:"inbox"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// ProjectsResponse is the response for a list of projects.
+type ProjectsResponse struct {
+	Projects []ProjectDTO `json:"projects"`
+}
+
+// ProjectRequest is the request body for creating or replacing a project.
+type ProjectRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ToDomain maps the request to the domain project with the given ID.
+func (r ProjectRequest) ToDomain(id int64) domain.Project {
+	return domain.Project{ID: id, Name: r.Name, Description: r.Description}
+}
+
+// MapProjectToDTO maps a domain project to a DTO.
+func MapProjectToDTO(p domain.Project) ProjectDTO {
+	return ProjectDTO{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		Inbox:       p.Inbox,
+		CreatedAt:   formatTime(p.CreatedAt),
+		UpdatedAt:   formatTime(p.UpdatedAt),
+	}
+}
+
+// MapProjectsToDTO maps domain projects to DTOs.
+func MapProjectsToDTO(projects []domain.Project) []ProjectDTO {
+	dtos := make([]ProjectDTO, len(projects))
+	for i, p := range projects {
+		dtos[i] = MapProjectToDTO(p)
+	}
+	return dtos
+}
@@ -0,0 +1,45 @@
+package dto
+
+import "github.com/mkeOrt/tasks-go/internal/domain"
+
+// TagDTO is a data transfer object for Tag.
+type TagDTO struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// TagsResponse is the response for a list of tags.
+type TagsResponse struct {
+	Tags []TagDTO `json:"tags"`
+}
+
+// TagRequest is the request body for creating or renaming a tag.
+type TagRequest struct {
+	Name string `json:"name"`
+}
+
+// ToDomain maps the request to the domain tag with the given ID.
+func (r TagRequest) ToDomain(id int64) domain.Tag {
+	return domain.Tag{ID: id, Name: r.Name}
+}
+
+// MapTagToDTO maps a domain tag to a DTO.
+func MapTagToDTO(t domain.Tag) TagDTO {
+	return TagDTO{
+		ID:        t.ID,
+		Name:      t.Name,
+		CreatedAt: formatTime(t.CreatedAt),
+		UpdatedAt: formatTime(t.UpdatedAt),
+	}
+}
+
+// MapTagsToDTO maps domain tags to DTOs.
+func MapTagsToDTO(tags []domain.Tag) []TagDTO {
+	dtos := make([]TagDTO, len(tags))
+	for i, t := range tags {
+		dtos[i] = MapTagToDTO(t)
+	}
+	return dtos
+}
@@ -1,32 +1,260 @@
 package dto
 
-import "github.com/mkeOrt/tasks-go/internal/domain"
+import (
+	"time"
 
-// TaskDTO is a data transfer object for Task.
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+// TaskDTO is a data transfer object for Task. Timestamps are RFC 3339;
+// optional ones are null when unset.
 type TaskDTO struct {
-	ID        int64  `json:"id"`
-	Title     string `json:"title"`
-	Done      bool   `json:"done"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID          int64    `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Done        bool     `json:"done"`
+	Priority    string   `json:"priority"`
+	StartAt     *string  `json:"start_at"`
+	DueAt       *string  `json:"due_at"`
+	ProjectID   *int64   `json:"project_id"`
+	ParentID    *int64   `json:"parent_id"`
+	Recurrence  string   `json:"recurrence"`
+	Blocked     bool     `json:"blocked"`
+	CompletedAt *string  `json:"completed_at"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+	Version     int64    `json:"version"`
+	Tags        []TagDTO `json:"tags"`
 }
 
 // TasksResponse is the response for a list of tasks.
 type TasksResponse struct {
-	Tasks []TaskDTO `json:"tasks"`
+	Tasks      []TaskDTO `json:"tasks"`
+	Total      int       `json:"total"`
+	Limit      int       `json:"limit"`
+	Offset     int       `json:"offset"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	PrevCursor string    `json:"prev_cursor,omitempty"`
+}
+
+// TaskTreeDTO is a task with its subtasks nested below it.
+type TaskTreeDTO struct {
+	TaskDTO
+	Children []TaskTreeDTO `json:"children"`
+}
+
+// BlockersResponse is the response for the tasks a task depends on.
+type BlockersResponse struct {
+	Blockers []TaskDTO `json:"blockers"`
+}
+
+// OccurrencesResponse is the response for a preview of a task's upcoming
+// occurrences.
+type OccurrencesResponse struct {
+	Occurrences []string `json:"occurrences"`
+}
+
+// TaskSearchResultDTO is a task matched by a full-text search.
+type TaskSearchResultDTO struct {
+	TaskDTO
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
+// TaskSearchResponse is the response for a full-text search.
+type TaskSearchResponse struct {
+	Results []TaskSearchResultDTO `json:"results"`
+}
+
+// TrashedTaskDTO is a task in the trash.
+type TrashedTaskDTO struct {
+	TaskDTO
+	DeletedAt string `json:"deleted_at"`
+}
+
+// TrashResponse is the response for the contents of the trash.
+type TrashResponse struct {
+	Tasks []TrashedTaskDTO `json:"tasks"`
+}
+
+// CreateTaskRequest is the request body for creating a task.
+type CreateTaskRequest struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Done        bool       `json:"done"`
+	Priority    string     `json:"priority"`
+	StartAt     *time.Time `json:"start_at"`
+	DueAt       *time.Time `json:"due_at"`
+	ProjectID   *int64     `json:"project_id"`
+	ParentID    *int64     `json:"parent_id"`
+	Recurrence  string     `json:"recurrence"`
+}
+
+// UpdateTaskRequest is the request body for replacing a task.
+// Omitted fields are reset to their defaults.
+type UpdateTaskRequest struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Done        bool       `json:"done"`
+	Priority    string     `json:"priority"`
+	StartAt     *time.Time `json:"start_at"`
+	DueAt       *time.Time `json:"due_at"`
+	ProjectID   *int64     `json:"project_id"`
+	ParentID    *int64     `json:"parent_id"`
+	Recurrence  string     `json:"recurrence"`
+}
+
+// PatchTaskRequest is the request body for partially updating a task.
+// Send null for start_at, due_at, project_id or parent_id to clear them,
+// and an empty recurrence to stop the task repeating.
+type PatchTaskRequest struct {
+	Title       *string             `json:"title"`
+	Description *string             `json:"description"`
+	Done        *bool               `json:"done"`
+	Priority    *string             `json:"priority"`
+	StartAt     Nullable[time.Time] `json:"start_at"`
+	DueAt       Nullable[time.Time] `json:"due_at"`
+	ProjectID   Nullable[int64]     `json:"project_id"`
+	ParentID    Nullable[int64]     `json:"parent_id"`
+	Recurrence  *string             `json:"recurrence"`
+}
+
+// ToDomain maps the request to a new domain task.
+func (r CreateTaskRequest) ToDomain() (domain.Task, error) {
+	return UpdateTaskRequest(r).ToDomain(0)
+}
+
+// ToDomain maps the request to the domain task with the given ID.
+func (r UpdateTaskRequest) ToDomain(id int64) (domain.Task, error) {
+	v := validation.New()
+	task := domain.Task{
+		ID:          id,
+		Title:       r.Title,
+		Description: r.Description,
+		Done:        r.Done,
+		StartAt:     r.StartAt,
+		DueAt:       r.DueAt,
+		ProjectID:   r.ProjectID,
+		ParentID:    r.ParentID,
+		Recurrence:  r.Recurrence,
+	}
+	if r.Priority != "" {
+		task.Priority = parsePriority(v, r.Priority)
+	}
+	return task, v.Err()
+}
+
+// ToDomain maps the request to a domain patch.
+func (r PatchTaskRequest) ToDomain() (domain.TaskPatch, error) {
+	v := validation.New()
+	patch := domain.TaskPatch{
+		Title:       r.Title,
+		Description: r.Description,
+		Done:        r.Done,
+		StartAt:     domain.NullableTime{Set: r.StartAt.Set, Time: r.StartAt.Value},
+		DueAt:       domain.NullableTime{Set: r.DueAt.Set, Time: r.DueAt.Value},
+		ProjectID:   domain.NullableID{Set: r.ProjectID.Set, ID: r.ProjectID.Value},
+		ParentID:    domain.NullableID{Set: r.ParentID.Set, ID: r.ParentID.Value},
+		Recurrence:  r.Recurrence,
+	}
+	if r.Priority != nil {
+		p := parsePriority(v, *r.Priority)
+		patch.Priority = &p
+	}
+	return patch, v.Err()
+}
+
+func parsePriority(v *validation.Validator, s string) domain.Priority {
+	p, ok := domain.ParsePriority(s)
+	if !ok {
+		v.Add("priority", validation.CodeInvalidValue, "priority must be one of none, low, medium, high or urgent")
+	}
+	return p
+}
+
+// MapTaskToDTO maps a domain task to a DTO.
+func MapTaskToDTO(t domain.Task) TaskDTO {
+	return TaskDTO{
+		ID:          t.ID,
+		Title:       t.Title,
+		Description: t.Description,
+		Done:        t.Done,
+		Priority:    t.Priority.String(),
+		StartAt:     formatOptionalTime(t.StartAt),
+		DueAt:       formatOptionalTime(t.DueAt),
+		ProjectID:   t.ProjectID,
+		ParentID:    t.ParentID,
+		Recurrence:  t.Recurrence,
+		Blocked:     t.Blocked,
+		CompletedAt: formatOptionalTime(t.CompletedAt),
+		CreatedAt:   formatTime(t.CreatedAt),
+		UpdatedAt:   formatTime(t.UpdatedAt),
+		Version:     t.Version,
+		Tags:        MapTagsToDTO(t.Tags),
+	}
 }
 
 // MapTasksToDTO maps domain tasks to DTOs.
 func MapTasksToDTO(tasks []domain.Task) []TaskDTO {
 	dtos := make([]TaskDTO, len(tasks))
 	for i, t := range tasks {
-		dtos[i] = TaskDTO{
-			ID:        t.ID,
-			Title:     t.Title,
-			Done:      t.Done,
-			CreatedAt: t.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt: t.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		dtos[i] = MapTaskToDTO(t)
+	}
+	return dtos
+}
+
+// MapTaskTreeToDTO maps a domain task tree to a DTO.
+func MapTaskTreeToDTO(n domain.TaskNode) TaskTreeDTO {
+	children := make([]TaskTreeDTO, len(n.Children))
+	for i, c := range n.Children {
+		children[i] = MapTaskTreeToDTO(c)
+	}
+	return TaskTreeDTO{TaskDTO: MapTaskToDTO(n.Task), Children: children}
+}
+
+// MapSearchResultsToDTO maps domain search results to DTOs.
+func MapSearchResultsToDTO(results []domain.TaskSearchResult) []TaskSearchResultDTO {
+	dtos := make([]TaskSearchResultDTO, len(results))
+	for i, r := range results {
+		dtos[i] = TaskSearchResultDTO{
+			TaskDTO: MapTaskToDTO(r.Task),
+			Score:   r.Score,
+			Snippet: r.Snippet,
 		}
 	}
 	return dtos
 }
+
+// MapTrashedTasksToDTO maps tasks in the trash to DTOs.
+func MapTrashedTasksToDTO(tasks []domain.TrashedTask) []TrashedTaskDTO {
+	dtos := make([]TrashedTaskDTO, len(tasks))
+	for i, t := range tasks {
+		dtos[i] = TrashedTaskDTO{
+			TaskDTO:   MapTaskToDTO(t.Task),
+			DeletedAt: formatTime(t.DeletedAt),
+		}
+	}
+	return dtos
+}
+
+// MapOccurrencesToDTO maps occurrence times to the preview response.
+func MapOccurrencesToDTO(times []time.Time) OccurrencesResponse {
+	occurrences := make([]string, len(times))
+	for i, t := range times {
+		occurrences[i] = formatTime(t)
+	}
+	return OccurrencesResponse{Occurrences: occurrences}
+}
+
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := formatTime(*t)
+	return &s
+}
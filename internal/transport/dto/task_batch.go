@@ -0,0 +1,133 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+// Batch modes accepted in BatchTaskRequest.Mode.
+const (
+	BatchModeAtomic   = "atomic"
+	BatchModeContinue = "continue"
+)
+
+// BatchTaskRequest is the request body for running several task writes in
+// one transaction. Mode is atomic (the default) to roll everything back on
+// the first failure, or continue to keep going past failed operations.
+type BatchTaskRequest struct {
+	Mode       string                 `json:"mode"`
+	Operations []TaskOperationRequest `json:"operations"`
+}
+
+// TaskOperationRequest is one operation of a batch. Task is the body the
+// single-task endpoint of the operation takes: a CreateTaskRequest,
+// UpdateTaskRequest or PatchTaskRequest. Version plays the role of If-Match.
+type TaskOperationRequest struct {
+	Op      string          `json:"op"`
+	ID      int64           `json:"id"`
+	Version int64           `json:"version"`
+	Task    json.RawMessage `json:"task"`
+}
+
+// TaskOperationResultDTO is the outcome of one batch operation. Status is the
+// HTTP status the operation would have answered with on its own.
+type TaskOperationResultDTO struct {
+	Index  int                     `json:"index"`
+	Op     string                  `json:"op"`
+	Status int                     `json:"status"`
+	Task   *TaskDTO                `json:"task,omitempty"`
+	Error  string                  `json:"error,omitempty"`
+	Errors []validation.FieldError `json:"errors,omitempty"`
+}
+
+// TaskBatchResponse is the response for a batch. Committed is false when an
+// atomic batch was rolled back.
+type TaskBatchResponse struct {
+	Committed bool                     `json:"committed"`
+	Results   []TaskOperationResultDTO `json:"results"`
+}
+
+// ToDomain maps the request to domain operations and reports whether the
+// batch continues past failures. Task bodies that cannot be decoded fail
+// the whole request. When requireVersion is set, every operation on an
+// existing task must state its version.
+func (r BatchTaskRequest) ToDomain(requireVersion bool) ([]domain.TaskOperation, bool, error) {
+	req := validation.New()
+	v := validation.New()
+
+	var continueOnError bool
+	switch r.Mode {
+	case "", BatchModeAtomic:
+	case BatchModeContinue:
+		continueOnError = true
+	default:
+		v.Add("mode", validation.CodeInvalidValue, "mode must be atomic or continue")
+	}
+
+	ops := make([]domain.TaskOperation, len(r.Operations))
+	for i, o := range r.Operations {
+		field := fmt.Sprintf("operations[%d]", i)
+		op := domain.TaskOperation{Kind: domain.TaskOperationKind(o.Op), ID: o.ID, Version: o.Version}
+
+		var err error
+		switch op.Kind {
+		case domain.TaskOperationCreate:
+			var body CreateTaskRequest
+			if decodeOperationTask(req, field, o.Task, &body) {
+				op.Task, err = body.ToDomain()
+			}
+		case domain.TaskOperationUpdate:
+			var body UpdateTaskRequest
+			if decodeOperationTask(req, field, o.Task, &body) {
+				op.Task, err = body.ToDomain(o.ID)
+			}
+		case domain.TaskOperationPatch:
+			var body PatchTaskRequest
+			if decodeOperationTask(req, field, o.Task, &body) {
+				op.Patch, err = body.ToDomain()
+			}
+		}
+		addFieldErrors(v, field+".task.", err)
+
+		if requireVersion && op.Kind != domain.TaskOperationCreate && op.Version == 0 {
+			v.Add(field+".version", validation.CodeRequired, "version is required")
+		}
+		ops[i] = op
+	}
+
+	if err := req.RequestErr(); err != nil {
+		return nil, false, err
+	}
+	return ops, continueOnError, v.Err()
+}
+
+// decodeOperationTask decodes the task body of the operation at field into
+// dst, recording any problem in req.
+func decodeOperationTask(req *validation.Validator, field string, raw json.RawMessage, dst any) bool {
+	if len(raw) == 0 {
+		req.Add(field+".task", validation.CodeRequired, "task is required")
+		return false
+	}
+	if err := validation.DecodeJSON(bytes.NewReader(raw), dst); err != nil {
+		for _, f := range validation.Fields(err) {
+			if f.Code == validation.CodeInvalidJSON {
+				req.Add(field+".task", f.Code, "task must be a JSON object")
+				continue
+			}
+			req.Add(field+".task."+f.Field, f.Code, f.Message)
+		}
+		return false
+	}
+	return true
+}
+
+// addFieldErrors records the field failures of err in v under prefix.
+func addFieldErrors(v *validation.Validator, prefix string, err error) {
+	for _, f := range validation.Fields(err) {
+		v.Add(prefix+f.Field, f.Code, f.Message)
+	}
+}
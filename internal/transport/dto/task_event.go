@@ -0,0 +1,62 @@
+package dto
+
+import "github.com/mkeOrt/tasks-go/internal/domain"
+
+// FieldChangeDTO is the value of a task field before and after a change.
+type FieldChangeDTO struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// TaskEventDTO is an entry in the change history of a task.
+type TaskEventDTO struct {
+	ID        int64                     `json:"id"`
+	TaskID    int64                     `json:"task_id"`
+	Action    string                    `json:"action"`
+	Changes   map[string]FieldChangeDTO `json:"changes"`
+	Actor     *string                   `json:"actor"`
+	RequestID *string                   `json:"request_id"`
+	CreatedAt string                    `json:"created_at"`
+}
+
+// TaskHistoryResponse is the response for a page of a task's change history.
+type TaskHistoryResponse struct {
+	Events []TaskEventDTO `json:"events"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+// MapTaskEventToDTO maps a domain task event to a DTO.
+func MapTaskEventToDTO(e domain.TaskEvent) TaskEventDTO {
+	changes := make(map[string]FieldChangeDTO, len(e.Changes))
+	for field, c := range e.Changes {
+		changes[field] = FieldChangeDTO{Old: c.Old, New: c.New}
+	}
+	return TaskEventDTO{
+		ID:        e.ID,
+		TaskID:    e.TaskID,
+		Action:    string(e.Action),
+		Changes:   changes,
+		Actor:     optionalString(e.Actor),
+		RequestID: optionalString(e.RequestID),
+		CreatedAt: formatTime(e.CreatedAt),
+	}
+}
+
+// MapTaskHistoryToDTO maps a page of task events to the history response.
+func MapTaskHistoryToDTO(page domain.TaskEventPage) TaskHistoryResponse {
+	events := make([]TaskEventDTO, len(page.Events))
+	for i, e := range page.Events {
+		events[i] = MapTaskEventToDTO(e)
+	}
+	return TaskHistoryResponse{Events: events, Total: page.Total, Limit: page.Limit, Offset: page.Offset}
+}
+
+// optionalString maps an empty string to nil.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
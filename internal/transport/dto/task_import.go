@@ -0,0 +1,25 @@
+package dto
+
+import "github.com/mkeOrt/tasks-go/internal/validation"
+
+// TaskImportRowDTO is the outcome of one row of an import. Line is the line
+// of the row in the file and Op is create or update.
+type TaskImportRowDTO struct {
+	Line   int                     `json:"line"`
+	Op     string                  `json:"op"`
+	Status int                     `json:"status"`
+	Task   *TaskDTO                `json:"task,omitempty"`
+	Error  string                  `json:"error,omitempty"`
+	Errors []validation.FieldError `json:"errors,omitempty"`
+}
+
+// TaskImportResponse is the response for an import. Committed is false for
+// a dry run and for an import rolled back because rows failed.
+type TaskImportResponse struct {
+	Committed bool               `json:"committed"`
+	DryRun    bool               `json:"dry_run"`
+	Created   int                `json:"created"`
+	Updated   int                `json:"updated"`
+	Failed    int                `json:"failed"`
+	Rows      []TaskImportRowDTO `json:"rows"`
+}
@@ -0,0 +1,114 @@
+package httphandler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+	"github.com/mkeOrt/tasks-go/internal/transport/response"
+)
+
+// APIKeyService defines the business logic interface for API keys.
+type APIKeyService interface {
+	GetAll(ctx context.Context, userID int64) ([]domain.APIKey, error)
+	Create(ctx context.Context, key domain.APIKey) (domain.APIKey, string, error)
+	Delete(ctx context.Context, userID, id int64) error
+}
+
+// APIKeyHandler handles HTTP requests for the API keys of the signed-in
+// user.
+type APIKeyHandler struct {
+	logger *slog.Logger
+	svc    APIKeyService
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler.
+func NewAPIKeyHandler(logger *slog.Logger, svc APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		logger: logger,
+		svc:    svc,
+	}
+}
+
+// RegisterRoutes returns the API key routes.
+func (h *APIKeyHandler) RegisterRoutes() http.Handler {
+	g := http.NewServeMux()
+	g.HandleFunc("GET /api/api-keys", h.GetAll)
+	g.HandleFunc("POST /api/api-keys", h.Create)
+	g.HandleFunc("DELETE /api/api-keys/{id}", h.Delete)
+	return g
+}
+
+func (h *APIKeyHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	keys, err := h.svc.GetAll(r.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("failed to get api keys", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusOK, dto.APIKeysResponse{Keys: dto.MapAPIKeysToDTO(keys)})
+}
+
+// Create serves POST /api/api-keys. The response carries the secret key,
+// which is not shown again.
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	var req dto.APIKeyRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	key, secret, err := h.svc.Create(r.Context(), req.ToDomain(user))
+	if err != nil {
+		h.logger.Error("failed to create api key", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	response.RespondWithJson(w, http.StatusCreated, dto.CreatedAPIKeyDTO{
+		APIKeyDTO: dto.MapAPIKeyToDTO(key),
+		Key:       secret,
+	})
+}
+
+// Delete serves DELETE /api/api-keys/{id}, revoking the key.
+func (h *APIKeyHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := parsePathID(w, r, "id", response.ErrMsgInvalidAPIKeyID)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), user.ID, id); err != nil {
+		h.logger.Error("failed to delete api key", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireUser returns the signed-in user of the request, writing a 401
+// response when there is none.
+func requireUser(w http.ResponseWriter, r *http.Request) (domain.User, bool) {
+	user, ok := domain.UserFrom(r.Context())
+	if !ok {
+		response.RespondWithError(w, domain.ErrUnauthenticated)
+	}
+	return user, ok
+}
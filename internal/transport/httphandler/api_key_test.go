@@ -0,0 +1,154 @@
+package httphandler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+type mockAPIKeyService struct {
+	getAllFunc func(ctx context.Context, userID int64) ([]domain.APIKey, error)
+	createFunc func(ctx context.Context, key domain.APIKey) (domain.APIKey, string, error)
+	deleteFunc func(ctx context.Context, userID, id int64) error
+}
+
+func (m *mockAPIKeyService) GetAll(ctx context.Context, userID int64) ([]domain.APIKey, error) {
+	return m.getAllFunc(ctx, userID)
+}
+
+func (m *mockAPIKeyService) Create(ctx context.Context, key domain.APIKey) (domain.APIKey, string, error) {
+	return m.createFunc(ctx, key)
+}
+
+func (m *mockAPIKeyService) Delete(ctx context.Context, userID, id int64) error {
+	return m.deleteFunc(ctx, userID, id)
+}
+
+func withTestUser(req *http.Request) *http.Request {
+	return req.WithContext(domain.WithUser(req.Context(), domain.User{ID: 7, Email: "ada@example.com"}))
+}
+
+func TestAPIKeyHandler_GetAll(t *testing.T) {
+	used := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	svc := &mockAPIKeyService{
+		getAllFunc: func(ctx context.Context, userID int64) ([]domain.APIKey, error) {
+			if userID != 7 {
+				t.Errorf("expected the keys of user 7, got %d", userID)
+			}
+			return []domain.APIKey{{ID: 1, Name: "ci", Prefix: "tsk_abcdefgh", Scopes: []domain.APIKeyScope{domain.ScopeTasksRead}, LastUsedAt: &used}}, nil
+		},
+	}
+	mux := NewAPIKeyHandler(slog.Default(), svc).RegisterRoutes()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/api-keys", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d without a user, got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, withTestUser(req))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data dto.APIKeysResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Data.Keys) != 1 || resp.Data.Keys[0].Scopes[0] != "tasks:read" || resp.Data.Keys[0].LastUsedAt == nil {
+		t.Errorf("unexpected response %+v", resp.Data)
+	}
+}
+
+func TestAPIKeyHandler_Create(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should create a key and show its secret", body: `{"name":"ci","scopes":["tasks:read"],"expires_at":"2027-01-01T00:00:00Z"}`, expectedStatus: http.StatusCreated},
+		{name: "should report validation errors", body: `{"name":"","scopes":[]}`, svcErr: &validation.Error{Kind: domain.ErrValidationFailed, Fields: []validation.FieldError{{Field: "name", Code: validation.CodeRequired, Message: "name is required"}}}, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "should reject unknown fields", body: `{"name":"ci","admin":true}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockAPIKeyService{
+				createFunc: func(ctx context.Context, key domain.APIKey) (domain.APIKey, string, error) {
+					if tc.svcErr != nil {
+						return domain.APIKey{}, "", tc.svcErr
+					}
+					if key.User.ID != 7 || key.Name != "ci" || len(key.Scopes) != 1 || key.ExpiresAt == nil {
+						t.Errorf("unexpected key %+v", key)
+					}
+					key.ID = 1
+					key.Prefix = "tsk_abcdefgh"
+					return key, "tsk_abcdefgh-secret", nil
+				},
+			}
+			mux := NewAPIKeyHandler(slog.Default(), svc).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/api-keys", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, withTestUser(req))
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus != http.StatusCreated {
+				return
+			}
+			if !strings.Contains(w.Body.String(), `"key":"tsk_abcdefgh-secret"`) || w.Header().Get("Cache-Control") != "no-store" {
+				t.Errorf("expected the secret in an uncached response, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAPIKeyHandler_Delete(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should delete the key", path: "/api/api-keys/3", expectedStatus: http.StatusNoContent},
+		{name: "should report a missing key", path: "/api/api-keys/3", svcErr: domain.ErrAPIKeyNotFound, expectedStatus: http.StatusNotFound},
+		{name: "should reject an invalid ID", path: "/api/api-keys/abc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockAPIKeyService{
+				deleteFunc: func(ctx context.Context, userID, id int64) error {
+					if userID != 7 || id != 3 {
+						t.Errorf("unexpected delete of key %d of user %d", id, userID)
+					}
+					return tc.svcErr
+				},
+			}
+			mux := NewAPIKeyHandler(slog.Default(), svc).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodDelete, tc.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, withTestUser(req))
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
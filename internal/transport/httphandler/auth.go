@@ -0,0 +1,130 @@
+package httphandler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+	"github.com/mkeOrt/tasks-go/internal/transport/middleware"
+	"github.com/mkeOrt/tasks-go/internal/transport/response"
+)
+
+// AuthService defines the business logic interface for accounts and
+// sessions.
+type AuthService interface {
+	Register(ctx context.Context, user domain.User, password string) (domain.Session, string, error)
+	Login(ctx context.Context, email, password string) (domain.Session, string, error)
+	Logout(ctx context.Context, token string) error
+}
+
+// AuthHandler handles HTTP requests for accounts and sessions. Session
+// tokens travel in an HttpOnly cookie that scripts cannot read.
+type AuthHandler struct {
+	logger        *slog.Logger
+	svc           AuthService
+	secureCookies bool
+}
+
+// NewAuthHandler creates a new AuthHandler. secureCookies limits the
+// session cookie to HTTPS, and should only be off for local development.
+func NewAuthHandler(logger *slog.Logger, svc AuthService, secureCookies bool) *AuthHandler {
+	return &AuthHandler{
+		logger:        logger,
+		svc:           svc,
+		secureCookies: secureCookies,
+	}
+}
+
+// RegisterRoutes returns the account and session routes.
+func (h *AuthHandler) RegisterRoutes() *http.ServeMux {
+	g := http.NewServeMux()
+	g.HandleFunc("POST /api/auth/register", h.Register)
+	g.HandleFunc("POST /api/auth/login", h.Login)
+	g.HandleFunc("POST /api/auth/logout", h.Logout)
+	g.HandleFunc("GET /api/auth/me", h.Me)
+	return g
+}
+
+// Register serves POST /api/auth/register, creating an account and signing
+// it in.
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var req dto.RegisterRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	session, token, err := h.svc.Register(r.Context(), req.ToDomain(), req.Password)
+	if err != nil {
+		h.logger.Error("failed to register user", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	h.setSessionCookie(w, token, session.ExpiresAt)
+	response.RespondWithJson(w, http.StatusCreated, dto.MapSessionToDTO(session))
+}
+
+// Login serves POST /api/auth/login, starting a session.
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req dto.LoginRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	session, token, err := h.svc.Login(r.Context(), req.Email, req.Password)
+	if err != nil {
+		h.logger.Error("failed to log in", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	h.setSessionCookie(w, token, session.ExpiresAt)
+	response.RespondWithJson(w, http.StatusOK, dto.MapSessionToDTO(session))
+}
+
+// Logout serves POST /api/auth/logout, ending the request's session and
+// clearing its cookie. It succeeds without a session too.
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(middleware.SessionCookie); err == nil {
+		if err := h.svc.Logout(r.Context(), cookie.Value); err != nil {
+			h.logger.Error("failed to log out", slog.String("error", err.Error()))
+			response.RespondWithError(w, err)
+			return
+		}
+	}
+
+	h.setSessionCookie(w, "", time.Time{})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Me serves GET /api/auth/me, returning the signed-in user.
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusOK, dto.MapUserToDTO(user))
+}
+
+// setSessionCookie sends the session cookie, or deletes it when token is
+// empty.
+func (h *AuthHandler) setSessionCookie(w http.ResponseWriter, token string, expires time.Time) {
+	cookie := &http.Cookie{
+		Name:     middleware.SessionCookie,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if token == "" {
+		cookie.MaxAge = -1
+	} else {
+		cookie.Expires = expires
+	}
+	http.SetCookie(w, cookie)
+}
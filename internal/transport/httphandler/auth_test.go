@@ -0,0 +1,184 @@
+package httphandler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+	"github.com/mkeOrt/tasks-go/internal/transport/middleware"
+)
+
+type mockAuthService struct {
+	registerFunc func(ctx context.Context, user domain.User, password string) (domain.Session, string, error)
+	loginFunc    func(ctx context.Context, email, password string) (domain.Session, string, error)
+	logoutFunc   func(ctx context.Context, token string) error
+}
+
+func (m *mockAuthService) Register(ctx context.Context, user domain.User, password string) (domain.Session, string, error) {
+	return m.registerFunc(ctx, user, password)
+}
+
+func (m *mockAuthService) Login(ctx context.Context, email, password string) (domain.Session, string, error) {
+	return m.loginFunc(ctx, email, password)
+}
+
+func (m *mockAuthService) Logout(ctx context.Context, token string) error {
+	return m.logoutFunc(ctx, token)
+}
+
+func sessionCookie(t *testing.T, w *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+	for _, c := range w.Result().Cookies() {
+		if c.Name == middleware.SessionCookie {
+			return c
+		}
+	}
+	t.Fatal("expected a session cookie")
+	return nil
+}
+
+func TestAuthHandler_Register(t *testing.T) {
+	expires := time.Date(2026, 11, 17, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		body           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should register and set the session cookie", body: `{"email":"ada@example.com","name":"Ada","password":"correct horse"}`, expectedStatus: http.StatusCreated},
+		{name: "should report a taken email", body: `{"email":"ada@example.com","password":"correct horse"}`, svcErr: domain.ErrUserAlreadyExists, expectedStatus: http.StatusConflict},
+		{name: "should reject unknown fields", body: `{"email":"ada@example.com","admin":true}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockAuthService{
+				registerFunc: func(ctx context.Context, user domain.User, password string) (domain.Session, string, error) {
+					if user.Email != "ada@example.com" || password != "correct horse" {
+						t.Errorf("unexpected user %+v with password %q", user, password)
+					}
+					user.ID = 7
+					return domain.Session{User: user, ExpiresAt: expires}, "secret", tc.svcErr
+				},
+			}
+			mux := NewAuthHandler(slog.Default(), svc, true).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/register", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus != http.StatusCreated {
+				if len(w.Result().Cookies()) != 0 {
+					t.Error("expected no cookie on failure")
+				}
+				return
+			}
+
+			c := sessionCookie(t, w)
+			if c.Value != "secret" || !c.HttpOnly || !c.Secure || c.SameSite != http.SameSiteLaxMode || c.Path != "/" || !c.Expires.Equal(expires) {
+				t.Errorf("unexpected cookie %+v", c)
+			}
+			var resp struct {
+				Data dto.SessionDTO `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Data.User.ID != 7 || strings.Contains(w.Body.String(), "secret") {
+				t.Errorf("unexpected response %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthHandler_Login(t *testing.T) {
+	testCases := []struct {
+		name           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should sign in", expectedStatus: http.StatusOK},
+		{name: "should reject bad credentials", svcErr: domain.ErrInvalidCredentials, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockAuthService{
+				loginFunc: func(ctx context.Context, email, password string) (domain.Session, string, error) {
+					return domain.Session{User: domain.User{ID: 7, Email: email}}, "secret", tc.svcErr
+				},
+			}
+			mux := NewAuthHandler(slog.Default(), svc, false).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(`{"email":"ada@example.com","password":"correct horse"}`))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.svcErr == nil && sessionCookie(t, w).Secure {
+				t.Error("expected an insecure cookie when secure cookies are off")
+			}
+		})
+	}
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	var ended []string
+	svc := &mockAuthService{
+		logoutFunc: func(ctx context.Context, token string) error {
+			ended = append(ended, token)
+			return nil
+		},
+	}
+	mux := NewAuthHandler(slog.Default(), svc, true).RegisterRoutes()
+
+	for _, token := range []string{"secret", ""} {
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/logout", nil)
+		if token != "" {
+			req.AddCookie(&http.Cookie{Name: middleware.SessionCookie, Value: token})
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("expected status %d, got %d", http.StatusNoContent, w.Code)
+		}
+		if c := sessionCookie(t, w); c.MaxAge >= 0 || c.Value != "" {
+			t.Errorf("expected the cookie to be deleted, got %+v", c)
+		}
+	}
+	if len(ended) != 1 || ended[0] != "secret" {
+		t.Errorf("expected only the cookie's session to end, got %v", ended)
+	}
+}
+
+func TestAuthHandler_Me(t *testing.T) {
+	mux := NewAuthHandler(slog.Default(), &mockAuthService{}, true).RegisterRoutes()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/me", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d without a user, got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	req = req.WithContext(domain.WithUser(req.Context(), domain.User{ID: 7, Email: "ada@example.com"}))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"email":"ada@example.com"`) {
+		t.Fatalf("expected the signed-in user, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,102 @@
+package httphandler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+	"github.com/mkeOrt/tasks-go/internal/transport/response"
+)
+
+// TokenService defines the business logic interface for bearer tokens.
+type TokenService interface {
+	Issue(ctx context.Context, email, password string) (domain.TokenPair, error)
+	Refresh(ctx context.Context, token string) (domain.TokenPair, error)
+	Revoke(ctx context.Context, token string) error
+}
+
+// TokenHandler handles HTTP requests for bearer tokens, the sign-in of
+// clients that cannot keep a session cookie.
+type TokenHandler struct {
+	logger *slog.Logger
+	svc    TokenService
+}
+
+// NewTokenHandler creates a new TokenHandler.
+func NewTokenHandler(logger *slog.Logger, svc TokenService) *TokenHandler {
+	return &TokenHandler{
+		logger: logger,
+		svc:    svc,
+	}
+}
+
+// RegisterRoutes returns the bearer token routes.
+func (h *TokenHandler) RegisterRoutes() *http.ServeMux {
+	g := http.NewServeMux()
+	g.HandleFunc("POST /api/auth/token", h.Issue)
+	g.HandleFunc("POST /api/auth/token/refresh", h.Refresh)
+	g.HandleFunc("POST /api/auth/token/revoke", h.Revoke)
+	return g
+}
+
+// Issue serves POST /api/auth/token, trading an email and password for a
+// pair of tokens.
+func (h *TokenHandler) Issue(w http.ResponseWriter, r *http.Request) {
+	var req dto.LoginRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	pair, err := h.svc.Issue(r.Context(), req.Email, req.Password)
+	if err != nil {
+		h.logger.Error("failed to issue tokens", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	respondTokens(w, pair)
+}
+
+// Refresh serves POST /api/auth/token/refresh, trading a refresh token for
+// the next pair.
+func (h *TokenHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req dto.RefreshTokenRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	pair, err := h.svc.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		h.logger.Error("failed to refresh tokens", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	respondTokens(w, pair)
+}
+
+// Revoke serves POST /api/auth/token/revoke, ending the refresh token's
+// family.
+func (h *TokenHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	var req dto.RefreshTokenRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := h.svc.Revoke(r.Context(), req.RefreshToken); err != nil {
+		h.logger.Error("failed to revoke tokens", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondTokens writes a token pair. Caches must not keep it, as RFC 6749
+// asks of token responses.
+func respondTokens(w http.ResponseWriter, pair domain.TokenPair) {
+	w.Header().Set("Cache-Control", "no-store")
+	response.RespondWithJson(w, http.StatusOK, dto.MapTokenPairToDTO(pair))
+}
@@ -0,0 +1,153 @@
+package httphandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+)
+
+type mockTokenService struct {
+	issueFunc   func(ctx context.Context, email, password string) (domain.TokenPair, error)
+	refreshFunc func(ctx context.Context, token string) (domain.TokenPair, error)
+	revokeFunc  func(ctx context.Context, token string) error
+}
+
+func (m *mockTokenService) Issue(ctx context.Context, email, password string) (domain.TokenPair, error) {
+	return m.issueFunc(ctx, email, password)
+}
+
+func (m *mockTokenService) Refresh(ctx context.Context, token string) (domain.TokenPair, error) {
+	return m.refreshFunc(ctx, token)
+}
+
+func (m *mockTokenService) Revoke(ctx context.Context, token string) error {
+	return m.revokeFunc(ctx, token)
+}
+
+var testTokenPair = domain.TokenPair{
+	User:             domain.User{ID: 7, Email: "ada@example.com"},
+	AccessToken:      "access",
+	AccessExpiresAt:  time.Date(2026, 10, 18, 9, 15, 0, 0, time.UTC),
+	RefreshToken:     "refresh",
+	RefreshExpiresAt: time.Date(2026, 11, 17, 9, 0, 0, 0, time.UTC),
+}
+
+func TestTokenHandler_Issue(t *testing.T) {
+	testCases := []struct {
+		name           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should issue a pair", expectedStatus: http.StatusOK},
+		{name: "should reject bad credentials", svcErr: domain.ErrInvalidCredentials, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTokenService{
+				issueFunc: func(ctx context.Context, email, password string) (domain.TokenPair, error) {
+					if email != "ada@example.com" || password != "correct horse" {
+						t.Errorf("unexpected credentials %q and %q", email, password)
+					}
+					return testTokenPair, tc.svcErr
+				},
+			}
+			mux := NewTokenHandler(slog.Default(), svc).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/token", strings.NewReader(`{"email":"ada@example.com","password":"correct horse"}`))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.svcErr != nil {
+				return
+			}
+			if got := w.Header().Get("Cache-Control"); got != "no-store" {
+				t.Errorf("expected Cache-Control no-store, got %q", got)
+			}
+			var resp struct {
+				Data dto.TokenDTO `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			expected := dto.TokenDTO{
+				User:             dto.MapUserToDTO(testTokenPair.User),
+				TokenType:        "Bearer",
+				AccessToken:      "access",
+				AccessExpiresAt:  "2026-10-18T09:15:00Z",
+				RefreshToken:     "refresh",
+				RefreshExpiresAt: "2026-11-17T09:00:00Z",
+			}
+			if resp.Data != expected {
+				t.Errorf("expected %+v, got %+v", expected, resp.Data)
+			}
+		})
+	}
+}
+
+func TestTokenHandler_Refresh(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should refresh the pair", body: `{"refresh_token":"refresh"}`, expectedStatus: http.StatusOK},
+		{name: "should reject a reused token", body: `{"refresh_token":"refresh"}`, svcErr: fmt.Errorf("refresh: %w", domain.ErrRefreshTokenReused), expectedStatus: http.StatusUnauthorized},
+		{name: "should reject an invalid token", body: `{"refresh_token":"refresh"}`, svcErr: domain.ErrInvalidToken, expectedStatus: http.StatusUnauthorized},
+		{name: "should reject a malformed body", body: `{"refresh_token":1}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTokenService{
+				refreshFunc: func(ctx context.Context, token string) (domain.TokenPair, error) {
+					if token != "refresh" {
+						t.Errorf("expected token %q, got %q", "refresh", token)
+					}
+					return testTokenPair, tc.svcErr
+				},
+			}
+			mux := NewTokenHandler(slog.Default(), svc).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/token/refresh", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTokenHandler_Revoke(t *testing.T) {
+	var revoked string
+	svc := &mockTokenService{
+		revokeFunc: func(ctx context.Context, token string) error {
+			revoked = token
+			return nil
+		},
+	}
+	mux := NewTokenHandler(slog.Default(), svc).RegisterRoutes()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/token/revoke", strings.NewReader(`{"refresh_token":"refresh"}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent || revoked != "refresh" {
+		t.Fatalf("expected the token to be revoked with status %d, got %d and %q", http.StatusNoContent, w.Code, revoked)
+	}
+}
@@ -0,0 +1,148 @@
+package httphandler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/ical"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+	"github.com/mkeOrt/tasks-go/internal/transport/response"
+)
+
+// calendarFeedPath is where calendar clients read a feed. The token goes in
+// the query rather than the path so it stays out of the request log.
+const calendarFeedPath = "/api/calendar/tasks.ics"
+
+// CalendarService defines the business logic interface for calendar feeds.
+type CalendarService interface {
+	GetAll(ctx context.Context) ([]domain.CalendarFeed, error)
+	Create(ctx context.Context, feed domain.CalendarFeed) (domain.CalendarFeed, string, error)
+	Delete(ctx context.Context, id int64) error
+	Open(ctx context.Context, token string) (domain.CalendarFeed, error)
+	Tasks(ctx context.Context, feed domain.CalendarFeed, fn func(domain.Task) error) error
+}
+
+// CalendarHandler handles HTTP requests for iCalendar feeds of the tasks.
+type CalendarHandler struct {
+	logger    *slog.Logger
+	svc       CalendarService
+	uidDomain string
+}
+
+// NewCalendarHandler creates a new CalendarHandler whose task UIDs end in
+// @uidDomain.
+func NewCalendarHandler(logger *slog.Logger, svc CalendarService, uidDomain string) *CalendarHandler {
+	return &CalendarHandler{
+		logger:    logger,
+		svc:       svc,
+		uidDomain: uidDomain,
+	}
+}
+
+// RegisterRoutes returns the calendar routes.
+func (h *CalendarHandler) RegisterRoutes() *http.ServeMux {
+	g := http.NewServeMux()
+	g.HandleFunc("GET /api/calendar/feeds", h.GetAll)
+	g.HandleFunc("POST /api/calendar/feeds", h.Create)
+	g.HandleFunc("DELETE /api/calendar/feeds/{id}", h.Delete)
+	g.HandleFunc("GET "+calendarFeedPath, h.Feed)
+	return g
+}
+
+func (h *CalendarHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	feeds, err := h.svc.GetAll(r.Context())
+	if err != nil {
+		h.logger.Error("failed to get calendar feeds", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusOK, dto.CalendarFeedsResponse{Feeds: dto.MapCalendarFeedsToDTO(feeds)})
+}
+
+// Create serves POST /api/calendar/feeds. The response carries the feed's
+// secret token and URL, which are not shown again.
+func (h *CalendarHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CalendarFeedRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	feed, token, err := h.svc.Create(r.Context(), req.ToDomain())
+	if err != nil {
+		h.logger.Error("failed to create calendar feed", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusCreated, dto.CreatedCalendarFeedDTO{
+		CalendarFeedDTO: dto.MapCalendarFeedToDTO(feed),
+		Token:           token,
+		URL:             calendarFeedPath + "?" + url.Values{"token": {token}}.Encode(),
+	})
+}
+
+// Delete serves DELETE /api/calendar/feeds/{id}, revoking the feed's URL.
+func (h *CalendarHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", response.ErrMsgInvalidCalendarFeedID)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		h.logger.Error("failed to delete calendar feed", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Feed serves GET /api/calendar/tasks.ics?token=..., streaming the tasks
+// with a due time as VTODO components. The token is the only credential,
+// since calendar clients cannot send auth headers.
+func (h *CalendarHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.svc.Open(r.Context(), r.URL.Query().Get("token"))
+	if err != nil {
+		h.logger.Error("failed to open calendar feed", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	cw := ical.NewWriter(w, h.uidDomain)
+	started := false
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", `inline; filename="tasks.ics"`)
+		w.Header().Set("Cache-Control", response.CacheControl)
+		return cw.Begin(feed.Name)
+	}
+	err = h.svc.Tasks(r.Context(), feed, func(task domain.Task) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return cw.Write(task)
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err == nil {
+		err = cw.End()
+	}
+	if err != nil {
+		h.logger.Error("failed to write calendar feed", slog.Int64("id", feed.ID), slog.String("error", err.Error()))
+		if !started {
+			response.RespondWithError(w, err)
+			return
+		}
+		// Headers are out: drop the connection so the client keeps its copy
+		// rather than replacing it with a truncated calendar.
+		panic(http.ErrAbortHandler)
+	}
+}
@@ -0,0 +1,174 @@
+package httphandler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+)
+
+type mockCalendarService struct {
+	getAllFunc func(ctx context.Context) ([]domain.CalendarFeed, error)
+	createFunc func(ctx context.Context, feed domain.CalendarFeed) (domain.CalendarFeed, string, error)
+	deleteFunc func(ctx context.Context, id int64) error
+	openFunc   func(ctx context.Context, token string) (domain.CalendarFeed, error)
+	tasksFunc  func(ctx context.Context, feed domain.CalendarFeed, fn func(domain.Task) error) error
+}
+
+func (m *mockCalendarService) GetAll(ctx context.Context) ([]domain.CalendarFeed, error) {
+	return m.getAllFunc(ctx)
+}
+
+func (m *mockCalendarService) Create(ctx context.Context, feed domain.CalendarFeed) (domain.CalendarFeed, string, error) {
+	return m.createFunc(ctx, feed)
+}
+
+func (m *mockCalendarService) Delete(ctx context.Context, id int64) error {
+	return m.deleteFunc(ctx, id)
+}
+
+func (m *mockCalendarService) Open(ctx context.Context, token string) (domain.CalendarFeed, error) {
+	return m.openFunc(ctx, token)
+}
+
+func (m *mockCalendarService) Tasks(ctx context.Context, feed domain.CalendarFeed, fn func(domain.Task) error) error {
+	return m.tasksFunc(ctx, feed, fn)
+}
+
+func TestCalendarHandler_Create(t *testing.T) {
+	created := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	svc := &mockCalendarService{
+		createFunc: func(ctx context.Context, feed domain.CalendarFeed) (domain.CalendarFeed, string, error) {
+			if feed.Name != "Phone" {
+				t.Errorf("expected name Phone, got %q", feed.Name)
+			}
+			return domain.CalendarFeed{ID: 1, Name: feed.Name, CreatedAt: created}, "s3cr-et_", nil
+		},
+	}
+	mux := NewCalendarHandler(slog.Default(), svc, "example.com").RegisterRoutes()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/calendar/feeds", strings.NewReader(`{"name":"Phone"}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data dto.CreatedCalendarFeedDTO `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data.ID != 1 || resp.Data.Token != "s3cr-et_" || resp.Data.URL != "/api/calendar/tasks.ics?token=s3cr-et_" {
+		t.Errorf("unexpected feed %+v", resp.Data)
+	}
+}
+
+func TestCalendarHandler_Delete(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should revoke the feed", path: "/api/calendar/feeds/1", expectedStatus: http.StatusNoContent},
+		{name: "should return not found", path: "/api/calendar/feeds/1", svcErr: domain.ErrCalendarFeedNotFound, expectedStatus: http.StatusNotFound},
+		{name: "should reject an invalid ID", path: "/api/calendar/feeds/abc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockCalendarService{
+				deleteFunc: func(ctx context.Context, id int64) error { return tc.svcErr },
+			}
+			mux := NewCalendarHandler(slog.Default(), svc, "example.com").RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodDelete, tc.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestCalendarHandler_Feed(t *testing.T) {
+	due := time.Date(2026, 10, 20, 17, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		token          string
+		tasksErr       error
+		expectedStatus int
+		expectedLines  []string
+	}{
+		{
+			name:           "should render the tasks as VTODOs",
+			token:          "good",
+			expectedStatus: http.StatusOK,
+			expectedLines: []string{
+				"BEGIN:VCALENDAR", "X-WR-CALNAME:Phone",
+				"UID:task-7@example.com", "SUMMARY:Ship", "DUE:20261020T173000Z", "STATUS:NEEDS-ACTION",
+				"END:VCALENDAR",
+			},
+		},
+		{name: "should reject an unknown token", token: "bad", expectedStatus: http.StatusNotFound},
+		{name: "should reject a missing token", expectedStatus: http.StatusNotFound},
+		{name: "should report a failure before any output", token: "good", tasksErr: domain.ErrTaskRetrievalFailed, expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockCalendarService{
+				openFunc: func(ctx context.Context, token string) (domain.CalendarFeed, error) {
+					if token != "good" {
+						return domain.CalendarFeed{}, domain.ErrCalendarFeedNotFound
+					}
+					return domain.CalendarFeed{ID: 1, Name: "Phone"}, nil
+				},
+				tasksFunc: func(ctx context.Context, feed domain.CalendarFeed, fn func(domain.Task) error) error {
+					if tc.tasksErr != nil {
+						return tc.tasksErr
+					}
+					return fn(domain.Task{ID: 7, Title: "Ship", DueAt: &due, UpdatedAt: due, CreatedAt: due})
+				},
+			}
+			mux := NewCalendarHandler(slog.Default(), svc, "example.com").RegisterRoutes()
+
+			path := "/api/calendar/tasks.ics"
+			if tc.token != "" {
+				path += "?token=" + tc.token
+			}
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedLines == nil {
+				return
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "text/calendar; charset=utf-8" {
+				t.Errorf("unexpected Content-Type %s", ct)
+			}
+			lines := strings.Split(w.Body.String(), "\r\n")
+			for _, expected := range tc.expectedLines {
+				if !slices.Contains(lines, expected) {
+					t.Errorf("expected line %q in\n%s", expected, w.Body.String())
+				}
+			}
+		})
+	}
+}
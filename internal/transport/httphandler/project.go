@@ -0,0 +1,156 @@
+package httphandler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+	"github.com/mkeOrt/tasks-go/internal/transport/response"
+)
+
+// ProjectService defines the business logic interface for projects.
+type ProjectService interface {
+	GetAll(ctx context.Context) ([]domain.Project, error)
+	GetByID(ctx context.Context, id int64) (domain.Project, error)
+	Create(ctx context.Context, project domain.Project) (domain.Project, error)
+	Update(ctx context.Context, project domain.Project) (domain.Project, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// ProjectHandler handles HTTP requests for projects.
+type ProjectHandler struct {
+	logger *slog.Logger
+	svc    ProjectService
+	tasks  *TaskHandler
+}
+
+// NewProjectHandler creates a new ProjectHandler. tasks serves the task
+// lists nested under a project.
+func NewProjectHandler(logger *slog.Logger, svc ProjectService, tasks *TaskHandler) *ProjectHandler {
+	return &ProjectHandler{
+		logger: logger,
+		svc:    svc,
+		tasks:  tasks,
+	}
+}
+
+func (h *ProjectHandler) RegisterRoutes() *http.ServeMux {
+	g := http.NewServeMux()
+	g.HandleFunc("GET /api/projects", h.GetAll)
+	g.HandleFunc("POST /api/projects", h.Create)
+	g.HandleFunc("GET /api/projects/{id}", h.GetByID)
+	g.HandleFunc("PUT /api/projects/{id}", h.Update)
+	g.HandleFunc("DELETE /api/projects/{id}", h.Delete)
+	g.HandleFunc("GET /api/projects/{id}/tasks", h.Tasks)
+	return g
+}
+
+func (h *ProjectHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	projects, err := h.svc.GetAll(r.Context())
+	if err != nil {
+		h.logger.Error("failed to get all projects", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusOK, dto.ProjectsResponse{Projects: dto.MapProjectsToDTO(projects)})
+}
+
+func (h *ProjectHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+
+	project, err := h.svc.GetByID(r.Context(), id)
+	if err != nil {
+		h.logger.Error("failed to get project", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusOK, dto.MapProjectToDTO(project))
+}
+
+func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.ProjectRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	project, err := h.svc.Create(r.Context(), req.ToDomain(0))
+	if err != nil {
+		h.logger.Error("failed to create project", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusCreated, dto.MapProjectToDTO(project))
+}
+
+func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.ProjectRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	project, err := h.svc.Update(r.Context(), req.ToDomain(id))
+	if err != nil {
+		h.logger.Error("failed to update project", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusOK, dto.MapProjectToDTO(project))
+}
+
+func (h *ProjectHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		h.logger.Error("failed to delete project", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Tasks serves GET /api/projects/{id}/tasks. It accepts the same query
+// parameters as GET /api/tasks, with project_id fixed to the path value.
+func (h *ProjectHandler) Tasks(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+
+	filter, err := h.tasks.parseTaskFilter(r.URL.Query())
+	if err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+
+	if _, err := h.svc.GetByID(r.Context(), id); err != nil {
+		h.logger.Error("failed to get project", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	filter.ProjectID = &id
+	h.tasks.list(w, r, filter)
+}
+
+// parseProjectID reads the {id} path value, writing a 400 response when it is invalid.
+func parseProjectID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	return parsePathID(w, r, "id", response.ErrMsgInvalidProjectID)
+}
@@ -0,0 +1,176 @@
+package httphandler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+)
+
+type mockProjectService struct {
+	getAllFunc  func(ctx context.Context) ([]domain.Project, error)
+	getByIDFunc func(ctx context.Context, id int64) (domain.Project, error)
+	createFunc  func(ctx context.Context, project domain.Project) (domain.Project, error)
+	updateFunc  func(ctx context.Context, project domain.Project) (domain.Project, error)
+	deleteFunc  func(ctx context.Context, id int64) error
+}
+
+func (m *mockProjectService) GetAll(ctx context.Context) ([]domain.Project, error) {
+	return m.getAllFunc(ctx)
+}
+
+func (m *mockProjectService) GetByID(ctx context.Context, id int64) (domain.Project, error) {
+	return m.getByIDFunc(ctx, id)
+}
+
+func (m *mockProjectService) Create(ctx context.Context, project domain.Project) (domain.Project, error) {
+	return m.createFunc(ctx, project)
+}
+
+func (m *mockProjectService) Update(ctx context.Context, project domain.Project) (domain.Project, error) {
+	return m.updateFunc(ctx, project)
+}
+
+func (m *mockProjectService) Delete(ctx context.Context, id int64) error {
+	return m.deleteFunc(ctx, id)
+}
+
+func TestProjectHandler_GetAll(t *testing.T) {
+	svc := &mockProjectService{
+		getAllFunc: func(ctx context.Context) ([]domain.Project, error) {
+			return []domain.Project{{ID: 1, Name: "Inbox", Inbox: true}}, nil
+		},
+	}
+	mux := NewProjectHandler(slog.Default(), svc, nil).RegisterRoutes()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/projects", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp struct {
+		Data dto.ProjectsResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	expected := []dto.ProjectDTO{{ID: 1, Name: "Inbox", Inbox: true, CreatedAt: "0001-01-01T00:00:00Z", UpdatedAt: "0001-01-01T00:00:00Z"}}
+	if !reflect.DeepEqual(resp.Data.Projects, expected) {
+		t.Errorf("expected projects %v, got %v", expected, resp.Data.Projects)
+	}
+}
+
+func TestProjectHandler_Create(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "should create project", body: `{"name":"Work","description":"Office"}`, expectedStatus: http.StatusCreated},
+		{name: "should reject the inbox flag", body: `{"name":"Work","inbox":true}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockProjectService{
+				createFunc: func(ctx context.Context, project domain.Project) (domain.Project, error) {
+					project.ID = 2
+					return project, nil
+				},
+			}
+			mux := NewProjectHandler(slog.Default(), svc, nil).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/projects", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestProjectHandler_Delete(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should delete project", path: "/api/projects/2", expectedStatus: http.StatusNoContent},
+		{name: "should report a project with tasks", path: "/api/projects/2", svcErr: domain.ErrProjectNotEmpty, expectedStatus: http.StatusConflict},
+		{name: "should refuse to delete the inbox", path: "/api/projects/2", svcErr: domain.ErrInboxProjectDelete, expectedStatus: http.StatusConflict},
+		{name: "should return not found", path: "/api/projects/2", svcErr: domain.ErrProjectNotFound, expectedStatus: http.StatusNotFound},
+		{name: "should reject invalid id", path: "/api/projects/abc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockProjectService{
+				deleteFunc: func(ctx context.Context, id int64) error {
+					return tc.svcErr
+				},
+			}
+			mux := NewProjectHandler(slog.Default(), svc, nil).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodDelete, tc.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestProjectHandler_Tasks(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		projectErr     error
+		expectedStatus int
+	}{
+		{name: "should list the project's tasks", path: "/api/projects/4/tasks?done=false&project_id=9", expectedStatus: http.StatusOK},
+		{name: "should return project not found", path: "/api/projects/4/tasks", projectErr: domain.ErrProjectNotFound, expectedStatus: http.StatusNotFound},
+		{name: "should reject invalid query parameters", path: "/api/projects/4/tasks?done=maybe", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tasks := &mockTaskService{
+				getAllFunc: func(ctx context.Context, filter domain.TaskFilter) (domain.TaskPage, error) {
+					if filter.ProjectID == nil || *filter.ProjectID != 4 || filter.Done == nil || *filter.Done {
+						t.Errorf("unexpected filter %+v", filter)
+					}
+					return domain.TaskPage{Tasks: []domain.Task{{ID: 1, ProjectID: filter.ProjectID}}, Total: 1}, nil
+				},
+			}
+			svc := &mockProjectService{
+				getByIDFunc: func(ctx context.Context, id int64) (domain.Project, error) {
+					return domain.Project{ID: id}, tc.projectErr
+				},
+			}
+			mux := NewProjectHandler(slog.Default(), svc, NewTaskHandler(slog.Default(), tasks, testCursors, false)).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package httphandler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+	"github.com/mkeOrt/tasks-go/internal/transport/response"
+)
+
+// TagService defines the business logic interface for tags.
+type TagService interface {
+	GetAll(ctx context.Context) ([]domain.Tag, error)
+	GetByID(ctx context.Context, id int64) (domain.Tag, error)
+	Create(ctx context.Context, tag domain.Tag) (domain.Tag, error)
+	Update(ctx context.Context, tag domain.Tag) (domain.Tag, error)
+	Delete(ctx context.Context, id int64) error
+	Attach(ctx context.Context, taskID, tagID int64) error
+	Detach(ctx context.Context, taskID, tagID int64) error
+}
+
+// TagHandler handles HTTP requests for tags and their assignment to tasks.
+type TagHandler struct {
+	logger *slog.Logger
+	svc    TagService
+}
+
+// NewTagHandler creates a new TagHandler.
+func NewTagHandler(logger *slog.Logger, svc TagService) *TagHandler {
+	return &TagHandler{
+		logger: logger,
+		svc:    svc,
+	}
+}
+
+// RegisterRoutes returns the tag routes, including the task tag assignment
+// routes under /api/tasks/{id}/tags/{tag_id}.
+func (h *TagHandler) RegisterRoutes() *http.ServeMux {
+	g := http.NewServeMux()
+	g.HandleFunc("GET /api/tags", h.GetAll)
+	g.HandleFunc("POST /api/tags", h.Create)
+	g.HandleFunc("GET /api/tags/{id}", h.GetByID)
+	g.HandleFunc("PUT /api/tags/{id}", h.Update)
+	g.HandleFunc("DELETE /api/tags/{id}", h.Delete)
+	g.HandleFunc("PUT /api/tasks/{id}/tags/{tag_id}", h.Attach)
+	g.HandleFunc("DELETE /api/tasks/{id}/tags/{tag_id}", h.Detach)
+	return g
+}
+
+func (h *TagHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.svc.GetAll(r.Context())
+	if err != nil {
+		h.logger.Error("failed to get all tags", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusOK, dto.TagsResponse{Tags: dto.MapTagsToDTO(tags)})
+}
+
+func (h *TagHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTagID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	tag, err := h.svc.GetByID(r.Context(), id)
+	if err != nil {
+		h.logger.Error("failed to get tag", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusOK, dto.MapTagToDTO(tag))
+}
+
+func (h *TagHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.TagRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	tag, err := h.svc.Create(r.Context(), req.ToDomain(0))
+	if err != nil {
+		h.logger.Error("failed to create tag", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusCreated, dto.MapTagToDTO(tag))
+}
+
+func (h *TagHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTagID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.TagRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	tag, err := h.svc.Update(r.Context(), req.ToDomain(id))
+	if err != nil {
+		h.logger.Error("failed to update tag", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusOK, dto.MapTagToDTO(tag))
+}
+
+func (h *TagHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTagID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		h.logger.Error("failed to delete tag", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Attach serves PUT /api/tasks/{id}/tags/{tag_id}. It is idempotent.
+func (h *TagHandler) Attach(w http.ResponseWriter, r *http.Request) {
+	h.assign(w, r, "attach", h.svc.Attach)
+}
+
+// Detach serves DELETE /api/tasks/{id}/tags/{tag_id}. It is idempotent.
+func (h *TagHandler) Detach(w http.ResponseWriter, r *http.Request) {
+	h.assign(w, r, "detach", h.svc.Detach)
+}
+
+func (h *TagHandler) assign(w http.ResponseWriter, r *http.Request, action string, fn func(ctx context.Context, taskID, tagID int64) error) {
+	taskID, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+	tagID, ok := parseTagID(w, r, "tag_id")
+	if !ok {
+		return
+	}
+
+	if err := fn(r.Context(), taskID, tagID); err != nil {
+		h.logger.Error("failed to "+action+" tag",
+			slog.Int64("task_id", taskID), slog.Int64("tag_id", tagID), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseTagID reads a tag ID path value, writing a 400 response when it is invalid.
+func parseTagID(w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	return parsePathID(w, r, name, response.ErrMsgInvalidTagID)
+}
@@ -0,0 +1,204 @@
+package httphandler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+)
+
+type mockTagService struct {
+	getAllFunc  func(ctx context.Context) ([]domain.Tag, error)
+	getByIDFunc func(ctx context.Context, id int64) (domain.Tag, error)
+	createFunc  func(ctx context.Context, tag domain.Tag) (domain.Tag, error)
+	updateFunc  func(ctx context.Context, tag domain.Tag) (domain.Tag, error)
+	deleteFunc  func(ctx context.Context, id int64) error
+	attachFunc  func(ctx context.Context, taskID, tagID int64) error
+	detachFunc  func(ctx context.Context, taskID, tagID int64) error
+}
+
+func (m *mockTagService) GetAll(ctx context.Context) ([]domain.Tag, error) {
+	return m.getAllFunc(ctx)
+}
+
+func (m *mockTagService) GetByID(ctx context.Context, id int64) (domain.Tag, error) {
+	return m.getByIDFunc(ctx, id)
+}
+
+func (m *mockTagService) Create(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+	return m.createFunc(ctx, tag)
+}
+
+func (m *mockTagService) Update(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+	return m.updateFunc(ctx, tag)
+}
+
+func (m *mockTagService) Delete(ctx context.Context, id int64) error {
+	return m.deleteFunc(ctx, id)
+}
+
+func (m *mockTagService) Attach(ctx context.Context, taskID, tagID int64) error {
+	return m.attachFunc(ctx, taskID, tagID)
+}
+
+func (m *mockTagService) Detach(ctx context.Context, taskID, tagID int64) error {
+	return m.detachFunc(ctx, taskID, tagID)
+}
+
+func TestTagHandler_GetAll(t *testing.T) {
+	svc := &mockTagService{
+		getAllFunc: func(ctx context.Context) ([]domain.Tag, error) {
+			return []domain.Tag{{ID: 1, Name: "backend"}}, nil
+		},
+	}
+	mux := NewTagHandler(slog.Default(), svc).RegisterRoutes()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tags", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp struct {
+		Data dto.TagsResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	expected := []dto.TagDTO{{ID: 1, Name: "backend", CreatedAt: "0001-01-01T00:00:00Z", UpdatedAt: "0001-01-01T00:00:00Z"}}
+	if !reflect.DeepEqual(resp.Data.Tags, expected) {
+		t.Errorf("expected tags %v, got %v", expected, resp.Data.Tags)
+	}
+}
+
+func TestTagHandler_Create(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should create tag", body: `{"name":"backend"}`, expectedStatus: http.StatusCreated},
+		{name: "should reject unknown fields", body: `{"name":"backend","color":"red"}`, expectedStatus: http.StatusBadRequest},
+		{name: "should report duplicate names", body: `{"name":"backend"}`, svcErr: domain.ErrTagAlreadyExists, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTagService{
+				createFunc: func(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+					tag.ID = 1
+					return tag, tc.svcErr
+				},
+			}
+			mux := NewTagHandler(slog.Default(), svc).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/tags", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestTagHandler_Update(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should rename tag", path: "/api/tags/4", expectedStatus: http.StatusOK},
+		{name: "should return not found", path: "/api/tags/4", svcErr: domain.ErrTagNotFound, expectedStatus: http.StatusNotFound},
+		{name: "should reject invalid id", path: "/api/tags/abc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTagService{
+				updateFunc: func(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+					if tag.ID != 4 || tag.Name != "frontend" {
+						t.Errorf("unexpected tag %+v", tag)
+					}
+					return tag, tc.svcErr
+				},
+			}
+			mux := NewTagHandler(slog.Default(), svc).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPut, tc.path, strings.NewReader(`{"name":"frontend"}`))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestTagHandler_Delete(t *testing.T) {
+	svc := &mockTagService{
+		deleteFunc: func(ctx context.Context, id int64) error {
+			return nil
+		},
+	}
+	mux := NewTagHandler(slog.Default(), svc).RegisterRoutes()
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/tags/1", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+}
+
+func TestTagHandler_Assign(t *testing.T) {
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should attach tag", method: http.MethodPut, path: "/api/tasks/3/tags/7", expectedStatus: http.StatusNoContent},
+		{name: "should detach tag", method: http.MethodDelete, path: "/api/tasks/3/tags/7", expectedStatus: http.StatusNoContent},
+		{name: "should return task not found", method: http.MethodPut, path: "/api/tasks/3/tags/7", svcErr: domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+		{name: "should return tag not found", method: http.MethodDelete, path: "/api/tasks/3/tags/7", svcErr: domain.ErrTagNotFound, expectedStatus: http.StatusNotFound},
+		{name: "should reject invalid task id", method: http.MethodPut, path: "/api/tasks/x/tags/7", expectedStatus: http.StatusBadRequest},
+		{name: "should reject invalid tag id", method: http.MethodPut, path: "/api/tasks/3/tags/0", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assign := func(ctx context.Context, taskID, tagID int64) error {
+				if taskID != 3 || tagID != 7 {
+					t.Errorf("unexpected ids %d, %d", taskID, tagID)
+				}
+				return tc.svcErr
+			}
+			svc := &mockTagService{attachFunc: assign, detachFunc: assign}
+			mux := NewTagHandler(slog.Default(), svc).RegisterRoutes()
+
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
@@ -2,46 +2,493 @@ package httphandler
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/taskmd"
+	"github.com/mkeOrt/tasks-go/internal/transport/cursor"
 	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+	"github.com/mkeOrt/tasks-go/internal/transport/middleware"
 	"github.com/mkeOrt/tasks-go/internal/transport/response"
+	"github.com/mkeOrt/tasks-go/internal/validation"
 )
 
 // TaskService defines the business logic interface for tasks.
 type TaskService interface {
-	GetAll(ctx context.Context) ([]domain.Task, error)
+	GetAll(ctx context.Context, filter domain.TaskFilter) (domain.TaskPage, error)
+	Search(ctx context.Context, query string, filter domain.TaskFilter) ([]domain.TaskSearchResult, error)
+	GetByID(ctx context.Context, id int64) (domain.Task, error)
+	Create(ctx context.Context, task domain.Task) (domain.Task, error)
+	Update(ctx context.Context, task domain.Task) (domain.Task, error)
+	Patch(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error)
+	Delete(ctx context.Context, id, version int64) error
+	GetTree(ctx context.Context, id int64) (domain.TaskNode, error)
+	AddBlocker(ctx context.Context, taskID, blockerID int64) error
+	RemoveBlocker(ctx context.Context, taskID, blockerID int64) error
+	Blockers(ctx context.Context, id int64) ([]domain.Task, error)
+	Occurrences(ctx context.Context, id int64, n int) ([]time.Time, error)
+	History(ctx context.Context, id int64, limit, offset int) (domain.TaskEventPage, error)
+	Batch(ctx context.Context, ops []domain.TaskOperation, continueOnError bool) (domain.TaskBatchResult, error)
+	Export(ctx context.Context, filter domain.TaskFilter, fn func(domain.Task) error) error
+	Import(ctx context.Context, ops []domain.TaskOperation, dryRun bool) (domain.TaskBatchResult, error)
 }
 
 // TaskHandler handles HTTP requests for tasks.
 type TaskHandler struct {
-	logger *slog.Logger
-	svc    TaskService
+	logger         *slog.Logger
+	svc            TaskService
+	cursors        *cursor.Codec
+	requireIfMatch bool
 }
 
-// NewTaskHandler creates a new TaskHandler.
-func NewTaskHandler(logger *slog.Logger, svc TaskService) *TaskHandler {
+// NewTaskHandler creates a new TaskHandler. cursors signs the pagination
+// tokens returned by the list endpoint. requireIfMatch rejects writes to an
+// existing task that do not send an If-Match header.
+func NewTaskHandler(logger *slog.Logger, svc TaskService, cursors *cursor.Codec, requireIfMatch bool) *TaskHandler {
 	return &TaskHandler{
-		logger: logger,
-		svc:    svc,
+		logger:         logger,
+		svc:            svc,
+		cursors:        cursors,
+		requireIfMatch: requireIfMatch,
 	}
 }
 
+// RegisterRoutes returns the task routes. API keys reach them with the
+// tasks:read scope for reads and tasks:write for writes.
 func (h *TaskHandler) RegisterRoutes() *http.ServeMux {
 	g := http.NewServeMux()
-	g.HandleFunc("GET /", h.GetAll)
+	read, write := domain.ScopeTasksRead, domain.ScopeTasksWrite
+	g.Handle("GET /api/tasks", scoped(read, h.GetAll))
+	g.Handle("POST /api/tasks", scoped(write, h.Create))
+	g.Handle("POST /api/tasks/batch", scoped(write, h.Batch))
+	g.Handle("GET /api/tasks/export.csv", scoped(read, h.ExportCSV))
+	g.Handle("POST /api/tasks/import", scoped(write, h.ImportCSV))
+	g.Handle("GET /api/tasks/export.md", scoped(read, h.ExportMarkdown))
+	g.Handle("POST /api/tasks/import/markdown", scoped(write, h.ImportMarkdown))
+	g.Handle("GET /api/tasks/{id}", scoped(read, h.GetByID))
+	g.Handle("PUT /api/tasks/{id}", scoped(write, h.Update))
+	g.Handle("PATCH /api/tasks/{id}", scoped(write, h.Patch))
+	g.Handle("DELETE /api/tasks/{id}", scoped(write, h.Delete))
+	g.Handle("GET /api/tasks/{id}/tree", scoped(read, h.GetTree))
+	g.Handle("GET /api/tasks/{id}/blockers", scoped(read, h.GetBlockers))
+	g.Handle("PUT /api/tasks/{id}/blockers/{blocker_id}", scoped(write, h.AddBlocker))
+	g.Handle("DELETE /api/tasks/{id}/blockers/{blocker_id}", scoped(write, h.RemoveBlocker))
+	g.Handle("GET /api/tasks/{id}/occurrences", scoped(read, h.GetOccurrences))
+	g.Handle("GET /api/tasks/{id}/history", scoped(read, h.GetHistory))
 	return g
 }
 
+// scoped wraps a route so that requests made with an API key need scope to
+// reach it.
+func scoped(scope domain.APIKeyScope, handler http.HandlerFunc) http.Handler {
+	return middleware.RequireScope(scope)(handler)
+}
+
 func (h *TaskHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	tasks, err := h.svc.GetAll(r.Context())
+	filter, err := h.parseTaskFilter(r.URL.Query())
+	if err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+
+	h.list(w, r, filter)
+}
+
+// list serves a task list for filter, or the search results when the q
+// parameter is present. Clients sending Accept: text/markdown get the page
+// as a Markdown task list instead of JSON.
+func (h *TaskHandler) list(w http.ResponseWriter, r *http.Request, filter domain.TaskFilter) {
+	w.Header().Add("Vary", "Accept")
+	markdown := response.Negotiate(r, "application/json", taskmd.ContentType) == taskmd.ContentType
+	if r.URL.Query().Has("q") {
+		h.search(w, r, filter, markdown)
+		return
+	}
+
+	page, err := h.svc.GetAll(r.Context(), filter)
 	if err != nil {
 		h.logger.Error("failed to get all tasks", slog.String("error", err.Error()))
 		response.RespondWithError(w, err)
 		return
 	}
-	dtos := dto.MapTasksToDTO(tasks)
-	response.RespondWithJson(w, http.StatusOK, dto.TasksResponse{Tasks: dtos})
+	// Lists carry no Last-Modified: deleting a task, or one leaving the
+	// page, changes the list without changing any task left in it. The ETag
+	// hashed from the body catches both.
+	if markdown {
+		h.respondMarkdown(w, r, page.Tasks, response.Validators{})
+		return
+	}
+	dtos := dto.MapTasksToDTO(page.Tasks)
+	resp := dto.TasksResponse{
+		Tasks:  dtos,
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+	if resp.NextCursor, err = h.encodeCursor(page.NextCursor); err != nil {
+		h.logger.Error("failed to encode cursor", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+	if resp.PrevCursor, err = h.encodeCursor(page.PrevCursor); err != nil {
+		h.logger.Error("failed to encode cursor", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+	response.RespondWithConditionalJson(w, r, resp, response.Validators{})
+}
+
+// search serves GET /api/tasks?q=..., ranking matches by relevance.
+func (h *TaskHandler) search(w http.ResponseWriter, r *http.Request, filter domain.TaskFilter, markdown bool) {
+	results, err := h.svc.Search(r.Context(), r.URL.Query().Get("q"), filter)
+	if err != nil {
+		h.logger.Error("failed to search tasks", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+	if markdown {
+		tasks := make([]domain.Task, len(results))
+		for i, res := range results {
+			tasks[i] = res.Task
+		}
+		h.respondMarkdown(w, r, tasks, response.Validators{})
+		return
+	}
+
+	response.RespondWithConditionalJson(w, r, dto.TaskSearchResponse{Results: dto.MapSearchResultsToDTO(results)}, response.Validators{})
+}
+
+func (h *TaskHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	task, err := h.svc.GetByID(r.Context(), id)
+	if err != nil {
+		h.logger.Error("failed to get task", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithConditionalJson(w, r, dto.MapTaskToDTO(task), response.Validators{
+		ETag:         taskETag(task),
+		LastModified: task.UpdatedAt,
+	})
+}
+
+func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateTaskRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	task, err := req.ToDomain()
+	if err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+
+	task, err = h.svc.Create(r.Context(), task)
+	if err != nil {
+		h.logger.Error("failed to create task", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task))
+	response.RespondWithJson(w, http.StatusCreated, dto.MapTaskToDTO(task))
+}
+
+func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateTaskRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	task, err := req.ToDomain(id)
+	if err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+	if task.Version, err = h.ifMatchVersion(r); err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+
+	task, err = h.svc.Update(r.Context(), task)
+	if err != nil {
+		h.logger.Error("failed to update task", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task))
+	response.RespondWithJson(w, http.StatusOK, dto.MapTaskToDTO(task))
+}
+
+func (h *TaskHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.PatchTaskRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	patch, err := req.ToDomain()
+	if err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+	if patch.Version, err = h.ifMatchVersion(r); err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+
+	task, err := h.svc.Patch(r.Context(), id, patch)
+	if err != nil {
+		h.logger.Error("failed to patch task", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task))
+	response.RespondWithJson(w, http.StatusOK, dto.MapTaskToDTO(task))
+}
+
+func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	version, err := h.ifMatchVersion(r)
+	if err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id, version); err != nil {
+		h.logger.Error("failed to delete task", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetTree serves the task with its subtasks nested below it.
+func (h *TaskHandler) GetTree(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	tree, err := h.svc.GetTree(r.Context(), id)
+	if err != nil {
+		h.logger.Error("failed to get task tree", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusOK, dto.MapTaskTreeToDTO(tree))
+}
+
+// GetBlockers serves the tasks the task depends on.
+func (h *TaskHandler) GetBlockers(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	blockers, err := h.svc.Blockers(r.Context(), id)
+	if err != nil {
+		h.logger.Error("failed to get task blockers", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusOK, dto.BlockersResponse{Blockers: dto.MapTasksToDTO(blockers)})
+}
+
+// AddBlocker makes the task depend on {blocker_id}. Repeating the request
+// has no further effect.
+func (h *TaskHandler) AddBlocker(w http.ResponseWriter, r *http.Request) {
+	id, blockerID, ok := parseBlockerPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.AddBlocker(r.Context(), id, blockerID); err != nil {
+		h.logger.Error("failed to add task blocker", slog.Int64("id", id), slog.Int64("blocker_id", blockerID), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveBlocker drops the dependency of the task on {blocker_id}.
+func (h *TaskHandler) RemoveBlocker(w http.ResponseWriter, r *http.Request) {
+	id, blockerID, ok := parseBlockerPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.RemoveBlocker(r.Context(), id, blockerID); err != nil {
+		h.logger.Error("failed to remove task blocker", slog.Int64("id", id), slog.Int64("blocker_id", blockerID), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetOccurrences previews the upcoming occurrences of a recurring task,
+// starting with its current due date. The limit parameter sets how many.
+func (h *TaskHandler) GetOccurrences(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := parseOccurrenceLimit(r.URL.Query())
+	if err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+
+	occurrences, err := h.svc.Occurrences(r.Context(), id, limit)
+	if err != nil {
+		h.logger.Error("failed to get task occurrences", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusOK, dto.MapOccurrencesToDTO(occurrences))
+}
+
+// GetHistory serves a page of the task's change history, newest first. The
+// limit and offset parameters select the page.
+func (h *TaskHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, offset, err := parseHistoryPage(r.URL.Query())
+	if err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+
+	page, err := h.svc.History(r.Context(), id, limit, offset)
+	if err != nil {
+		h.logger.Error("failed to get task history", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusOK, dto.MapTaskHistoryToDTO(page))
+}
+
+// parseBlockerPath reads the {id} and {blocker_id} path values, writing a
+// 400 response when either is invalid.
+func parseBlockerPath(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	blockerID, ok := parsePathID(w, r, "blocker_id", response.ErrMsgInvalidTaskID)
+	if !ok {
+		return 0, 0, false
+	}
+	return id, blockerID, true
+}
+
+func (h *TaskHandler) encodeCursor(c *domain.TaskCursor) (string, error) {
+	if c == nil {
+		return "", nil
+	}
+	return h.cursors.Encode(*c)
+}
+
+// taskETag returns the entity tag of a task: its version, quoted.
+func taskETag(t domain.Task) string {
+	return `"` + strconv.FormatInt(t.Version, 10) + `"`
+}
+
+// ifMatchVersion reads the task version a write is conditioned on from the
+// If-Match header, which holds "*" or a single ETag returned by this API.
+// Zero means the write is unconditional. Any other value cannot match the
+// task and fails with domain.ErrTaskVersionConflict.
+func (h *TaskHandler) ifMatchVersion(r *http.Request) (int64, error) {
+	raw := strings.TrimSpace(r.Header.Get("If-Match"))
+	switch {
+	case raw == "" && h.requireIfMatch:
+		return 0, domain.ErrTaskVersionRequired
+	case raw == "" || raw == "*":
+		return 0, nil
+	}
+
+	unquoted, ok := strings.CutPrefix(raw, `"`)
+	if ok {
+		unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if !ok || err != nil || version <= 0 {
+		return 0, domain.ErrTaskVersionConflict
+	}
+	return version, nil
+}
+
+// parseTaskID reads the {id} path value, writing a 400 response when it is invalid.
+func parseTaskID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	return parsePathID(w, r, "id", response.ErrMsgInvalidTaskID)
+}
+
+// parsePathID reads a positive integer path value, writing a 400 response
+// with msg when it is invalid.
+func parsePathID(w http.ResponseWriter, r *http.Request, name, msg string) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil || id <= 0 {
+		response.RespondWithErrorJson(w, http.StatusBadRequest, msg)
+		return 0, false
+	}
+	return id, true
+}
+
+// maxJSONBytes caps the size of a JSON request body. It leaves room for a
+// batch of domain.MaxBatchOperations tasks with the longest titles and
+// descriptions.
+const maxJSONBytes = 4 << 20
+
+// decodeJSON decodes the request body into dst, writing a 400 response
+// listing every invalid field on failure, or a 413 one when the body is
+// larger than maxJSONBytes.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	err := validation.DecodeJSON(http.MaxBytesReader(w, r.Body, maxJSONBytes), dst)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		response.RespondWithErrorJson(w, http.StatusRequestEntityTooLarge, response.ErrMsgBodyTooLarge)
+		return false
+	}
+	if err != nil {
+		response.RespondWithError(w, err)
+		return false
+	}
+	return true
 }
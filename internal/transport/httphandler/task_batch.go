@@ -0,0 +1,82 @@
+package httphandler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+	"github.com/mkeOrt/tasks-go/internal/transport/response"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+// Batch serves POST /api/tasks/batch, running a list of task writes in one
+// transaction. A committed batch answers 200 with a result per operation,
+// failed ones included. A rolled back atomic batch answers with the status
+// of the operation that failed.
+func (h *TaskHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	var req dto.BatchTaskRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	ops, continueOnError, err := req.ToDomain(h.requireIfMatch)
+	if err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+
+	batch, err := h.svc.Batch(r.Context(), ops, continueOnError)
+	if err != nil {
+		h.logger.Error("failed to run task batch", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	resp := dto.TaskBatchResponse{
+		Committed: batch.Committed,
+		Results:   make([]dto.TaskOperationResultDTO, len(batch.Results)),
+	}
+	failed := -1
+	for i, res := range batch.Results {
+		resp.Results[i] = mapTaskOperationResult(i, ops[i].Kind, res)
+		if res.Err != nil && !errors.Is(res.Err, domain.ErrTaskOperationSkipped) && failed < 0 {
+			failed = i
+		}
+	}
+
+	if batch.Committed || failed < 0 {
+		response.RespondWithJson(w, http.StatusOK, resp)
+		return
+	}
+	response.ResponseWithJson(w, resp.Results[failed].Status, &response.Response{
+		Success: false,
+		Data:    resp,
+		Error:   resp.Results[failed].Error,
+	})
+}
+
+// mapTaskOperationResult maps the outcome of the operation at index to a DTO,
+// with the status its single-task endpoint would have answered.
+func mapTaskOperationResult(index int, kind domain.TaskOperationKind, res domain.TaskOperationResult) dto.TaskOperationResultDTO {
+	result := dto.TaskOperationResultDTO{Index: index, Op: string(kind)}
+	if res.Err != nil {
+		result.Status, result.Error = response.MapErrorToResponse(res.Err)
+		result.Errors = validation.Fields(res.Err)
+		return result
+	}
+
+	switch kind {
+	case domain.TaskOperationCreate:
+		result.Status = http.StatusCreated
+	case domain.TaskOperationDelete:
+		result.Status = http.StatusNoContent
+		return result
+	default:
+		result.Status = http.StatusOK
+	}
+	task := dto.MapTaskToDTO(res.Task)
+	result.Task = &task
+	return result
+}
@@ -0,0 +1,208 @@
+package httphandler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/taskcsv"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+	"github.com/mkeOrt/tasks-go/internal/transport/response"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+// maxImportBytes caps the size of an import file.
+const maxImportBytes = 10 << 20
+
+// ExportCSV serves GET /api/tasks/export.csv, streaming every task that
+// matches the list filters as CSV. Pagination parameters are ignored.
+func (h *TaskHandler) ExportCSV(w http.ResponseWriter, r *http.Request) {
+	filter, err := h.parseTaskFilter(r.URL.Query())
+	if err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+
+	cw := taskcsv.NewWriter(w)
+	h.export(w, r, filter, exportFile{
+		contentType: "text/csv; charset=utf-8",
+		name:        "tasks.csv",
+		begin:       cw.WriteHeader,
+		write:       cw.Write,
+		end:         cw.Flush,
+	})
+}
+
+// exportFile describes the file an export streams.
+type exportFile struct {
+	contentType string
+	name        string
+	// begin, when set, runs before the first task, even if there is none.
+	begin func() error
+	write func(domain.Task) error
+	end   func() error
+}
+
+// export streams every task matching filter as a file download. Output only
+// starts with the first task, so a failure to load the tasks can still be
+// answered with an error.
+func (h *TaskHandler) export(w http.ResponseWriter, r *http.Request, filter domain.TaskFilter, f exportFile) {
+	started := false
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", f.contentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="`+f.name+`"`)
+		if f.begin == nil {
+			return nil
+		}
+		return f.begin()
+	}
+	err := h.svc.Export(r.Context(), filter, func(task domain.Task) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return f.write(task)
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err == nil {
+		err = f.end()
+	}
+	if err != nil {
+		h.abortExport(w, started, err)
+	}
+}
+
+// abortExport handles an export that failed. Before any output the error
+// is answered as usual; afterwards the connection is dropped so the client
+// cannot mistake the partial file for a complete one.
+func (h *TaskHandler) abortExport(w http.ResponseWriter, started bool, err error) {
+	h.logger.Error("failed to export tasks", slog.String("error", err.Error()))
+	if !started {
+		response.RespondWithError(w, err)
+		return
+	}
+	panic(http.ErrAbortHandler)
+}
+
+// ImportCSV serves POST /api/tasks/import, importing the CSV in the body in
+// one transaction. map.<field>=<header> parameters name the column a field
+// is read from, and dry_run=true previews the import without keeping it.
+func (h *TaskHandler) ImportCSV(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	dryRun, err := parseDryRun(q)
+	if err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+	mapping := make(taskcsv.Mapping)
+	for key, values := range q {
+		if field, ok := strings.CutPrefix(key, "map."); ok {
+			mapping[field] = values[0]
+		}
+	}
+
+	rows, err := taskcsv.Read(http.MaxBytesReader(w, r.Body, maxImportBytes), mapping)
+	if err != nil {
+		respondImportReadError(w, err)
+		return
+	}
+
+	lines := make([]int, len(rows))
+	ops := make([]domain.TaskOperation, len(rows))
+	for i, row := range rows {
+		lines[i], ops[i] = row.Line, row.Op
+	}
+	h.importTasks(w, r, lines, ops, dryRun)
+}
+
+// parseDryRun reads the dry_run parameter of an import.
+func parseDryRun(q url.Values) (bool, error) {
+	raw := q.Get("dry_run")
+	if raw == "" {
+		return false, nil
+	}
+	dryRun, err := strconv.ParseBool(raw)
+	if err != nil {
+		v := validation.New()
+		v.Add("dry_run", validation.CodeInvalidValue, "dry_run must be true or false")
+		return false, v.RequestErr()
+	}
+	return dryRun, nil
+}
+
+// respondImportReadError answers an import file that could not be read.
+func respondImportReadError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		response.RespondWithErrorJson(w, http.StatusRequestEntityTooLarge, response.ErrMsgTaskImportTooLarge)
+		return
+	}
+	response.RespondWithError(w, err)
+}
+
+// importTasks runs the operations read from an import file, the one at i
+// from line lines[i], and answers with a result per row. An import with
+// failing rows is rolled back and answered with 422.
+func (h *TaskHandler) importTasks(w http.ResponseWriter, r *http.Request, lines []int, ops []domain.TaskOperation, dryRun bool) {
+	if len(ops) == 0 {
+		v := validation.New()
+		v.Add("body", validation.CodeRequired, "the file has no tasks to import")
+		response.RespondWithError(w, v.RequestErr())
+		return
+	}
+
+	batch, err := h.svc.Import(r.Context(), ops, dryRun)
+	if err != nil {
+		h.logger.Error("failed to import tasks", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	resp := dto.TaskImportResponse{
+		Committed: batch.Committed,
+		DryRun:    dryRun,
+		Rows:      make([]dto.TaskImportRowDTO, len(batch.Results)),
+	}
+	for i, res := range batch.Results {
+		kind := ops[i].Kind
+		if res.Created {
+			kind = domain.TaskOperationCreate
+		}
+		result := mapTaskOperationResult(i, kind, res)
+		row := dto.TaskImportRowDTO{
+			Line:   lines[i],
+			Op:     string(domain.TaskOperationUpdate),
+			Status: result.Status,
+			Task:   result.Task,
+			Error:  result.Error,
+			Errors: result.Errors,
+		}
+		switch {
+		case res.Err != nil:
+			resp.Failed++
+		case kind == domain.TaskOperationCreate:
+			resp.Created++
+		default:
+			resp.Updated++
+		}
+		if kind == domain.TaskOperationCreate {
+			row.Op = string(domain.TaskOperationCreate)
+		}
+		resp.Rows[i] = row
+	}
+
+	if resp.Failed == 0 {
+		response.RespondWithJson(w, http.StatusOK, resp)
+		return
+	}
+	code, msg := response.MapErrorToResponse(domain.ErrTaskImportRejected)
+	response.ResponseWithJson(w, code, &response.Response{Success: false, Data: resp, Error: msg})
+}
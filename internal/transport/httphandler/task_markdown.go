@@ -0,0 +1,76 @@
+package httphandler
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/taskmd"
+	"github.com/mkeOrt/tasks-go/internal/transport/response"
+)
+
+// ExportMarkdown serves GET /api/tasks/export.md, writing every task that
+// matches the list filters as a Markdown task list with subtasks nested
+// under their parents. Pagination parameters are ignored. Nesting needs
+// every task before the first line, so the list is not streamed.
+func (h *TaskHandler) ExportMarkdown(w http.ResponseWriter, r *http.Request) {
+	filter, err := h.parseTaskFilter(r.URL.Query())
+	if err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+
+	var tasks []domain.Task
+	err = h.svc.Export(r.Context(), filter, func(task domain.Task) error {
+		tasks = append(tasks, task)
+		return nil
+	})
+	if err != nil {
+		h.abortExport(w, false, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", taskmd.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="tasks.md"`)
+	if err := taskmd.Write(w, tasks); err != nil {
+		h.abortExport(w, true, err)
+	}
+}
+
+// ImportMarkdown serves POST /api/tasks/import/markdown, creating a task
+// for every task list item in the body in one transaction. Nested items
+// become subtasks of the item above them, and dry_run=true previews the
+// import without keeping it.
+func (h *TaskHandler) ImportMarkdown(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := parseDryRun(r.URL.Query())
+	if err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+
+	rows, err := taskmd.Read(http.MaxBytesReader(w, r.Body, maxImportBytes))
+	if err != nil {
+		respondImportReadError(w, err)
+		return
+	}
+
+	lines := make([]int, len(rows))
+	ops := make([]domain.TaskOperation, len(rows))
+	for i, row := range rows {
+		lines[i], ops[i] = row.Line, row.Op
+	}
+	h.importTasks(w, r, lines, ops, dryRun)
+}
+
+// respondMarkdown writes tasks as a Markdown task list, for clients that
+// ask for one with Accept: text/markdown.
+func (h *TaskHandler) respondMarkdown(w http.ResponseWriter, r *http.Request, tasks []domain.Task, v response.Validators) {
+	var body bytes.Buffer
+	if err := taskmd.Write(&body, tasks); err != nil {
+		h.logger.Error("failed to write task list", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+	response.RespondWithConditional(w, r, taskmd.ContentType, body.Bytes(), v)
+}
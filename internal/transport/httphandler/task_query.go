@@ -0,0 +1,184 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/validation"
+)
+
+// parseTaskFilter reads the list query parameters into a domain.TaskFilter.
+// Every invalid parameter is reported in a single error.
+func (h *TaskHandler) parseTaskFilter(q url.Values) (domain.TaskFilter, error) {
+	var filter domain.TaskFilter
+	v := validation.New()
+
+	if raw := q.Get("done"); raw != "" {
+		done, err := strconv.ParseBool(raw)
+		if err != nil {
+			v.Add("done", validation.CodeInvalidValue, "done must be true or false")
+		} else {
+			filter.Done = &done
+		}
+	}
+
+	filter.CreatedAfter = parseTimeParam(v, q, "created_after")
+	filter.CreatedBefore = parseTimeParam(v, q, "created_before")
+	filter.UpdatedAfter = parseTimeParam(v, q, "updated_after")
+	filter.UpdatedBefore = parseTimeParam(v, q, "updated_before")
+	filter.Tags = parseTagsParam(q)
+
+	if raw := q.Get("project_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			v.Add("project_id", validation.CodeInvalidValue, "project_id must be a positive integer")
+		} else {
+			filter.ProjectID = &id
+		}
+	}
+
+	if raw := q.Get("tag_mode"); raw != "" {
+		if mode := domain.TagMatchMode(raw); !mode.Valid() {
+			v.Add("tag_mode", validation.CodeInvalidValue, "tag_mode must be all or any")
+		} else {
+			filter.TagMode = mode
+		}
+	}
+
+	if raw := q.Get("sort"); raw != "" {
+		if sort, ok := domain.ParseTaskSort(raw); !ok {
+			v.Add("sort", validation.CodeInvalidValue, "sort must be one of created_at, updated_at or title, optionally prefixed with -")
+		} else {
+			filter.Sort = sort
+		}
+	}
+
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > domain.MaxTaskLimit {
+			v.Add("limit", validation.CodeInvalidValue, fmt.Sprintf("limit must be an integer between 1 and %d", domain.MaxTaskLimit))
+		} else {
+			filter.Limit = limit
+		}
+	}
+
+	if raw := q.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			v.Add("offset", validation.CodeInvalidValue, "offset must be a non-negative integer")
+		} else {
+			filter.Offset = offset
+		}
+	}
+
+	if raw := q.Get("cursor"); raw != "" {
+		cur, err := h.cursors.Decode(raw)
+		switch {
+		case err != nil:
+			v.Add("cursor", validation.CodeInvalidValue, "cursor is not valid")
+		case q.Has("sort") && cur.Sort != filter.Sort:
+			v.Add("cursor", validation.CodeInvalidValue, "cursor was issued for a different sort order")
+		case q.Has("offset"):
+			v.Add("offset", validation.CodeInvalidValue, "offset cannot be combined with cursor")
+		default:
+			filter.Sort = cur.Sort
+			filter.Cursor = &cur
+		}
+	}
+
+	if q.Has("q") {
+		// Search results are ranked by relevance, so ordering and cursors do not apply.
+		if q.Has("sort") {
+			v.Add("sort", validation.CodeInvalidValue, "sort cannot be combined with q")
+		}
+		if q.Has("cursor") {
+			v.Add("cursor", validation.CodeInvalidValue, "cursor cannot be combined with q")
+		}
+	}
+
+	if err := v.RequestErr(); err != nil {
+		return domain.TaskFilter{}, err
+	}
+	return filter, nil
+}
+
+// parseTagsParam collects the tag names from every tag parameter, each of
+// which may hold a comma-separated list. Names are deduplicated ignoring case.
+func parseTagsParam(q url.Values) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, raw := range q["tag"] {
+		for name := range strings.SplitSeq(raw, ",") {
+			name = strings.TrimSpace(name)
+			key := strings.ToLower(name)
+			if name == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			tags = append(tags, name)
+		}
+	}
+	return tags
+}
+
+func parseTimeParam(v *validation.Validator, q url.Values, name string) *time.Time {
+	raw := q.Get(name)
+	if raw == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		v.Add(name, validation.CodeInvalidValue, name+" must be an RFC 3339 timestamp")
+		return nil
+	}
+	return &t
+}
+
+// parseOccurrenceLimit reads the limit parameter of the occurrence preview.
+func parseOccurrenceLimit(q url.Values) (int, error) {
+	raw := q.Get("limit")
+	if raw == "" {
+		return domain.DefaultOccurrenceLimit, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > domain.MaxOccurrenceLimit {
+		v := validation.New()
+		v.Add("limit", validation.CodeInvalidValue, fmt.Sprintf("limit must be an integer between 1 and %d", domain.MaxOccurrenceLimit))
+		return 0, v.RequestErr()
+	}
+	return limit, nil
+}
+
+// parseHistoryPage reads the limit and offset parameters of the history
+// endpoint. A missing limit is returned as zero.
+func parseHistoryPage(q url.Values) (int, int, error) {
+	var limit, offset int
+	v := validation.New()
+
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > domain.MaxHistoryLimit {
+			v.Add("limit", validation.CodeInvalidValue, fmt.Sprintf("limit must be an integer between 1 and %d", domain.MaxHistoryLimit))
+		} else {
+			limit = n
+		}
+	}
+
+	if raw := q.Get("offset"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			v.Add("offset", validation.CodeInvalidValue, "offset must be a non-negative integer")
+		} else {
+			offset = n
+		}
+	}
+
+	if err := v.RequestErr(); err != nil {
+		return 0, 0, err
+	}
+	return limit, offset, nil
+}
@@ -8,27 +8,112 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/taskcsv"
+	"github.com/mkeOrt/tasks-go/internal/taskmd"
+	"github.com/mkeOrt/tasks-go/internal/transport/cursor"
 	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+	"github.com/mkeOrt/tasks-go/internal/transport/response"
+	"github.com/mkeOrt/tasks-go/internal/validation"
 )
 
+var testCursors = cursor.NewCodec([]byte("test-secret"))
+
 type mockTaskService struct {
-	getAllFunc func(ctx context.Context) ([]domain.Task, error)
+	getAllFunc  func(ctx context.Context, filter domain.TaskFilter) (domain.TaskPage, error)
+	searchFunc  func(ctx context.Context, query string, filter domain.TaskFilter) ([]domain.TaskSearchResult, error)
+	getByIDFunc func(ctx context.Context, id int64) (domain.Task, error)
+	createFunc  func(ctx context.Context, task domain.Task) (domain.Task, error)
+	updateFunc  func(ctx context.Context, task domain.Task) (domain.Task, error)
+	patchFunc   func(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error)
+	deleteFunc  func(ctx context.Context, id, version int64) error
+	getTreeFunc func(ctx context.Context, id int64) (domain.TaskNode, error)
+
+	addBlockerFunc    func(ctx context.Context, taskID, blockerID int64) error
+	removeBlockerFunc func(ctx context.Context, taskID, blockerID int64) error
+	blockersFunc      func(ctx context.Context, id int64) ([]domain.Task, error)
+	occurrencesFunc   func(ctx context.Context, id int64, n int) ([]time.Time, error)
+	historyFunc       func(ctx context.Context, id int64, limit, offset int) (domain.TaskEventPage, error)
+	batchFunc         func(ctx context.Context, ops []domain.TaskOperation, continueOnError bool) (domain.TaskBatchResult, error)
+	exportFunc        func(ctx context.Context, filter domain.TaskFilter, fn func(domain.Task) error) error
+	importFunc        func(ctx context.Context, ops []domain.TaskOperation, dryRun bool) (domain.TaskBatchResult, error)
+}
+
+func (m *mockTaskService) GetAll(ctx context.Context, filter domain.TaskFilter) (domain.TaskPage, error) {
+	return m.getAllFunc(ctx, filter)
+}
+
+func (m *mockTaskService) Search(ctx context.Context, query string, filter domain.TaskFilter) ([]domain.TaskSearchResult, error) {
+	return m.searchFunc(ctx, query, filter)
+}
+
+func (m *mockTaskService) GetByID(ctx context.Context, id int64) (domain.Task, error) {
+	return m.getByIDFunc(ctx, id)
+}
+
+func (m *mockTaskService) Create(ctx context.Context, task domain.Task) (domain.Task, error) {
+	return m.createFunc(ctx, task)
+}
+
+func (m *mockTaskService) Update(ctx context.Context, task domain.Task) (domain.Task, error) {
+	return m.updateFunc(ctx, task)
+}
+
+func (m *mockTaskService) Patch(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error) {
+	return m.patchFunc(ctx, id, patch)
+}
+
+func (m *mockTaskService) Delete(ctx context.Context, id, version int64) error {
+	return m.deleteFunc(ctx, id, version)
+}
+
+func (m *mockTaskService) GetTree(ctx context.Context, id int64) (domain.TaskNode, error) {
+	return m.getTreeFunc(ctx, id)
+}
+
+func (m *mockTaskService) AddBlocker(ctx context.Context, taskID, blockerID int64) error {
+	return m.addBlockerFunc(ctx, taskID, blockerID)
+}
+
+func (m *mockTaskService) RemoveBlocker(ctx context.Context, taskID, blockerID int64) error {
+	return m.removeBlockerFunc(ctx, taskID, blockerID)
+}
+
+func (m *mockTaskService) Blockers(ctx context.Context, id int64) ([]domain.Task, error) {
+	return m.blockersFunc(ctx, id)
+}
+
+func (m *mockTaskService) Occurrences(ctx context.Context, id int64, n int) ([]time.Time, error) {
+	return m.occurrencesFunc(ctx, id, n)
+}
+
+func (m *mockTaskService) History(ctx context.Context, id int64, limit, offset int) (domain.TaskEventPage, error) {
+	return m.historyFunc(ctx, id, limit, offset)
+}
+
+func (m *mockTaskService) Batch(ctx context.Context, ops []domain.TaskOperation, continueOnError bool) (domain.TaskBatchResult, error) {
+	return m.batchFunc(ctx, ops, continueOnError)
+}
+
+func (m *mockTaskService) Export(ctx context.Context, filter domain.TaskFilter, fn func(domain.Task) error) error {
+	return m.exportFunc(ctx, filter, fn)
 }
 
-func (m *mockTaskService) GetAll(ctx context.Context) ([]domain.Task, error) {
-	return m.getAllFunc(ctx)
+func (m *mockTaskService) Import(ctx context.Context, ops []domain.TaskOperation, dryRun bool) (domain.TaskBatchResult, error) {
+	return m.importFunc(ctx, ops, dryRun)
 }
 
 func TestNewTaskHandler(t *testing.T) {
 	svc := &mockTaskService{
-		getAllFunc: func(ctx context.Context) ([]domain.Task, error) {
-			return []domain.Task{}, nil
+		getAllFunc: func(ctx context.Context, filter domain.TaskFilter) (domain.TaskPage, error) {
+			return domain.TaskPage{Tasks: []domain.Task{}}, nil
 		},
 	}
-	h := NewTaskHandler(slog.Default(), svc)
+	h := NewTaskHandler(slog.Default(), svc, testCursors, false)
 	if h == nil {
 		t.Fatal("expected handler to be initialized")
 	}
@@ -36,11 +121,11 @@ func TestNewTaskHandler(t *testing.T) {
 
 func TestTaskHandler_RegisterRoutes(t *testing.T) {
 	svc := &mockTaskService{
-		getAllFunc: func(ctx context.Context) ([]domain.Task, error) {
-			return []domain.Task{}, nil
+		getAllFunc: func(ctx context.Context, filter domain.TaskFilter) (domain.TaskPage, error) {
+			return domain.TaskPage{Tasks: []domain.Task{}}, nil
 		},
 	}
-	h := NewTaskHandler(slog.Default(), svc)
+	h := NewTaskHandler(slog.Default(), svc, testCursors, false)
 	mux := h.RegisterRoutes()
 
 	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
@@ -53,6 +138,48 @@ func TestTaskHandler_RegisterRoutes(t *testing.T) {
 	}
 }
 
+func TestTaskHandler_Scopes(t *testing.T) {
+	svc := &mockTaskService{
+		getByIDFunc: func(ctx context.Context, id int64) (domain.Task, error) {
+			return domain.Task{ID: id, Title: "Task", Version: 1}, nil
+		},
+		deleteFunc: func(ctx context.Context, id, version int64) error {
+			return nil
+		},
+	}
+	mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+	readOnly := domain.APIKey{ID: 3, Scopes: []domain.APIKeyScope{domain.ScopeTasksRead}}
+	writeOnly := domain.APIKey{ID: 4, Scopes: []domain.APIKeyScope{domain.ScopeTasksWrite}}
+
+	testCases := []struct {
+		name           string
+		method         string
+		key            *domain.APIKey
+		expectedStatus int
+	}{
+		{name: "should let a read key read", method: http.MethodGet, key: &readOnly, expectedStatus: http.StatusOK},
+		{name: "should keep a read key from writing", method: http.MethodDelete, key: &readOnly, expectedStatus: http.StatusForbidden},
+		{name: "should keep a write key from reading", method: http.MethodGet, key: &writeOnly, expectedStatus: http.StatusForbidden},
+		{name: "should let a write key write", method: http.MethodDelete, key: &writeOnly, expectedStatus: http.StatusNoContent},
+		{name: "should not limit requests without a key", method: http.MethodDelete, expectedStatus: http.StatusNoContent},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/api/tasks/1", nil)
+			if tc.key != nil {
+				req = req.WithContext(domain.WithAPIKey(req.Context(), *tc.key))
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
 func TestTaskHandler_GetAll(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -64,26 +191,26 @@ func TestTaskHandler_GetAll(t *testing.T) {
 			name: "should return tasks successfully",
 			setup: func() *mockTaskService {
 				return &mockTaskService{
-					getAllFunc: func(ctx context.Context) ([]domain.Task, error) {
-						return []domain.Task{
+					getAllFunc: func(ctx context.Context, filter domain.TaskFilter) (domain.TaskPage, error) {
+						return domain.TaskPage{Tasks: []domain.Task{
 							{ID: 1, Title: "Task 1"},
 							{ID: 2, Title: "Task 2"},
-						}, nil
+						}}, nil
 					},
 				}
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: []dto.TaskDTO{
-				{ID: 1, Title: "Task 1", CreatedAt: "0001-01-01T00:00:00Z", UpdatedAt: "0001-01-01T00:00:00Z"},
-				{ID: 2, Title: "Task 2", CreatedAt: "0001-01-01T00:00:00Z", UpdatedAt: "0001-01-01T00:00:00Z"},
+				{ID: 1, Title: "Task 1", Priority: "none", CreatedAt: "0001-01-01T00:00:00Z", UpdatedAt: "0001-01-01T00:00:00Z", Tags: []dto.TagDTO{}},
+				{ID: 2, Title: "Task 2", Priority: "none", CreatedAt: "0001-01-01T00:00:00Z", UpdatedAt: "0001-01-01T00:00:00Z", Tags: []dto.TagDTO{}},
 			},
 		},
 		{
 			name: "should return empty list successfully",
 			setup: func() *mockTaskService {
 				return &mockTaskService{
-					getAllFunc: func(ctx context.Context) ([]domain.Task, error) {
-						return []domain.Task{}, nil
+					getAllFunc: func(ctx context.Context, filter domain.TaskFilter) (domain.TaskPage, error) {
+						return domain.TaskPage{Tasks: []domain.Task{}}, nil
 					},
 				}
 			},
@@ -94,8 +221,8 @@ func TestTaskHandler_GetAll(t *testing.T) {
 			name: "should return error when service fails",
 			setup: func() *mockTaskService {
 				return &mockTaskService{
-					getAllFunc: func(ctx context.Context) ([]domain.Task, error) {
-						return nil, errors.New("service error")
+					getAllFunc: func(ctx context.Context, filter domain.TaskFilter) (domain.TaskPage, error) {
+						return domain.TaskPage{}, errors.New("service error")
 					},
 				}
 			},
@@ -107,7 +234,7 @@ func TestTaskHandler_GetAll(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			svc := tc.setup()
-			h := NewTaskHandler(slog.Default(), svc)
+			h := NewTaskHandler(slog.Default(), svc, testCursors, false)
 
 			req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
 			w := httptest.NewRecorder()
@@ -148,3 +275,1323 @@ func TestTaskHandler_GetAll(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskHandler_ConditionalGet(t *testing.T) {
+	updated := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	svc := &mockTaskService{
+		getAllFunc: func(ctx context.Context, filter domain.TaskFilter) (domain.TaskPage, error) {
+			return domain.TaskPage{
+				Tasks: []domain.Task{
+					{ID: 1, UpdatedAt: updated.Add(-time.Hour)},
+					{ID: 2, UpdatedAt: updated},
+				},
+				Total: 2,
+				Limit: 20,
+			}, nil
+		},
+		getByIDFunc: func(ctx context.Context, id int64) (domain.Task, error) {
+			return domain.Task{ID: id, Version: 4, UpdatedAt: updated}, nil
+		},
+	}
+	mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+	get := func(path string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	testCases := []struct {
+		path         string
+		lastModified string
+	}{
+		// A list cannot tell when a task left it, so it is only validated
+		// by its ETag.
+		{path: "/api/tasks"},
+		{path: "/api/tasks/1", lastModified: "Sun, 18 Oct 2026 12:00:00 GMT"},
+	}
+
+	for _, tc := range testCases {
+		path := tc.path
+		t.Run(path, func(t *testing.T) {
+			w := get(path, nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if got := w.Header().Get("Last-Modified"); got != tc.lastModified {
+				t.Errorf("unexpected Last-Modified %s", got)
+			}
+			if got := w.Header().Get("Cache-Control"); got != response.CacheControl {
+				t.Errorf("unexpected Cache-Control %s", got)
+			}
+
+			etag := w.Header().Get("ETag")
+			if w := get(path, map[string]string{"If-None-Match": etag}); w.Code != http.StatusNotModified {
+				t.Errorf("expected status %d for the current ETag, got %d", http.StatusNotModified, w.Code)
+			}
+			if w := get(path, map[string]string{"If-None-Match": `"stale"`}); w.Code != http.StatusOK {
+				t.Errorf("expected status %d for a stale ETag, got %d", http.StatusOK, w.Code)
+			}
+			expected := http.StatusOK
+			if tc.lastModified != "" {
+				expected = http.StatusNotModified
+			}
+			since := map[string]string{"If-Modified-Since": "Sun, 18 Oct 2026 12:00:00 GMT"}
+			if w := get(path, since); w.Code != expected {
+				t.Errorf("expected status %d for If-Modified-Since, got %d", expected, w.Code)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_GetByID(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should return task", path: "/api/tasks/3", expectedStatus: http.StatusOK},
+		{name: "should return not found", path: "/api/tasks/3", svcErr: domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+		{name: "should reject invalid id", path: "/api/tasks/abc", expectedStatus: http.StatusBadRequest},
+		{name: "should reject non positive id", path: "/api/tasks/0", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				getByIDFunc: func(ctx context.Context, id int64) (domain.Task, error) {
+					if tc.svcErr != nil {
+						return domain.Task{}, tc.svcErr
+					}
+					return domain.Task{ID: id, Title: "Task", Version: 4}, nil
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+			if tc.expectedStatus == http.StatusOK {
+				if etag := w.Header().Get("ETag"); etag != `"4"` {
+					t.Errorf(`expected ETag "4", got %s`, etag)
+				}
+				var resp struct {
+					Data dto.TaskDTO `json:"data"`
+				}
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatal(err)
+				}
+				if resp.Data.ID != 3 {
+					t.Errorf("expected task 3, got %v", resp.Data)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskHandler_Create(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should create task", body: `{"title":"New"}`, expectedStatus: http.StatusCreated},
+		{name: "should reject malformed body", body: `{"title":`, expectedStatus: http.StatusBadRequest},
+		{name: "should reject unknown fields", body: `{"title":"New","owner":"me"}`, expectedStatus: http.StatusBadRequest},
+		{name: "should reject wrong types", body: `{"title":1}`, expectedStatus: http.StatusBadRequest},
+		{name: "should create task with details", body: `{"title":"New","priority":"urgent","due_at":"2026-10-02T17:30:00Z"}`, expectedStatus: http.StatusCreated},
+		{name: "should reject unknown priority", body: `{"title":"New","priority":"critical"}`, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "should reject malformed dates", body: `{"title":"New","due_at":"tomorrow"}`, expectedStatus: http.StatusBadRequest},
+		{name: "should return error when service fails", body: `{"title":"New"}`, svcErr: domain.ErrTaskCreationFailed, expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				createFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+					if tc.svcErr != nil {
+						return domain.Task{}, tc.svcErr
+					}
+					task.ID = 1
+					return task, nil
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_Update(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		body           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should update task", path: "/api/tasks/2", body: `{"title":"Edit","done":true}`, expectedStatus: http.StatusOK},
+		{name: "should return not found", path: "/api/tasks/2", body: `{"title":"Edit"}`, svcErr: domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+		{name: "should reject invalid id", path: "/api/tasks/x", body: `{"title":"Edit"}`, expectedStatus: http.StatusBadRequest},
+		{name: "should reject malformed body", path: "/api/tasks/2", body: `nope`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				updateFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+					if task.ID != 2 {
+						t.Errorf("expected id 2, got %d", task.ID)
+					}
+					return task, tc.svcErr
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPut, tc.path, strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_Patch(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		svcErr         error
+		expectedStatus int
+		expectedPatch  domain.TaskPatch
+	}{
+		{name: "should patch done only", body: `{"done":true}`, expectedStatus: http.StatusOK, expectedPatch: domain.TaskPatch{Done: ptr(true)}},
+		{name: "should patch title only", body: `{"title":"T"}`, expectedStatus: http.StatusOK, expectedPatch: domain.TaskPatch{Title: ptr("T")}},
+		{name: "should patch priority", body: `{"priority":"low"}`, expectedStatus: http.StatusOK, expectedPatch: domain.TaskPatch{Priority: ptr(domain.PriorityLow)}},
+		{name: "should clear due date on null", body: `{"due_at":null}`, expectedStatus: http.StatusOK, expectedPatch: domain.TaskPatch{DueAt: domain.NullableTime{Set: true}}},
+		{
+			name:           "should patch start date",
+			body:           `{"start_at":"2026-10-01T09:00:00Z"}`,
+			expectedStatus: http.StatusOK,
+			expectedPatch:  domain.TaskPatch{StartAt: domain.NullableTime{Set: true, Time: ptr(time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC))}},
+		},
+		{name: "should return not found", body: `{"done":true}`, svcErr: domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound, expectedPatch: domain.TaskPatch{Done: ptr(true)}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				patchFunc: func(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error) {
+					if !reflect.DeepEqual(patch, tc.expectedPatch) {
+						t.Errorf("expected patch %+v, got %+v", tc.expectedPatch, patch)
+					}
+					return domain.Task{ID: id}, tc.svcErr
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/tasks/9", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_Delete(t *testing.T) {
+	testCases := []struct {
+		name            string
+		ifMatch         string
+		svcErr          error
+		expectedVersion int64
+		expectedStatus  int
+	}{
+		{name: "should delete task", expectedStatus: http.StatusNoContent},
+		{name: "should pass the If-Match version", ifMatch: `"3"`, expectedVersion: 3, expectedStatus: http.StatusNoContent},
+		{name: "should return not found", svcErr: domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+		{name: "should return precondition failed", ifMatch: `"3"`, expectedVersion: 3, svcErr: domain.ErrTaskVersionConflict, expectedStatus: http.StatusPreconditionFailed},
+		{name: "should return error when service fails", svcErr: domain.ErrTaskDeletionFailed, expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				deleteFunc: func(ctx context.Context, id, version int64) error {
+					if version != tc.expectedVersion {
+						t.Errorf("expected version %d, got %d", tc.expectedVersion, version)
+					}
+					return tc.svcErr
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/tasks/1", nil)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_IfMatch(t *testing.T) {
+	testCases := []struct {
+		name            string
+		ifMatch         string
+		require         bool
+		expectedVersion int64
+		expectedStatus  int
+	}{
+		{name: "should write unconditionally without If-Match", expectedStatus: http.StatusOK},
+		{name: "should write unconditionally on a wildcard", ifMatch: "*", require: true, expectedStatus: http.StatusOK},
+		{name: "should pass the version of the ETag", ifMatch: `"7"`, expectedVersion: 7, expectedStatus: http.StatusOK},
+		{name: "should never match a weak ETag", ifMatch: `W/"7"`, expectedStatus: http.StatusPreconditionFailed},
+		{name: "should never match a foreign ETag", ifMatch: `"abc"`, expectedStatus: http.StatusPreconditionFailed},
+		{name: "should require If-Match when configured", require: true, expectedStatus: http.StatusPreconditionRequired},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				patchFunc: func(ctx context.Context, id int64, patch domain.TaskPatch) (domain.Task, error) {
+					if patch.Version != tc.expectedVersion {
+						t.Errorf("expected version %d, got %d", tc.expectedVersion, patch.Version)
+					}
+					return domain.Task{ID: id, Version: 8}, nil
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, tc.require).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/tasks/9", strings.NewReader(`{"title":"T"}`))
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+			if tc.expectedStatus == http.StatusOK && w.Header().Get("ETag") != `"8"` {
+				t.Errorf(`expected ETag "8", got %s`, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestTaskHandler_ValidationErrors(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		svcErr         error
+		expectedStatus int
+		expectedFields []validation.FieldError
+	}{
+		{
+			name:           "should list every decoding failure",
+			body:           `{"title":false,"color":"red"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []validation.FieldError{
+				{Field: "color", Code: validation.CodeUnknownField, Message: "color is not a known field"},
+				{Field: "title", Code: validation.CodeInvalidType, Message: "title must be a string"},
+			},
+		},
+		{
+			name: "should return service validation failures",
+			body: `{"title":""}`,
+			svcErr: &validation.Error{
+				Kind:   domain.ErrValidationFailed,
+				Fields: []validation.FieldError{{Field: "title", Code: validation.CodeRequired, Message: "title is required"}},
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedFields: []validation.FieldError{
+				{Field: "title", Code: validation.CodeRequired, Message: "title is required"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				createFunc: func(ctx context.Context, task domain.Task) (domain.Task, error) {
+					return domain.Task{}, tc.svcErr
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+
+			var resp struct {
+				Success bool                    `json:"success"`
+				Errors  []validation.FieldError `json:"errors"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Success {
+				t.Error("expected success to be false")
+			}
+			if !reflect.DeepEqual(resp.Errors, tc.expectedFields) {
+				t.Errorf("expected errors %v, got %v", tc.expectedFields, resp.Errors)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_GetAll_Filter(t *testing.T) {
+	createdAfter := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedFilter domain.TaskFilter
+		expectedFields []string
+	}{
+		{
+			name:           "should pass parsed filter to service",
+			query:          "?done=true&created_after=2025-01-02T03:04:05Z&sort=-updated_at&limit=10&offset=20",
+			expectedStatus: http.StatusOK,
+			expectedFilter: domain.TaskFilter{
+				Done:         ptr(true),
+				CreatedAfter: &createdAfter,
+				Sort:         domain.TaskSort{Field: domain.TaskSortUpdatedAt, Desc: true},
+				Limit:        10,
+				Offset:       20,
+			},
+		},
+		{
+			name:           "should pass empty filter without parameters",
+			query:          "",
+			expectedStatus: http.StatusOK,
+			expectedFilter: domain.TaskFilter{},
+		},
+		{
+			name:           "should collect comma-separated and repeated tags",
+			query:          "?tag=backend,%20urgent&tag=Backend,customer-x,&tag_mode=any",
+			expectedStatus: http.StatusOK,
+			expectedFilter: domain.TaskFilter{
+				Tags:    []string{"backend", "urgent", "customer-x"},
+				TagMode: domain.TagMatchAny,
+			},
+		},
+		{
+			name:           "should filter by project",
+			query:          "?project_id=4",
+			expectedStatus: http.StatusOK,
+			expectedFilter: domain.TaskFilter{ProjectID: ptr(int64(4))},
+		},
+		{
+			name:           "should report every invalid parameter",
+			query:          "?done=maybe&updated_before=yesterday&project_id=0&tag_mode=some&sort=priority&limit=1000&offset=-1",
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []string{"done", "updated_before", "project_id", "tag_mode", "sort", "limit", "offset"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				getAllFunc: func(ctx context.Context, filter domain.TaskFilter) (domain.TaskPage, error) {
+					if !reflect.DeepEqual(filter, tc.expectedFilter) {
+						t.Errorf("expected filter %+v, got %+v", tc.expectedFilter, filter)
+					}
+					return domain.TaskPage{Tasks: []domain.Task{}, Total: 3, Limit: filter.Limit, Offset: filter.Offset}, nil
+				},
+			}
+			h := NewTaskHandler(slog.Default(), svc, testCursors, false)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/tasks"+tc.query, nil)
+			w := httptest.NewRecorder()
+			h.GetAll(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+
+			var resp struct {
+				Data   *dto.TasksResponse      `json:"data"`
+				Errors []validation.FieldError `json:"errors"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.expectedStatus == http.StatusOK {
+				if resp.Data.Total != 3 || resp.Data.Limit != tc.expectedFilter.Limit || resp.Data.Offset != tc.expectedFilter.Offset {
+					t.Errorf("unexpected pagination metadata %+v", resp.Data)
+				}
+				return
+			}
+
+			var fields []string
+			for _, f := range resp.Errors {
+				fields = append(fields, f.Field)
+			}
+			if !reflect.DeepEqual(fields, tc.expectedFields) {
+				t.Errorf("expected invalid fields %v, got %v", tc.expectedFields, fields)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_GetAll_Cursor(t *testing.T) {
+	sort := domain.TaskSort{Field: domain.TaskSortTitle, Desc: true}
+	cur := domain.TaskCursor{Sort: sort, Value: "m", ID: 5}
+	token, err := testCursors.Encode(cur)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{name: "should accept cursor and adopt its sort", query: "?cursor=" + token, expectedStatus: http.StatusOK},
+		{name: "should accept cursor with matching sort", query: "?sort=-title&cursor=" + token, expectedStatus: http.StatusOK},
+		{name: "should reject cursor for another sort", query: "?sort=title&cursor=" + token, expectedStatus: http.StatusBadRequest},
+		{name: "should reject cursor with offset", query: "?offset=3&cursor=" + token, expectedStatus: http.StatusBadRequest},
+		{name: "should reject tampered cursor", query: "?cursor=" + token + "x", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				getAllFunc: func(ctx context.Context, filter domain.TaskFilter) (domain.TaskPage, error) {
+					if filter.Cursor == nil || *filter.Cursor != cur || filter.Sort != sort {
+						t.Errorf("expected cursor %+v with sort %+v, got %+v", cur, sort, filter)
+					}
+					next := domain.TaskCursor{Sort: sort, Value: "a", ID: 9}
+					return domain.TaskPage{Tasks: []domain.Task{{ID: 9, Title: "a"}}, NextCursor: &next}, nil
+				},
+			}
+			h := NewTaskHandler(slog.Default(), svc, testCursors, false)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/tasks"+tc.query, nil)
+			w := httptest.NewRecorder()
+			h.GetAll(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Data dto.TasksResponse `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Data.PrevCursor != "" {
+				t.Errorf("expected no prev cursor, got %q", resp.Data.PrevCursor)
+			}
+			next, err := testCursors.Decode(resp.Data.NextCursor)
+			if err != nil {
+				t.Fatalf("expected valid next cursor, got %v", err)
+			}
+			if next.ID != 9 || next.Sort != sort {
+				t.Errorf("unexpected next cursor %+v", next)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_Search(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should return ranked results", query: "?q=invoice+OR+%22quarterly+report%22&done=false", expectedStatus: http.StatusOK},
+		{name: "should reject invalid syntax", query: "?q=%22open", svcErr: domain.ErrInvalidSearchQuery, expectedStatus: http.StatusBadRequest},
+		{name: "should reject sort with q", query: "?q=invoice&sort=title", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				searchFunc: func(ctx context.Context, query string, filter domain.TaskFilter) ([]domain.TaskSearchResult, error) {
+					if tc.svcErr != nil {
+						return nil, tc.svcErr
+					}
+					if query != `invoice OR "quarterly report"` {
+						t.Errorf("unexpected query %q", query)
+					}
+					if filter.Done == nil || *filter.Done {
+						t.Errorf("expected done=false filter, got %+v", filter)
+					}
+					return []domain.TaskSearchResult{
+						{Task: domain.Task{ID: 4, Title: "Send invoice"}, Score: 2.5, Snippet: "Send <mark>invoice</mark>"},
+					}, nil
+				},
+			}
+			h := NewTaskHandler(slog.Default(), svc, testCursors, false)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/tasks"+tc.query, nil)
+			w := httptest.NewRecorder()
+			h.GetAll(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Data dto.TaskSearchResponse `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Data.Results) != 1 {
+				t.Fatalf("expected 1 result, got %v", resp.Data.Results)
+			}
+			got := resp.Data.Results[0]
+			if got.ID != 4 || got.Score != 2.5 || got.Snippet != "Send <mark>invoice</mark>" {
+				t.Errorf("unexpected result %+v", got)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_GetTree(t *testing.T) {
+	parentID := int64(1)
+	testCases := []struct {
+		name           string
+		path           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should return nested subtasks", path: "/api/tasks/1/tree", expectedStatus: http.StatusOK},
+		{name: "should return not found", path: "/api/tasks/1/tree", svcErr: domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+		{name: "should reject invalid id", path: "/api/tasks/x/tree", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				getTreeFunc: func(ctx context.Context, id int64) (domain.TaskNode, error) {
+					return domain.TaskNode{
+						Task: domain.Task{ID: id, Title: "Root"},
+						Children: []domain.TaskNode{
+							{Task: domain.Task{ID: 2, Title: "Child", ParentID: &parentID}, Children: []domain.TaskNode{}},
+						},
+					}, tc.svcErr
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Data dto.TaskTreeDTO `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Data.ID != 1 || len(resp.Data.Children) != 1 || resp.Data.Children[0].ID != 2 ||
+				*resp.Data.Children[0].ParentID != 1 || resp.Data.Children[0].Children == nil {
+				t.Errorf("unexpected tree %+v", resp.Data)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_Blockers(t *testing.T) {
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should list blockers", method: http.MethodGet, path: "/api/tasks/1/blockers", expectedStatus: http.StatusOK},
+		{name: "should return not found when listing", method: http.MethodGet, path: "/api/tasks/1/blockers", svcErr: domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+		{name: "should add blocker", method: http.MethodPut, path: "/api/tasks/1/blockers/2", expectedStatus: http.StatusNoContent},
+		{name: "should report a dependency cycle", method: http.MethodPut, path: "/api/tasks/1/blockers/2", svcErr: domain.ErrDependencyCycle, expectedStatus: http.StatusConflict},
+		{name: "should return not found when adding", method: http.MethodPut, path: "/api/tasks/1/blockers/2", svcErr: domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+		{name: "should reject invalid blocker id", method: http.MethodPut, path: "/api/tasks/1/blockers/x", expectedStatus: http.StatusBadRequest},
+		{name: "should remove blocker", method: http.MethodDelete, path: "/api/tasks/1/blockers/2", expectedStatus: http.StatusNoContent},
+		{name: "should reject invalid task id", method: http.MethodDelete, path: "/api/tasks/0/blockers/2", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checkIDs := func(taskID, blockerID int64) error {
+				if taskID != 1 || blockerID != 2 {
+					t.Errorf("expected ids 1 and 2, got %d and %d", taskID, blockerID)
+				}
+				return tc.svcErr
+			}
+			svc := &mockTaskService{
+				addBlockerFunc: func(ctx context.Context, taskID, blockerID int64) error {
+					return checkIDs(taskID, blockerID)
+				},
+				removeBlockerFunc: func(ctx context.Context, taskID, blockerID int64) error {
+					return checkIDs(taskID, blockerID)
+				},
+				blockersFunc: func(ctx context.Context, id int64) ([]domain.Task, error) {
+					return []domain.Task{{ID: 2, Title: "Blocker"}}, tc.svcErr
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+			if tc.method != http.MethodGet || tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Data dto.BlockersResponse `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Data.Blockers) != 1 || resp.Data.Blockers[0].ID != 2 {
+				t.Errorf("unexpected blockers %+v", resp.Data.Blockers)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_GetOccurrences(t *testing.T) {
+	due := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		path           string
+		svcErr         error
+		expectedLimit  int
+		expectedStatus int
+	}{
+		{name: "should preview the default number of occurrences", path: "/api/tasks/1/occurrences", expectedLimit: domain.DefaultOccurrenceLimit, expectedStatus: http.StatusOK},
+		{name: "should honour the limit", path: "/api/tasks/1/occurrences?limit=2", expectedLimit: 2, expectedStatus: http.StatusOK},
+		{name: "should reject a limit above the maximum", path: "/api/tasks/1/occurrences?limit=101", expectedStatus: http.StatusBadRequest},
+		{name: "should return not found", path: "/api/tasks/1/occurrences", svcErr: domain.ErrTaskNotFound, expectedLimit: domain.DefaultOccurrenceLimit, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				occurrencesFunc: func(ctx context.Context, id int64, n int) ([]time.Time, error) {
+					if n != tc.expectedLimit {
+						t.Errorf("expected limit %d, got %d", tc.expectedLimit, n)
+					}
+					return []time.Time{due, due.AddDate(0, 0, 7)}, tc.svcErr
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Data dto.OccurrencesResponse `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			expected := []string{"2026-10-19T09:00:00Z", "2026-10-26T09:00:00Z"}
+			if !reflect.DeepEqual(resp.Data.Occurrences, expected) {
+				t.Errorf("expected occurrences %v, got %v", expected, resp.Data.Occurrences)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_GetHistory(t *testing.T) {
+	createdAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		path           string
+		svcErr         error
+		expectedLimit  int
+		expectedOffset int
+		expectedStatus int
+	}{
+		{name: "should return the history", path: "/api/tasks/1/history", expectedStatus: http.StatusOK},
+		{name: "should pass the page", path: "/api/tasks/1/history?limit=5&offset=10", expectedLimit: 5, expectedOffset: 10, expectedStatus: http.StatusOK},
+		{name: "should reject a limit above the maximum", path: "/api/tasks/1/history?limit=101", expectedStatus: http.StatusBadRequest},
+		{name: "should reject a negative offset", path: "/api/tasks/1/history?offset=-1", expectedStatus: http.StatusBadRequest},
+		{name: "should return not found", path: "/api/tasks/1/history", svcErr: domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				historyFunc: func(ctx context.Context, id int64, limit, offset int) (domain.TaskEventPage, error) {
+					if limit != tc.expectedLimit || offset != tc.expectedOffset {
+						t.Errorf("expected page %d/%d, got %d/%d", tc.expectedLimit, tc.expectedOffset, limit, offset)
+					}
+					events := []domain.TaskEvent{{
+						ID:        4,
+						TaskID:    id,
+						Action:    domain.TaskEventUpdated,
+						Changes:   map[string]domain.FieldChange{"done": {Old: false, New: true}},
+						RequestID: "req-1",
+						CreatedAt: createdAt,
+					}}
+					return domain.TaskEventPage{Events: events, Total: 1, Limit: 50}, tc.svcErr
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Data dto.TaskHistoryResponse `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			expected := dto.TaskEventDTO{
+				ID:        4,
+				TaskID:    1,
+				Action:    "updated",
+				Changes:   map[string]dto.FieldChangeDTO{"done": {Old: false, New: true}},
+				RequestID: &[]string{"req-1"}[0],
+				CreatedAt: "2026-10-18T09:00:00Z",
+			}
+			if resp.Data.Total != 1 || len(resp.Data.Events) != 1 || !reflect.DeepEqual(resp.Data.Events[0], expected) {
+				t.Errorf("unexpected history %+v", resp.Data)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_Batch(t *testing.T) {
+	body := `{"mode":"continue","operations":[
+		{"op":"create","task":{"title":"new"}},
+		{"op":"patch","id":2,"version":4,"task":{"done":true}},
+		{"op":"delete","id":3}
+	]}`
+
+	testCases := []struct {
+		name             string
+		body             string
+		require          bool
+		batch            domain.TaskBatchResult
+		svcErr           error
+		expectedStatus   int
+		expectedStatuses []int
+		expectedFields   []string
+	}{
+		{
+			name: "should report every result of a committed batch",
+			body: body,
+			batch: domain.TaskBatchResult{Committed: true, Results: []domain.TaskOperationResult{
+				{Task: domain.Task{ID: 1, Title: "new"}},
+				{Err: domain.ErrTaskVersionConflict},
+				{},
+			}},
+			expectedStatus:   http.StatusOK,
+			expectedStatuses: []int{http.StatusCreated, http.StatusPreconditionFailed, http.StatusNoContent},
+		},
+		{
+			name: "should answer with the failed operation of a rolled back batch",
+			body: body,
+			batch: domain.TaskBatchResult{Results: []domain.TaskOperationResult{
+				{Task: domain.Task{ID: 1, Title: "new"}},
+				{Err: domain.ErrTaskNotFound},
+				{Err: domain.ErrTaskOperationSkipped},
+			}},
+			expectedStatus:   http.StatusNotFound,
+			expectedStatuses: []int{http.StatusCreated, http.StatusNotFound, http.StatusFailedDependency},
+		},
+		{
+			name:           "should reject an undecodable task body",
+			body:           `{"operations":[{"op":"create","task":{"title":1}},{"op":"patch","id":1,"task":[]}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []string{"operations[0].task.title", "operations[1].task"},
+		},
+		{
+			name:           "should reject data after the body",
+			body:           body + `{"operations":[]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []string{"body"},
+		},
+		{
+			name:           "should reject a body that is too large",
+			body:           `{"operations":[{"op":"create","task":{"title":"` + strings.Repeat("x", maxJSONBytes) + `"}}]}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "should reject an unknown mode and invalid fields",
+			body:           `{"mode":"sometimes","operations":[{"op":"create","task":{"priority":"extreme"}}]}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedFields: []string{"mode", "operations[0].task.priority"},
+		},
+		{
+			name:           "should require versions when If-Match is required",
+			body:           body,
+			require:        true,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedFields: []string{"operations[2].version"},
+		},
+		{
+			name:           "should return error when service fails",
+			body:           body,
+			svcErr:         domain.ErrTaskBatchFailed,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				batchFunc: func(ctx context.Context, ops []domain.TaskOperation, continueOnError bool) (domain.TaskBatchResult, error) {
+					if !continueOnError {
+						t.Error("expected the batch to continue on error")
+					}
+					expected := []domain.TaskOperation{
+						{Kind: domain.TaskOperationCreate, Task: domain.Task{Title: "new"}},
+						{Kind: domain.TaskOperationPatch, ID: 2, Version: 4, Patch: domain.TaskPatch{Done: ptr(true)}},
+						{Kind: domain.TaskOperationDelete, ID: 3},
+					}
+					if !reflect.DeepEqual(ops, expected) {
+						t.Errorf("expected operations %+v, got %+v", expected, ops)
+					}
+					return tc.batch, tc.svcErr
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, tc.require).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/tasks/batch", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			var resp struct {
+				Data   dto.TaskBatchResponse   `json:"data"`
+				Errors []validation.FieldError `json:"errors"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if tc.expectedStatuses != nil {
+				if resp.Data.Committed != tc.batch.Committed {
+					t.Errorf("expected committed %v, got %v", tc.batch.Committed, resp.Data.Committed)
+				}
+				var statuses []int
+				for _, r := range resp.Data.Results {
+					statuses = append(statuses, r.Status)
+				}
+				if !reflect.DeepEqual(statuses, tc.expectedStatuses) {
+					t.Errorf("expected statuses %v, got %v", tc.expectedStatuses, statuses)
+				}
+				if resp.Data.Results[0].Task == nil || resp.Data.Results[0].Task.ID != 1 {
+					t.Errorf("expected the created task in the first result, got %+v", resp.Data.Results[0])
+				}
+			}
+			if tc.expectedFields != nil {
+				var fields []string
+				for _, f := range resp.Errors {
+					fields = append(fields, f.Field)
+				}
+				if !reflect.DeepEqual(fields, tc.expectedFields) {
+					t.Errorf("expected fields %v, got %v", tc.expectedFields, fields)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskHandler_ExportCSV(t *testing.T) {
+	created := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name           string
+		path           string
+		svcErr         error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "should stream matching tasks",
+			path:           "/api/tasks/export.csv?done=false&limit=1",
+			expectedStatus: http.StatusOK,
+			expectedBody: strings.Join(taskcsv.Columns, ",") + "\n" +
+				"1,First,,false,none,,,,,,,,false,1,2026-10-18T09:00:00Z,2026-10-18T09:00:00Z\n" +
+				"2,Second,,false,none,,,,,,,,false,1,2026-10-18T09:00:00Z,2026-10-18T09:00:00Z\n",
+		},
+		{name: "should reject invalid filters", path: "/api/tasks/export.csv?done=maybe", expectedStatus: http.StatusBadRequest},
+		{name: "should report a failure before any output", path: "/api/tasks/export.csv", svcErr: domain.ErrTaskRetrievalFailed, expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				exportFunc: func(ctx context.Context, filter domain.TaskFilter, fn func(domain.Task) error) error {
+					if tc.svcErr != nil {
+						return tc.svcErr
+					}
+					if filter.Done == nil || *filter.Done {
+						t.Errorf("expected the done filter to be passed, got %+v", filter)
+					}
+					for i, title := range []string{"First", "Second"} {
+						if err := fn(domain.Task{ID: int64(i + 1), Title: title, Version: 1, CreatedAt: created, UpdatedAt: created}); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+				t.Errorf("unexpected Content-Type %s", ct)
+			}
+			if w.Body.String() != tc.expectedBody {
+				t.Errorf("expected body\n%s\nbut got\n%s", tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTaskHandler_ImportCSV(t *testing.T) {
+	body := "Name,id\nnew,\nrenamed,2\n"
+
+	testCases := []struct {
+		name           string
+		path           string
+		body           string
+		results        []domain.TaskOperationResult
+		committed      bool
+		expectedStatus int
+		expectedDryRun bool
+		expectedRows   []dto.TaskImportRowDTO
+	}{
+		{
+			name:           "should import every row",
+			path:           "/api/tasks/import?map.title=Name",
+			body:           body,
+			results:        []domain.TaskOperationResult{{Task: domain.Task{ID: 3}}, {Task: domain.Task{ID: 2}}},
+			committed:      true,
+			expectedStatus: http.StatusOK,
+			expectedRows: []dto.TaskImportRowDTO{
+				{Line: 2, Op: "create", Status: http.StatusCreated},
+				{Line: 3, Op: "update", Status: http.StatusOK},
+			},
+		},
+		{
+			name:           "should preview a dry run",
+			path:           "/api/tasks/import?map.title=Name&dry_run=true",
+			body:           body,
+			results:        []domain.TaskOperationResult{{Task: domain.Task{ID: 3}}, {Task: domain.Task{ID: 2}}},
+			expectedStatus: http.StatusOK,
+			expectedDryRun: true,
+			expectedRows: []dto.TaskImportRowDTO{
+				{Line: 2, Op: "create", Status: http.StatusCreated},
+				{Line: 3, Op: "update", Status: http.StatusOK},
+			},
+		},
+		{
+			name:           "should report a row whose unknown id created a task",
+			path:           "/api/tasks/import?map.title=Name",
+			body:           body,
+			results:        []domain.TaskOperationResult{{Task: domain.Task{ID: 3}}, {Task: domain.Task{ID: 4}, Created: true}},
+			committed:      true,
+			expectedStatus: http.StatusOK,
+			expectedRows: []dto.TaskImportRowDTO{
+				{Line: 2, Op: "create", Status: http.StatusCreated},
+				{Line: 3, Op: "create", Status: http.StatusCreated},
+			},
+		},
+		{
+			name:           "should report failing rows",
+			path:           "/api/tasks/import?map.title=Name",
+			body:           body,
+			results:        []domain.TaskOperationResult{{Task: domain.Task{ID: 3}}, {Err: domain.ErrTaskNotFound}},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedRows: []dto.TaskImportRowDTO{
+				{Line: 2, Op: "create", Status: http.StatusCreated},
+				{Line: 3, Op: "update", Status: http.StatusNotFound, Error: response.ErrMsgTaskNotFound},
+			},
+		},
+		{name: "should reject malformed cells", path: "/api/tasks/import", body: "title,done\nx,maybe\n", expectedStatus: http.StatusBadRequest},
+		{name: "should reject an empty file", path: "/api/tasks/import", body: "title\n", expectedStatus: http.StatusBadRequest},
+		{name: "should reject an invalid dry_run", path: "/api/tasks/import?dry_run=often", body: body, expectedStatus: http.StatusBadRequest},
+		{name: "should reject a file that is too large", path: "/api/tasks/import", body: "title\n" + strings.Repeat("x", maxImportBytes) + "\n", expectedStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				importFunc: func(ctx context.Context, ops []domain.TaskOperation, dryRun bool) (domain.TaskBatchResult, error) {
+					if dryRun != tc.expectedDryRun {
+						t.Errorf("expected dry run %v, got %v", tc.expectedDryRun, dryRun)
+					}
+					if len(ops) != 2 || ops[0].Task.Title != "new" || ops[1].ID != 2 || *ops[1].Patch.Title != "renamed" {
+						t.Errorf("unexpected operations %+v", ops)
+					}
+					return domain.TaskBatchResult{Results: tc.results, Committed: tc.committed}, nil
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedRows == nil {
+				return
+			}
+			var resp struct {
+				Data dto.TaskImportResponse `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Data.Committed != tc.committed || resp.Data.DryRun != tc.expectedDryRun {
+				t.Errorf("unexpected committed %v and dry run %v", resp.Data.Committed, resp.Data.DryRun)
+			}
+			for i := range resp.Data.Rows {
+				resp.Data.Rows[i].Task = nil
+			}
+			if !reflect.DeepEqual(resp.Data.Rows, tc.expectedRows) {
+				t.Errorf("expected rows %+v, got %+v", tc.expectedRows, resp.Data.Rows)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_ExportMarkdown(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		svcErr         error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "should nest subtasks under their parents",
+			path:           "/api/tasks/export.md?done=false&limit=1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "- [ ] Release\n  - [x] Write notes\n- [ ] Celebrate\n",
+		},
+		{name: "should reject invalid filters", path: "/api/tasks/export.md?done=maybe", expectedStatus: http.StatusBadRequest},
+		{name: "should report a failure to load the tasks", path: "/api/tasks/export.md?done=false", svcErr: domain.ErrTaskRetrievalFailed, expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parentID := int64(1)
+			svc := &mockTaskService{
+				exportFunc: func(ctx context.Context, filter domain.TaskFilter, fn func(domain.Task) error) error {
+					if filter.Done == nil || *filter.Done {
+						t.Errorf("expected the done filter to be passed, got %+v", filter)
+					}
+					tasks := []domain.Task{
+						{ID: 1, Title: "Release"},
+						{ID: 3, Title: "Celebrate"},
+						{ID: 2, Title: "Write notes", Done: true, ParentID: &parentID},
+					}
+					for _, task := range tasks {
+						if err := fn(task); err != nil {
+							return err
+						}
+					}
+					return tc.svcErr
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+			if ct := w.Header().Get("Content-Type"); ct != taskmd.ContentType {
+				t.Errorf("unexpected Content-Type %s", ct)
+			}
+			if w.Body.String() != tc.expectedBody {
+				t.Errorf("expected body\n%s\nbut got\n%s", tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTaskHandler_ImportMarkdown(t *testing.T) {
+	body := "# Launch\n- [ ] Release\n  - [x] Write notes\n"
+
+	testCases := []struct {
+		name           string
+		path           string
+		body           string
+		expectedStatus int
+		expectedDryRun bool
+	}{
+		{name: "should import every task item", path: "/api/tasks/import/markdown", body: body, expectedStatus: http.StatusOK},
+		{name: "should preview a dry run", path: "/api/tasks/import/markdown?dry_run=true", body: body, expectedStatus: http.StatusOK, expectedDryRun: true},
+		{name: "should reject a file without task items", path: "/api/tasks/import/markdown", body: "# Nothing\n- plain\n", expectedStatus: http.StatusBadRequest},
+		{name: "should reject an invalid dry_run", path: "/api/tasks/import/markdown?dry_run=often", body: body, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				importFunc: func(ctx context.Context, ops []domain.TaskOperation, dryRun bool) (domain.TaskBatchResult, error) {
+					if dryRun != tc.expectedDryRun {
+						t.Errorf("expected dry run %v, got %v", tc.expectedDryRun, dryRun)
+					}
+					if len(ops) != 2 || ops[0].Task.Title != "Release" || ops[1].ParentOp == nil || *ops[1].ParentOp != 0 || !ops[1].Task.Done {
+						t.Errorf("unexpected operations %+v", ops)
+					}
+					results := []domain.TaskOperationResult{{Task: domain.Task{ID: 1}}, {Task: domain.Task{ID: 2}}}
+					return domain.TaskBatchResult{Results: results, Committed: !dryRun}, nil
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTaskHandler_GetAll_Markdown(t *testing.T) {
+	testCases := []struct {
+		name                string
+		path                string
+		accept              string
+		expectedContentType string
+	}{
+		{name: "should default to JSON", path: "/api/tasks", expectedContentType: "application/json"},
+		{name: "should list the page as Markdown", path: "/api/tasks", accept: "text/markdown", expectedContentType: taskmd.ContentType},
+		{name: "should search as Markdown", path: "/api/tasks?q=release", accept: "text/markdown", expectedContentType: taskmd.ContentType},
+		{name: "should prefer JSON when it weighs more", path: "/api/tasks", accept: "text/markdown;q=0.5, application/json", expectedContentType: "application/json"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			task := domain.Task{ID: 1, Title: "Release"}
+			svc := &mockTaskService{
+				getAllFunc: func(ctx context.Context, filter domain.TaskFilter) (domain.TaskPage, error) {
+					return domain.TaskPage{Tasks: []domain.Task{task}, Total: 1}, nil
+				},
+				searchFunc: func(ctx context.Context, query string, filter domain.TaskFilter) ([]domain.TaskSearchResult, error) {
+					return []domain.TaskSearchResult{{Task: task}}, nil
+				},
+			}
+			mux := NewTaskHandler(slog.Default(), svc, testCursors, false).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tc.expectedContentType {
+				t.Fatalf("expected Content-Type %s, got %s", tc.expectedContentType, ct)
+			}
+			if w.Header().Get("Vary") != "Accept" {
+				t.Errorf("expected Vary: Accept, got %q", w.Header().Get("Vary"))
+			}
+			if tc.expectedContentType == taskmd.ContentType && w.Body.String() != "- [ ] Release\n" {
+				t.Errorf("unexpected body %q", w.Body.String())
+			}
+		})
+	}
+}
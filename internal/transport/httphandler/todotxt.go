@@ -0,0 +1,107 @@
+package httphandler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/todotxt"
+	"github.com/mkeOrt/tasks-go/internal/transport/response"
+)
+
+// TodoTxtHandler handles HTTP requests that move tasks in and out as
+// todo.txt files.
+type TodoTxtHandler struct {
+	logger   *slog.Logger
+	tasks    *TaskHandler
+	projects ProjectService
+	tags     TagService
+}
+
+// NewTodoTxtHandler creates a new TodoTxtHandler. tasks streams and imports
+// the tasks, while projects and tags name the +project and @context tokens.
+func NewTodoTxtHandler(logger *slog.Logger, tasks *TaskHandler, projects ProjectService, tags TagService) *TodoTxtHandler {
+	return &TodoTxtHandler{
+		logger:   logger,
+		tasks:    tasks,
+		projects: projects,
+		tags:     tags,
+	}
+}
+
+func (h *TodoTxtHandler) RegisterRoutes() *http.ServeMux {
+	g := http.NewServeMux()
+	read, write := domain.ScopeTasksRead, domain.ScopeTasksWrite
+	g.Handle("GET /api/tasks/export.txt", scoped(read, h.Export))
+	g.Handle("POST /api/tasks/import/todotxt", scoped(write, h.Import))
+	return g
+}
+
+// Export serves GET /api/tasks/export.txt, streaming every task that
+// matches the list filters as todo.txt lines. Pagination parameters are
+// ignored.
+func (h *TodoTxtHandler) Export(w http.ResponseWriter, r *http.Request) {
+	filter, err := h.tasks.parseTaskFilter(r.URL.Query())
+	if err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+	projects, err := h.projects.GetAll(r.Context())
+	if err != nil {
+		h.logger.Error("failed to get projects for export", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	tw := todotxt.NewWriter(w, todotxt.Catalog{Projects: projects})
+	h.tasks.export(w, r, filter, exportFile{
+		contentType: "text/plain; charset=utf-8",
+		name:        "todo.txt",
+		write:       tw.Write,
+		end:         tw.Flush,
+	})
+}
+
+// Import serves POST /api/tasks/import/todotxt, importing the todo.txt
+// lines in the body in one transaction. Lines with an id:N extension
+// update that task and the rest create new ones; dry_run=true previews the
+// import without keeping it.
+func (h *TodoTxtHandler) Import(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := parseDryRun(r.URL.Query())
+	if err != nil {
+		response.RespondWithError(w, err)
+		return
+	}
+	catalog, err := h.catalog(r)
+	if err != nil {
+		h.logger.Error("failed to get projects and tags for import", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	rows, err := todotxt.Read(http.MaxBytesReader(w, r.Body, maxImportBytes), catalog)
+	if err != nil {
+		respondImportReadError(w, err)
+		return
+	}
+
+	lines := make([]int, len(rows))
+	ops := make([]domain.TaskOperation, len(rows))
+	for i, row := range rows {
+		lines[i], ops[i] = row.Line, row.Op
+	}
+	h.tasks.importTasks(w, r, lines, ops, dryRun)
+}
+
+// catalog loads the projects and tags an import may name.
+func (h *TodoTxtHandler) catalog(r *http.Request) (todotxt.Catalog, error) {
+	projects, err := h.projects.GetAll(r.Context())
+	if err != nil {
+		return todotxt.Catalog{}, err
+	}
+	tags, err := h.tags.GetAll(r.Context())
+	if err != nil {
+		return todotxt.Catalog{}, err
+	}
+	return todotxt.Catalog{Projects: projects, Tags: tags}, nil
+}
@@ -0,0 +1,187 @@
+package httphandler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+)
+
+func newTestTodoTxtHandler(tasks *mockTaskService, projectErr error) *http.ServeMux {
+	projects := &mockProjectService{
+		getAllFunc: func(ctx context.Context) ([]domain.Project, error) {
+			return []domain.Project{{ID: 1, Name: "Inbox"}, {ID: 2, Name: "Home Improvement"}}, projectErr
+		},
+	}
+	tags := &mockTagService{
+		getAllFunc: func(ctx context.Context) ([]domain.Tag, error) {
+			return []domain.Tag{{ID: 1, Name: "deep work"}}, nil
+		},
+	}
+	taskHandler := NewTaskHandler(slog.Default(), tasks, testCursors, false)
+	return NewTodoTxtHandler(slog.Default(), taskHandler, projects, tags).RegisterRoutes()
+}
+
+func TestTodoTxtHandler_Export(t *testing.T) {
+	created := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	projectID := int64(2)
+
+	testCases := []struct {
+		name           string
+		path           string
+		svcErr         error
+		projectErr     error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "should stream matching tasks",
+			path:           "/api/tasks/export.txt?done=false&limit=1",
+			expectedStatus: http.StatusOK,
+			expectedBody: "(A) 2026-10-18 First +Home_Improvement @deep_work id:1\n" +
+				"2026-10-18 Second id:2\n",
+		},
+		{name: "should reject invalid filters", path: "/api/tasks/export.txt?done=maybe", expectedStatus: http.StatusBadRequest},
+		{name: "should report a failure to load projects", path: "/api/tasks/export.txt", projectErr: domain.ErrProjectRetrievalFailed, expectedStatus: http.StatusInternalServerError},
+		{name: "should report a failure before any output", path: "/api/tasks/export.txt", svcErr: domain.ErrTaskRetrievalFailed, expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				exportFunc: func(ctx context.Context, filter domain.TaskFilter, fn func(domain.Task) error) error {
+					if tc.svcErr != nil {
+						return tc.svcErr
+					}
+					if filter.Done == nil || *filter.Done {
+						t.Errorf("expected the done filter to be passed, got %+v", filter)
+					}
+					tasks := []domain.Task{
+						{ID: 1, Title: "First", Priority: domain.PriorityUrgent, ProjectID: &projectID, Tags: []domain.Tag{{Name: "deep work"}}, CreatedAt: created},
+						{ID: 2, Title: "Second", CreatedAt: created},
+					}
+					for _, task := range tasks {
+						if err := fn(task); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			}
+			mux := newTestTodoTxtHandler(svc, tc.projectErr)
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+				t.Errorf("unexpected Content-Type %s", ct)
+			}
+			if w.Body.String() != tc.expectedBody {
+				t.Errorf("expected body\n%s\nbut got\n%s", tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTodoTxtHandler_Import(t *testing.T) {
+	body := "(B) New task +home_improvement @Deep_Work\nx Renamed id:2\n"
+
+	testCases := []struct {
+		name           string
+		path           string
+		body           string
+		results        []domain.TaskOperationResult
+		committed      bool
+		expectedStatus int
+		expectedDryRun bool
+		expectedRows   []dto.TaskImportRowDTO
+	}{
+		{
+			name:           "should import every line",
+			path:           "/api/tasks/import/todotxt",
+			body:           body,
+			results:        []domain.TaskOperationResult{{Task: domain.Task{ID: 3}}, {Task: domain.Task{ID: 2}}},
+			committed:      true,
+			expectedStatus: http.StatusOK,
+			expectedRows: []dto.TaskImportRowDTO{
+				{Line: 1, Op: "create", Status: http.StatusCreated},
+				{Line: 2, Op: "update", Status: http.StatusOK},
+			},
+		},
+		{
+			name:           "should preview a dry run",
+			path:           "/api/tasks/import/todotxt?dry_run=true",
+			body:           body,
+			results:        []domain.TaskOperationResult{{Task: domain.Task{ID: 3}}, {Task: domain.Task{ID: 2}}},
+			expectedStatus: http.StatusOK,
+			expectedDryRun: true,
+			expectedRows: []dto.TaskImportRowDTO{
+				{Line: 1, Op: "create", Status: http.StatusCreated},
+				{Line: 2, Op: "update", Status: http.StatusOK},
+			},
+		},
+		{name: "should reject unknown projects", path: "/api/tasks/import/todotxt", body: "task +nowhere\n", expectedStatus: http.StatusBadRequest},
+		{name: "should reject an empty file", path: "/api/tasks/import/todotxt", body: "\n\n", expectedStatus: http.StatusBadRequest},
+		{name: "should reject an invalid dry_run", path: "/api/tasks/import/todotxt?dry_run=often", body: body, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTaskService{
+				importFunc: func(ctx context.Context, ops []domain.TaskOperation, dryRun bool) (domain.TaskBatchResult, error) {
+					if dryRun != tc.expectedDryRun {
+						t.Errorf("expected dry run %v, got %v", tc.expectedDryRun, dryRun)
+					}
+					create := ops[0]
+					if len(ops) != 2 || create.Task.Title != "New task" || *create.Task.ProjectID != 2 ||
+						!reflect.DeepEqual(create.Tags, []string{"deep work"}) || ops[1].ID != 2 || !*ops[1].Patch.Done {
+						t.Errorf("unexpected operations %+v", ops)
+					}
+					return domain.TaskBatchResult{Results: tc.results, Committed: tc.committed}, nil
+				},
+			}
+			mux := newTestTodoTxtHandler(svc, nil)
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedRows == nil {
+				return
+			}
+			var resp struct {
+				Data dto.TaskImportResponse `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Data.Committed != tc.committed || resp.Data.DryRun != tc.expectedDryRun {
+				t.Errorf("unexpected committed %v and dry run %v", resp.Data.Committed, resp.Data.DryRun)
+			}
+			for i := range resp.Data.Rows {
+				resp.Data.Rows[i].Task = nil
+			}
+			if !reflect.DeepEqual(resp.Data.Rows, tc.expectedRows) {
+				t.Errorf("expected rows %+v, got %+v", tc.expectedRows, resp.Data.Rows)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package httphandler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+	"github.com/mkeOrt/tasks-go/internal/transport/response"
+)
+
+// TrashService defines the business logic interface for deleted tasks.
+type TrashService interface {
+	List(ctx context.Context) ([]domain.TrashedTask, error)
+	Restore(ctx context.Context, id int64) (domain.Task, error)
+	Purge(ctx context.Context, id int64) error
+}
+
+// TrashHandler handles HTTP requests for the trash of deleted tasks.
+type TrashHandler struct {
+	logger *slog.Logger
+	svc    TrashService
+}
+
+// NewTrashHandler creates a new TrashHandler.
+func NewTrashHandler(logger *slog.Logger, svc TrashService) *TrashHandler {
+	return &TrashHandler{
+		logger: logger,
+		svc:    svc,
+	}
+}
+
+// RegisterRoutes returns the trash routes, including the restore route
+// under /api/tasks/{id}/restore.
+func (h *TrashHandler) RegisterRoutes() *http.ServeMux {
+	g := http.NewServeMux()
+	g.HandleFunc("GET /api/trash", h.List)
+	g.HandleFunc("DELETE /api/trash/{id}", h.Purge)
+	g.HandleFunc("POST /api/tasks/{id}/restore", h.Restore)
+	return g
+}
+
+func (h *TrashHandler) List(w http.ResponseWriter, r *http.Request) {
+	tasks, err := h.svc.List(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list trash", slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusOK, dto.TrashResponse{Tasks: dto.MapTrashedTasksToDTO(tasks)})
+}
+
+// Restore serves POST /api/tasks/{id}/restore, taking the task and the
+// subtasks deleted with it out of the trash.
+func (h *TrashHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	task, err := h.svc.Restore(r.Context(), id)
+	if err != nil {
+		h.logger.Error("failed to restore task", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	response.RespondWithJson(w, http.StatusOK, dto.MapTaskToDTO(task))
+}
+
+// Purge serves DELETE /api/trash/{id}, permanently deleting a task in the
+// trash.
+func (h *TrashHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Purge(r.Context(), id); err != nil {
+		h.logger.Error("failed to purge task", slog.Int64("id", id), slog.String("error", err.Error()))
+		response.RespondWithError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
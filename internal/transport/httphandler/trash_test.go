@@ -0,0 +1,123 @@
+package httphandler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/dto"
+)
+
+type mockTrashService struct {
+	listFunc    func(ctx context.Context) ([]domain.TrashedTask, error)
+	restoreFunc func(ctx context.Context, id int64) (domain.Task, error)
+	purgeFunc   func(ctx context.Context, id int64) error
+}
+
+func (m *mockTrashService) List(ctx context.Context) ([]domain.TrashedTask, error) {
+	return m.listFunc(ctx)
+}
+
+func (m *mockTrashService) Restore(ctx context.Context, id int64) (domain.Task, error) {
+	return m.restoreFunc(ctx, id)
+}
+
+func (m *mockTrashService) Purge(ctx context.Context, id int64) error {
+	return m.purgeFunc(ctx, id)
+}
+
+func TestTrashHandler_List(t *testing.T) {
+	deletedAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	svc := &mockTrashService{
+		listFunc: func(ctx context.Context) ([]domain.TrashedTask, error) {
+			return []domain.TrashedTask{{Task: domain.Task{ID: 4, Title: "Old"}, DeletedAt: deletedAt}}, nil
+		},
+	}
+	mux := NewTrashHandler(slog.Default(), svc).RegisterRoutes()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/trash", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp struct {
+		Data dto.TrashResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Data.Tasks) != 1 || resp.Data.Tasks[0].ID != 4 || resp.Data.Tasks[0].DeletedAt != "2026-10-18T09:00:00Z" {
+		t.Errorf("unexpected trash %+v", resp.Data.Tasks)
+	}
+}
+
+func TestTrashHandler_Restore(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should restore the task", path: "/api/tasks/3/restore", expectedStatus: http.StatusOK},
+		{name: "should reject an invalid ID", path: "/api/tasks/abc/restore", expectedStatus: http.StatusBadRequest},
+		{name: "should report a task outside the trash", path: "/api/tasks/3/restore", svcErr: domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+		{name: "should report a deleted parent", path: "/api/tasks/3/restore", svcErr: domain.ErrParentTaskDeleted, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTrashService{
+				restoreFunc: func(ctx context.Context, id int64) (domain.Task, error) {
+					return domain.Task{ID: id}, tc.svcErr
+				},
+			}
+			mux := NewTrashHandler(slog.Default(), svc).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestTrashHandler_Purge(t *testing.T) {
+	testCases := []struct {
+		name           string
+		svcErr         error
+		expectedStatus int
+	}{
+		{name: "should purge the task", expectedStatus: http.StatusNoContent},
+		{name: "should report a task outside the trash", svcErr: domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockTrashService{
+				purgeFunc: func(ctx context.Context, id int64) error {
+					return tc.svcErr
+				},
+			}
+			mux := NewTrashHandler(slog.Default(), svc).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/trash/3", nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
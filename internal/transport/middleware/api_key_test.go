@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+type apiKeyAuthenticatorFunc func(ctx context.Context, secret string) (domain.APIKey, error)
+
+func (f apiKeyAuthenticatorFunc) Authenticate(ctx context.Context, secret string) (domain.APIKey, error) {
+	return f(ctx, secret)
+}
+
+func TestAPIKey(t *testing.T) {
+	testCases := []struct {
+		name            string
+		headers         map[string]string
+		authErr         error
+		expectedSecret  string
+		expectedKey     int64
+		expectedStatus  int
+		expectChallenge bool
+	}{
+		{name: "should pass requests without a key through", expectedStatus: http.StatusOK},
+		{name: "should leave access tokens alone", headers: map[string]string{"Authorization": "Bearer eyJhbGciOi"}, expectedStatus: http.StatusOK},
+		{name: "should accept the key header", headers: map[string]string{APIKeyHeader: "tsk_good"}, expectedSecret: "tsk_good", expectedKey: 3, expectedStatus: http.StatusOK},
+		{name: "should accept a bearer key", headers: map[string]string{"Authorization": "Bearer tsk_good"}, expectedSecret: "tsk_good", expectedKey: 3, expectedStatus: http.StatusOK},
+		{name: "should prefer the key header", headers: map[string]string{APIKeyHeader: "tsk_good", "Authorization": "Bearer tsk_other"}, expectedSecret: "tsk_good", expectedKey: 3, expectedStatus: http.StatusOK},
+		{name: "should reject an invalid key", headers: map[string]string{APIKeyHeader: "nope"}, expectedSecret: "nope", authErr: fmt.Errorf("authenticate: %w", domain.ErrInvalidAPIKey), expectedStatus: http.StatusUnauthorized},
+		{name: "should challenge an invalid bearer key", headers: map[string]string{"Authorization": "Bearer tsk_bad"}, expectedSecret: "tsk_bad", authErr: domain.ErrInvalidAPIKey, expectedStatus: http.StatusUnauthorized, expectChallenge: true},
+		{name: "should fail when keys cannot be checked", headers: map[string]string{APIKeyHeader: "tsk_good"}, expectedSecret: "tsk_good", authErr: errors.Join(domain.ErrAPIKeyAuthenticationFailed, errors.New("db down")), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auth := apiKeyAuthenticatorFunc(func(ctx context.Context, secret string) (domain.APIKey, error) {
+				if secret != tc.expectedSecret {
+					t.Errorf("expected secret %q, got %q", tc.expectedSecret, secret)
+				}
+				return domain.APIKey{ID: 3, User: domain.User{ID: 7}}, tc.authErr
+			})
+			var (
+				key    domain.APIKey
+				user   domain.User
+				actor  string
+				tenant int64
+			)
+			handler := APIKey(slog.Default(), auth)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				key, _ = domain.APIKeyFrom(r.Context())
+				user, _ = domain.UserFrom(r.Context())
+				actor = domain.ActorFrom(r.Context())
+				tenant, _ = domain.TenantFrom(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if key.ID != tc.expectedKey {
+				t.Errorf("expected key %d, got %d", tc.expectedKey, key.ID)
+			}
+			if tc.expectedKey != 0 && (user.ID != 7 || tenant != 7 || actor != "api_key:3") {
+				t.Errorf("expected user 7 acting as api_key:3 on their tasks, got %d and %q on the tasks of %d", user.ID, actor, tenant)
+			}
+			if got := rr.Header().Get("WWW-Authenticate") != ""; got != tc.expectChallenge {
+				t.Errorf("expected a challenge %v, got %v", tc.expectChallenge, got)
+			}
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	readOnly := domain.APIKey{ID: 3, Scopes: []domain.APIKeyScope{domain.ScopeTasksRead}}
+
+	testCases := []struct {
+		name           string
+		key            *domain.APIKey
+		middleware     func(http.Handler) http.Handler
+		expectedStatus int
+	}{
+		{name: "should not limit requests without a key", middleware: RequireScope(domain.ScopeTasksWrite), expectedStatus: http.StatusOK},
+		{name: "should let a key with the scope through", key: &readOnly, middleware: RequireScope(domain.ScopeTasksRead), expectedStatus: http.StatusOK},
+		{name: "should forbid a key without the scope", key: &readOnly, middleware: RequireScope(domain.ScopeTasksWrite), expectedStatus: http.StatusForbidden},
+		{name: "should let requests without a key reach unscoped routes", middleware: RejectAPIKeys(), expectedStatus: http.StatusOK},
+		{name: "should forbid keys on unscoped routes", key: &readOnly, middleware: RejectAPIKeys(), expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reached := false
+			handler := tc.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tc.key != nil {
+				req = req.WithContext(domain.WithAPIKey(req.Context(), *tc.key))
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus || reached != (tc.expectedStatus == http.StatusOK) {
+				t.Fatalf("expected status %d, got %d (handler reached: %v)", tc.expectedStatus, rr.Code, reached)
+			}
+		})
+	}
+}
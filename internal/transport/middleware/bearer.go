@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/response"
+)
+
+// BearerAuthenticator verifies access tokens.
+type BearerAuthenticator interface {
+	VerifyAccessToken(ctx context.Context, token string) (domain.User, error)
+}
+
+// Bearer signs requests in from an "Authorization: Bearer" access token,
+// the way Session does from a cookie. Unlike a stale cookie, a bad token is
+// an error the client must fix: it is answered with 401 and a
+// WWW-Authenticate challenge. Requests without a bearer token, or whose
+// token is an API key, pass through.
+func Bearer(logger *slog.Logger, auth BearerAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok || strings.HasPrefix(token, domain.APIKeyPrefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			user, err := auth.VerifyAccessToken(r.Context(), token)
+			if errors.Is(err, domain.ErrInvalidToken) {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				response.RespondWithError(w, err)
+				return
+			}
+			if err != nil {
+				logger.Error("failed to verify access token", slog.String("error", err.Error()))
+				response.RespondWithError(w, err)
+				return
+			}
+
+			ctx := domain.WithUser(r.Context(), user)
+			ctx = domain.WithTenant(ctx, user.ID)
+			ctx = domain.WithActor(ctx, user.Actor())
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header. The
+// scheme is matched ignoring case, as RFC 9110 asks.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
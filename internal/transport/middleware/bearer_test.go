@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+type bearerAuthenticatorFunc func(ctx context.Context, token string) (domain.User, error)
+
+func (f bearerAuthenticatorFunc) VerifyAccessToken(ctx context.Context, token string) (domain.User, error) {
+	return f(ctx, token)
+}
+
+func TestBearer(t *testing.T) {
+	testCases := []struct {
+		name           string
+		header         string
+		authErr        error
+		expectedToken  string
+		expectedUser   int64
+		expectedActor  string
+		expectedStatus int
+	}{
+		{name: "should pass anonymous requests through", expectedStatus: http.StatusOK},
+		{name: "should ignore other schemes", header: "Basic YWRhOnNlY3JldA==", expectedStatus: http.StatusOK},
+		{name: "should leave API keys to their middleware", header: "Bearer tsk_key", expectedStatus: http.StatusOK},
+		{name: "should sign in a valid token", header: "Bearer good", expectedToken: "good", expectedUser: 7, expectedActor: "user:7", expectedStatus: http.StatusOK},
+		{name: "should match the scheme ignoring case", header: "bearer good", expectedToken: "good", expectedUser: 7, expectedActor: "user:7", expectedStatus: http.StatusOK},
+		{name: "should reject an invalid token", header: "Bearer bad", expectedToken: "bad", authErr: fmt.Errorf("verify: %w", domain.ErrInvalidToken), expectedStatus: http.StatusUnauthorized},
+		{name: "should fail when tokens cannot be verified", header: "Bearer good", expectedToken: "good", authErr: errors.New("boom"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auth := bearerAuthenticatorFunc(func(ctx context.Context, token string) (domain.User, error) {
+				if token != tc.expectedToken {
+					t.Errorf("expected token %q, got %q", tc.expectedToken, token)
+				}
+				return domain.User{ID: 7}, tc.authErr
+			})
+			var (
+				user   domain.User
+				actor  string
+				tenant int64
+			)
+			handler := Bearer(slog.Default(), auth)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				user, _ = domain.UserFrom(r.Context())
+				actor = domain.ActorFrom(r.Context())
+				tenant, _ = domain.TenantFrom(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if user.ID != tc.expectedUser || tenant != tc.expectedUser || actor != tc.expectedActor {
+				t.Errorf("expected user %d and actor %q on the tasks of the user, got %d and %q on the tasks of %d", tc.expectedUser, tc.expectedActor, user.ID, actor, tenant)
+			}
+			if tc.expectedStatus == http.StatusUnauthorized {
+				if got := rr.Header().Get("WWW-Authenticate"); got != `Bearer error="invalid_token"` {
+					t.Errorf("expected a bearer challenge, got %q", got)
+				}
+				if !strings.Contains(rr.Body.String(), `"success":false`) {
+					t.Errorf("expected a JSON error body, got %s", rr.Body.String())
+				}
+			}
+		})
+	}
+}
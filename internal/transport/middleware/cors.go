@@ -15,12 +15,17 @@ func Cors(cfg *config.CorsConfig) func(http.Handler) http.Handler {
 				return
 			}
 
-			allowed := false
+			// Only origins listed by name may send credentials: a wildcard
+			// would let any site make requests with the user's session cookie.
+			allowed, listed := false, false
 			for _, o := range cfg.AllowedOrigins {
-				if o == "*" || o == origin {
-					allowed = true
+				if o == origin {
+					allowed, listed = true, true
 					break
 				}
+				if o == "*" {
+					allowed = true
+				}
 			}
 
 			if !allowed {
@@ -30,8 +35,11 @@ func Cors(cfg *config.CorsConfig) func(http.Handler) http.Handler {
 
 			w.Header().Set("Access-Control-Allow-Origin", origin)
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-			w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-CSRF-Token")
-			w.Header().Set("Access-Control-Allow-Credentials", "true")
+			w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-CSRF-Token, If-Match, If-None-Match, If-Modified-Since, "+APIKeyHeader+", "+RequestIDHeader)
+			w.Header().Set("Access-Control-Expose-Headers", "ETag, Last-Modified, "+RequestIDHeader)
+			if listed {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
 			w.Header().Set("Access-Control-Max-Age", "600")
 
 			if r.Method == http.MethodOptions {
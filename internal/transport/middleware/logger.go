@@ -4,6 +4,8 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
 )
 
 type responseWriter struct {
@@ -28,12 +30,16 @@ func Logger(logger *slog.Logger) func(http.Handler) http.Handler {
 
 			next.ServeHTTP(rw, r)
 
-			logger.Info("request completed",
+			attrs := []any{
 				"method", r.Method,
 				"path", r.URL.Path,
 				"status", rw.status,
 				"duration", time.Since(start),
-			)
+			}
+			if id := domain.RequestIDFrom(r.Context()); id != "" {
+				attrs = append(attrs, "request_id", id)
+			}
+			logger.Info("request completed", attrs...)
 		})
 	}
 }
@@ -51,3 +51,17 @@ func TestLogger(t *testing.T) {
 		t.Error("expected log to contain 'status=201'")
 	}
 }
+
+func TestLogger_RequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	handler := RequestID()(Logger(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(RequestIDHeader, "req-42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !strings.Contains(buf.String(), "request_id=req-42") {
+		t.Errorf("expected log to contain 'request_id=req-42', got %q", buf.String())
+	}
+}
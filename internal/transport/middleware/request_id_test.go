@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+func TestRequestID(t *testing.T) {
+	testCases := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{name: "should generate an ID when none is sent"},
+		{name: "should reuse the client's ID", incoming: "abc-123.def_4", keep: true},
+		{name: "should replace an ID with unsafe characters", incoming: "bad id\n"},
+		{name: "should replace an overly long ID", incoming: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var seen string
+			handler := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = domain.RequestIDFrom(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tc.incoming != "" {
+				req.Header.Set(RequestIDHeader, tc.incoming)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			echoed := rr.Header().Get(RequestIDHeader)
+			if seen == "" || echoed != seen {
+				t.Fatalf("expected the context ID %q to be echoed, got %q", seen, echoed)
+			}
+			if tc.keep && seen != tc.incoming {
+				t.Errorf("expected ID %q, got %q", tc.incoming, seen)
+			}
+			if !tc.keep && seen == tc.incoming {
+				t.Errorf("expected a generated ID, got %q", seen)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+	"github.com/mkeOrt/tasks-go/internal/transport/response"
+)
+
+// SessionCookie is the name of the cookie that carries a session token.
+const SessionCookie = "session"
+
+// SessionAuthenticator resolves session tokens.
+type SessionAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (domain.Session, error)
+}
+
+// Session signs requests in from their session cookie: the session's user
+// is carried in the request context, the request reaches only that user's
+// tasks, and task changes made by the request are attributed to it.
+// Requests without a live session pass through anonymously; handlers that
+// need a user check for one.
+func Session(logger *slog.Logger, auth SessionAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cookie, err := r.Cookie(SessionCookie)
+			if err != nil || cookie.Value == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			session, err := auth.Authenticate(r.Context(), cookie.Value)
+			if errors.Is(err, domain.ErrUnauthenticated) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if err != nil {
+				logger.Error("failed to authenticate session", slog.String("error", err.Error()))
+				response.RespondWithError(w, err)
+				return
+			}
+
+			ctx := domain.WithUser(r.Context(), session.User)
+			ctx = domain.WithTenant(ctx, session.User.ID)
+			ctx = domain.WithActor(ctx, session.User.Actor())
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
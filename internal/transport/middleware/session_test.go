@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+type sessionAuthenticatorFunc func(ctx context.Context, token string) (domain.Session, error)
+
+func (f sessionAuthenticatorFunc) Authenticate(ctx context.Context, token string) (domain.Session, error) {
+	return f(ctx, token)
+}
+
+func TestSession(t *testing.T) {
+	testCases := []struct {
+		name           string
+		cookie         string
+		authErr        error
+		expectedUser   int64
+		expectedActor  string
+		expectedStatus int
+	}{
+		{name: "should pass anonymous requests through", expectedStatus: http.StatusOK},
+		{name: "should sign in a live session", cookie: "good", expectedUser: 7, expectedActor: "user:7", expectedStatus: http.StatusOK},
+		{name: "should ignore an unknown session", cookie: "stale", authErr: domain.ErrUnauthenticated, expectedStatus: http.StatusOK},
+		{name: "should fail when sessions cannot be read", cookie: "good", authErr: errors.Join(domain.ErrSessionFailed, errors.New("db down")), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auth := sessionAuthenticatorFunc(func(ctx context.Context, token string) (domain.Session, error) {
+				if token != tc.cookie {
+					t.Errorf("expected token %q, got %q", tc.cookie, token)
+				}
+				return domain.Session{User: domain.User{ID: 7}}, tc.authErr
+			})
+			var (
+				user   domain.User
+				actor  string
+				tenant int64
+			)
+			handler := Session(slog.Default(), auth)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				user, _ = domain.UserFrom(r.Context())
+				actor = domain.ActorFrom(r.Context())
+				tenant, _ = domain.TenantFrom(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tc.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: SessionCookie, Value: tc.cookie})
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if user.ID != tc.expectedUser || tenant != tc.expectedUser || actor != tc.expectedActor {
+				t.Errorf("expected user %d and actor %q on the tasks of the user, got %d and %q on the tasks of %d", tc.expectedUser, tc.expectedActor, user.ID, actor, tenant)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package response
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CacheControl is sent with conditional responses: clients may keep a copy
+// but must revalidate it before every reuse, and shared caches must not store
+// it.
+const CacheControl = "private, no-cache"
+
+// Validators describe the current representation of a resource for a
+// conditional GET.
+type Validators struct {
+	// ETag is the quoted strong entity tag. When empty, one is derived from
+	// a hash of the encoded response body.
+	ETag string
+	// LastModified is when the resource last changed; the zero value omits
+	// the Last-Modified header.
+	LastModified time.Time
+}
+
+// RespondWithConditionalJson writes a successful JSON response carrying the
+// ETag, Last-Modified and Cache-Control headers, or 304 Not Modified when the
+// request's If-None-Match or If-Modified-Since shows the client already holds
+// the current representation.
+func RespondWithConditionalJson(w http.ResponseWriter, r *http.Request, payload any, v Validators) {
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(&Response{Success: true, Data: payload}); err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	RespondWithConditional(w, r, "application/json", body.Bytes(), v)
+}
+
+// RespondWithConditional is RespondWithConditionalJson for a body already
+// encoded as contentType.
+func RespondWithConditional(w http.ResponseWriter, r *http.Request, contentType string, body []byte, v Validators) {
+	if v.ETag == "" {
+		sum := sha256.Sum256(body)
+		v.ETag = `"` + hex.EncodeToString(sum[:16]) + `"`
+	}
+
+	h := w.Header()
+	h.Set("ETag", v.ETag)
+	h.Set("Cache-Control", CacheControl)
+	if !v.LastModified.IsZero() {
+		h.Set("Last-Modified", v.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if NotModified(r, v) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	h.Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// NotModified reports whether a GET or HEAD request's preconditions match v.
+// If-None-Match takes precedence; If-Modified-Since is only consulted when it
+// is absent.
+func NotModified(r *http.Request, v Validators) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagListMatches(inm, v.ETag)
+	}
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || v.LastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	return !v.LastModified.Truncate(time.Second).After(since)
+}
+
+// etagListMatches reports whether the If-None-Match list contains etag, using
+// the weak comparison RFC 9110 prescribes for that header.
+func etagListMatches(list, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRespondWithConditionalJson(t *testing.T) {
+	modified := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	v := Validators{ETag: `"7"`, LastModified: modified}
+
+	testCases := []struct {
+		name           string
+		method         string
+		headers        map[string]string
+		expectedStatus int
+	}{
+		{name: "should send the body without preconditions", method: http.MethodGet, expectedStatus: http.StatusOK},
+		{name: "should match a listed ETag", method: http.MethodGet, headers: map[string]string{"If-None-Match": `"6", "7"`}, expectedStatus: http.StatusNotModified},
+		{name: "should match a weak form of the ETag", method: http.MethodGet, headers: map[string]string{"If-None-Match": `W/"7"`}, expectedStatus: http.StatusNotModified},
+		{name: "should match a wildcard", method: http.MethodHead, headers: map[string]string{"If-None-Match": "*"}, expectedStatus: http.StatusNotModified},
+		{name: "should send the body on a stale ETag", method: http.MethodGet, headers: map[string]string{"If-None-Match": `"6"`}, expectedStatus: http.StatusOK},
+		{
+			name:           "should ignore If-Modified-Since when If-None-Match is present",
+			method:         http.MethodGet,
+			headers:        map[string]string{"If-None-Match": `"6"`, "If-Modified-Since": modified.Format(http.TimeFormat)},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "should not be modified since the same second", method: http.MethodGet, headers: map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, expectedStatus: http.StatusNotModified},
+		{name: "should be modified since an earlier time", method: http.MethodGet, headers: map[string]string{"If-Modified-Since": modified.Add(-time.Second).Format(http.TimeFormat)}, expectedStatus: http.StatusOK},
+		{name: "should ignore a malformed If-Modified-Since", method: http.MethodGet, headers: map[string]string{"If-Modified-Since": "yesterday"}, expectedStatus: http.StatusOK},
+		{name: "should only apply to safe methods", method: http.MethodPost, headers: map[string]string{"If-None-Match": `"7"`}, expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/", nil)
+			for k, val := range tc.headers {
+				req.Header.Set(k, val)
+			}
+			w := httptest.NewRecorder()
+			RespondWithConditionalJson(w, req, map[string]int{"id": 1}, v)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+			if got := w.Header().Get("ETag"); got != `"7"` {
+				t.Errorf(`expected ETag "7", got %s`, got)
+			}
+			if got := w.Header().Get("Last-Modified"); got != "Sun, 18 Oct 2026 12:00:00 GMT" {
+				t.Errorf("unexpected Last-Modified %s", got)
+			}
+			if got := w.Header().Get("Cache-Control"); got != CacheControl {
+				t.Errorf("expected Cache-Control %q, got %q", CacheControl, got)
+			}
+			if tc.expectedStatus == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Errorf("expected an empty body, got %q", w.Body.String())
+			}
+			if tc.expectedStatus == http.StatusOK && w.Body.String() != `{"success":true,"data":{"id":1}}`+"\n" {
+				t.Errorf("unexpected body %q", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRespondWithConditionalJson_ContentHash(t *testing.T) {
+	respond := func(payload any, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		RespondWithConditionalJson(w, req, payload, Validators{})
+		return w
+	}
+
+	first := respond([]string{"a", "b"}, "")
+	etag := first.Header().Get("ETag")
+	if len(etag) != 34 || etag[0] != '"' {
+		t.Fatalf("expected a quoted hash ETag, got %s", etag)
+	}
+	if first.Header().Get("Last-Modified") != "" {
+		t.Errorf("expected no Last-Modified without a modification time")
+	}
+	if w := respond([]string{"a", "b"}, etag); w.Code != http.StatusNotModified {
+		t.Errorf("expected the same payload to be not modified, got %d", w.Code)
+	}
+	if w := respond([]string{"a", "c"}, etag); w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("expected a changed payload to get a new ETag, got %d %s", w.Code, w.Header().Get("ETag"))
+	}
+}
@@ -12,8 +12,110 @@ import (
 // Everything else returns a generic internal error message.
 func MapErrorToResponse(err error) (int, string) {
 	switch {
+	case errors.Is(err, domain.ErrInvalidRequest):
+		return http.StatusBadRequest, ErrMsgInvalidRequest
+	case errors.Is(err, domain.ErrValidationFailed):
+		return http.StatusUnprocessableEntity, ErrMsgValidationFailed
+	case errors.Is(err, domain.ErrInvalidCursor):
+		return http.StatusBadRequest, ErrMsgInvalidCursor
+	case errors.Is(err, domain.ErrInvalidSearchQuery):
+		return http.StatusBadRequest, ErrMsgInvalidSearchQuery
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return http.StatusNotFound, ErrMsgTaskNotFound
 	case errors.Is(err, domain.ErrTaskRetrievalFailed):
 		return http.StatusInternalServerError, ErrMsgTaskRetrieve
+	case errors.Is(err, domain.ErrTaskCreationFailed):
+		return http.StatusInternalServerError, ErrMsgTaskCreate
+	case errors.Is(err, domain.ErrTaskUpdateFailed):
+		return http.StatusInternalServerError, ErrMsgTaskUpdate
+	case errors.Is(err, domain.ErrTaskDeletionFailed):
+		return http.StatusInternalServerError, ErrMsgTaskDelete
+	case errors.Is(err, domain.ErrTagNotFound):
+		return http.StatusNotFound, ErrMsgTagNotFound
+	case errors.Is(err, domain.ErrTagAlreadyExists):
+		return http.StatusConflict, ErrMsgTagExists
+	case errors.Is(err, domain.ErrTagRetrievalFailed):
+		return http.StatusInternalServerError, ErrMsgTagRetrieve
+	case errors.Is(err, domain.ErrTagCreationFailed):
+		return http.StatusInternalServerError, ErrMsgTagCreate
+	case errors.Is(err, domain.ErrTagUpdateFailed):
+		return http.StatusInternalServerError, ErrMsgTagUpdate
+	case errors.Is(err, domain.ErrTagDeletionFailed):
+		return http.StatusInternalServerError, ErrMsgTagDelete
+	case errors.Is(err, domain.ErrTaskHierarchyCycle):
+		return http.StatusConflict, ErrMsgTaskCycle
+	case errors.Is(err, domain.ErrTaskTooDeep):
+		return http.StatusConflict, ErrMsgTaskTooDeep
+	case errors.Is(err, domain.ErrDependencyCycle):
+		return http.StatusConflict, ErrMsgDependencyCycle
+	case errors.Is(err, domain.ErrTaskBlocked):
+		return http.StatusConflict, ErrMsgTaskBlocked
+	case errors.Is(err, domain.ErrParentTaskDeleted):
+		return http.StatusConflict, ErrMsgParentTaskDeleted
+	case errors.Is(err, domain.ErrTaskVersionConflict):
+		return http.StatusPreconditionFailed, ErrMsgTaskVersionConflict
+	case errors.Is(err, domain.ErrTaskVersionRequired):
+		return http.StatusPreconditionRequired, ErrMsgTaskVersionRequired
+	case errors.Is(err, domain.ErrTaskOperationSkipped):
+		return http.StatusFailedDependency, ErrMsgTaskOperationSkipped
+	case errors.Is(err, domain.ErrTaskBatchFailed):
+		return http.StatusInternalServerError, ErrMsgTaskBatch
+	case errors.Is(err, domain.ErrTaskImportRejected):
+		return http.StatusUnprocessableEntity, ErrMsgTaskImportRejected
+	case errors.Is(err, domain.ErrTaskImportFailed):
+		return http.StatusInternalServerError, ErrMsgTaskImport
+	case errors.Is(err, domain.ErrProjectNotFound):
+		return http.StatusNotFound, ErrMsgProjectNotFound
+	case errors.Is(err, domain.ErrProjectNotEmpty):
+		return http.StatusConflict, ErrMsgProjectNotEmpty
+	case errors.Is(err, domain.ErrInboxProjectDelete):
+		return http.StatusConflict, ErrMsgInboxProjectDelete
+	case errors.Is(err, domain.ErrProjectRetrievalFailed):
+		return http.StatusInternalServerError, ErrMsgProjectRetrieve
+	case errors.Is(err, domain.ErrProjectCreationFailed):
+		return http.StatusInternalServerError, ErrMsgProjectCreate
+	case errors.Is(err, domain.ErrProjectUpdateFailed):
+		return http.StatusInternalServerError, ErrMsgProjectUpdate
+	case errors.Is(err, domain.ErrProjectDeletionFailed):
+		return http.StatusInternalServerError, ErrMsgProjectDelete
+	case errors.Is(err, domain.ErrCalendarFeedNotFound):
+		return http.StatusNotFound, ErrMsgCalendarFeedNotFound
+	case errors.Is(err, domain.ErrCalendarFeedRetrievalFailed):
+		return http.StatusInternalServerError, ErrMsgCalendarFeedRetrieve
+	case errors.Is(err, domain.ErrCalendarFeedCreationFailed):
+		return http.StatusInternalServerError, ErrMsgCalendarFeedCreate
+	case errors.Is(err, domain.ErrCalendarFeedDeletionFailed):
+		return http.StatusInternalServerError, ErrMsgCalendarFeedDelete
+	case errors.Is(err, domain.ErrUserAlreadyExists):
+		return http.StatusConflict, ErrMsgUserExists
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		return http.StatusUnauthorized, ErrMsgInvalidCredentials
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return http.StatusUnauthorized, ErrMsgUnauthenticated
+	case errors.Is(err, domain.ErrUserCreationFailed):
+		return http.StatusInternalServerError, ErrMsgUserCreate
+	case errors.Is(err, domain.ErrSessionFailed):
+		return http.StatusInternalServerError, ErrMsgSession
+	case errors.Is(err, domain.ErrRefreshTokenReused):
+		return http.StatusUnauthorized, ErrMsgRefreshTokenReused
+	case errors.Is(err, domain.ErrInvalidToken):
+		return http.StatusUnauthorized, ErrMsgInvalidToken
+	case errors.Is(err, domain.ErrTokenFailed):
+		return http.StatusInternalServerError, ErrMsgToken
+	case errors.Is(err, domain.ErrAPIKeyNotFound):
+		return http.StatusNotFound, ErrMsgAPIKeyNotFound
+	case errors.Is(err, domain.ErrInvalidAPIKey):
+		return http.StatusUnauthorized, ErrMsgInvalidAPIKey
+	case errors.Is(err, domain.ErrInsufficientScope):
+		return http.StatusForbidden, ErrMsgInsufficientScope
+	case errors.Is(err, domain.ErrAPIKeyRetrievalFailed):
+		return http.StatusInternalServerError, ErrMsgAPIKeyRetrieve
+	case errors.Is(err, domain.ErrAPIKeyCreationFailed):
+		return http.StatusInternalServerError, ErrMsgAPIKeyCreate
+	case errors.Is(err, domain.ErrAPIKeyDeletionFailed):
+		return http.StatusInternalServerError, ErrMsgAPIKeyDelete
+	case errors.Is(err, domain.ErrAPIKeyAuthenticationFailed):
+		return http.StatusInternalServerError, ErrMsgAPIKeyAuthenticate
 	default:
 		return http.StatusInternalServerError, ErrMsgUnexpected
 	}
@@ -2,6 +2,7 @@ package response
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -21,6 +22,228 @@ func TestMapErrorToResponse(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedMsg:    "Failed to retrieve the task list",
 		},
+		{
+			name:           "Task Not Found",
+			err:            fmt.Errorf("wrapped: %w", domain.ErrTaskNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedMsg:    "The requested task was not found",
+		},
+		{
+			name:           "Task Create Error",
+			err:            domain.ErrTaskCreationFailed,
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    "Failed to create the task",
+		},
+		{
+			name:           "Task Update Error",
+			err:            domain.ErrTaskUpdateFailed,
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    "Failed to update the task",
+		},
+		{
+			name:           "Task Delete Error",
+			err:            domain.ErrTaskDeletionFailed,
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    "Failed to delete the task",
+		},
+		{
+			name:           "Invalid Request",
+			err:            fmt.Errorf("decode: %w", domain.ErrInvalidRequest),
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "The request is malformed",
+		},
+		{
+			name:           "Validation Failed",
+			err:            fmt.Errorf("create: %w", domain.ErrValidationFailed),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedMsg:    "One or more fields are invalid",
+		},
+		{
+			name:           "Invalid Cursor",
+			err:            domain.ErrInvalidCursor,
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "The pagination cursor is invalid or does not match the requested sort",
+		},
+		{
+			name:           "Invalid Search Query",
+			err:            fmt.Errorf("search: %w", domain.ErrInvalidSearchQuery),
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "The search query syntax is invalid",
+		},
+		{
+			name:           "Tag Not Found",
+			err:            fmt.Errorf("attach: %w", domain.ErrTagNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedMsg:    "The requested tag was not found",
+		},
+		{
+			name:           "Tag Already Exists",
+			err:            fmt.Errorf("create: %w", domain.ErrTagAlreadyExists),
+			expectedStatus: http.StatusConflict,
+			expectedMsg:    "A tag with this name already exists",
+		},
+		{
+			name:           "Tag Update Error",
+			err:            domain.ErrTagUpdateFailed,
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    "Failed to update the tag",
+		},
+		{
+			name:           "Task Hierarchy Cycle",
+			err:            fmt.Errorf("patch: %w", domain.ErrTaskHierarchyCycle),
+			expectedStatus: http.StatusConflict,
+			expectedMsg:    "A task cannot be nested under itself or one of its subtasks",
+		},
+		{
+			name:           "Task Too Deep",
+			err:            fmt.Errorf("create: %w", domain.ErrTaskTooDeep),
+			expectedStatus: http.StatusConflict,
+			expectedMsg:    "The task tree would exceed the maximum depth",
+		},
+		{
+			name:           "Dependency Cycle",
+			err:            fmt.Errorf("add blocker: %w", domain.ErrDependencyCycle),
+			expectedStatus: http.StatusConflict,
+			expectedMsg:    "The dependency would create a cycle",
+		},
+		{
+			name:           "Task Blocked",
+			err:            fmt.Errorf("patch: %w", domain.ErrTaskBlocked),
+			expectedStatus: http.StatusConflict,
+			expectedMsg:    "The task is blocked by tasks that are still open",
+		},
+		{
+			name:           "Parent Task Deleted",
+			err:            fmt.Errorf("restore: %w", domain.ErrParentTaskDeleted),
+			expectedStatus: http.StatusConflict,
+			expectedMsg:    "The parent task is in the trash and must be restored first",
+		},
+		{
+			name:           "Task Version Conflict",
+			err:            fmt.Errorf("update: %w", domain.ErrTaskVersionConflict),
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedMsg:    "The task was modified since it was read; fetch it again and retry",
+		},
+		{
+			name:           "Task Version Required",
+			err:            fmt.Errorf("update: %w", domain.ErrTaskVersionRequired),
+			expectedStatus: http.StatusPreconditionRequired,
+			expectedMsg:    "An If-Match header with the task's ETag is required",
+		},
+		{
+			name:           "Task Operation Skipped",
+			err:            domain.ErrTaskOperationSkipped,
+			expectedStatus: http.StatusFailedDependency,
+			expectedMsg:    "Not run because an earlier operation in the batch failed",
+		},
+		{
+			name:           "Task Batch Failed",
+			err:            fmt.Errorf("batch: %w", domain.ErrTaskBatchFailed),
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    "Failed to run the batch of task operations",
+		},
+		{
+			name:           "Task Import Rejected",
+			err:            domain.ErrTaskImportRejected,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedMsg:    "One or more rows could not be imported, so nothing was changed",
+		},
+		{
+			name:           "Task Import Failed",
+			err:            fmt.Errorf("import: %w", domain.ErrTaskImportFailed),
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    "Failed to import the tasks",
+		},
+		{
+			name:           "Project Not Found",
+			err:            fmt.Errorf("get: %w", domain.ErrProjectNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedMsg:    "The requested project was not found",
+		},
+		{
+			name:           "Project Not Empty",
+			err:            fmt.Errorf("delete: %w", domain.ErrProjectNotEmpty),
+			expectedStatus: http.StatusConflict,
+			expectedMsg:    "The project still has tasks",
+		},
+		{
+			name:           "Inbox Project Delete",
+			err:            fmt.Errorf("delete: %w", domain.ErrInboxProjectDelete),
+			expectedStatus: http.StatusConflict,
+			expectedMsg:    "The inbox project cannot be deleted",
+		},
+		{
+			name:           "Calendar Feed Not Found",
+			err:            fmt.Errorf("open: %w", domain.ErrCalendarFeedNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedMsg:    "The requested calendar feed was not found",
+		},
+		{
+			name:           "Calendar Feed Create Failed",
+			err:            fmt.Errorf("create: %w", domain.ErrCalendarFeedCreationFailed),
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    "Failed to create the calendar feed",
+		},
+		{
+			name:           "User Already Exists",
+			err:            fmt.Errorf("register: %w", domain.ErrUserAlreadyExists),
+			expectedStatus: http.StatusConflict,
+			expectedMsg:    "An account with this email already exists",
+		},
+		{
+			name:           "Invalid Credentials",
+			err:            fmt.Errorf("login: %w", domain.ErrInvalidCredentials),
+			expectedStatus: http.StatusUnauthorized,
+			expectedMsg:    "The email or password is incorrect",
+		},
+		{
+			name:           "Unauthenticated",
+			err:            fmt.Errorf("authenticate: %w", domain.ErrUnauthenticated),
+			expectedStatus: http.StatusUnauthorized,
+			expectedMsg:    "Authentication is required",
+		},
+		{
+			name:           "Invalid Token",
+			err:            fmt.Errorf("verify: %w", domain.ErrInvalidToken),
+			expectedStatus: http.StatusUnauthorized,
+			expectedMsg:    "The token is invalid or has expired",
+		},
+		{
+			name:           "Refresh Token Reused",
+			err:            fmt.Errorf("refresh: %w", domain.ErrRefreshTokenReused),
+			expectedStatus: http.StatusUnauthorized,
+			expectedMsg:    "The refresh token was already used; sign in again",
+		},
+		{
+			name:           "Token Failure",
+			err:            fmt.Errorf("issue: %w: %w", domain.ErrTokenFailed, errors.New("db down")),
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    "Failed to process the tokens",
+		},
+		{
+			name:           "API Key Not Found",
+			err:            fmt.Errorf("delete: %w", domain.ErrAPIKeyNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedMsg:    "The requested API key was not found",
+		},
+		{
+			name:           "Invalid API Key",
+			err:            fmt.Errorf("authenticate: %w", domain.ErrInvalidAPIKey),
+			expectedStatus: http.StatusUnauthorized,
+			expectedMsg:    "The API key is invalid or has expired",
+		},
+		{
+			name:           "Insufficient Scope",
+			err:            domain.ErrInsufficientScope,
+			expectedStatus: http.StatusForbidden,
+			expectedMsg:    "The API key does not allow this request",
+		},
+		{
+			name:           "API Key Creation Failure",
+			err:            fmt.Errorf("create: %w: %w", domain.ErrAPIKeyCreationFailed, errors.New("db down")),
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    "Failed to create the API key",
+		},
 		{
 			name:           "Unknown Error",
 			err:            errors.New("unknown error"),
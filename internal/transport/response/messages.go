@@ -1,6 +1,64 @@
 package response
 
 const (
-	ErrMsgTaskRetrieve = "Failed to retrieve the task list"
-	ErrMsgUnexpected   = "An unexpected error occurred while processing the request"
+	ErrMsgTaskRetrieve          = "Failed to retrieve the task list"
+	ErrMsgTaskNotFound          = "The requested task was not found"
+	ErrMsgTaskCreate            = "Failed to create the task"
+	ErrMsgTaskUpdate            = "Failed to update the task"
+	ErrMsgTaskDelete            = "Failed to delete the task"
+	ErrMsgInvalidTaskID         = "The task ID must be a positive integer"
+	ErrMsgInvalidRequest        = "The request is malformed"
+	ErrMsgValidationFailed      = "One or more fields are invalid"
+	ErrMsgBodyTooLarge          = "The request body is too large"
+	ErrMsgInvalidCursor         = "The pagination cursor is invalid or does not match the requested sort"
+	ErrMsgInvalidSearchQuery    = "The search query syntax is invalid"
+	ErrMsgTaskCycle             = "A task cannot be nested under itself or one of its subtasks"
+	ErrMsgTaskTooDeep           = "The task tree would exceed the maximum depth"
+	ErrMsgDependencyCycle       = "The dependency would create a cycle"
+	ErrMsgTaskBlocked           = "The task is blocked by tasks that are still open"
+	ErrMsgParentTaskDeleted     = "The parent task is in the trash and must be restored first"
+	ErrMsgTaskVersionConflict   = "The task was modified since it was read; fetch it again and retry"
+	ErrMsgTaskVersionRequired   = "An If-Match header with the task's ETag is required"
+	ErrMsgTaskOperationSkipped  = "Not run because an earlier operation in the batch failed"
+	ErrMsgTaskBatch             = "Failed to run the batch of task operations"
+	ErrMsgTaskImportRejected    = "One or more rows could not be imported, so nothing was changed"
+	ErrMsgTaskImport            = "Failed to import the tasks"
+	ErrMsgTaskImportTooLarge    = "The import file is too large"
+	ErrMsgTagRetrieve           = "Failed to retrieve the tags"
+	ErrMsgTagNotFound           = "The requested tag was not found"
+	ErrMsgTagExists             = "A tag with this name already exists"
+	ErrMsgTagCreate             = "Failed to create the tag"
+	ErrMsgTagUpdate             = "Failed to update the tag"
+	ErrMsgTagDelete             = "Failed to delete the tag"
+	ErrMsgInvalidTagID          = "The tag ID must be a positive integer"
+	ErrMsgProjectRetrieve       = "Failed to retrieve the projects"
+	ErrMsgProjectNotFound       = "The requested project was not found"
+	ErrMsgProjectNotEmpty       = "The project still has tasks"
+	ErrMsgInboxProjectDelete    = "The inbox project cannot be deleted"
+	ErrMsgProjectCreate         = "Failed to create the project"
+	ErrMsgProjectUpdate         = "Failed to update the project"
+	ErrMsgProjectDelete         = "Failed to delete the project"
+	ErrMsgInvalidProjectID      = "The project ID must be a positive integer"
+	ErrMsgCalendarFeedRetrieve  = "Failed to retrieve the calendar feeds"
+	ErrMsgCalendarFeedNotFound  = "The requested calendar feed was not found"
+	ErrMsgCalendarFeedCreate    = "Failed to create the calendar feed"
+	ErrMsgCalendarFeedDelete    = "Failed to delete the calendar feed"
+	ErrMsgInvalidCalendarFeedID = "The calendar feed ID must be a positive integer"
+	ErrMsgUserExists            = "An account with this email already exists"
+	ErrMsgInvalidCredentials    = "The email or password is incorrect"
+	ErrMsgUnauthenticated       = "Authentication is required"
+	ErrMsgUserCreate            = "Failed to create the account"
+	ErrMsgSession               = "Failed to process the session"
+	ErrMsgInvalidToken          = "The token is invalid or has expired"
+	ErrMsgRefreshTokenReused    = "The refresh token was already used; sign in again"
+	ErrMsgToken                 = "Failed to process the tokens"
+	ErrMsgAPIKeyNotFound        = "The requested API key was not found"
+	ErrMsgInvalidAPIKeyID       = "The API key ID must be a positive integer"
+	ErrMsgInvalidAPIKey         = "The API key is invalid or has expired"
+	ErrMsgInsufficientScope     = "The API key does not allow this request"
+	ErrMsgAPIKeyRetrieve        = "Failed to retrieve the API keys"
+	ErrMsgAPIKeyCreate          = "Failed to create the API key"
+	ErrMsgAPIKeyDelete          = "Failed to delete the API key"
+	ErrMsgAPIKeyAuthenticate    = "Failed to check the API key"
+	ErrMsgUnexpected            = "An unexpected error occurred while processing the request"
 )
@@ -0,0 +1,67 @@
+package response
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Negotiate returns the offered content type the request's Accept header
+// ranks highest. Ties, a missing header and a header accepting none of the
+// offers all go to the first offer, so clients that ask for something else
+// still get the default representation rather than 406.
+func Negotiate(r *http.Request, offers ...string) string {
+	accept := r.Header.Values("Accept")
+	best, bestQ := offers[0], 0.0
+	for _, offer := range offers {
+		if q := quality(accept, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// quality returns the weight accept gives to offer, taken from the most
+// specific media range that matches it. Without an Accept header
+// everything weighs 1.
+func quality(accept []string, offer string) float64 {
+	if len(accept) == 0 {
+		return 1
+	}
+	offerType, _, err := mime.ParseMediaType(offer)
+	if err != nil {
+		return 0
+	}
+	major, _, _ := strings.Cut(offerType, "/")
+
+	q, specificity := 0.0, -1
+	for _, header := range accept {
+		for _, value := range strings.Split(header, ",") {
+			mediaType, params, err := mime.ParseMediaType(value)
+			if err != nil {
+				continue
+			}
+			s := -1
+			switch mediaType {
+			case offerType:
+				s = 2
+			case major + "/*":
+				s = 1
+			case "*/*":
+				s = 0
+			}
+			if s <= specificity {
+				continue
+			}
+			weight := 1.0
+			if raw, ok := params["q"]; ok {
+				if weight, err = strconv.ParseFloat(raw, 64); err != nil {
+					weight = 0
+				}
+			}
+			q, specificity = weight, s
+		}
+	}
+	return q
+}
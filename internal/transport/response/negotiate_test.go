@@ -0,0 +1,38 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{"application/json", "text/markdown; charset=utf-8"}
+
+	testCases := []struct {
+		name     string
+		accept   string
+		expected string
+	}{
+		{name: "should default to the first offer without a header", expected: "application/json"},
+		{name: "should pick an exact match", accept: "text/markdown", expected: offers[1]},
+		{name: "should pick the highest weight", accept: "application/json;q=0.5, text/markdown", expected: offers[1]},
+		{name: "should prefer the first offer on a tie", accept: "text/markdown, application/json", expected: "application/json"},
+		{name: "should match a type wildcard", accept: "text/*, application/json;q=0.1", expected: offers[1]},
+		{name: "should let an exact range override a wildcard", accept: "text/*, text/markdown;q=0", expected: "application/json"},
+		{name: "should fall back when nothing is acceptable", accept: "image/png", expected: "application/json"},
+		{name: "should skip malformed ranges", accept: "text/markdown;q=x, ;;, */*", expected: "application/json"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			if got := Negotiate(req, offers...); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
@@ -3,13 +3,16 @@ package response
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/mkeOrt/tasks-go/internal/validation"
 )
 
 // Response is a generic HTTP response wrapper.
 type Response struct {
-	Success bool   `json:"success"`
-	Data    any    `json:"data,omitempty"`
-	Error   string `json:"error,omitempty"`
+	Success bool                    `json:"success"`
+	Data    any                     `json:"data,omitempty"`
+	Error   string                  `json:"error,omitempty"`
+	Errors  []validation.FieldError `json:"errors,omitempty"`
 }
 
 // ResponseWithJson writes a JSON response and handles encoding errors.
@@ -32,7 +35,11 @@ func RespondWithJson(w http.ResponseWriter, code int, payload any) {
 // RespondWithError writes an error response, mapping the error to a status code and message.
 func RespondWithError(w http.ResponseWriter, err error) {
 	code, msg := MapErrorToResponse(err)
-	RespondWithErrorJson(w, code, msg)
+	ResponseWithJson(w, code, &Response{
+		Success: false,
+		Error:   msg,
+		Errors:  validation.Fields(err),
+	})
 }
 
 // RespondWithErrorJson writes an error JSON response.
@@ -0,0 +1,152 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+// CodeInvalidJSON is reported when the body is not a JSON object.
+const CodeInvalidJSON = "invalid_json"
+
+// DecodeJSON decodes a JSON object from r into dst, which must point to a struct.
+// Unlike json.Decoder it does not stop at the first problem: every unknown
+// field and every value of the wrong type is reported in a single
+// domain.ErrInvalidRequest error. Anything but whitespace after the object
+// is rejected the same way, while errors reading r, such as the
+// *http.MaxBytesError of a body over its limit, are returned as they are.
+func DecodeJSON(r io.Reader, dst any) error {
+	body := &readErrRecorder{r: r}
+	dec := json.NewDecoder(body)
+	var raw map[string]json.RawMessage
+	err := dec.Decode(&raw)
+	if body.err != nil {
+		return body.err
+	}
+	if err != nil || raw == nil {
+		return invalidBody("request body must be a JSON object")
+	}
+	err = dec.Decode(&json.RawMessage{})
+	if body.err != nil {
+		return body.err
+	}
+	if !errors.Is(err, io.EOF) {
+		return invalidBody("request body must hold a single JSON object")
+	}
+
+	rv := reflect.ValueOf(dst).Elem()
+	known := jsonFields(rv.Type())
+
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var fields []FieldError
+	for _, key := range keys {
+		idx, ok := known[key]
+		if !ok {
+			fields = append(fields, FieldError{Field: key, Code: CodeUnknownField, Message: key + " is not a known field"})
+			continue
+		}
+		field := rv.Field(idx)
+		if err := json.Unmarshal(raw[key], field.Addr().Interface()); err != nil {
+			fields = append(fields, FieldError{Field: key, Code: CodeInvalidType, Message: key + " must be " + DescribeType(field.Type())})
+		}
+	}
+
+	if len(fields) > 0 {
+		return &Error{Kind: domain.ErrInvalidRequest, Fields: fields}
+	}
+	return nil
+}
+
+// invalidBody reports a body that is not a single JSON object.
+func invalidBody(message string) error {
+	return &Error{
+		Kind:   domain.ErrInvalidRequest,
+		Fields: []FieldError{{Field: "body", Code: CodeInvalidJSON, Message: message}},
+	}
+}
+
+// readErrRecorder keeps the last error, other than io.EOF, met reading r,
+// which json.Decoder does not tell apart from malformed JSON.
+type readErrRecorder struct {
+	r   io.Reader
+	err error
+}
+
+func (e *readErrRecorder) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		e.err = err
+	}
+	return n, err
+}
+
+// jsonFields maps the JSON names of the exported fields of t to their index.
+func jsonFields(t reflect.Type) map[string]int {
+	fields := make(map[string]int, t.NumField())
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = i
+	}
+	return fields
+}
+
+// TypeDescriber is implemented by field types that need a custom
+// description in type mismatch messages.
+type TypeDescriber interface {
+	DescribeType() string
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	typeDescriberType = reflect.TypeFor[TypeDescriber]()
+)
+
+// DescribeType returns a human readable description of the JSON value
+// expected for t, such as "a string".
+func DescribeType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Implements(typeDescriberType) {
+		return reflect.Zero(t).Interface().(TypeDescriber).DescribeType()
+	}
+	if t == timeType {
+		return "an RFC 3339 timestamp"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
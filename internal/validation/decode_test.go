@@ -0,0 +1,104 @@
+package validation
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+type decodeTarget struct {
+	Title string `json:"title"`
+	Done  *bool  `json:"done"`
+	Count int    `json:"count,omitempty"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	done := true
+
+	testCases := []struct {
+		name           string
+		body           string
+		expected       decodeTarget
+		expectedFields []FieldError
+	}{
+		{
+			name:     "should decode valid object",
+			body:     `{"title":"a","done":true,"count":2}`,
+			expected: decodeTarget{Title: "a", Done: &done, Count: 2},
+		},
+		{
+			name: "should reject malformed json",
+			body: `{"title":`,
+			expectedFields: []FieldError{
+				{Field: "body", Code: CodeInvalidJSON, Message: "request body must be a JSON object"},
+			},
+		},
+		{
+			name: "should reject non object body",
+			body: `null`,
+			expectedFields: []FieldError{
+				{Field: "body", Code: CodeInvalidJSON, Message: "request body must be a JSON object"},
+			},
+		},
+		{
+			name: "should reject data after the object",
+			body: `{"title":"a"} {"title":"b"}`,
+			expectedFields: []FieldError{
+				{Field: "body", Code: CodeInvalidJSON, Message: "request body must hold a single JSON object"},
+			},
+		},
+		{
+			name:     "should accept whitespace after the object",
+			body:     "{\"title\":\"a\"}\n",
+			expected: decodeTarget{Title: "a"},
+		},
+		{
+			name: "should report every failing field",
+			body: `{"title":1,"done":"yes","extra":true,"count":1.5}`,
+			expectedFields: []FieldError{
+				{Field: "count", Code: CodeInvalidType, Message: "count must be an integer"},
+				{Field: "done", Code: CodeInvalidType, Message: "done must be a boolean"},
+				{Field: "extra", Code: CodeUnknownField, Message: "extra is not a known field"},
+				{Field: "title", Code: CodeInvalidType, Message: "title must be a string"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var dst decodeTarget
+			err := DecodeJSON(strings.NewReader(tc.body), &dst)
+
+			if tc.expectedFields == nil {
+				if err != nil {
+					t.Fatalf("expected no error but got %v", err)
+				}
+				if !reflect.DeepEqual(dst, tc.expected) {
+					t.Fatalf("expected %+v but got %+v", tc.expected, dst)
+				}
+				return
+			}
+			if !errors.Is(err, domain.ErrInvalidRequest) {
+				t.Fatalf("expected error %v but got %v", domain.ErrInvalidRequest, err)
+			}
+			if !reflect.DeepEqual(Fields(err), tc.expectedFields) {
+				t.Fatalf("expected fields %v but got %v", tc.expectedFields, Fields(err))
+			}
+		})
+	}
+}
+
+func TestDecodeJSON_ReadError(t *testing.T) {
+	errRead := errors.New("connection reset")
+	r := io.MultiReader(strings.NewReader(`{"title":`), iotest.ErrReader(errRead))
+
+	var dst decodeTarget
+	if err := DecodeJSON(r, &dst); !errors.Is(err, errRead) {
+		t.Fatalf("expected error %v but got %v", errRead, err)
+	}
+}
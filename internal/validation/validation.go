@@ -0,0 +1,117 @@
+// Package validation collects field-level validation failures so that every
+// problem in a request can be reported at once.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+// Error codes reported in FieldError.Code.
+const (
+	CodeRequired     = "required"
+	CodeTooShort     = "too_short"
+	CodeTooLong      = "too_long"
+	CodeUnknownField = "unknown_field"
+	CodeInvalidType  = "invalid_type"
+	CodeInvalidValue = "invalid_value"
+)
+
+// FieldError describes a single invalid field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error is a set of field failures. It unwraps to its Kind, which is either
+// domain.ErrValidationFailed or domain.ErrInvalidRequest.
+type Error struct {
+	Kind   error
+	Fields []FieldError
+}
+
+func (e *Error) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Kind.Error()
+	}
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Field + ": " + f.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Kind, strings.Join(parts, "; "))
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// Validator accumulates field failures.
+type Validator struct {
+	fields []FieldError
+}
+
+// New creates an empty Validator.
+func New() *Validator {
+	return &Validator{}
+}
+
+// Add records a failure for field.
+func (v *Validator) Add(field, code, message string) {
+	v.fields = append(v.fields, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Required checks that value is not blank.
+func (v *Validator) Required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.Add(field, CodeRequired, field+" is required")
+		return false
+	}
+	return true
+}
+
+// MaxLength checks that value has at most max characters.
+func (v *Validator) MaxLength(field, value string, max int) bool {
+	if utf8.RuneCountInString(value) > max {
+		v.Add(field, CodeTooLong, fmt.Sprintf("%s must be at most %d characters", field, max))
+		return false
+	}
+	return true
+}
+
+// Valid reports whether no failures were recorded.
+func (v *Validator) Valid() bool {
+	return len(v.fields) == 0
+}
+
+// Err returns the recorded failures as a domain.ErrValidationFailed error,
+// or nil when there are none.
+func (v *Validator) Err() error {
+	if v.Valid() {
+		return nil
+	}
+	return &Error{Kind: domain.ErrValidationFailed, Fields: v.fields}
+}
+
+// RequestErr returns the recorded failures as a domain.ErrInvalidRequest
+// error, or nil when there are none. Use it for input that could not be
+// parsed, such as query parameters.
+func (v *Validator) RequestErr() error {
+	if v.Valid() {
+		return nil
+	}
+	return &Error{Kind: domain.ErrInvalidRequest, Fields: v.fields}
+}
+
+// Fields returns the failures recorded in err, if any.
+func Fields(err error) []FieldError {
+	var verr *Error
+	if errors.As(err, &verr) {
+		return verr.Fields
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mkeOrt/tasks-go/internal/domain"
+)
+
+func TestValidator(t *testing.T) {
+	testCases := []struct {
+		name     string
+		title    string
+		expected []FieldError
+	}{
+		{
+			name:     "should accept valid title",
+			title:    "Work",
+			expected: nil,
+		},
+		{
+			name:  "should reject blank title",
+			title: "   ",
+			expected: []FieldError{
+				{Field: "title", Code: CodeRequired, Message: "title is required"},
+			},
+		},
+		{
+			name:  "should reject long title",
+			title: strings.Repeat("á", 6),
+			expected: []FieldError{
+				{Field: "title", Code: CodeTooLong, Message: "title must be at most 5 characters"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := New()
+			if v.Required("title", tc.title) {
+				v.MaxLength("title", tc.title, 5)
+			}
+
+			err := v.Err()
+			if tc.expected == nil {
+				if err != nil {
+					t.Fatalf("expected no error but got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, domain.ErrValidationFailed) {
+				t.Fatalf("expected error %v but got %v", domain.ErrValidationFailed, err)
+			}
+			if !reflect.DeepEqual(Fields(err), tc.expected) {
+				t.Fatalf("expected fields %v but got %v", tc.expected, Fields(err))
+			}
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	verr := &Error{Kind: domain.ErrValidationFailed, Fields: []FieldError{{Field: "a", Code: "b", Message: "c"}}}
+
+	if got := Fields(fmt.Errorf("wrapped: %w", verr)); len(got) != 1 {
+		t.Fatalf("expected wrapped fields to be found, got %v", got)
+	}
+	if got := Fields(errors.New("plain")); got != nil {
+		t.Fatalf("expected no fields, got %v", got)
+	}
+}
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE VIRTUAL TABLE IF NOT EXISTS tasks_fts USING fts5(
+	title,
+	content = 'tasks',
+	content_rowid = 'id',
+	tokenize = 'unicode61 remove_diacritics 2'
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS tasks_fts_after_insert AFTER INSERT ON tasks BEGIN
+	INSERT INTO tasks_fts (rowid, title) VALUES (new.id, new.title);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS tasks_fts_after_delete AFTER DELETE ON tasks BEGIN
+	INSERT INTO tasks_fts (tasks_fts, rowid, title) VALUES ('delete', old.id, old.title);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS tasks_fts_after_update AFTER UPDATE OF title ON tasks BEGIN
+	INSERT INTO tasks_fts (tasks_fts, rowid, title) VALUES ('delete', old.id, old.title);
+	INSERT INTO tasks_fts (rowid, title) VALUES (new.id, new.title);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO tasks_fts (tasks_fts) VALUES ('rebuild');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS tasks_fts_after_update;
+DROP TRIGGER IF EXISTS tasks_fts_after_delete;
+DROP TRIGGER IF EXISTS tasks_fts_after_insert;
+DROP TABLE IF EXISTS tasks_fts;
+-- +goose StatementEnd
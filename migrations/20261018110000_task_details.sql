@@ -0,0 +1,94 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE tasks ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE tasks ADD COLUMN start_at TIMESTAMP NULL;
+ALTER TABLE tasks ADD COLUMN due_at TIMESTAMP NULL;
+ALTER TABLE tasks ADD COLUMN completed_at TIMESTAMP NULL;
+UPDATE tasks SET completed_at = updated_at WHERE done = 1;
+CREATE INDEX IF NOT EXISTS idx_tasks_due_at ON tasks (due_at);
+-- +goose StatementEnd
+
+-- Rebuild the search index so descriptions are searchable too.
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS tasks_fts_after_update;
+DROP TRIGGER IF EXISTS tasks_fts_after_delete;
+DROP TRIGGER IF EXISTS tasks_fts_after_insert;
+DROP TABLE IF EXISTS tasks_fts;
+CREATE VIRTUAL TABLE tasks_fts USING fts5(
+	title,
+	description,
+	content = 'tasks',
+	content_rowid = 'id',
+	tokenize = 'unicode61 remove_diacritics 2'
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER tasks_fts_after_insert AFTER INSERT ON tasks BEGIN
+	INSERT INTO tasks_fts (rowid, title, description) VALUES (new.id, new.title, new.description);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER tasks_fts_after_delete AFTER DELETE ON tasks BEGIN
+	INSERT INTO tasks_fts (tasks_fts, rowid, title, description) VALUES ('delete', old.id, old.title, old.description);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER tasks_fts_after_update AFTER UPDATE OF title, description ON tasks BEGIN
+	INSERT INTO tasks_fts (tasks_fts, rowid, title, description) VALUES ('delete', old.id, old.title, old.description);
+	INSERT INTO tasks_fts (rowid, title, description) VALUES (new.id, new.title, new.description);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO tasks_fts (tasks_fts) VALUES ('rebuild');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS tasks_fts_after_update;
+DROP TRIGGER IF EXISTS tasks_fts_after_delete;
+DROP TRIGGER IF EXISTS tasks_fts_after_insert;
+DROP TABLE IF EXISTS tasks_fts;
+CREATE VIRTUAL TABLE tasks_fts USING fts5(
+	title,
+	content = 'tasks',
+	content_rowid = 'id',
+	tokenize = 'unicode61 remove_diacritics 2'
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER tasks_fts_after_insert AFTER INSERT ON tasks BEGIN
+	INSERT INTO tasks_fts (rowid, title) VALUES (new.id, new.title);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER tasks_fts_after_delete AFTER DELETE ON tasks BEGIN
+	INSERT INTO tasks_fts (tasks_fts, rowid, title) VALUES ('delete', old.id, old.title);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER tasks_fts_after_update AFTER UPDATE OF title ON tasks BEGIN
+	INSERT INTO tasks_fts (tasks_fts, rowid, title) VALUES ('delete', old.id, old.title);
+	INSERT INTO tasks_fts (rowid, title) VALUES (new.id, new.title);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO tasks_fts (tasks_fts) VALUES ('rebuild');
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_tasks_due_at;
+ALTER TABLE tasks DROP COLUMN completed_at;
+ALTER TABLE tasks DROP COLUMN due_at;
+ALTER TABLE tasks DROP COLUMN start_at;
+ALTER TABLE tasks DROP COLUMN priority;
+ALTER TABLE tasks DROP COLUMN description;
+-- +goose StatementEnd
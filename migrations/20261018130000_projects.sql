@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS projects (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	inbox BOOLEAN NOT NULL DEFAULT 0,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- At most one project is the inbox that receives the tasks of deleted projects.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_projects_inbox ON projects (inbox) WHERE inbox = 1;
+INSERT INTO projects (name, inbox) VALUES ('Inbox', 1);
+
+ALTER TABLE tasks ADD COLUMN project_id INTEGER NULL REFERENCES projects (id);
+CREATE INDEX IF NOT EXISTS idx_tasks_project_id ON tasks (project_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_tasks_project_id;
+ALTER TABLE tasks DROP COLUMN project_id;
+DROP INDEX IF EXISTS idx_projects_inbox;
+DROP TABLE IF EXISTS projects;
+-- +goose StatementEnd
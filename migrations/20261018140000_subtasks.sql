@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Deleting a task deletes its whole subtree.
+ALTER TABLE tasks ADD COLUMN parent_id INTEGER NULL REFERENCES tasks (id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks (parent_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_tasks_parent_id;
+ALTER TABLE tasks DROP COLUMN parent_id;
+-- +goose StatementEnd
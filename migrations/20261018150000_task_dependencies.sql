@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A row means task_id cannot be completed until blocker_id is done.
+CREATE TABLE IF NOT EXISTS task_dependencies (
+	task_id INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+	blocker_id INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (task_id, blocker_id),
+	CHECK (task_id <> blocker_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocker_id ON task_dependencies (blocker_id, task_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_task_dependencies_blocker_id;
+DROP TABLE IF EXISTS task_dependencies;
+-- +goose StatementEnd
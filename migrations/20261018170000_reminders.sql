@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One reminder per task and due time: moving the due time schedules a new
+-- one, while restarts never schedule the same reminder twice.
+CREATE TABLE IF NOT EXISTS reminders (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	task_id INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+	due_at TIMESTAMP NOT NULL,
+	remind_at TIMESTAMP NOT NULL,
+	fired_at TIMESTAMP NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (task_id, due_at)
+);
+CREATE INDEX IF NOT EXISTS idx_reminders_pending ON reminders (remind_at) WHERE fired_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_reminders_pending;
+DROP TABLE IF EXISTS reminders;
+-- +goose StatementEnd
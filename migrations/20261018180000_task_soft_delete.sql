@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A task with deleted_at set is in the trash until it is restored or purged.
+ALTER TABLE tasks ADD COLUMN deleted_at TIMESTAMP NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_tasks_deleted_at;
+ALTER TABLE tasks DROP COLUMN deleted_at;
+-- +goose StatementEnd
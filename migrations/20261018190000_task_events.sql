@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Each row records one change to a task. changes maps the API name of every
+-- changed field to an object with its old and new value.
+CREATE TABLE IF NOT EXISTS task_events (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	task_id INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+	action TEXT NOT NULL,
+	changes TEXT NOT NULL DEFAULT '{}',
+	actor TEXT NULL,
+	request_id TEXT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_events_task_id ON task_events (task_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_task_events_task_id;
+DROP TABLE IF EXISTS task_events;
+-- +goose StatementEnd
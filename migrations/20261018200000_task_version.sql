@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- version grows with every change to a task and backs optimistic locking.
+ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tasks DROP COLUMN version;
+-- +goose StatementEnd
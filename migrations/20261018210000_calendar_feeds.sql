@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Calendar clients authenticate with a secret token in the feed URL; only
+-- its SHA-256 hash is kept, so a leaked database does not leak feeds.
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	token_hash TEXT NOT NULL UNIQUE,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	last_used_at TIMESTAMP NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS calendar_feeds;
+-- +goose StatementEnd
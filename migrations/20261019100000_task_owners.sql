@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tasks belong to the user who created them and are invisible to everyone
+-- else. Tasks without an owner, such as those created before accounts
+-- existed, are shared by anonymous callers. Calendar feeds list the tasks
+-- of their owner the same way.
+ALTER TABLE tasks ADD COLUMN owner_id INTEGER NULL REFERENCES users (id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_tasks_owner_id ON tasks (owner_id);
+ALTER TABLE calendar_feeds ADD COLUMN owner_id INTEGER NULL REFERENCES users (id) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE calendar_feeds DROP COLUMN owner_id;
+DROP INDEX IF EXISTS idx_tasks_owner_id;
+ALTER TABLE tasks DROP COLUMN owner_id;
+-- +goose StatementEnd
@@ -0,0 +1,89 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tags belong to a tenant like tasks do, and their names are unique per
+-- tenant instead of globally. Existing tags stay without an owner, and each
+-- tenant whose tasks carry one of them gets its own copy, which its tasks
+-- are moved to. SQLite cannot drop the old UNIQUE constraint in place, so
+-- both tables are rebuilt; task_tags first points at the new table, so that
+-- dropping the old one cascades to nothing.
+CREATE TABLE tags_owned (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	owner_id INTEGER NULL REFERENCES users (id) ON DELETE CASCADE,
+	name TEXT NOT NULL COLLATE NOCASE,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO tags_owned (id, name, created_at, updated_at)
+SELECT id, name, created_at, updated_at FROM tags;
+
+INSERT INTO tags_owned (owner_id, name, created_at, updated_at)
+SELECT DISTINCT tasks.owner_id, tags.name, tags.created_at, tags.updated_at
+FROM task_tags
+JOIN tasks ON tasks.id = task_tags.task_id
+JOIN tags ON tags.id = task_tags.tag_id
+WHERE tasks.owner_id IS NOT NULL;
+
+CREATE TABLE task_tags_owned (
+	task_id INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+	tag_id INTEGER NOT NULL REFERENCES tags_owned (id) ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (task_id, tag_id)
+);
+
+INSERT INTO task_tags_owned (task_id, tag_id, created_at)
+SELECT task_tags.task_id,
+	IFNULL((
+		SELECT owned.id FROM tags_owned AS owned JOIN tags ON tags.id = task_tags.tag_id
+		WHERE owned.owner_id = tasks.owner_id AND owned.name = tags.name
+	), task_tags.tag_id),
+	task_tags.created_at
+FROM task_tags
+JOIN tasks ON tasks.id = task_tags.task_id;
+
+DROP INDEX IF EXISTS idx_task_tags_tag_id;
+DROP TABLE task_tags;
+DROP TABLE tags;
+ALTER TABLE tags_owned RENAME TO tags;
+ALTER TABLE task_tags_owned RENAME TO task_tags;
+
+-- A plain UNIQUE (owner_id, name) would let tags without an owner repeat
+-- names, since SQLite treats NULLs as distinct.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_owner_name ON tags (IFNULL(owner_id, 0), name);
+CREATE INDEX IF NOT EXISTS idx_task_tags_tag_id ON task_tags (tag_id, task_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Tags with the same name are merged into the one created first.
+CREATE TABLE tags_shared (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO tags_shared (id, name, created_at, updated_at)
+SELECT MIN(id), name, created_at, updated_at FROM tags GROUP BY name;
+
+CREATE TABLE task_tags_shared (
+	task_id INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+	tag_id INTEGER NOT NULL REFERENCES tags_shared (id) ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (task_id, tag_id)
+);
+
+INSERT OR IGNORE INTO task_tags_shared (task_id, tag_id, created_at)
+SELECT task_tags.task_id, tags_shared.id, task_tags.created_at
+FROM task_tags
+JOIN tags ON tags.id = task_tags.tag_id
+JOIN tags_shared ON tags_shared.name = tags.name;
+
+DROP INDEX IF EXISTS idx_task_tags_tag_id;
+DROP INDEX IF EXISTS idx_tags_owner_name;
+DROP TABLE task_tags;
+DROP TABLE tags;
+ALTER TABLE tags_shared RENAME TO tags;
+ALTER TABLE task_tags_shared RENAME TO task_tags;
+CREATE INDEX IF NOT EXISTS idx_task_tags_tag_id ON task_tags (tag_id, task_id);
+-- +goose StatementEnd
@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Projects belong to a tenant like tasks do, and every tenant has an inbox
+-- of its own. Existing projects stay without an owner, and each tenant with
+-- tasks in one of them gets its own copy, which its tasks are moved to.
+ALTER TABLE projects ADD COLUMN owner_id INTEGER NULL REFERENCES users (id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_projects_owner_id ON projects (owner_id);
+DROP INDEX IF EXISTS idx_projects_inbox;
+
+CREATE TEMP TABLE project_copies AS
+SELECT (SELECT IFNULL(MAX(id), 0) FROM projects) + ROW_NUMBER() OVER (ORDER BY project_id, owner_id) AS id, project_id, owner_id
+FROM (SELECT DISTINCT project_id, owner_id FROM tasks WHERE project_id IS NOT NULL AND owner_id IS NOT NULL);
+
+INSERT INTO projects (id, owner_id, name, description, inbox, created_at, updated_at)
+SELECT project_copies.id, project_copies.owner_id, projects.name, projects.description, projects.inbox, projects.created_at, projects.updated_at
+FROM project_copies
+JOIN projects ON projects.id = project_copies.project_id;
+
+UPDATE tasks SET
+	project_id = (
+		SELECT id FROM project_copies
+		WHERE project_copies.project_id = tasks.project_id AND project_copies.owner_id = tasks.owner_id
+	),
+	version = version + 1
+WHERE project_id IS NOT NULL AND owner_id IS NOT NULL;
+
+DROP TABLE project_copies;
+
+INSERT INTO projects (owner_id, name, inbox)
+SELECT id, 'Inbox', 1 FROM users
+WHERE NOT EXISTS (SELECT 1 FROM projects WHERE projects.owner_id = users.id AND projects.inbox = 1);
+
+-- At most one project of each tenant is the inbox that receives the tasks of
+-- its deleted projects. IFNULL keeps the inbox without an owner unique too.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_projects_inbox ON projects (IFNULL(owner_id, 0)) WHERE inbox = 1;
+
+CREATE TRIGGER IF NOT EXISTS users_after_insert_inbox AFTER INSERT ON users BEGIN
+	INSERT INTO projects (owner_id, name, inbox) VALUES (new.id, 'Inbox', 1);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- The tasks in the inboxes of tenants go back to the shared inbox.
+DROP TRIGGER IF EXISTS users_after_insert_inbox;
+DROP INDEX IF EXISTS idx_projects_inbox;
+
+UPDATE tasks SET
+	project_id = (SELECT id FROM projects WHERE inbox = 1 AND owner_id IS NULL),
+	version = version + 1
+WHERE project_id IN (SELECT id FROM projects WHERE inbox = 1 AND owner_id IS NOT NULL);
+DELETE FROM projects WHERE inbox = 1 AND owner_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_projects_owner_id;
+ALTER TABLE projects DROP COLUMN owner_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_projects_inbox ON projects (inbox) WHERE inbox = 1;
+-- +goose StatementEnd
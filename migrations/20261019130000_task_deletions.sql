@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every delete stamps the tasks it moves to the trash with a deletion ID of
+-- its own, so that restoring a task brings back the subtasks deleted with it
+-- and no others. Tasks already in the trash get one deletion per tenant and
+-- deleted_at, the closest record left of how they were deleted.
+ALTER TABLE tasks ADD COLUMN deletion_id INTEGER NULL;
+
+UPDATE tasks SET deletion_id = (
+	SELECT deletions.deletion_id FROM (
+		SELECT id, DENSE_RANK() OVER (ORDER BY owner_id, deleted_at) AS deletion_id
+		FROM tasks WHERE deleted_at IS NOT NULL
+	) AS deletions
+	WHERE deletions.id = tasks.id
+)
+WHERE deleted_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_deletion_id ON tasks (deletion_id) WHERE deletion_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_tasks_deletion_id;
+ALTER TABLE tasks DROP COLUMN deletion_id;
+-- +goose StatementEnd